@@ -0,0 +1,10 @@
+package db
+
+import "embed"
+
+// Migrations are applied by `go-account migrate` and on start when
+// dataSource.migrate is on, see go-common/pkg/migrate. A migration never
+// changes once it is merged, fix it with a new version instead.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,11 @@
+-- indexes go with their tables, the extensions stay
+DROP TABLE IF EXISTS user_activities;
+DROP TABLE IF EXISTS socialmedia;
+DROP TABLE IF EXISTS comment;
+DROP TABLE IF EXISTS photo;
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS account_activities;
+DROP TABLE IF EXISTS accounts;
+
+DROP TYPE IF EXISTS activity_type;
+DROP TYPE IF EXISTS account_role;
@@ -1,18 +1,33 @@
-CREATE DATABASE mygram;
+-- baseline, the schema of migration.sql and go-account/db/init.sql when
+-- migrations were introduced. Every later change is its own version, a
+-- database created from those files needs `migrate baseline 1` instead.
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
-USE mygram;
-
-DROP INDEX if exists idx_username;
-DROP INDEX if exists idx_email;
-DROP INDEX if exists idx_user_id;
-DROP INDEX if exists idx_comment_user_id;
-DROP INDEX if exists idx_comment_photo_id;
-
-drop table if exists "socialmedia";
-drop table if exists "comment";
-drop table if exists "photo";
-drop table if exists "user";
+-- best practice to use account role as enum
+CREATE TYPE account_role AS ENUM ('admin', 'normal');
+create table if not exists accounts(
+id uuid primary key not null default uuid_generate_v4(),
+username text not null,
+password text not null,
+role account_role not null,
+created_at timestamptz not null default now(),
+updated_at timestamptz not null default now(),
+deleted_at timestamptz
+);
+CREATE INDEX accounts_deleted_at ON accounts(deleted_at);
+CREATE UNIQUE INDEX accounts_unique_username ON accounts(username);
 
+CREATE TYPE activity_type AS ENUM ('login', 'logout');
+create table if not exists account_activities(
+	id uuid primary key not null default uuid_generate_v4(),
+	user_id uuid not null,
+	type activity_type not null,
+	created_at timestamptz not null default now(),
+	updated_at timestamptz not null default now(),
+	deleted_at timestamptz
+);
+CREATE INDEX accoount_activity_deleted_at ON account_activities(deleted_at);
 
 create table if not exists "user" (
   -- id INT PRIMARY KEY,
@@ -73,10 +88,6 @@ create table if not exists socialmedia (
   deleted_at timestamptz
 );
 
-
-CREATE TYPE account_role AS ENUM ('admin', 'normal');
-
-CREATE TYPE activity_type AS ENUM ('login', 'logout');
 create table if not exists user_activities(
 	id uuid primary key not null default uuid_generate_v4(),
 	user_id INT not null,
@@ -85,4 +96,4 @@ create table if not exists user_activities(
 	updated_at timestamptz not null default now(),
 	deleted_at timestamptz
 );
-CREATE INDEX user_activity_deleted_at ON user_activities(deleted_at);
\ No newline at end of file
+CREATE INDEX user_activity_deleted_at ON user_activities(deleted_at);
@@ -0,0 +1,11 @@
+ALTER TABLE user_activities DROP COLUMN family_id;
+ALTER TABLE user_activities DROP COLUMN rotated_at;
+ALTER TABLE user_activities DROP COLUMN revoked_at;
+
+-- an enum value can not be dropped, the type is created again without it
+DELETE FROM user_activities WHERE type = 'refresh';
+ALTER TYPE activity_type RENAME TO activity_type_refresh;
+CREATE TYPE activity_type AS ENUM ('login', 'logout');
+ALTER TABLE user_activities ALTER COLUMN type TYPE activity_type USING type::text::activity_type;
+ALTER TABLE account_activities ALTER COLUMN type TYPE activity_type USING type::text::activity_type;
+DROP TYPE activity_type_refresh;
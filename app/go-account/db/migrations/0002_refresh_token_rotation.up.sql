@@ -0,0 +1,10 @@
+ALTER TYPE activity_type ADD VALUE 'refresh';
+
+-- refresh token rotation, family_id is the id of the login activity
+ALTER TABLE user_activities ADD COLUMN family_id uuid;
+ALTER TABLE user_activities ADD COLUMN rotated_at timestamptz;
+ALTER TABLE user_activities ADD COLUMN revoked_at timestamptz;
+-- every older activity starts its own family, none of them can be refreshed
+UPDATE user_activities SET family_id = id, revoked_at = now();
+ALTER TABLE user_activities ALTER COLUMN family_id SET NOT NULL;
+CREATE INDEX user_activity_family_id ON user_activities(family_id);
@@ -0,0 +1 @@
+ALTER TABLE "user" ADD COLUMN role account_role NOT NULL DEFAULT 'normal';
@@ -0,0 +1,13 @@
+-- every role change is recorded, actor_user_id is null for the bootstrap cli
+create table if not exists role_audits(
+	id uuid primary key not null default uuid_generate_v4(),
+	actor_user_id INT,
+	target_user_id INT not null,
+	old_role account_role not null,
+	new_role account_role not null,
+	reason TEXT not null default '',
+	FOREIGN KEY (actor_user_id) REFERENCES "user"(id),
+	FOREIGN KEY (target_user_id) REFERENCES "user"(id),
+	created_at timestamptz not null default now()
+);
+CREATE INDEX role_audit_target_user_id ON role_audits(target_user_id);
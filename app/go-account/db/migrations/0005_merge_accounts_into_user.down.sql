@@ -0,0 +1,8 @@
+-- the accounts rows were never removed, only the copies go. It fails
+-- while anything else refers to a merged user, or a user created
+-- through the /account routes has no email or age.
+DELETE FROM user_activities WHERE user_id IN (SELECT id FROM "user" WHERE legacy_account_id IS NOT NULL);
+DELETE FROM "user" WHERE legacy_account_id IS NOT NULL;
+ALTER TABLE "user" DROP COLUMN legacy_account_id;
+ALTER TABLE "user" ALTER COLUMN age SET NOT NULL;
+ALTER TABLE "user" ALTER COLUMN email SET NOT NULL;
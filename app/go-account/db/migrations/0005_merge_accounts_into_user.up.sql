@@ -0,0 +1,51 @@
+-- merges the uuid keyed accounts/account_activities into
+-- user/user_activities, the old tables stay until the /account
+-- routes are gone
+
+-- a username taken on both sides needs a person to decide who keeps it,
+-- rename one of them and run the migration again
+DO $$
+DECLARE
+	taken TEXT;
+BEGIN
+	SELECT string_agg(a.username, ', ' ORDER BY a.username) INTO taken
+	FROM accounts a
+	JOIN "user" u ON u.username = a.username;
+	IF taken IS NOT NULL THEN
+		RAISE EXCEPTION 'usernames in both accounts and user: %', taken;
+	END IF;
+END $$;
+
+ALTER TABLE "user" ALTER COLUMN email DROP NOT NULL;
+ALTER TABLE "user" ALTER COLUMN age DROP NOT NULL;
+-- email and age are null for users created through the deprecated /account routes,
+-- legacy_account_id is the id of the row in the old accounts table
+ALTER TABLE "user" ADD COLUMN legacy_account_id uuid UNIQUE;
+
+-- anyone could sign up as admin through /account, every merged user
+-- starts as normal and admins are granted again through the audited
+-- PUT /api/v1/admin/user/:id/role
+INSERT INTO "user" (username, password, role, legacy_account_id, created_at, updated_at, deleted_at)
+SELECT
+	a.username,
+	a.password,
+	'normal',
+	a.id,
+	a.created_at,
+	a.updated_at,
+	a.deleted_at
+FROM accounts a;
+
+-- every old activity starts its own family, none of them can be refreshed
+INSERT INTO user_activities (id, user_id, type, family_id, revoked_at, created_at, updated_at, deleted_at)
+SELECT
+	aa.id,
+	u.id,
+	aa.type,
+	aa.id,
+	now(),
+	aa.created_at,
+	aa.updated_at,
+	aa.deleted_at
+FROM account_activities aa
+JOIN "user" u ON u.legacy_account_id = aa.user_id;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_socialmedia_created_at_id;
+DROP INDEX IF EXISTS idx_comment_created_at_id;
+DROP INDEX IF EXISTS idx_photo_created_at_id;
@@ -0,0 +1,4 @@
+-- keyset pagination, see go-common/pkg/pagination
+CREATE INDEX idx_photo_created_at_id ON photo (created_at, id);
+CREATE INDEX idx_comment_created_at_id ON comment (created_at, id);
+CREATE INDEX idx_socialmedia_created_at_id ON socialmedia (created_at, id);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS photo_variant;
+
+ALTER TABLE photo DROP COLUMN processing_status;
+ALTER TABLE photo DROP COLUMN blur_hash;
+ALTER TABLE photo DROP COLUMN dominant_color;
+ALTER TABLE photo DROP COLUMN height;
+ALTER TABLE photo DROP COLUMN width;
+ALTER TABLE photo DROP COLUMN photo_key;
+
+DROP TYPE IF EXISTS photo_processing_status;
@@ -0,0 +1,27 @@
+CREATE TYPE photo_processing_status AS ENUM ('pending', 'processing', 'done', 'failed');
+
+-- uploaded photos only, null for photos created from an url
+ALTER TABLE photo ADD COLUMN photo_key VARCHAR(255);
+ALTER TABLE photo ADD COLUMN width INT;
+ALTER TABLE photo ADD COLUMN height INT;
+ALTER TABLE photo ADD COLUMN dominant_color VARCHAR(7);
+ALTER TABLE photo ADD COLUMN blur_hash VARCHAR(64);
+ALTER TABLE photo ADD COLUMN processing_status photo_processing_status;
+
+-- unfinished uploads are picked up on start
+CREATE INDEX idx_photo_processing_status ON photo (processing_status) WHERE processing_status IN ('pending', 'processing');
+
+-- resized copies of uploaded photos, see go-account/pkg/imaging
+create table if not exists photo_variant (
+  id serial NOT NULL PRIMARY KEY,
+  photo_id INT NOT NULL,
+  name VARCHAR(32) NOT NULL,
+  format VARCHAR(16) NOT NULL,
+  width INT NOT NULL,
+  height INT NOT NULL,
+  key VARCHAR(255) NOT NULL,
+  url VARCHAR(255) NOT NULL,
+  FOREIGN KEY (photo_id) REFERENCES photo(id) ON DELETE CASCADE,
+  UNIQUE (photo_id, name, format),
+  created_at timestamptz not null default now()
+);
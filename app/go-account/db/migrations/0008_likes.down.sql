@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS likes;
+
+ALTER TABLE comment DROP COLUMN like_count;
+ALTER TABLE photo DROP COLUMN like_count;
+
+DROP TYPE IF EXISTS like_target;
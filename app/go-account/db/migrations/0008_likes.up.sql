@@ -0,0 +1,18 @@
+CREATE TYPE like_target AS ENUM ('photo', 'comment');
+
+-- kept in step with likes, see reconcile-likes
+ALTER TABLE photo ADD COLUMN like_count INT NOT NULL DEFAULT 0;
+ALTER TABLE comment ADD COLUMN like_count INT NOT NULL DEFAULT 0;
+
+-- one like per user and target, target_id is a photo or comment id
+create table if not exists likes (
+  id serial NOT NULL PRIMARY KEY,
+  user_id INT NOT NULL,
+  target_type like_target NOT NULL,
+  target_id INT NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES "user"(id),
+  UNIQUE (user_id, target_type, target_id),
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX idx_likes_target ON likes (target_type, target_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS timelines;
+DROP TABLE IF EXISTS follows;
+
+ALTER TABLE photo DROP COLUMN fanned_out;
+ALTER TABLE "user" DROP COLUMN follower_count;
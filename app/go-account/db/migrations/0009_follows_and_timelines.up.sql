@@ -0,0 +1,33 @@
+-- kept in step with follows, decides fan-out on write or on read
+ALTER TABLE "user" ADD COLUMN follower_count INT NOT NULL DEFAULT 0;
+-- copied into the timelines of the followers, false photos are read at feed time
+ALTER TABLE photo ADD COLUMN fanned_out BOOLEAN NOT NULL DEFAULT false;
+
+-- fan-out on read, only photos of large accounts
+CREATE INDEX idx_photo_not_fanned_out ON photo (user_id, created_at, id) WHERE NOT fanned_out;
+
+create table if not exists follows (
+  id serial NOT NULL PRIMARY KEY,
+  follower_id INT NOT NULL,
+  followee_id INT NOT NULL,
+  FOREIGN KEY (follower_id) REFERENCES "user"(id),
+  FOREIGN KEY (followee_id) REFERENCES "user"(id),
+  UNIQUE (follower_id, followee_id),
+  CHECK (follower_id <> followee_id),
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX idx_follows_followee ON follows (followee_id, follower_id);
+
+-- home feed, photos fanned out on write, see go-account/modules/repository/follow
+create table if not exists timelines (
+  user_id INT NOT NULL,
+  photo_id INT NOT NULL,
+  author_id INT NOT NULL,
+  created_at timestamptz not null,
+  PRIMARY KEY (user_id, photo_id),
+  FOREIGN KEY (user_id) REFERENCES "user"(id),
+  FOREIGN KEY (photo_id) REFERENCES photo(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_timelines_user_created_at ON timelines (user_id, created_at DESC, photo_id);
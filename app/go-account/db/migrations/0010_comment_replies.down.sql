@@ -0,0 +1,3 @@
+ALTER TABLE comment DROP COLUMN reply_count;
+ALTER TABLE comment DROP COLUMN depth;
+ALTER TABLE comment DROP COLUMN parent_id;
@@ -0,0 +1,8 @@
+-- replies, depth is 0 for top level comments
+ALTER TABLE comment ADD COLUMN parent_id INT REFERENCES comment(id);
+ALTER TABLE comment ADD COLUMN depth INT NOT NULL DEFAULT 0;
+ALTER TABLE comment ADD COLUMN reply_count INT NOT NULL DEFAULT 0;
+
+-- /photo/:id/comments and /comment/:id/replies
+CREATE INDEX idx_comment_photo_top_level ON comment (photo_id, created_at, id) WHERE parent_id IS NULL;
+CREATE INDEX idx_comment_parent_id ON comment (parent_id, created_at, id);
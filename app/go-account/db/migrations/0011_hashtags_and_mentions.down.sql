@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS mentions;
+DROP TABLE IF EXISTS photo_hashtags;
+DROP TABLE IF EXISTS hashtags;
+
+DROP TYPE IF EXISTS mention_source;
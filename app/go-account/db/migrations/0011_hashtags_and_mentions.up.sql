@@ -0,0 +1,35 @@
+CREATE TYPE mention_source AS ENUM ('photo', 'comment');
+
+-- parsed from captions, see go-account/pkg/tagparse
+create table if not exists hashtags (
+  id serial NOT NULL PRIMARY KEY,
+  name VARCHAR(64) UNIQUE NOT NULL,
+  created_at timestamptz not null default now()
+);
+
+create table if not exists photo_hashtags (
+  photo_id INT NOT NULL,
+  hashtag_id INT NOT NULL,
+  PRIMARY KEY (photo_id, hashtag_id),
+  FOREIGN KEY (photo_id) REFERENCES photo(id) ON DELETE CASCADE,
+  FOREIGN KEY (hashtag_id) REFERENCES hashtags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_photo_hashtags_hashtag ON photo_hashtags (hashtag_id, photo_id);
+
+-- source_id is a photo or a comment, photo_id is the photo either way
+create table if not exists mentions (
+  id serial NOT NULL PRIMARY KEY,
+  user_id INT NOT NULL,
+  author_id INT NOT NULL,
+  source_type mention_source NOT NULL,
+  source_id INT NOT NULL,
+  photo_id INT NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES "user"(id),
+  FOREIGN KEY (author_id) REFERENCES "user"(id),
+  FOREIGN KEY (photo_id) REFERENCES photo(id) ON DELETE CASCADE,
+  UNIQUE (source_type, source_id, user_id),
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX idx_mentions_user_created_at ON mentions (user_id, created_at, id);
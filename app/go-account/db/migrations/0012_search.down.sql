@@ -0,0 +1,2 @@
+ALTER TABLE photo DROP COLUMN search_vector;
+ALTER TABLE "user" DROP COLUMN search_vector;
@@ -0,0 +1,9 @@
+-- full-text search, see go-account/modules/repository/search
+ALTER TABLE "user" ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (to_tsvector('simple', username)) STORED;
+-- the title weighs more than the caption
+ALTER TABLE photo ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', caption), 'B')
+) STORED;
+
+CREATE INDEX idx_user_search_vector ON "user" USING GIN (search_vector);
+CREATE INDEX idx_photo_search_vector ON photo USING GIN (search_vector);
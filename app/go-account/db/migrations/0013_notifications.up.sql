@@ -0,0 +1,29 @@
+CREATE TYPE notification_type AS ENUM ('comment', 'like', 'follow', 'mention');
+
+-- user_id is told that actor_id did something, see go-account/modules/service/notification
+create table if not exists notifications (
+  id serial NOT NULL PRIMARY KEY,
+  user_id INT NOT NULL,
+  actor_id INT NOT NULL,
+  type notification_type NOT NULL,
+  photo_id INT,
+  comment_id INT,
+  read_at timestamptz,
+  FOREIGN KEY (user_id) REFERENCES "user"(id),
+  FOREIGN KEY (actor_id) REFERENCES "user"(id),
+  FOREIGN KEY (photo_id) REFERENCES photo(id) ON DELETE CASCADE,
+  FOREIGN KEY (comment_id) REFERENCES comment(id) ON DELETE CASCADE,
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX idx_notifications_user_created_at ON notifications (user_id, created_at, id);
+CREATE INDEX idx_notifications_user_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- only types a user changed are stored, the others are on
+create table if not exists notification_preferences (
+  user_id INT NOT NULL,
+  type notification_type NOT NULL,
+  enabled BOOLEAN NOT NULL,
+  PRIMARY KEY (user_id, type),
+  FOREIGN KEY (user_id) REFERENCES "user"(id)
+);
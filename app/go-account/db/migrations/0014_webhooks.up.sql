@@ -0,0 +1,42 @@
+CREATE TYPE webhook_scope AS ENUM ('user', 'app');
+CREATE TYPE webhook_delivery_status AS ENUM ('pending', 'succeeded', 'failed');
+
+-- user scope gets the events of the content of user_id, app scope gets
+-- the events of everyone, see go-account/modules/service/webhook
+create table if not exists webhooks (
+  id serial NOT NULL PRIMARY KEY,
+  user_id INT NOT NULL,
+  scope webhook_scope NOT NULL DEFAULT 'user',
+  url TEXT NOT NULL,
+  secret VARCHAR(255) NOT NULL,
+  events TEXT[] NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT true,
+  FOREIGN KEY (user_id) REFERENCES "user"(id),
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+CREATE INDEX idx_webhooks_user_id ON webhooks (user_id);
+
+-- the delivery queue and its log, pending rows are retried until next_attempt_at
+create table if not exists webhook_deliveries (
+  id serial NOT NULL PRIMARY KEY,
+  webhook_id INT NOT NULL,
+  -- shared by the replays of a delivery, receivers dedupe on it
+  event_id uuid NOT NULL,
+  event_type VARCHAR(64) NOT NULL,
+  payload JSONB NOT NULL,
+  status webhook_delivery_status NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at timestamptz NOT NULL DEFAULT now(),
+  last_status_code INT,
+  last_error TEXT NOT NULL DEFAULT '',
+  delivered_at timestamptz,
+  replay_of INT,
+  FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+  FOREIGN KEY (replay_of) REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook_created_at ON webhook_deliveries (webhook_id, created_at, id);
@@ -0,0 +1,17 @@
+-- domain events written in the transaction of the change, the relay
+-- publishes them in id order, see go-account/modules/service/outbox
+create table if not exists outbox (
+  id bigserial NOT NULL PRIMARY KEY,
+  -- consumers dedupe on it, it is the same when an event is published again
+  event_id uuid NOT NULL UNIQUE,
+  event_type VARCHAR(64) NOT NULL,
+  aggregate_type VARCHAR(32) NOT NULL,
+  aggregate_id VARCHAR(64) NOT NULL,
+  payload JSONB NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  published_at timestamptz,
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX idx_outbox_unpublished ON outbox (id) WHERE published_at IS NULL;
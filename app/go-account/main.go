@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -9,10 +10,32 @@ import (
 	"time"
 
 	"github.com/mygram/go-account/servers"
+	"github.com/mygram/go-common/config"
 	"github.com/mygram/go-common/pkg/logger"
 )
 
 func main() {
+	config.Init()
+
+	// flags are parsed by config, what is left is the subcommand
+	switch flag.Arg(0) {
+	case servers.CMD_BOOTSTRAP_ADMIN:
+		if err := servers.RunBootstrapAdmin(flag.Args()[1:]); err != nil {
+			log.Fatalf("%v: %v", servers.CMD_BOOTSTRAP_ADMIN, err)
+		}
+		return
+	case servers.CMD_RECONCILE_LIKES:
+		if err := servers.RunReconcileLikes(flag.Args()[1:]); err != nil {
+			log.Fatalf("%v: %v", servers.CMD_RECONCILE_LIKES, err)
+		}
+		return
+	case servers.CMD_MIGRATE:
+		if err := servers.RunMigrate(flag.Args()[1:]); err != nil {
+			log.Fatalf("%v: %v", servers.CMD_MIGRATE, err)
+		}
+		return
+	}
+
 	// run http server
 	srv := servers.NewHttpServer()
 
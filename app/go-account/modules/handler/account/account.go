@@ -7,8 +7,15 @@ type IAccountHandler interface {
 	CreateAccount(ctx *gin.Context)
 	GetAccount(ctx *gin.Context)
 
+	GetJWKS(ctx *gin.Context)
+
 	RegisterUserHdl(ctx *gin.Context)
 	LoginUserHdl(ctx *gin.Context)
+	RefreshTokenHdl(ctx *gin.Context)
+	LogoutUserHdl(ctx *gin.Context)
+	LogoutAllUserHdl(ctx *gin.Context)
+	UpdateUserRoleHdl(ctx *gin.Context)
+	GetRoleAuditsHdl(ctx *gin.Context)
 	GetUser(ctx *gin.Context)
 
 	GetAllPhotos(ctx *gin.Context)
@@ -19,6 +26,8 @@ type IAccountHandler interface {
 
 	GetAllComments(ctx *gin.Context)
 	GetCommentById(ctx *gin.Context)
+	GetPhotoComments(ctx *gin.Context)
+	GetCommentReplies(ctx *gin.Context)
 	CreateComment(ctx *gin.Context)
 	UpdateComment(ctx *gin.Context)
 	DeleteComment(ctx *gin.Context)
@@ -1,82 +1,117 @@
 package account
 
 import (
-	"errors"
 	"net/http"
-	"net/mail"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	accountmodel "github.com/mygram/go-account/modules/models/account"
+	likemodel "github.com/mygram/go-account/modules/models/like"
 	"github.com/mygram/go-account/modules/models/token"
 	accountservice "github.com/mygram/go-account/modules/service/account"
+	likeservice "github.com/mygram/go-account/modules/service/like"
+	"github.com/mygram/go-account/pkg/crypto"
 	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/domainerr"
 	"github.com/mygram/go-common/pkg/json"
 	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
 	"github.com/mygram/go-common/pkg/response"
 )
 
+// Handlers only push errors with ctx.Error and return,
+// the response is written by commonmidware.ErrorHandler.
 type AccountHandlerImpl struct {
 	accService accountservice.IAccountService
+	likeSvc    likeservice.ILikeService
 }
 
-func NewAccountHandlerImpl(accService accountservice.IAccountService) IAccountHandler {
+func NewAccountHandlerImpl(accService accountservice.IAccountService, likeSvc likeservice.ILikeService) IAccountHandler {
 	return &AccountHandlerImpl{
 		accService: accService,
+		likeSvc:    likeSvc,
 	}
 }
 
 // util SECTION
 func (a *AccountHandlerImpl) getIdFromParam(ctx *gin.Context) (idUint uint64, err error) {
-	id := ctx.Param("id")
+	return parseId(ctx.Param("id"))
+}
+
+func (a *AccountHandlerImpl) getIdFromQuery(ctx *gin.Context) (idUint uint64, err error) {
+	return parseId(ctx.Query("id"))
+}
+
+func parseId(id string) (idUint uint64, err error) {
 	if id == "" {
-		err = errors.New("failed id")
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to update user",
-			Error: response.InvalidParam,
-		})
+		err = domainerr.New(domainerr.KIND_VALIDATION, response.CODE_INVALID_PARAM, "id is required")
 		return
 	}
 	// transform id string to uint64
 	idUint, err = strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		err = errors.New("failed parse id")
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to update user",
-			Error: response.InvalidParam,
-		})
+		err = domainerr.WrapCode(domainerr.KIND_VALIDATION, response.CODE_INVALID_PARAM, "id must be a number", err)
 		return
 	}
 	return
 }
 
+func notOwner(message string) error {
+	return domainerr.New(domainerr.KIND_FORBIDDEN, response.CODE_NOT_OWNER, message)
+}
+
+func bindError(err error) error {
+	return validation.BindError(err)
+}
+
+// bindList binds ?limit=&cursor=&sort= and the filters of a list endpoint.
+func bindList(ctx *gin.Context, filter any) (params pagination.Params, err error) {
+	var query pagination.Query
+	if err = ctx.ShouldBindQuery(&query); err != nil {
+		err = validation.BindQueryError(err)
+		return
+	}
+	if err = ctx.ShouldBindQuery(filter); err != nil {
+		err = validation.BindQueryError(err)
+		return
+	}
+	return query.Params()
+}
+
+// likedByMe is nil for anonymous requests, see middleware.OptionalBearerOAuth
+func (a *AccountHandlerImpl) likedByMe(ctx *gin.Context, target likemodel.LikeTarget, ids ...uint64) (liked map[uint64]bool, err error) {
+	userId, ok := middleware.UserIDFromClaim(ctx)
+	if !ok {
+		return
+	}
+	return a.likeSvc.LikedByMe(ctx, userId, target, ids)
+}
+
+func getClaim(ctx *gin.Context, key middleware.ContextKey, claim any) (err error) {
+	claimI, ok := ctx.Get(key.String())
+	if !ok {
+		return domainerr.Unauthenticated("error get claim from context")
+	}
+	if err = json.ObjectMapper(claimI, claim); err != nil {
+		return domainerr.WrapCode(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "error mapping claim", err)
+	}
+	return
+}
 
 // ACCOUNT SECTION
+// deprecated, see router
 func (a *AccountHandlerImpl) LoginAccount(ctx *gin.Context) {
 	// binding payload
 	var loginAccount accountmodel.LoginAccount
-	if err := ctx.BindJSON(&loginAccount); err != nil {
-		logger.Error(ctx, "error binding payload",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest,
-			response.ErrorResponse{
-				Message: response.InvalidBody,
-				Error:   "error binding payload",
-			},
-		)
+	if err := ctx.ShouldBindJSON(&loginAccount); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
 
 	tokens, err := a.accService.LoginAccountByUserName(ctx, loginAccount)
 	if err != nil {
-		logger.Error(ctx, "error create account",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: response.InternalServer,
-				Error:   response.SomethingWentWrong,
-			},
-		)
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
@@ -88,28 +123,14 @@ func (a *AccountHandlerImpl) LoginAccount(ctx *gin.Context) {
 func (a *AccountHandlerImpl) CreateAccount(ctx *gin.Context) {
 	// binding payload
 	var createAccount accountmodel.CreateAccount
-	if err := ctx.BindJSON(&createAccount); err != nil {
-		logger.Error(ctx, "error binding payload",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest,
-			response.ErrorResponse{
-				Message: response.InvalidBody,
-				Error:   "error binding payload",
-			},
-		)
+	if err := ctx.ShouldBindJSON(&createAccount); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
 
 	created, err := a.accService.CreateAccount(ctx, createAccount)
 	if err != nil {
-		logger.Error(ctx, "error create account",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: response.InternalServer,
-				Error:   response.SomethingWentWrong,
-			},
-		)
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
@@ -120,35 +141,15 @@ func (a *AccountHandlerImpl) CreateAccount(ctx *gin.Context) {
 
 func (a *AccountHandlerImpl) GetAccount(ctx *gin.Context) {
 	// get user_id from context first
-	accessClaimI, ok := ctx.Get(middleware.AccessClaim.String())
-	if !ok {
-		err := errors.New("error get claim from context")
-		logger.Error(ctx, "error get payload",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, response.ErrorResponse{
-			Message: response.InvalidPayload,
-			Error:   "invalid user id",
-		})
-		return
-	}
-
 	var accessClaim token.AccessClaim
-	if err := json.ObjectMapper(accessClaimI, &accessClaim); err != nil {
-		logger.Error(ctx, "error mapping object payload",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, response.ErrorResponse{
-			Message: response.InvalidPayload,
-			Error:   "invalid payload",
-		})
+	if err := getClaim(ctx, middleware.AccessClaim, &accessClaim); err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	account, err := a.accService.GetAccount(ctx, accessClaim.UserID)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message: response.InternalServer,
-			Error:   "something went wrong",
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
@@ -156,37 +157,29 @@ func (a *AccountHandlerImpl) GetAccount(ctx *gin.Context) {
 		Data:    account,
 	})
 }
-// ACCOUNT SECTION
 
-// AUTH 
-func (a *AccountHandlerImpl) AuthIncomingRequest(ctx *gin.Context) (user accountmodel.User, err error, message string) {
-		// get user_id from context first
-	accessClaimI, ok := ctx.Get(middleware.AccessClaim.String())
-	message = ""
-	if !ok {
-		message = "error get claim from context"
-		err = errors.New(message)
-		logger.Error(ctx, message,
-			"error", err)
-		return
-	}
+// JWKS SECTION
+func (a *AccountHandlerImpl) GetJWKS(ctx *gin.Context) {
+	// public keys are safe to be cached by verifiers for a while
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, crypto.JWKS())
+}
 
+// AUTH
+func (a *AccountHandlerImpl) AuthIncomingRequest(ctx *gin.Context) (user accountmodel.User, err error) {
+	// get user_id from context first
 	var accessClaim token.AccessClaim
-	if err = json.ObjectMapper(accessClaimI, &accessClaim); err != nil {
-		message = "error mapping object payload"
-		logger.Error(ctx, message,
-			"error", err)
+	if err = getClaim(ctx, middleware.AccessClaim, &accessClaim); err != nil {
 		return
 	}
 
-	account, err := a.accService.GetUser(ctx, accessClaim.UserID)
+	user, err = a.accService.GetUser(ctx, accessClaim.UserID)
 	if err != nil {
-		message = "error while getting user"
-		logger.Error(ctx, message,
+		logger.Error(ctx, "error while getting user",
 			"error", err)
 		return
 	}
-	return account, err, message
+	return
 }
 
 func CreateEntityAuth(userId uint64, payloadUserId uint64) (allowed bool, message string) {
@@ -221,196 +214,130 @@ func DeleteEntityAuth(userId uint64, payloadUserId uint64) (allowed bool, messag
 	return true, "aman"
 }
 
-func EmailValidation(email string) (con bool, emailAddres string, message string) {
-	addr, err := mail.ParseAddress(email)
+// USER SECTION
+func (a *AccountHandlerImpl) LoginUserHdl(ctx *gin.Context) {
+	// binding payload
+	var loginAccount accountmodel.LoginUser
+	if err := ctx.ShouldBindJSON(&loginAccount); err != nil {
+		ctx.Error(bindError(err))
+		return
+	}
+
+	tokens, err := a.accService.LoginUser(ctx, loginAccount)
 	if err != nil {
-			return false, addr.Address, "invalid email"
+		ctx.Error(err)
+		return
 	}
-	return true, addr.Address, "valid"
+	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
+		Message: "success created",
+		Data:    tokens,
+	})
 }
 
-func PhotoPayloadValidation(pho accountmodel.Photo) (con bool, message string) {
-	con = true
-	if pho.Title == "" {
-		message += "title cannot be empty,"
-		con = false
+func (a *AccountHandlerImpl) RefreshTokenHdl(ctx *gin.Context) {
+	// binding payload
+	var refreshReq token.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&refreshReq); err != nil {
+		ctx.Error(bindError(err))
+		return
 	}
-	if pho.PhotoUrl == "" {
-		message += "photo url cannot be empty"
-		con = false
+
+	tokens, err := a.accService.RefreshUserToken(ctx, refreshReq.RefreshToken)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
-	return con, message
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success refresh token",
+		Data:    tokens,
+	})
 }
 
-func CommentPayloadValidation(comment accountmodel.Comment) (con bool, message string) {
-	con = true
-	if comment.Message == "" {
-		message += "message cannot be empty,"
-		con = false
-	}
-	return con, message
+func (a *AccountHandlerImpl) LogoutUserHdl(ctx *gin.Context) {
+	a.logoutUser(ctx, false)
+}
+
+func (a *AccountHandlerImpl) LogoutAllUserHdl(ctx *gin.Context) {
+	a.logoutUser(ctx, true)
 }
 
-func SocialMediaPayloadValidation(socialMedia accountmodel.SocialMedia) (con bool, message string) {
-	con = true
-	if socialMedia.Name == "" {
-		message += "name cannot be empty,"
-		con = false
+func (a *AccountHandlerImpl) logoutUser(ctx *gin.Context, all bool) {
+	// get user_id and jti from context first
+	var accessClaim token.AccessClaim
+	if err := getClaim(ctx, middleware.AccessClaim, &accessClaim); err != nil {
+		ctx.Error(err)
+		return
+	}
+	var tokenClaim token.DefaultClaim
+	if err := getClaim(ctx, middleware.TokenClaim, &tokenClaim); err != nil {
+		ctx.Error(err)
+		return
 	}
-	if socialMedia.SocialMediaUrl == "" {
-		message += "social media url cannot be empty,"
-		con = false
+
+	if err := a.accService.LogoutUser(ctx, accessClaim.UserID, tokenClaim.JTI, all); err != nil {
+		ctx.Error(err)
+		return
 	}
-	return con, message
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success logout",
+	})
 }
 
+func (a *AccountHandlerImpl) UpdateUserRoleHdl(ctx *gin.Context) {
+	// get actor user_id from context first
+	var accessClaim token.AccessClaim
+	if err := getClaim(ctx, middleware.AccessClaim, &accessClaim); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	targetId, err := a.getIdFromParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-// USER SECTION
-func (a *AccountHandlerImpl) LoginUserHdl(ctx *gin.Context) {
 	// binding payload
-	var loginAccount accountmodel.LoginUser
-	if err := ctx.BindJSON(&loginAccount); err != nil {
-		logger.Error(ctx, "error binding payload",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest,
-			response.ErrorResponse{
-				Message: response.InvalidBody,
-				Error:   "error binding payload",
-			},
-		)
+	var req accountmodel.UpdateUserRole
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
 
-	tokens, err := a.accService.LoginUser(ctx, loginAccount)
+	user, err := a.accService.UpdateUserRole(ctx, accessClaim.UserID, targetId, req)
 	if err != nil {
-		logger.Error(ctx, "error create account",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: response.InternalServer,
-				Error:   response.SomethingWentWrong,
-			},
-		)
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
-		Message: "success created",
-		Data:    tokens,
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success update user role",
+		Data:    accountmodel.ToUserRoleResponse(user),
+	})
+}
+
+func (a *AccountHandlerImpl) GetRoleAuditsHdl(ctx *gin.Context) {
+	audits, err := a.accService.GetRoleAudits(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success",
+		Data:    audits,
 	})
 }
 
 func (a *AccountHandlerImpl) RegisterUserHdl(ctx *gin.Context) {
 	// binding payload
 	var createAccount accountmodel.RegisterUser
-	if err := ctx.BindJSON(&createAccount); err != nil {
-		logger.Error(ctx, "error binding payload",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest,
-			response.ErrorResponse{
-				Message: response.InvalidBody,
-				Error:   "error binding payload",
-			},
-		)
-		return
-	}
-
-	// not null validation
-	isEmailEmpty := createAccount.Email == ""
-	isUsernameEmpty := createAccount.Username == ""
-	isPasswordEmpty := createAccount.Password == ""
-	isAgeEmpty := createAccount.Age == ""
-
-	if isEmailEmpty || isUsernameEmpty || isPasswordEmpty || isAgeEmpty {
-		message := "empty fields "
-		if isEmailEmpty {
-			message += "email "
-		}
-		if isUsernameEmpty {
-			message += "username "
-		}
-		if isPasswordEmpty {
-			message += "password "
-		}
-		if isAgeEmpty {
-			message += "age "
-		}
-		logger.Error(ctx, "error create account",
-			"error", message)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: message,
-				Error:   response.InvalidPayload,
-			},
-		)
-		return
-	}
-
-	// password >= 6
-	isPasswordLenEnough := len(createAccount.Password) >= 6
-
-	if !isPasswordLenEnough {
-		logger.Error(ctx, "error create account",
-			"error", "password length insufficient")
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: "password length insufficient",
-				Error:   response.InvalidPayload,
-			},
-		)
-		return
-	}
-
-	// age > 8
-	age, err := strconv.ParseInt(createAccount.Age, 10, 64)
-	if err != nil {
-		message := "age conversion failed"
-		logger.Error(ctx, "error create account",
-			"error", message)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: message,
-				Error:   response.InvalidPayload,
-			},
-		)
-		return
-	}
-	isAgeEnough := age > 8
-
-	if !isAgeEnough {
-		logger.Error(ctx, "error create account",
-			"error", "age insufficient")
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: "age insufficient",
-				Error:   response.InvalidPayload,
-			},
-		)
-		return
-	}
-
-	con, emailAddress, message := EmailValidation(createAccount.Email)
-	if !con {
-		logger.Error(ctx, "error create account",
-			"error", message)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: message + " " + emailAddress,
-				Error:   response.InvalidPayload,
-			},
-		)
+	if err := ctx.ShouldBindJSON(&createAccount); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
 
 	created, err := a.accService.RegisterUser(ctx, createAccount)
 	if err != nil {
-		logger.Error(ctx, "error create account",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError,
-			response.ErrorResponse{
-				Message: response.InternalServer,
-				Error:   response.SomethingWentWrong,
-			},
-		)
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
@@ -421,824 +348,594 @@ func (a *AccountHandlerImpl) RegisterUserHdl(ctx *gin.Context) {
 
 func (a *AccountHandlerImpl) GetUser(ctx *gin.Context) {
 	// get user_id from context first
-	accessClaimI, ok := ctx.Get(middleware.AccessClaim.String())
-	if !ok {
-		err := errors.New("error get claim from context")
-		logger.Error(ctx, "error get payload",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, response.ErrorResponse{
-			Message: response.InvalidPayload,
-			Error:   "invalid user id",
-		})
-		return
-	}
-
 	var accessClaim token.AccessClaim
-	if err := json.ObjectMapper(accessClaimI, &accessClaim); err != nil {
-		logger.Error(ctx, "error mapping object payload",
-			"error", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, response.ErrorResponse{
-			Message: response.InvalidPayload,
-			Error:   "invalid payload",
-		})
+	if err := getClaim(ctx, middleware.AccessClaim, &accessClaim); err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	account, err := a.accService.GetUser(ctx, accessClaim.UserID)
+	user, err := a.accService.GetUser(ctx, accessClaim.UserID)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message: response.InternalServer,
-			Error:   "something went wrong",
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
 		Message: "success",
-		Data:    account,
+		Data:    accountmodel.ToUserResponse(user),
 	})
 }
 
 // PHOTO SECTION
 func (a *AccountHandlerImpl) GetAllPhotos(ctx *gin.Context) {
-	photos, err := a.accService.GetAllPhotos(ctx)
+	var filter accountmodel.PhotoFilter
+	params, err := bindList(ctx, &filter)
 	if err != nil {
-		// bad code, should be wrapped in other package
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message: response.SomethingWentWrong,
-			Error: "failed to get photos",
-		})
+		ctx.Error(err)
 		return
 	}
+
+	photos, page, err := a.accService.GetAllPhotos(ctx, filter, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res := accountmodel.ToPhotoResponses(photos)
+	ids := make([]uint64, 0, len(res))
+	for _, photo := range res {
+		ids = append(ids, photo.ID)
+	}
+	liked, err := a.likedByMe(ctx, likemodel.TARGET_PHOTO, ids...)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	for i := range res {
+		res[i].SetLikedByMe(liked)
+	}
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
-		Message: "success get photos",
-		Data:    photos,
+		Message:    "success get photos",
+		Data:       res,
+		Pagination: &page,
 	})
 }
 func (a *AccountHandlerImpl) GetPhotoById(ctx *gin.Context) {
-	id := ctx.Query("id")
-	if id == "" {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to find photo",
-			Error: response.InvalidQuery,
-		})
-		return
-	}
-	// transform id string to uint64
-	idUint, err := strconv.ParseUint(id, 10, 64)
+	idUint, err := a.getIdFromQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to find photo",
-			Error: response.InvalidParam,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	// call service
 	photo, err := a.accService.GetPhotoById(ctx, idUint)
 	if err != nil {
-		if err.Error() == "photo is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to find photo",
-				Error: err.Error(),
-			})
-			return
-		}
+		ctx.Error(err)
+		return
+	}
 
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to find photo",
-			Error: response.SomethingWentWrong,
-		})
+	res := accountmodel.ToPhotoResponse(photo)
+	liked, err := a.likedByMe(ctx, likemodel.TARGET_PHOTO, photo.ID)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
+	res.SetLikedByMe(liked)
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
 		Message: "success find photo",
-		Data:    photo,
+		Data:    res,
 	})
 }
+// CreatePhoto only takes multipart requests carrying the file, a json
+// body fails on the missing photo.
 func (a *AccountHandlerImpl) CreatePhoto(ctx *gin.Context) {
-	// mendapatkan body
-	var photoIn accountmodel.Photo
-
-	logger.Info(ctx, "otw bind photo")
-	if err := ctx.Bind(&photoIn); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to insert photo",
-			Error: response.InvalidBody,
-		})
+	var req accountmodel.PhotoUploadRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
 
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
-	con, message := CreateEntityAuth(user.ID, photoIn.UserID)
-	if con != true {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
+	con, message := CreateEntityAuth(user.ID, req.UserID)
+	if !con {
+		ctx.Error(notOwner(message))
 		return
 	}
 
-	logger.Info(ctx, "otw validate photo")
-	con, message = PhotoPayloadValidation(photoIn)
-	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InvalidParam,
-		})
+	file, err := req.Photo.Open()
+	if err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
+	defer file.Close()
 
-	logger.Info(ctx, "otw insert photo")
-	insertedPhoto, err := a.accService.CreatePhoto(ctx, photoIn)
+	insertedPhoto, err := a.accService.UploadPhoto(ctx, req.ToPhoto(), file, req.Photo.Size)
 	if err != nil {
-		// bad code, should be wrapped in other package
-		if err.Error() == "error duplication email" {
-			ctx.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{
-				Message:  "failed to insert photo",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to insert photo",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
 		Message: "success create photo",
-		Data:    insertedPhoto,
+		Data:    accountmodel.ToPhotoResponse(insertedPhoto),
 	})
 }
 func (a *AccountHandlerImpl) UpdatePhoto(ctx *gin.Context) {
 	idUint, err := a.getIdFromParam(ctx)
 	if err != nil {
+		ctx.Error(err)
 		return
 	}
 	// binding payload
-	var photoIn accountmodel.Photo
-	if err := ctx.Bind(&photoIn); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to update user",
-			Error: response.InvalidBody,
-		})
+	var req accountmodel.PhotoRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
+	photoIn := req.ToPhoto()
 	photoIn.ID = idUint
 
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	toBeUpdatedPhoto, err := a.accService.GetPhotoById(ctx, photoIn.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "error before update, while getting photo",
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 
-	con, message := UpdateEntityAuth(user.ID, photoIn.UserID, toBeUpdatedPhoto.UserID)
-	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
-		return
-	}
-
-	// validate name
-	// if photoIn.Name == "" {
-	// 	ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-	// 		Message:  "failed to update user",
-	// 		Error: response.InvalidParam,
-	// 	})
-	// 	return
-	// }
-	logger.Info(ctx, "otw validate photo")
-	con, message = PhotoPayloadValidation(photoIn)
+	con, message := true, ""
+	if user.Role.Can(accountmodel.PERMISSION_MANAGE_ANY_CONTENT) {
+		// admin keeps the original owner
+		photoIn.UserID = toBeUpdatedPhoto.UserID
+	} else {
+		con, message = UpdateEntityAuth(user.ID, photoIn.UserID, toBeUpdatedPhoto.UserID)
+	}
 	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InvalidParam,
-		})
+		ctx.Error(notOwner(message))
 		return
 	}
 
-	updatedPhoto, err := a.accService.UpdatePhoto(ctx, photoIn);
+	updatedPhoto, err := a.accService.UpdatePhoto(ctx, photoIn)
 	if err != nil {
-		if err.Error() == "user is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to update user",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to update user",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
-		Message: "success update user",
-		Data: updatedPhoto,
+		Message: "success update photo",
+		Data:    accountmodel.ToPhotoResponse(updatedPhoto),
 	})
 }
 func (a *AccountHandlerImpl) DeletePhoto(ctx *gin.Context) {
 	idUint, err := a.getIdFromParam(ctx)
 	if err != nil {
+		ctx.Error(err)
 		return
 	}
-	
+
 	photo, err := a.accService.GetPhotoById(ctx, idUint)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "error get photo",
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
-	
-	con, message := DeleteEntityAuth(photo.UserID, user.ID)
+
+	con, message := true, ""
+	if !user.Role.Can(accountmodel.PERMISSION_MANAGE_ANY_CONTENT) {
+		con, message = DeleteEntityAuth(photo.UserID, user.ID)
+	}
 	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
+		ctx.Error(notOwner(message))
 		return
 	}
-	
+
 	deletedPhoto, err := a.accService.DeletePhoto(ctx, idUint)
 	if err != nil {
-		if err.Error() == "photo is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to delete photo",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to delete photo",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
 		Message: "success delete photo",
-		Data:    deletedPhoto,
+		Data:    accountmodel.ToPhotoResponse(deletedPhoto),
 	})
 }
 
+// COMMENT SECTION
 func (a *AccountHandlerImpl) GetAllComments(ctx *gin.Context) {
-	comments, err := a.accService.GetAllComments(ctx)
+	var filter accountmodel.CommentFilter
+	params, err := bindList(ctx, &filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	comments, page, err := a.accService.GetAllComments(ctx, filter, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	a.writeComments(ctx, comments, page)
+}
+
+// GetPhotoComments lists the top level comments of a photo,
+// GET /photo/:id/comments
+func (a *AccountHandlerImpl) GetPhotoComments(ctx *gin.Context) {
+	idUint, err := a.getIdFromParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	params, err := bindList(ctx, &struct{}{})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	comments, page, err := a.accService.GetPhotoComments(ctx, idUint, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	a.writeComments(ctx, comments, page)
+}
+
+// GetCommentReplies lists the replies of a comment oldest first,
+// GET /comment/:id/replies?cursor= with the replies_cursor of the comment
+func (a *AccountHandlerImpl) GetCommentReplies(ctx *gin.Context) {
+	idUint, err := a.getIdFromParam(ctx)
 	if err != nil {
-		// bad code, should be wrapped in other package
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message: response.SomethingWentWrong,
-			Error: "failed to get comments",
-		})
+		ctx.Error(err)
 		return
 	}
+	var query pagination.Query
+	if err = ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	if query.Sort == "" {
+		query.Sort = accountmodel.RepliesSort.String()
+	}
+	params, err := query.Params()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	comments, page, err := a.accService.GetCommentReplies(ctx, idUint, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	a.writeComments(ctx, comments, page)
+}
+
+func (a *AccountHandlerImpl) writeComments(ctx *gin.Context, comments []accountmodel.Comment, page response.Pagination) {
+	res := accountmodel.ToCommentResponses(comments)
+	liked, err := a.likedByMe(ctx, likemodel.TARGET_COMMENT, accountmodel.CommentIds(res)...)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	for i := range res {
+		res[i].SetLikedByMe(liked)
+	}
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
-		Message: "success get comments",
-		Data:    comments,
+		Message:    "success get comments",
+		Data:       res,
+		Pagination: &page,
 	})
 }
 func (a *AccountHandlerImpl) GetCommentById(ctx *gin.Context) {
-	id := ctx.Query("id")
-	if id == "" {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to find comment",
-			Error: response.InvalidQuery,
-		})
-		return
-	}
-	// transform id string to uint64
-	idUint, err := strconv.ParseUint(id, 10, 64)
+	idUint, err := a.getIdFromQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to find comment",
-			Error: response.InvalidParam,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	// call service
 	comment, err := a.accService.GetCommentById(ctx, idUint)
 	if err != nil {
-		if err.Error() == "comment is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to find comment",
-				Error: err.Error(),
-			})
-			return
-		}
+		ctx.Error(err)
+		return
+	}
 
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to find comment",
-			Error: response.SomethingWentWrong,
-		})
+	res := accountmodel.ToCommentResponse(comment)
+	liked, err := a.likedByMe(ctx, likemodel.TARGET_COMMENT, comment.ID)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
+	res.SetLikedByMe(liked)
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
 		Message: "success find comment",
-		Data:    comment,
+		Data:    res,
 	})
 }
 func (a *AccountHandlerImpl) CreateComment(ctx *gin.Context) {
 	// mendapatkan body
-	var commentIn accountmodel.Comment
-
-	logger.Info(ctx, "otw bind comment")
-	if err := ctx.Bind(&commentIn); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to insert comment",
-			Error: response.InvalidBody,
-		})
+	var req accountmodel.CommentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
+	commentIn := req.ToComment()
 
-	logger.Info(ctx, "otw validate comment")
-	// validate name and email
-	// if commentIn.Title == "" || commentIn.PhotoUrl == "" {
-	// 	ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-	// 		Message:  "failed to insert comment",
-	// 		Error: response.InvalidParam,
-	// 	})
-	// 	return
-	// }
-	
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 	con, message := CreateEntityAuth(user.ID, commentIn.UserID)
-	if con != true {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
-		return
-	}
-	con, message = CommentPayloadValidation(commentIn)
 	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InvalidParam,
-		})
+		ctx.Error(notOwner(message))
 		return
 	}
-
-	logger.Info(ctx, "otw insert comment")
 	insertedComment, err := a.accService.CreateComment(ctx, commentIn)
 	if err != nil {
-		// bad code, should be wrapped in other package
-		if err.Error() == "error duplication email" {
-			ctx.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{
-				Message:  "failed to insert comment",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to insert comment",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
 		Message: "success create comment",
-		Data:    insertedComment,
+		Data:    accountmodel.ToCommentResponse(insertedComment),
 	})
 }
 func (a *AccountHandlerImpl) UpdateComment(ctx *gin.Context) {
 	idUint, err := a.getIdFromParam(ctx)
 	if err != nil {
+		ctx.Error(err)
 		return
 	}
 	// binding payload
-	var commentIn accountmodel.Comment
-	if err := ctx.Bind(&commentIn); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to update user",
-			Error: response.InvalidBody,
-		})
+	var req accountmodel.CommentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
+	commentIn := req.ToComment()
 	commentIn.ID = idUint
 
-	// validate name
-	// if commentIn.Name == "" {
-	// 	ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-	// 		Message:  "failed to update user",
-	// 		Error: response.InvalidParam,
-	// 	})
-	// 	return
-	// }
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	toBeUpdatedComment, err := a.accService.GetCommentById(ctx, commentIn.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "error before update, while getting comment",
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
+	// a comment stays on its photo and in its thread
+	commentIn.PhotoID = toBeUpdatedComment.PhotoID
+	commentIn.ParentID = nil
 
-	con, message := UpdateEntityAuth(user.ID, commentIn.UserID, toBeUpdatedComment.UserID)
-	if con != true {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
-		return
+	con, message := true, ""
+	if user.Role.Can(accountmodel.PERMISSION_MANAGE_ANY_CONTENT) {
+		// admin keeps the original owner
+		commentIn.UserID = toBeUpdatedComment.UserID
+	} else {
+		con, message = UpdateEntityAuth(user.ID, commentIn.UserID, toBeUpdatedComment.UserID)
 	}
-	con, message = CommentPayloadValidation(commentIn)
 	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InvalidParam,
-		})
+		ctx.Error(notOwner(message))
 		return
 	}
-
-	updatedComment, err := a.accService.UpdateComment(ctx, commentIn);
+	updatedComment, err := a.accService.UpdateComment(ctx, commentIn)
 	if err != nil {
-		if err.Error() == "user is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to update user",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to update user",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
-		Message: "success update user",
-		Data: updatedComment,
+		Message: "success update comment",
+		Data:    accountmodel.ToCommentResponse(updatedComment),
 	})
 }
 func (a *AccountHandlerImpl) DeleteComment(ctx *gin.Context) {
 	idUint, err := a.getIdFromParam(ctx)
 	if err != nil {
+		ctx.Error(err)
 		return
 	}
-	
+
 	comment, err := a.accService.GetCommentById(ctx, idUint)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "error get comment",
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
-	
-	con, message := DeleteEntityAuth(comment.UserID, user.ID)
+
+	con, message := true, ""
+	if !user.Role.Can(accountmodel.PERMISSION_MANAGE_ANY_CONTENT) {
+		con, message = DeleteEntityAuth(comment.UserID, user.ID)
+	}
 	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
+		ctx.Error(notOwner(message))
 		return
 	}
 
 	deletedComment, err := a.accService.DeleteComment(ctx, idUint)
 	if err != nil {
-		if err.Error() == "comment is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to delete comment",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to delete comment",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
 		Message: "success delete comment",
-		Data:    deletedComment,
+		Data:    accountmodel.ToCommentResponse(deletedComment),
 	})
 }
 
+// SOCIAL MEDIA SECTION
 func (a *AccountHandlerImpl) GetAllSocialMedias(ctx *gin.Context) {
-	socialMedias, err := a.accService.GetAllSocialMedias(ctx)
+	var filter accountmodel.SocialMediaFilter
+	params, err := bindList(ctx, &filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	socialMedias, page, err := a.accService.GetAllSocialMedias(ctx, filter, params)
 	if err != nil {
-		// bad code, should be wrapped in other package
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message: response.SomethingWentWrong,
-			Error: "failed to get socialMedias",
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
-		Message: "success get socialMedias",
-		Data:    socialMedias,
+		Message:    "success get socialMedias",
+		Data:       accountmodel.ToSocialMediaResponses(socialMedias),
+		Pagination: &page,
 	})
 }
 func (a *AccountHandlerImpl) GetSocialMediaById(ctx *gin.Context) {
-	id := ctx.Query("id")
-	if id == "" {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to find socialMedia",
-			Error: response.InvalidQuery,
-		})
-		return
-	}
-	// transform id string to uint64
-	idUint, err := strconv.ParseUint(id, 10, 64)
+	idUint, err := a.getIdFromQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to find socialMedia",
-			Error: response.InvalidParam,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	// call service
 	socialMedia, err := a.accService.GetSocialMediaById(ctx, idUint)
 	if err != nil {
-		if err.Error() == "socialMedia is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to find socialMedia",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to find socialMedia",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
 		Message: "success find socialMedia",
-		Data:    socialMedia,
+		Data:    accountmodel.ToSocialMediaResponse(socialMedia),
 	})
 }
 func (a *AccountHandlerImpl) CreateSocialMedia(ctx *gin.Context) {
 	// mendapatkan body
-	var socialMediaIn accountmodel.SocialMedia
-
-	logger.Info(ctx, "otw bind socialmedia")
-	if err := ctx.Bind(&socialMediaIn); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to insert socialmedia",
-			Error: response.InvalidBody,
-		})
+	var req accountmodel.SocialMediaRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
+	socialMediaIn := req.ToSocialMedia()
 
-	logger.Info(ctx, "otw validate socialmedia")
-	// validate name and email
-	// if socialMediaIn.Title == "" || socialMediaIn.PhotoUrl == "" {
-	// 	ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-	// 		Message:  "failed to insert socialmedia",
-	// 		Error: response.InvalidParam,
-	// 	})
-	// 	return
-	// }
-
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 	con, message := CreateEntityAuth(user.ID, socialMediaIn.UserID)
-	if con != true {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
-		return
-	}
-
-	con, message = SocialMediaPayloadValidation(socialMediaIn)
 	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InvalidParam,
-		})
+		ctx.Error(notOwner(message))
 		return
 	}
 
-	logger.Info(ctx, "otw insert socialmedia")
 	insertedSocialMedia, err := a.accService.CreateSocialMedia(ctx, socialMediaIn)
 	if err != nil {
-		// bad code, should be wrapped in other package
-		if err.Error() == "error duplication email" {
-			ctx.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{
-				Message:  "failed to insert socialmedia",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to insert socialmedia",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
 		Message: "success create socialmedia",
-		Data:    insertedSocialMedia,
+		Data:    accountmodel.ToSocialMediaResponse(insertedSocialMedia),
 	})
 }
 func (a *AccountHandlerImpl) UpdateSocialMedia(ctx *gin.Context) {
 	idUint, err := a.getIdFromParam(ctx)
 	if err != nil {
+		ctx.Error(err)
 		return
 	}
 	// binding payload
-	var socialMediaIn accountmodel.SocialMedia
-	if err := ctx.Bind(&socialMediaIn); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "failed to update user",
-			Error: response.InvalidBody,
-		})
+	var req accountmodel.SocialMediaRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.Error(bindError(err))
 		return
 	}
+	socialMediaIn := req.ToSocialMedia()
 	socialMediaIn.ID = idUint
 
-	// validate name
-	// if socialMediaIn.Name == "" {
-	// 	ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-	// 		Message:  "failed to update user",
-	// 		Error: response.InvalidParam,
-	// 	})
-	// 	return
-	// }
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
-	
+
 	toBeUpdatedSocialMedia, err := a.accService.GetSocialMediaById(ctx, socialMediaIn.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "error before update, while getting socialMedia",
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 
-	con, message := UpdateEntityAuth(user.ID, socialMediaIn.UserID, toBeUpdatedSocialMedia.UserID)
-	if con != true {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
-		return
+	con, message := true, ""
+	if user.Role.Can(accountmodel.PERMISSION_MANAGE_ANY_CONTENT) {
+		// admin keeps the original owner
+		socialMediaIn.UserID = toBeUpdatedSocialMedia.UserID
+	} else {
+		con, message = UpdateEntityAuth(user.ID, socialMediaIn.UserID, toBeUpdatedSocialMedia.UserID)
 	}
-	con, message = SocialMediaPayloadValidation(socialMediaIn)
 	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InvalidParam,
-		})
+		ctx.Error(notOwner(message))
 		return
 	}
 
-	updatedSocialMedia, err := a.accService.UpdateSocialMedia(ctx, socialMediaIn);
+	updatedSocialMedia, err := a.accService.UpdateSocialMedia(ctx, socialMediaIn)
 	if err != nil {
-		if err.Error() == "user is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to update user",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to update user",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
-		Message: "success update user",
-		Data: updatedSocialMedia,
+		Message: "success update social media",
+		Data:    accountmodel.ToSocialMediaResponse(updatedSocialMedia),
 	})
 }
 func (a *AccountHandlerImpl) DeleteSocialMedia(ctx *gin.Context) {
 	idUint, err := a.getIdFromParam(ctx)
 	if err != nil {
+		ctx.Error(err)
 		return
 	}
+
 	socialMedia, err := a.accService.GetSocialMediaById(ctx, idUint)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  "error get socialMedia",
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
 
-	user, err, message := a.AuthIncomingRequest(ctx)
+	user, err := a.AuthIncomingRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.InternalServer,
-		})
+		ctx.Error(err)
 		return
 	}
-	
-	con, message := DeleteEntityAuth(socialMedia.UserID, user.ID)
+
+	con, message := true, ""
+	if !user.Role.Can(accountmodel.PERMISSION_MANAGE_ANY_CONTENT) {
+		con, message = DeleteEntityAuth(socialMedia.UserID, user.ID)
+	}
 	if !con {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Message:  message,
-			Error: response.Unauthorized,
-		})
+		ctx.Error(notOwner(message))
 		return
 	}
 
-
 	deletedSocialMedia, err := a.accService.DeleteSocialMedia(ctx, idUint)
 	if err != nil {
-		if err.Error() == "social media is not found" {
-			ctx.JSON(http.StatusNotFound, response.ErrorResponse{
-				Message:  "failed to delete social media",
-				Error: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Message:  "failed to delete social media",
-			Error: response.SomethingWentWrong,
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
 		Message: "success delete social media",
-		Data:    deletedSocialMedia,
+		Data:    accountmodel.ToSocialMediaResponse(deletedSocialMedia),
 	})
 }
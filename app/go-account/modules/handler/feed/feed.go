@@ -0,0 +1,7 @@
+package feed
+
+import "github.com/gin-gonic/gin"
+
+type IFeedHandler interface {
+	GetFeed(ctx *gin.Context)
+}
@@ -0,0 +1,73 @@
+package feed
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	likemodel "github.com/mygram/go-account/modules/models/like"
+	feedservice "github.com/mygram/go-account/modules/service/feed"
+	likeservice "github.com/mygram/go-account/modules/service/like"
+	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type FeedHandlerImpl struct {
+	feedSvc feedservice.IFeedService
+	likeSvc likeservice.ILikeService
+}
+
+func NewFeedHandlerImpl(feedSvc feedservice.IFeedService, likeSvc likeservice.ILikeService) IFeedHandler {
+	return &FeedHandlerImpl{
+		feedSvc: feedSvc,
+		likeSvc: likeSvc,
+	}
+}
+
+func (f *FeedHandlerImpl) GetFeed(ctx *gin.Context) {
+	var query pagination.Query
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	// the feed is always newest first
+	query.Sort = ""
+	params, err := query.Params()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	userId, ok := middleware.UserIDFromClaim(ctx)
+	if !ok {
+		ctx.Error(domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "error get claim from context"))
+		return
+	}
+
+	photos, page, err := f.feedSvc.GetFeed(ctx, userId, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res := accountmodel.ToPhotoResponses(photos)
+	ids := make([]uint64, 0, len(res))
+	for _, photo := range res {
+		ids = append(ids, photo.ID)
+	}
+	liked, err := f.likeSvc.LikedByMe(ctx, userId, likemodel.TARGET_PHOTO, ids)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	for i := range res {
+		res[i].SetLikedByMe(liked)
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message:    "success get feed",
+		Data:       res,
+		Pagination: &page,
+	})
+}
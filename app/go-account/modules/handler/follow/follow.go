@@ -0,0 +1,10 @@
+package follow
+
+import "github.com/gin-gonic/gin"
+
+type IFollowHandler interface {
+	Follow(ctx *gin.Context)
+	Unfollow(ctx *gin.Context)
+	GetFollowers(ctx *gin.Context)
+	GetFollowing(ctx *gin.Context)
+}
@@ -0,0 +1,117 @@
+package follow
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	followmodel "github.com/mygram/go-account/modules/models/follow"
+	followservice "github.com/mygram/go-account/modules/service/follow"
+	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// Follows are idempotent like likes, following twice or unfollowing
+// someone never followed answers with the current state.
+type FollowHandlerImpl struct {
+	followSvc followservice.IFollowService
+}
+
+func NewFollowHandlerImpl(followSvc followservice.IFollowService) IFollowHandler {
+	return &FollowHandlerImpl{
+		followSvc: followSvc,
+	}
+}
+
+func (f *FollowHandlerImpl) Follow(ctx *gin.Context) {
+	f.setFollow(ctx, true)
+}
+
+func (f *FollowHandlerImpl) Unfollow(ctx *gin.Context) {
+	f.setFollow(ctx, false)
+}
+
+func (f *FollowHandlerImpl) setFollow(ctx *gin.Context, following bool) {
+	var uri followmodel.FollowUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	userId, ok := middleware.UserIDFromClaim(ctx)
+	if !ok {
+		ctx.Error(domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "error get claim from context"))
+		return
+	}
+
+	var (
+		state followmodel.FollowState
+		err   error
+	)
+	if following {
+		state, err = f.followSvc.Follow(ctx, userId, uri.ID)
+	} else {
+		state, err = f.followSvc.Unfollow(ctx, userId, uri.ID)
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success",
+		Data:    state,
+	})
+}
+
+func (f *FollowHandlerImpl) GetFollowers(ctx *gin.Context) {
+	uri, params, err := bindFollowList(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	follows, page, err := f.followSvc.GetFollowers(ctx, uri.ID, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message:    "success get followers",
+		Data:       followmodel.ToFollowerResponses(follows),
+		Pagination: &page,
+	})
+}
+
+func (f *FollowHandlerImpl) GetFollowing(ctx *gin.Context) {
+	uri, params, err := bindFollowList(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	follows, page, err := f.followSvc.GetFollowing(ctx, uri.ID, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message:    "success get following",
+		Data:       followmodel.ToFollowingResponses(follows),
+		Pagination: &page,
+	})
+}
+
+func bindFollowList(ctx *gin.Context) (uri followmodel.FollowUri, params pagination.Params, err error) {
+	if err = ctx.ShouldBindUri(&uri); err != nil {
+		err = validation.BindQueryError(err)
+		return
+	}
+	var query pagination.Query
+	if err = ctx.ShouldBindQuery(&query); err != nil {
+		err = validation.BindQueryError(err)
+		return
+	}
+	params, err = query.Params()
+	return
+}
@@ -0,0 +1,10 @@
+package like
+
+import "github.com/gin-gonic/gin"
+
+type ILikeHandler interface {
+	LikePhoto(ctx *gin.Context)
+	UnlikePhoto(ctx *gin.Context)
+	LikeComment(ctx *gin.Context)
+	UnlikeComment(ctx *gin.Context)
+}
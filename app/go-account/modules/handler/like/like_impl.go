@@ -0,0 +1,72 @@
+package like
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	likemodel "github.com/mygram/go-account/modules/models/like"
+	likeservice "github.com/mygram/go-account/modules/service/like"
+	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// Likes are idempotent, liking twice or unliking something never liked
+// answers with the current state.
+type LikeHandlerImpl struct {
+	likeSvc likeservice.ILikeService
+}
+
+func NewLikeHandlerImpl(likeSvc likeservice.ILikeService) ILikeHandler {
+	return &LikeHandlerImpl{
+		likeSvc: likeSvc,
+	}
+}
+
+func (l *LikeHandlerImpl) LikePhoto(ctx *gin.Context) {
+	l.setLike(ctx, likemodel.TARGET_PHOTO, true)
+}
+
+func (l *LikeHandlerImpl) UnlikePhoto(ctx *gin.Context) {
+	l.setLike(ctx, likemodel.TARGET_PHOTO, false)
+}
+
+func (l *LikeHandlerImpl) LikeComment(ctx *gin.Context) {
+	l.setLike(ctx, likemodel.TARGET_COMMENT, true)
+}
+
+func (l *LikeHandlerImpl) UnlikeComment(ctx *gin.Context) {
+	l.setLike(ctx, likemodel.TARGET_COMMENT, false)
+}
+
+func (l *LikeHandlerImpl) setLike(ctx *gin.Context, target likemodel.LikeTarget, liked bool) {
+	var uri likemodel.LikeUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	userId, ok := middleware.UserIDFromClaim(ctx)
+	if !ok {
+		ctx.Error(domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "error get claim from context"))
+		return
+	}
+
+	var (
+		state likemodel.LikeState
+		err   error
+	)
+	if liked {
+		state, err = l.likeSvc.Like(ctx, userId, target, uri.ID)
+	} else {
+		state, err = l.likeSvc.Unlike(ctx, userId, target, uri.ID)
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success",
+		Data:    state,
+	})
+}
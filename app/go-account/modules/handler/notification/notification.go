@@ -0,0 +1,11 @@
+package notification
+
+import "github.com/gin-gonic/gin"
+
+type INotificationHandler interface {
+	GetNotifications(ctx *gin.Context)
+	MarkRead(ctx *gin.Context)
+	MarkAllRead(ctx *gin.Context)
+	GetPreferences(ctx *gin.Context)
+	UpdatePreferences(ctx *gin.Context)
+}
@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	notificationservice "github.com/mygram/go-account/modules/service/notification"
+	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// every route only reads or changes the notifications of the caller
+type NotificationHandlerImpl struct {
+	notificationSvc notificationservice.INotificationService
+}
+
+func NewNotificationHandlerImpl(notificationSvc notificationservice.INotificationService) INotificationHandler {
+	return &NotificationHandlerImpl{
+		notificationSvc: notificationSvc,
+	}
+}
+
+func (n *NotificationHandlerImpl) GetNotifications(ctx *gin.Context) {
+	var query notificationmodel.NotificationQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	params, err := query.Params()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	notifications, page, unread, err := n.notificationSvc.GetNotifications(ctx, userId, query.Unread, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success get notifications",
+		Data: notificationmodel.NotificationListResponse{
+			UnreadCount:   unread,
+			Notifications: notificationmodel.ToNotificationResponses(notifications),
+		},
+		Pagination: &page,
+	})
+}
+
+func (n *NotificationHandlerImpl) MarkRead(ctx *gin.Context) {
+	var uri notificationmodel.NotificationUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	unread, err := n.notificationSvc.MarkRead(ctx, userId, uri.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success mark notification read",
+		Data:    notificationmodel.MarkReadResponse{Marked: 1, UnreadCount: unread},
+	})
+}
+
+func (n *NotificationHandlerImpl) MarkAllRead(ctx *gin.Context) {
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	marked, err := n.notificationSvc.MarkAllRead(ctx, userId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success mark all notifications read",
+		Data:    notificationmodel.MarkReadResponse{Marked: marked},
+	})
+}
+
+func (n *NotificationHandlerImpl) GetPreferences(ctx *gin.Context) {
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	prefs, err := n.notificationSvc.GetPreferences(ctx, userId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success get notification preferences",
+		Data:    prefs,
+	})
+}
+
+// UpdatePreferences takes {"like": false, ...}, types left out keep their value.
+func (n *NotificationHandlerImpl) UpdatePreferences(ctx *gin.Context) {
+	var changes notificationmodel.Preferences
+	if err := ctx.ShouldBindJSON(&changes); err != nil {
+		ctx.Error(validation.BindError(err))
+		return
+	}
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	prefs, err := n.notificationSvc.UpdatePreferences(ctx, userId, changes)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success update notification preferences",
+		Data:    prefs,
+	})
+}
+
+func userIdFromClaim(ctx *gin.Context) (userId uint64, ok bool) {
+	if userId, ok = middleware.UserIDFromClaim(ctx); !ok {
+		ctx.Error(domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "error get claim from context"))
+	}
+	return
+}
@@ -0,0 +1,8 @@
+package realtime
+
+import "github.com/gin-gonic/gin"
+
+type IRealtimeHandler interface {
+	WebSocket(ctx *gin.Context)
+	EventStream(ctx *gin.Context)
+}
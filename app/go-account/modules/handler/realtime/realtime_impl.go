@@ -0,0 +1,298 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	tokenmodel "github.com/mygram/go-account/modules/models/token"
+	realtimerepo "github.com/mygram/go-account/modules/repository/realtime"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	realtimeservice "github.com/mygram/go-account/modules/service/realtime"
+	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	commonmidware "github.com/mygram/go-common/pkg/middleware"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+const (
+	DEFAULT_HEARTBEAT     = 30 * time.Second
+	DEFAULT_WRITE_TIMEOUT = 10 * time.Second
+
+	// websocket clients only send small subscribe messages
+	maxClientMessageSize = 512
+)
+
+// Config tunes the streams, defaults are used for the empty values.
+type Config struct {
+	Heartbeat    time.Duration
+	WriteTimeout time.Duration
+}
+
+// streams are authenticated with a bearer token, not a cookie,
+// so any origin may open one
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type RealtimeHandlerImpl struct {
+	realtimeSvc     realtimeservice.IRealtimeService
+	revocationStore revocationrepo.IRevocationStore
+	conf            Config
+}
+
+// NewRealtimeHandlerImpl checks the token of every stream again on each
+// heartbeat against revocationStore, a logout closes the streams too.
+func NewRealtimeHandlerImpl(realtimeSvc realtimeservice.IRealtimeService, revocationStore revocationrepo.IRevocationStore, conf Config) IRealtimeHandler {
+	if conf.Heartbeat <= 0 {
+		conf.Heartbeat = DEFAULT_HEARTBEAT
+	}
+	if conf.WriteTimeout <= 0 {
+		conf.WriteTimeout = DEFAULT_WRITE_TIMEOUT
+	}
+	return &RealtimeHandlerImpl{
+		realtimeSvc:     realtimeSvc,
+		revocationStore: revocationStore,
+		conf:            conf,
+	}
+}
+
+// WebSocket pushes events as json text messages and pings every heartbeat,
+// a client that stops answering or falls behind is disconnected.
+func (r *RealtimeHandlerImpl) WebSocket(ctx *gin.Context) {
+	sub, claim, ok := r.subscribe(ctx)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// the upgrader already answered with the error
+		return
+	}
+
+	// the gin context is recycled once the handler returns,
+	// the reader must not outlive it
+	reqCtx := ctx.Request.Context()
+	rejected := make(chan error, 1)
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		r.readClient(reqCtx, conn, sub, rejected)
+	}()
+	r.writeEvents(reqCtx, conn, sub, claim, rejected)
+
+	// closing the connection stops the reader
+	conn.Close()
+	<-readerDone
+}
+
+// readClient handles subscribe messages and pongs, it closes the
+// subscription when the client goes away.
+func (r *RealtimeHandlerImpl) readClient(ctx context.Context, conn *websocket.Conn, sub *realtimerepo.Subscription, rejected chan<- error) {
+	defer sub.Close()
+
+	// two missed heartbeats and the connection is dead
+	conn.SetReadLimit(maxClientMessageSize)
+	conn.SetReadDeadline(time.Now().Add(2 * r.conf.Heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * r.conf.Heartbeat))
+	})
+
+	for {
+		var msg realtimemodel.ClientMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				reject(rejected, domainerr.Validation("message is not json"))
+				continue
+			}
+			return
+		}
+
+		var err error
+		switch msg.Action {
+		case realtimemodel.ACTION_SUBSCRIBE:
+			err = r.realtimeSvc.WatchPhoto(ctx, sub, msg.PhotoID)
+		case realtimemodel.ACTION_UNSUBSCRIBE:
+			err = r.realtimeSvc.UnwatchPhoto(ctx, sub, msg.PhotoID)
+		default:
+			err = domainerr.Validation(fmt.Sprintf("unknown action %v", msg.Action))
+		}
+		if err != nil {
+			reject(rejected, err)
+		}
+	}
+}
+
+// reject hands the error to the writer, only one goroutine may write,
+// an error is dropped while the previous one is still waiting.
+func reject(rejected chan<- error, err error) {
+	select {
+	case rejected <- err:
+	default:
+	}
+}
+
+func (r *RealtimeHandlerImpl) writeEvents(ctx context.Context, conn *websocket.Conn, sub *realtimerepo.Subscription, claim tokenmodel.DefaultClaim, rejected <-chan error) {
+	logCtx := fmt.Sprintf("%T - writeEvents", r)
+
+	heartbeat := time.NewTicker(r.conf.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-sub.Events():
+			conn.SetWriteDeadline(time.Now().Add(r.conf.WriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				logger.Info(ctx, "dropping websocket client",
+					"logCtx", logCtx,
+					"error", err)
+				return
+			}
+		case err := <-rejected:
+			conn.SetWriteDeadline(time.Now().Add(r.conf.WriteTimeout))
+			if err := conn.WriteJSON(errorEvent(err)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if code, reason := r.checkToken(ctx, claim); code != 0 {
+				logger.Info(ctx, "closing websocket client",
+					"logCtx", logCtx,
+					"reason", reason)
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(r.conf.WriteTimeout))
+				return
+			}
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(r.conf.WriteTimeout)); err != nil {
+				return
+			}
+		case <-sub.Done():
+			code, reason := closeReason(sub.Err())
+			if code != 0 {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(r.conf.WriteTimeout))
+			}
+			return
+		}
+	}
+}
+
+// EventStream is the fallback for clients without websocket, photos are
+// chosen with ?photo_id= and changed by reconnecting.
+func (r *RealtimeHandlerImpl) EventStream(ctx *gin.Context) {
+	sub, claim, ok := r.subscribe(ctx)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	// a proxy must not buffer the stream
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Writer.WriteHeaderNow()
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(r.conf.Heartbeat)
+	defer heartbeat.Stop()
+	rc := http.NewResponseController(ctx.Writer)
+	// a client that does not read is dropped like a slow websocket client
+	writeDeadline := func() {
+		rc.SetWriteDeadline(time.Now().Add(r.conf.WriteTimeout))
+	}
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-sub.Events():
+			writeDeadline()
+			return sse.Encode(w, sse.Event{Event: string(event.Type), Data: event}) == nil
+		case <-heartbeat.C:
+			if code, reason := r.checkToken(ctx.Request.Context(), claim); code != 0 {
+				writeDeadline()
+				sse.Encode(w, sse.Event{Event: string(realtimemodel.EVENT_CLOSE), Data: realtimemodel.CloseResponse{Reason: reason}})
+				return false
+			}
+			// comments are ignored by EventSource, they only keep proxies from timing out
+			writeDeadline()
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		case <-sub.Done():
+			if _, reason := closeReason(sub.Err()); reason != "" {
+				writeDeadline()
+				sse.Encode(w, sse.Event{Event: string(realtimemodel.EVENT_CLOSE), Data: realtimemodel.CloseResponse{Reason: reason}})
+			}
+			return false
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func (r *RealtimeHandlerImpl) subscribe(ctx *gin.Context) (sub *realtimerepo.Subscription, claim tokenmodel.DefaultClaim, ok bool) {
+	var query realtimemodel.StreamQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	userId, ok := middleware.UserIDFromClaim(ctx)
+	if !ok {
+		ctx.Error(domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "error get claim from context"))
+		return
+	}
+	claim, ok = middleware.TokenClaimFromContext(ctx)
+	if !ok {
+		ctx.Error(domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "error get claim from context"))
+		return
+	}
+
+	sub, err := r.realtimeSvc.Subscribe(ctx, userId, query.PhotoIDs)
+	if err != nil {
+		ctx.Error(err)
+		return nil, claim, false
+	}
+	return sub, claim, true
+}
+
+// checkToken is zero while the token of the stream may still be used,
+// a stream outliving the expiry or a logout of its token is closed.
+func (r *RealtimeHandlerImpl) checkToken(ctx context.Context, claim tokenmodel.DefaultClaim) (code int, reason string) {
+	logCtx := fmt.Sprintf("%T - checkToken", r)
+
+	err := middleware.CheckTokenClaim(ctx, r.revocationStore, claim)
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, domainerr.ErrUnauthenticated):
+		return websocket.ClosePolicyViolation, err.Error()
+	}
+	// a token that can not be checked is not trusted either
+	logger.Error(ctx, "error check token of stream",
+		"logCtx", logCtx,
+		"error", err)
+	return websocket.CloseInternalServerErr, "token could not be checked"
+}
+
+func errorEvent(err error) realtimemodel.Event {
+	event, _ := realtimemodel.NewEvent("", realtimemodel.EVENT_ERROR, commonmidware.ProblemOf(err, ""))
+	return event
+}
+
+// closeReason is empty when the client closed the stream itself.
+func closeReason(err error) (code int, reason string) {
+	switch {
+	case errors.Is(err, realtimerepo.ErrSlowConsumer):
+		return websocket.CloseTryAgainLater, "stream fell behind"
+	case errors.Is(err, realtimerepo.ErrHubClosed):
+		return websocket.CloseGoingAway, "server is shutting down"
+	}
+	return
+}
@@ -0,0 +1,7 @@
+package search
+
+import "github.com/gin-gonic/gin"
+
+type ISearchHandler interface {
+	Search(ctx *gin.Context)
+}
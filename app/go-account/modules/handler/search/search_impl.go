@@ -0,0 +1,63 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	likemodel "github.com/mygram/go-account/modules/models/like"
+	searchmodel "github.com/mygram/go-account/modules/models/search"
+	likeservice "github.com/mygram/go-account/modules/service/like"
+	searchservice "github.com/mygram/go-account/modules/service/search"
+	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type SearchHandlerImpl struct {
+	searchSvc searchservice.ISearchService
+	likeSvc   likeservice.ILikeService
+}
+
+func NewSearchHandlerImpl(searchSvc searchservice.ISearchService, likeSvc likeservice.ILikeService) ISearchHandler {
+	return &SearchHandlerImpl{
+		searchSvc: searchSvc,
+		likeSvc:   likeSvc,
+	}
+}
+
+func (s *SearchHandlerImpl) Search(ctx *gin.Context) {
+	var query searchmodel.SearchQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	params, err := query.Params()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	results, page, err := s.searchSvc.Search(ctx, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res := searchmodel.ToSearchResponses(results)
+	// liked_by_me is only known for signed in callers
+	if userId, ok := middleware.UserIDFromClaim(ctx); ok && params.Type == searchmodel.TYPE_PHOTO {
+		liked, err := s.likeSvc.LikedByMe(ctx, userId, likemodel.TARGET_PHOTO, searchmodel.PhotoIds(res))
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		for i := range res {
+			res[i].SetLikedByMe(liked)
+		}
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message:    "success search",
+		Data:       res,
+		Pagination: &page,
+	})
+}
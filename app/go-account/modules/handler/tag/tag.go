@@ -0,0 +1,8 @@
+package tag
+
+import "github.com/gin-gonic/gin"
+
+type ITagHandler interface {
+	GetTagPhotos(ctx *gin.Context)
+	GetUserMentions(ctx *gin.Context)
+}
@@ -0,0 +1,103 @@
+package tag
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	likemodel "github.com/mygram/go-account/modules/models/like"
+	tagmodel "github.com/mygram/go-account/modules/models/tag"
+	likeservice "github.com/mygram/go-account/modules/service/like"
+	tagservice "github.com/mygram/go-account/modules/service/tag"
+	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type TagHandlerImpl struct {
+	tagSvc  tagservice.ITagService
+	likeSvc likeservice.ILikeService
+}
+
+func NewTagHandlerImpl(tagSvc tagservice.ITagService, likeSvc likeservice.ILikeService) ITagHandler {
+	return &TagHandlerImpl{
+		tagSvc:  tagSvc,
+		likeSvc: likeSvc,
+	}
+}
+
+func (t *TagHandlerImpl) GetTagPhotos(ctx *gin.Context) {
+	var uri tagmodel.TagUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	params, err := bindPagination(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	photos, page, err := t.tagSvc.GetPhotosByTag(ctx, uri.Name, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res := accountmodel.ToPhotoResponses(photos)
+	// liked_by_me is only known for signed in callers
+	if userId, ok := middleware.UserIDFromClaim(ctx); ok {
+		ids := make([]uint64, 0, len(res))
+		for _, photo := range res {
+			ids = append(ids, photo.ID)
+		}
+		liked, err := t.likeSvc.LikedByMe(ctx, userId, likemodel.TARGET_PHOTO, ids)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		for i := range res {
+			res[i].SetLikedByMe(liked)
+		}
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message:    "success get tag photos",
+		Data:       res,
+		Pagination: &page,
+	})
+}
+
+func (t *TagHandlerImpl) GetUserMentions(ctx *gin.Context) {
+	var uri tagmodel.MentionUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	params, err := bindPagination(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	mentions, page, err := t.tagSvc.GetMentions(ctx, uri.ID, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message:    "success get mentions",
+		Data:       tagmodel.ToMentionResponses(mentions),
+		Pagination: &page,
+	})
+}
+
+func bindPagination(ctx *gin.Context) (params pagination.Params, err error) {
+	var query pagination.Query
+	if err = ctx.ShouldBindQuery(&query); err != nil {
+		err = validation.BindQueryError(err)
+		return
+	}
+	params, err = query.Params()
+	return
+}
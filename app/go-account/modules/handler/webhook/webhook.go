@@ -0,0 +1,12 @@
+package webhook
+
+import "github.com/gin-gonic/gin"
+
+type IWebhookHandler interface {
+	CreateWebhook(ctx *gin.Context)
+	CreateAppWebhook(ctx *gin.Context)
+	GetWebhooks(ctx *gin.Context)
+	DeleteWebhook(ctx *gin.Context)
+	GetDeliveries(ctx *gin.Context)
+	ReplayDelivery(ctx *gin.Context)
+}
@@ -0,0 +1,156 @@
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
+	webhookservice "github.com/mygram/go-account/modules/service/webhook"
+	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// every route only reads or changes the webhooks of the caller,
+// app webhooks belong to the admin who registered them
+type WebhookHandlerImpl struct {
+	webhookSvc webhookservice.IWebhookService
+}
+
+func NewWebhookHandlerImpl(webhookSvc webhookservice.IWebhookService) IWebhookHandler {
+	return &WebhookHandlerImpl{
+		webhookSvc: webhookSvc,
+	}
+}
+
+func (w *WebhookHandlerImpl) CreateWebhook(ctx *gin.Context) {
+	w.createWebhook(ctx, webhookmodel.SCOPE_USER)
+}
+
+// CreateAppWebhook is behind the app webhook permission, see the router.
+func (w *WebhookHandlerImpl) CreateAppWebhook(ctx *gin.Context) {
+	w.createWebhook(ctx, webhookmodel.SCOPE_APP)
+}
+
+func (w *WebhookHandlerImpl) createWebhook(ctx *gin.Context, scope webhookmodel.Scope) {
+	var req webhookmodel.WebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(validation.BindError(err))
+		return
+	}
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	webhook, err := w.webhookSvc.CreateWebhook(ctx, userId, scope, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		Message: "success create webhook",
+		Data:    webhookmodel.ToWebhookCreatedResponse(webhook),
+	})
+}
+
+func (w *WebhookHandlerImpl) GetWebhooks(ctx *gin.Context) {
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	webhooks, err := w.webhookSvc.GetWebhooks(ctx, userId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success get webhooks",
+		Data:    webhookmodel.ToWebhookResponses(webhooks),
+	})
+}
+
+func (w *WebhookHandlerImpl) DeleteWebhook(ctx *gin.Context) {
+	var uri webhookmodel.WebhookUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	if err := w.webhookSvc.DeleteWebhook(ctx, userId, uri.ID); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message: "success delete webhook",
+	})
+}
+
+// GetDeliveries is the delivery log of the webhook, newest first by default.
+func (w *WebhookHandlerImpl) GetDeliveries(ctx *gin.Context) {
+	var uri webhookmodel.WebhookUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	var query webhookmodel.DeliveryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	params, err := query.Params()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	deliveries, page, err := w.webhookSvc.GetDeliveries(ctx, userId, uri.ID, params)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		Message:    "success get webhook deliveries",
+		Data:       webhookmodel.ToDeliveryResponses(deliveries),
+		Pagination: &page,
+	})
+}
+
+func (w *WebhookHandlerImpl) ReplayDelivery(ctx *gin.Context) {
+	var uri webhookmodel.DeliveryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(validation.BindQueryError(err))
+		return
+	}
+	userId, ok := userIdFromClaim(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := w.webhookSvc.ReplayDelivery(ctx, userId, uri.ID, uri.DeliveryID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
+		Message: "success replay webhook delivery",
+		Data:    webhookmodel.ToDeliveryResponse(delivery),
+	})
+}
+
+func userIdFromClaim(ctx *gin.Context) (userId uint64, ok bool) {
+	if userId, ok = middleware.UserIDFromClaim(ctx); !ok {
+		ctx.Error(domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "error get claim from context"))
+	}
+	return
+}
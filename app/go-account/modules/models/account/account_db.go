@@ -14,26 +14,54 @@ const (
 	ROLE_NORMAL AccountRole = "normal"
 )
 
-type Account struct {
-	ID        uuid.UUID      `json:"id" gorm:"column:id"`
-	Username  string         `json:"username" gorm:"column:username"`
-	Password  string         `json:"password" gorm:"password"`
-	Role      AccountRole    `json:"role" gorm:"column:role"`
+type Permission string
 
-	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+const (
+	// manage (update/delete) photo, comment and social media of any user
+	PERMISSION_MANAGE_ANY_CONTENT Permission = "content:manage_any"
+	// register webhooks that get the events of every user
+	PERMISSION_MANAGE_APP_WEBHOOKS Permission = "webhook:manage_app"
+)
+
+var rolePermissions = map[AccountRole][]Permission{
+	ROLE_ADMIN: {
+		PERMISSION_MANAGE_ANY_CONTENT,
+		PERMISSION_MANAGE_APP_WEBHOOKS,
+	},
+	ROLE_NORMAL: {},
+}
+
+// EffectiveRole treats users created before roles existed as normal.
+func (u User) EffectiveRole() AccountRole {
+	if u.Role == "" {
+		return ROLE_NORMAL
+	}
+	return u.Role
+}
+
+func (r AccountRole) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
 
 // USER section
+// User is the only identity, rows of the old uuid keyed accounts table
+// were merged into it and keep their old id in LegacyAccountID.
+// Email and age are null for users created through the deprecated /account routes.
 type User struct {
 	// Id        		uint64         `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
 	// ID        uuid.UUID      `json:"id" gorm:"column:id"`
 	ID        uint64         `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
 	Username  string         `json:"username" gorm:"column:username"`
-	Email  string         	 `json:"email" gorm:"column:email"`
-	Password  string         `json:"password" gorm:"password"`
-	Age  			uint64         `json:"age" gorm:"column:age"`
+	Email  string         	 `json:"email" gorm:"column:email;default:null"`
+	Password  string         `json:"-" gorm:"password"`
+	Age  			uint64         `json:"age" gorm:"column:age;default:null"`
+	Role      AccountRole    `json:"role" gorm:"column:role;default:normal"`
+	LegacyAccountID *uuid.UUID `json:"-" gorm:"column:legacy_account_id"`
 
 	
 	CreatedAt 		time.Time      `json:"created_at"`
@@ -45,6 +73,17 @@ type User struct {
 }
 
 // PHOTO section
+type ProcessingStatus string
+
+// uploaded photos go pending -> processing -> done or failed,
+// photos created from an url are never processed and have no status
+const (
+	PROCESSING_PENDING ProcessingStatus = "pending"
+	PROCESSING_RUNNING ProcessingStatus = "processing"
+	PROCESSING_DONE    ProcessingStatus = "done"
+	PROCESSING_FAILED  ProcessingStatus = "failed"
+)
+
 type Photo struct {
 	// Id        		uint64         `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
 	
@@ -54,12 +93,46 @@ type Photo struct {
 	Title  string         	 `json:"title" gorm:"column:title"`
 	Caption  string          `json:"caption" gorm:"column:caption"`
 	PhotoUrl  string         `json:"photo_url" gorm:"column:photo_url"`
+	// key of the uploaded file in the blob store, empty for url photos
+	PhotoKey  string         `json:"-" gorm:"column:photo_key;default:null"`
+
+	// set once the upload is processed
+	Width            uint64           `json:"width" gorm:"column:width;default:null"`
+	Height           uint64           `json:"height" gorm:"column:height;default:null"`
+	DominantColor    string           `json:"dominant_color" gorm:"column:dominant_color;default:null"`
+	BlurHash         string           `json:"blur_hash" gorm:"column:blur_hash;default:null"`
+	ProcessingStatus ProcessingStatus `json:"processing_status" gorm:"column:processing_status;default:null"`
+	Variants         []PhotoVariant   `json:"variants" gorm:"foreignKey:PhotoID"`
+	// read only, only moved by the like repository
+	LikeCount uint64 `json:"like_count" gorm:"column:like_count;->"`
 	
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
 }
 
+const (
+	VARIANT_THUMBNAIL = "thumbnail"
+	VARIANT_FEED      = "feed"
+)
+
+// PhotoVariant is a resized copy of an uploaded photo.
+type PhotoVariant struct {
+	ID        uint64    `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
+	PhotoID   uint64    `json:"photo_id" gorm:"column:photo_id"`
+	Name      string    `json:"name" gorm:"column:name"`
+	Format    string    `json:"format" gorm:"column:format"`
+	Width     uint64    `json:"width" gorm:"column:width"`
+	Height    uint64    `json:"height" gorm:"column:height"`
+	Key       string    `json:"-" gorm:"column:key"`
+	Url       string    `json:"url" gorm:"column:url"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+func (PhotoVariant) TableName() string {
+	return "photo_variant"
+}
+
 // COMMENT section
 type Comment struct {
 	// Id        		uint64         `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
@@ -68,6 +141,16 @@ type Comment struct {
 	UserID    uint64      `json:"user_id" gorm:"column:user_id"`
 	PhotoID    uint64      `json:"photo_id" gorm:"column:photo_id"`
 	Message  string         		 `json:"message" gorm:"column:message"`
+	// replies point at the comment they answer, top level comments have none
+	ParentID *uint64 `json:"parent_id" gorm:"column:parent_id"`
+	// 0 for top level comments, a reply is one deeper than its parent
+	Depth uint64 `json:"depth" gorm:"column:depth"`
+	// read only, only moved by the like repository
+	LikeCount uint64 `json:"like_count" gorm:"column:like_count;->"`
+	// read only, moved when a reply is created or deleted
+	ReplyCount uint64 `json:"reply_count" gorm:"column:reply_count;->"`
+	// the first replies, only loaded by the comment lists
+	Replies []Comment `json:"replies" gorm:"-"`
 	
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
@@ -0,0 +1,208 @@
+package account
+
+import "github.com/mygram/go-common/pkg/pagination"
+
+// mappers between the gorm models and the request/response types,
+// handlers map at the edge and services keep working with the models
+
+func ToAccountResponse(user User) AccountResponse {
+	return AccountResponse{
+		ID:        user.ID,
+		LegacyID:  user.LegacyAccountID,
+		Username:  user.Username,
+		Role:      user.EffectiveRole(),
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func ToUserResponse(user User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Age:       user.Age,
+		Role:      user.EffectiveRole(),
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func ToUserRegisterResponse(user User) UserRegisterResponse {
+	return UserRegisterResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Age:       user.Age,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func ToUserRoleResponse(user User) UserRoleResponse {
+	return UserRoleResponse{
+		ID:       user.ID,
+		Username: user.Username,
+		Role:     user.EffectiveRole(),
+	}
+}
+
+func (r PhotoRequest) ToPhoto() Photo {
+	return Photo{
+		UserID:  r.UserID,
+		Title:   r.Title,
+		Caption: r.Caption,
+	}
+}
+
+func (r PhotoUploadRequest) ToPhoto() Photo {
+	return Photo{
+		UserID:  r.UserID,
+		Title:   r.Title,
+		Caption: r.Caption,
+	}
+}
+
+func ToPhotoResponse(photo Photo) PhotoResponse {
+	return PhotoResponse{
+		ID:               photo.ID,
+		UserID:           photo.UserID,
+		Title:            photo.Title,
+		Caption:          photo.Caption,
+		PhotoUrl:         photo.PhotoUrl,
+		Width:            photo.Width,
+		Height:           photo.Height,
+		DominantColor:    photo.DominantColor,
+		BlurHash:         photo.BlurHash,
+		ProcessingStatus: photo.ProcessingStatus,
+		Variants:         ToPhotoVariantResponses(photo.Variants),
+		LikeCount:        photo.LikeCount,
+		CreatedAt:        photo.CreatedAt,
+		UpdatedAt:        photo.UpdatedAt,
+	}
+}
+
+// SetLikedByMe leaves the flag out when liked is nil (anonymous request).
+func (r *PhotoResponse) SetLikedByMe(liked map[uint64]bool) {
+	if liked != nil {
+		likedByMe := liked[r.ID]
+		r.LikedByMe = &likedByMe
+	}
+}
+
+func ToPhotoVariantResponses(variants []PhotoVariant) []PhotoVariantResponse {
+	if len(variants) == 0 {
+		return nil
+	}
+	res := make([]PhotoVariantResponse, 0, len(variants))
+	for _, variant := range variants {
+		res = append(res, PhotoVariantResponse{
+			Name:   variant.Name,
+			Format: variant.Format,
+			Width:  variant.Width,
+			Height: variant.Height,
+			Url:    variant.Url,
+		})
+	}
+	return res
+}
+
+func ToPhotoResponses(photos []Photo) []PhotoResponse {
+	res := make([]PhotoResponse, 0, len(photos))
+	for _, photo := range photos {
+		res = append(res, ToPhotoResponse(photo))
+	}
+	return res
+}
+
+func (r CommentRequest) ToComment() Comment {
+	return Comment{
+		UserID:   r.UserID,
+		PhotoID:  r.PhotoID,
+		ParentID: r.ParentID,
+		Message:  r.Message,
+	}
+}
+
+func ToCommentResponse(comment Comment) CommentResponse {
+	res := CommentResponse{
+		ID:         comment.ID,
+		UserID:     comment.UserID,
+		PhotoID:    comment.PhotoID,
+		ParentID:   comment.ParentID,
+		Depth:      comment.Depth,
+		Message:    comment.Message,
+		LikeCount:  comment.LikeCount,
+		ReplyCount: comment.ReplyCount,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+	if len(comment.Replies) > 0 {
+		res.Replies = ToCommentResponses(comment.Replies)
+		if uint64(len(comment.Replies)) < comment.ReplyCount {
+			last := comment.Replies[len(comment.Replies)-1]
+			res.RepliesCursor = pagination.Cursor{
+				Sort:      RepliesSort.String(),
+				CreatedAt: last.CreatedAt,
+				ID:        last.ID,
+			}.Encode()
+		}
+	}
+	return res
+}
+
+// SetLikedByMe leaves the flag out when liked is nil (anonymous request),
+// the replies shown with the comment get it too.
+func (r *CommentResponse) SetLikedByMe(liked map[uint64]bool) {
+	if liked != nil {
+		likedByMe := liked[r.ID]
+		r.LikedByMe = &likedByMe
+	}
+	for i := range r.Replies {
+		r.Replies[i].SetLikedByMe(liked)
+	}
+}
+
+// CommentIds is the ids of the comments and of the replies shown with them.
+func CommentIds(comments []CommentResponse) []uint64 {
+	ids := make([]uint64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+		ids = append(ids, CommentIds(comment.Replies)...)
+	}
+	return ids
+}
+
+func ToCommentResponses(comments []Comment) []CommentResponse {
+	res := make([]CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		res = append(res, ToCommentResponse(comment))
+	}
+	return res
+}
+
+func (r SocialMediaRequest) ToSocialMedia() SocialMedia {
+	return SocialMedia{
+		UserID:         r.UserID,
+		Name:           r.Name,
+		SocialMediaUrl: r.SocialMediaUrl,
+	}
+}
+
+func ToSocialMediaResponse(socialMedia SocialMedia) SocialMediaResponse {
+	return SocialMediaResponse{
+		ID:             socialMedia.ID,
+		UserID:         socialMedia.UserID,
+		Name:           socialMedia.Name,
+		SocialMediaUrl: socialMedia.SocialMediaUrl,
+		CreatedAt:      socialMedia.CreatedAt,
+		UpdatedAt:      socialMedia.UpdatedAt,
+	}
+}
+
+func ToSocialMediaResponses(socialMedias []SocialMedia) []SocialMediaResponse {
+	res := make([]SocialMediaResponse, 0, len(socialMedias))
+	for _, socialMedia := range socialMedias {
+		res = append(res, ToSocialMediaResponse(socialMedia))
+	}
+	return res
+}
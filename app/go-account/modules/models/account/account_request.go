@@ -1,9 +1,17 @@
 package account
 
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/mygram/go-common/pkg/pagination"
+)
+
 type CreateAccount struct {
 	Username string      `json:"username" binding:"required"`
 	Password string      `json:"password" binding:"required"`
-	Role     AccountRole `json:"role" binding:"required"`
+	// ignored by the public endpoint, accounts are always created as normal
+	Role     AccountRole `json:"role"`
 }
 
 type LoginAccount struct {
@@ -17,8 +25,70 @@ type LoginUser struct {
 }
 
 type RegisterUser struct {
-	Username string      `json:"username" gorm:"column:username;not null"`
-	Email string      	 `json:"email" gorm:"column:email;not null"`
-	Password string      `json:"password" gorm:"column:password;not null"`
-	Age string      		 `json:"age" gorm:"column:age;not null"`
+	Username string      `json:"username" gorm:"column:username;not null" binding:"required"`
+	Email string      	 `json:"email" gorm:"column:email;not null" binding:"required,email_addr"`
+	Password string      `json:"password" gorm:"column:password;not null" binding:"required,password"`
+	Age string      		 `json:"age" gorm:"column:age;not null" binding:"required,age"`
+}
+
+type UpdateUserRole struct {
+	Role   AccountRole `json:"role" binding:"required"`
+	Reason string      `json:"reason"`
+}
+
+// user_id has to match the caller, it is checked by the handler.
+// The file of a photo is only set by the upload, never by the client.
+type PhotoRequest struct {
+	UserID  uint64 `json:"user_id" form:"user_id"`
+	Title   string `json:"title" form:"title" binding:"required"`
+	Caption string `json:"caption" form:"caption"`
+}
+
+// PhotoUploadRequest is the multipart form of PhotoRequest,
+// photo_url is set from the stored file
+type PhotoUploadRequest struct {
+	UserID  uint64                `form:"user_id"`
+	Title   string                `form:"title" binding:"required"`
+	Caption string                `form:"caption"`
+	Photo   *multipart.FileHeader `form:"photo" binding:"required"`
+}
+
+// CommentRequest is a reply when parent_id is set, photo_id is then
+// taken from the parent when empty.
+type CommentRequest struct {
+	UserID   uint64  `json:"user_id" form:"user_id"`
+	PhotoID  uint64  `json:"photo_id" form:"photo_id"`
+	ParentID *uint64 `json:"parent_id" form:"parent_id" binding:"omitempty,min=1"`
+	Message  string  `json:"message" form:"message" binding:"required"`
+}
+
+type SocialMediaRequest struct {
+	UserID         uint64 `json:"user_id" form:"user_id"`
+	Name           string `json:"name" form:"name" binding:"required"`
+	SocialMediaUrl string `json:"social_media_url" form:"social_media_url" binding:"required,web_url"`
+}
+
+// list filters, bound from the query string next to pagination.Query
+type CreatedRange struct {
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty,gtfield=CreatedFrom"`
+}
+
+type PhotoFilter struct {
+	UserID uint64 `form:"user_id"`
+	CreatedRange
+}
+
+// replies are read oldest first, top level comments keep the list sort
+var RepliesSort = pagination.Sort{Column: pagination.SORT_CREATED_AT}
+
+type CommentFilter struct {
+	UserID  uint64 `form:"user_id"`
+	PhotoID uint64 `form:"photo_id"`
+	CreatedRange
+}
+
+type SocialMediaFilter struct {
+	UserID uint64 `form:"user_id"`
+	CreatedRange
 }
@@ -6,40 +6,95 @@ import (
 	"github.com/google/uuid"
 )
 
+// AccountResponse is kept for the deprecated /account routes,
+// legacy_id is the uuid the account had before it was merged into user
 type AccountResponse struct {
-	ID        uuid.UUID   `json:"id"`
+	ID        uint64      `json:"id"`
+	LegacyID  *uuid.UUID  `json:"legacy_id,omitempty"`
 	Username  string      `json:"username"`
 	Role      AccountRole `json:"role"`
 	CreatedAt time.Time   `json:"created_at"`
 }
 
-type AccountResponseWithPassword struct {
-	AccountResponse
-	Password string
+// response types are the only shapes written to clients,
+// never put a password, hash or soft delete column in them
+type UserResponse struct {
+	ID        uint64      `json:"id"`
+	Username  string      `json:"username"`
+	Email     string      `json:"email"`
+	Age       uint64      `json:"age"`
+	Role      AccountRole `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
 }
 
-type UserResponse struct {
-	// - di json, menandakan golang akan mengabaikan properti
-	// - di gorm, menandakan gorm akan mengabaikan properti
-	ID        uint64      `json:"id" gorm:"column:id"`
-	Username  string         `json:"username" gorm:"column:username"`
-	Email  string         	 `json:"email" gorm:"column:email"`
-	Password  string         `json:"password" gorm:"password"`
-	Age  			uint64         `json:"age" gorm:"column:age"`
+type UserRegisterResponse struct {
+	ID        uint64    `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Age       uint64    `json:"age"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
 
-	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
-	// gorm.Model
+type UserRoleResponse struct {
+	ID       uint64      `json:"id"`
+	Username string      `json:"username"`
+	Role     AccountRole `json:"role"`
 }
 
-type UserRegisterResponse struct {
-	ID        uint64      `json:"id" gorm:"column:id"`
-	Username  string         `json:"username" gorm:"column:username"`
-	Email  string         	 `json:"email" gorm:"column:email"`
-	Password  string         `json:"password" gorm:"password"`
-	Age  			uint64         `json:"age" gorm:"column:age"`
-
-	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
-	// gorm.Model
-}
\ No newline at end of file
+type PhotoResponse struct {
+	ID       uint64 `json:"id"`
+	UserID   uint64 `json:"user_id"`
+	Title    string `json:"title"`
+	Caption  string `json:"caption"`
+	PhotoUrl string `json:"photo_url"`
+	// empty until the upload is processed
+	Width            uint64                 `json:"width,omitempty"`
+	Height           uint64                 `json:"height,omitempty"`
+	DominantColor    string                 `json:"dominant_color,omitempty"`
+	BlurHash         string                 `json:"blur_hash,omitempty"`
+	ProcessingStatus ProcessingStatus       `json:"processing_status,omitempty"`
+	Variants         []PhotoVariantResponse `json:"variants,omitempty"`
+	LikeCount        uint64                 `json:"like_count"`
+	// only set when the request carries a token
+	LikedByMe *bool     `json:"liked_by_me,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PhotoVariantResponse struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Width  uint64 `json:"width"`
+	Height uint64 `json:"height"`
+	Url    string `json:"url"`
+}
+
+type CommentResponse struct {
+	ID         uint64  `json:"id"`
+	UserID     uint64  `json:"user_id"`
+	PhotoID    uint64  `json:"photo_id"`
+	ParentID   *uint64 `json:"parent_id,omitempty"`
+	Depth      uint64  `json:"depth"`
+	Message    string  `json:"message"`
+	LikeCount  uint64  `json:"like_count"`
+	ReplyCount uint64  `json:"reply_count"`
+	// the first replies, the rest is loaded from /comment/:id/replies
+	// with replies_cursor, which is empty once every reply is shown
+	Replies       []CommentResponse `json:"replies,omitempty"`
+	RepliesCursor string            `json:"replies_cursor,omitempty"`
+	// only set when the request carries a token
+	LikedByMe *bool     `json:"liked_by_me,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SocialMediaResponse struct {
+	ID             uint64    `json:"id"`
+	UserID         uint64    `json:"user_id"`
+	Name           string    `json:"name"`
+	SocialMediaUrl string    `json:"social_media_url"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,119 @@
+package account
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var secretField = regexp.MustCompile(`(?i)password|hash|deleted`)
+
+// fields matching secretField that are fine to expose
+var publicFields = map[string]bool{
+	// placeholder of the photo, not a password hash
+	"PhotoResponse.BlurHash": true,
+}
+
+// TestResponseHasNoSecret walks every *Response type of this package,
+// including the package types they embed or nest, so a new response
+// type is covered without being listed here.
+func TestResponseHasNoSecret(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", nil, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	structs := map[string]*ast.StructType{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if spec, ok := n.(*ast.TypeSpec); ok {
+					if st, ok := spec.Type.(*ast.StructType); ok {
+						structs[spec.Name.Name] = st
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	checked := 0
+	for name := range structs {
+		if !strings.HasSuffix(name, "Response") {
+			continue
+		}
+		checked++
+		t.Run(name, func(t *testing.T) {
+			for _, leak := range secretFields(structs, name, map[string]bool{}) {
+				t.Errorf("%v exposes %v", name, leak)
+			}
+		})
+	}
+	assert.NotZero(t, checked)
+}
+
+func secretFields(structs map[string]*ast.StructType, name string, seen map[string]bool) (leaks []string) {
+	st, ok := structs[name]
+	if !ok || seen[name] {
+		return
+	}
+	seen[name] = true
+
+	for _, field := range st.Fields.List {
+		jsonName := ""
+		if field.Tag != nil {
+			tag, _ := strconv.Unquote(field.Tag.Value)
+			jsonName = strings.SplitN(reflect.StructTag(tag).Get("json"), ",", 2)[0]
+		}
+		if jsonName == "-" {
+			continue
+		}
+		for _, ident := range field.Names {
+			if publicFields[name+"."+ident.Name] {
+				continue
+			}
+			if secretField.MatchString(ident.Name) || secretField.MatchString(jsonName) {
+				leaks = append(leaks, name+"."+ident.Name)
+			}
+		}
+		leaks = append(leaks, secretFields(structs, typeName(field.Type), seen)...)
+	}
+	return
+}
+
+// typeName unwraps pointers, slices and maps down to a package level type name
+func typeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return typeName(e.X)
+	case *ast.ArrayType:
+		return typeName(e.Elt)
+	case *ast.MapType:
+		return typeName(e.Value)
+	}
+	return ""
+}
+
+func TestToUserResponse(t *testing.T) {
+	res := ToUserResponse(User{
+		ID:       1,
+		Username: "allam",
+		Password: "$2a$10$hash",
+	})
+	assert.Equal(t, ROLE_NORMAL, res.Role)
+
+	body, err := json.Marshal(res)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), "$2a$10$hash")
+}
@@ -10,23 +10,21 @@ import (
 type ActivityType string
 
 const (
-	ACTIVITY_LOGIN  ActivityType = "login"
-	ACTIVITY_LOGOUT ActivityType = "logout"
+	ACTIVITY_LOGIN   ActivityType = "login"
+	ACTIVITY_LOGOUT  ActivityType = "logout"
+	ACTIVITY_REFRESH ActivityType = "refresh"
 )
 
-type AccountActivity struct {
-	ID        uuid.UUID      `json:"id" gorm:"column:id"`
-	UserID    uuid.UUID      `json:"user_id" gorm:"column:user_id"`
-	Type      ActivityType   `json:"type" gorm:"column:type"`
-	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
-}
-
 type UserActivity struct {
 	ID        uuid.UUID      `json:"id" gorm:"column:id"`
 	UserID    uint64      `json:"user_id" gorm:"column:user_id"`
 	Type      ActivityType   `json:"type" gorm:"column:type"`
+	// family_id	id of the login activity that started the refresh token chain
+	// rotated_at	when the refresh token bound to this activity was used
+	// revoked_at	when the whole family got revoked (reuse detected)
+	FamilyID  uuid.UUID      `json:"family_id" gorm:"column:family_id"`
+	RotatedAt *time.Time     `json:"rotated_at" gorm:"column:rotated_at"`
+	RevokedAt *time.Time     `json:"revoked_at" gorm:"column:revoked_at"`
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
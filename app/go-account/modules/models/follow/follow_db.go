@@ -0,0 +1,63 @@
+package follow
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Follow is unique per follower and followee, following twice is a no-op.
+type Follow struct {
+	ID         uint64    `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
+	FollowerID uint64    `json:"follower_id" gorm:"column:follower_id"`
+	FolloweeID uint64    `json:"followee_id" gorm:"column:followee_id"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+
+	// the other side of the edge, only one is loaded by the list queries
+	Follower *FollowUser `json:"-" gorm:"foreignKey:FollowerID"`
+	Followee *FollowUser `json:"-" gorm:"foreignKey:FolloweeID"`
+}
+
+func (Follow) TableName() string {
+	return "follows"
+}
+
+// FollowUser is the public part of a user shown in follow lists,
+// deleted users are not loaded.
+type FollowUser struct {
+	ID        uint64         `gorm:"column:id"`
+	Username  string         `gorm:"column:username"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at"`
+}
+
+func (FollowUser) TableName() string {
+	return "user"
+}
+
+// Timeline is one photo in the home feed of a user, written when the
+// photo is posted (fan-out on write). Photos of users with more followers
+// than the fan-out limit are not copied and are read from photo instead.
+type Timeline struct {
+	UserID    uint64    `gorm:"column:user_id;primaryKey"`
+	PhotoID   uint64    `gorm:"column:photo_id;primaryKey"`
+	AuthorID  uint64    `gorm:"column:author_id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (Timeline) TableName() string {
+	return "timelines"
+}
+
+// FollowState is the state after a follow or unfollow.
+type FollowState struct {
+	UserID        uint64 `json:"user_id"`
+	Following     bool   `json:"following"`
+	FollowerCount uint64 `json:"follower_count"`
+	// Changed is false when the call repeated the current state
+	Changed bool `json:"-"`
+}
+
+// FollowUri is the :id of /user/:id/follow, /user/:id/followers and /user/:id/following.
+type FollowUri struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
@@ -0,0 +1,33 @@
+package follow
+
+// ToFollowerResponses maps the followers of a user, Follower must be
+// loaded, follows of deleted users are left out.
+func ToFollowerResponses(follows []Follow) []FollowResponse {
+	res := make([]FollowResponse, 0, len(follows))
+	for _, follow := range follows {
+		if follow.Follower != nil {
+			res = append(res, toFollowResponse(*follow.Follower, follow))
+		}
+	}
+	return res
+}
+
+// ToFollowingResponses maps the users a user follows, Followee must be
+// loaded, follows of deleted users are left out.
+func ToFollowingResponses(follows []Follow) []FollowResponse {
+	res := make([]FollowResponse, 0, len(follows))
+	for _, follow := range follows {
+		if follow.Followee != nil {
+			res = append(res, toFollowResponse(*follow.Followee, follow))
+		}
+	}
+	return res
+}
+
+func toFollowResponse(user FollowUser, follow Follow) FollowResponse {
+	return FollowResponse{
+		ID:         user.ID,
+		Username:   user.Username,
+		FollowedAt: follow.CreatedAt,
+	}
+}
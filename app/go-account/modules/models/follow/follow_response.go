@@ -0,0 +1,9 @@
+package follow
+
+import "time"
+
+type FollowResponse struct {
+	ID         uint64    `json:"id"`
+	Username   string    `json:"username"`
+	FollowedAt time.Time `json:"followed_at"`
+}
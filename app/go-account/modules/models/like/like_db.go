@@ -0,0 +1,55 @@
+package like
+
+import (
+	"time"
+)
+
+type LikeTarget string
+
+const (
+	TARGET_PHOTO   LikeTarget = "photo"
+	TARGET_COMMENT LikeTarget = "comment"
+)
+
+// Table is the table of the liked rows, it holds the like_count counter.
+func (t LikeTarget) Table() string {
+	return string(t)
+}
+
+// Like is unique per user and target, liking twice is a no-op.
+type Like struct {
+	ID         uint64     `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
+	UserID     uint64     `json:"user_id" gorm:"column:user_id"`
+	TargetType LikeTarget `json:"target_type" gorm:"column:target_type"`
+	TargetID   uint64     `json:"target_id" gorm:"column:target_id"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"column:created_at"`
+}
+
+func (Like) TableName() string {
+	return "likes"
+}
+
+// LikeState is the state after a like or unlike.
+type LikeState struct {
+	TargetType LikeTarget `json:"target_type"`
+	TargetID   uint64     `json:"target_id"`
+	Liked      bool       `json:"liked"`
+	LikeCount  uint64     `json:"like_count"`
+	// Changed is false when the call repeated the current state
+	Changed bool `json:"-"`
+	// OwnerID is the author of the target
+	OwnerID uint64 `json:"-"`
+}
+
+// LikeUri is the :id of /photo/:id/like and /comment/:id/like.
+type LikeUri struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
+
+// LikeCountResponse is pushed to everyone watching the target, it
+// leaves out whether the watcher liked it.
+type LikeCountResponse struct {
+	TargetType LikeTarget `json:"target_type"`
+	TargetID   uint64     `json:"target_id"`
+	LikeCount  uint64     `json:"like_count"`
+}
@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/mygram/go-common/pkg/pagination"
+	"gorm.io/gorm"
+)
+
+type NotificationType string
+
+const (
+	TYPE_COMMENT NotificationType = "comment"
+	TYPE_LIKE    NotificationType = "like"
+	TYPE_FOLLOW  NotificationType = "follow"
+	TYPE_MENTION NotificationType = "mention"
+)
+
+// NotificationTypes is every type a user can turn off.
+var NotificationTypes = []NotificationType{TYPE_COMMENT, TYPE_LIKE, TYPE_FOLLOW, TYPE_MENTION}
+
+func (t NotificationType) Valid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification tells UserID that ActorID did something, PhotoID and
+// CommentID point at what it was done to when there is one.
+type Notification struct {
+	ID        uint64           `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
+	UserID    uint64           `json:"user_id" gorm:"column:user_id"`
+	ActorID   uint64           `json:"actor_id" gorm:"column:actor_id"`
+	Type      NotificationType `json:"type" gorm:"column:type"`
+	PhotoID   *uint64          `json:"photo_id" gorm:"column:photo_id"`
+	CommentID *uint64          `json:"comment_id" gorm:"column:comment_id"`
+	ReadAt    *time.Time       `json:"read_at" gorm:"column:read_at"`
+	CreatedAt time.Time        `json:"created_at" gorm:"column:created_at"`
+
+	Actor *NotificationActor `json:"-" gorm:"foreignKey:ActorID"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationActor is the public part of the user who caused a
+// notification, deleted users are not loaded.
+type NotificationActor struct {
+	ID        uint64         `gorm:"column:id"`
+	Username  string         `gorm:"column:username"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at"`
+}
+
+func (NotificationActor) TableName() string {
+	return "user"
+}
+
+// Preference is only stored once a user changes it, every type is on
+// until then.
+type Preference struct {
+	UserID  uint64           `gorm:"column:user_id;primaryKey"`
+	Type    NotificationType `gorm:"column:type;primaryKey"`
+	Enabled bool             `gorm:"column:enabled"`
+}
+
+func (Preference) TableName() string {
+	return "notification_preferences"
+}
+
+// Preferences is whether each type creates notifications.
+type Preferences map[NotificationType]bool
+
+// NotificationQuery is bound from ?unread=&limit=&cursor=&sort=.
+type NotificationQuery struct {
+	pagination.Query
+	Unread bool `form:"unread"`
+}
+
+// NotificationUri is the :id of /notifications/:id/read.
+type NotificationUri struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
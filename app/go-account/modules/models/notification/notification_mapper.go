@@ -0,0 +1,40 @@
+package notification
+
+func ToNotificationResponse(notification Notification) NotificationResponse {
+	res := NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		PhotoID:   notification.PhotoID,
+		CommentID: notification.CommentID,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+	if notification.Actor != nil {
+		res.Actor = &NotificationActorResponse{
+			ID:       notification.Actor.ID,
+			Username: notification.Actor.Username,
+		}
+	}
+	return res
+}
+
+func ToNotificationResponses(notifications []Notification) []NotificationResponse {
+	res := make([]NotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		res = append(res, ToNotificationResponse(notification))
+	}
+	return res
+}
+
+// ToPreferences fills in the types the user never changed.
+func ToPreferences(stored []Preference) Preferences {
+	prefs := make(Preferences, len(NotificationTypes))
+	for _, t := range NotificationTypes {
+		prefs[t] = true
+	}
+	for _, pref := range stored {
+		prefs[pref.Type] = pref.Enabled
+	}
+	return prefs
+}
@@ -0,0 +1,37 @@
+package notification
+
+import "time"
+
+type NotificationActorResponse struct {
+	ID       uint64 `json:"id"`
+	Username string `json:"username"`
+}
+
+type NotificationResponse struct {
+	ID   uint64           `json:"id"`
+	Type NotificationType `json:"type"`
+	// nil once the actor is deleted
+	Actor     *NotificationActorResponse `json:"actor"`
+	PhotoID   *uint64                    `json:"photo_id,omitempty"`
+	CommentID *uint64                    `json:"comment_id,omitempty"`
+	Read      bool                       `json:"read"`
+	ReadAt    *time.Time                 `json:"read_at,omitempty"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	UnreadCount   int64                  `json:"unread_count"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+type MarkReadResponse struct {
+	Marked      int64 `json:"marked"`
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// NotificationEventResponse is pushed to the streams of the user
+// when a notification is created.
+type NotificationEventResponse struct {
+	UnreadCount  int64                `json:"unread_count"`
+	Notification NotificationResponse `json:"notification"`
+}
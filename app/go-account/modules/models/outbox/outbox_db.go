@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EVENT_USER_REGISTERED      EventType = "user.registered"
+	EVENT_USER_LOGGED_IN       EventType = "user.logged_in"
+	EVENT_PHOTO_CREATED        EventType = "photo.created"
+	EVENT_PHOTO_UPDATED        EventType = "photo.updated"
+	EVENT_PHOTO_DELETED        EventType = "photo.deleted"
+	EVENT_COMMENT_CREATED      EventType = "comment.created"
+	EVENT_COMMENT_UPDATED      EventType = "comment.updated"
+	EVENT_COMMENT_DELETED      EventType = "comment.deleted"
+	EVENT_SOCIAL_MEDIA_CREATED EventType = "social_media.created"
+	EVENT_SOCIAL_MEDIA_UPDATED EventType = "social_media.updated"
+	EVENT_SOCIAL_MEDIA_DELETED EventType = "social_media.deleted"
+)
+
+// AggregateType is what an event is about, the events of one aggregate
+// are published in the order they were written.
+type AggregateType string
+
+const (
+	AGGREGATE_USER         AggregateType = "user"
+	AGGREGATE_PHOTO        AggregateType = "photo"
+	AGGREGATE_COMMENT      AggregateType = "comment"
+	AGGREGATE_SOCIAL_MEDIA AggregateType = "social_media"
+)
+
+// Event is a row of the outbox, it is written in the transaction of the
+// change it is about and published afterwards by the relay.
+type Event struct {
+	ID            uint64        `gorm:"column:id;primaryKey;autoIncrement"`
+	EventID       string        `gorm:"column:event_id"`
+	EventType     EventType     `gorm:"column:event_type"`
+	AggregateType AggregateType `gorm:"column:aggregate_type"`
+	AggregateID   string        `gorm:"column:aggregate_id"`
+	Payload       string        `gorm:"column:payload;type:jsonb"`
+	Attempts      int           `gorm:"column:attempts"`
+	LastError     string        `gorm:"column:last_error"`
+	PublishedAt   *time.Time    `gorm:"column:published_at"`
+	CreatedAt     time.Time     `gorm:"column:created_at"`
+}
+
+func (Event) TableName() string {
+	return "outbox"
+}
+
+func NewEvent(aggregateType AggregateType, aggregateId uint64, eventType EventType, data interface{}) (event Event, err error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	return Event{
+		EventID:       uuid.New().String(),
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   strconv.FormatUint(aggregateId, 10),
+		Payload:       string(payload),
+		CreatedAt:     time.Now(),
+	}, err
+}
+
+// Key orders the events of an aggregate, the event bus keeps the
+// messages of one key in order.
+func (e Event) Key() string {
+	return string(e.AggregateType) + ":" + e.AggregateID
+}
+
+// Envelope is the published message, ID is the same every time the event
+// is published so consumers can dedupe.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          EventType       `json:"type"`
+	AggregateType AggregateType   `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func (e Event) Envelope() Envelope {
+	return Envelope{
+		ID:            e.EventID,
+		Type:          e.EventType,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		OccurredAt:    e.CreatedAt,
+		Data:          json.RawMessage(e.Payload),
+	}
+}
+
+// UserLoggedIn is the data of EVENT_USER_LOGGED_IN.
+type UserLoggedIn struct {
+	UserID     uint64 `json:"user_id"`
+	ActivityID string `json:"activity_id"`
+}
@@ -0,0 +1,77 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// a stream follows at most this many photos at once
+const MAX_PHOTO_SUBSCRIPTIONS = 50
+
+type EventType string
+
+const (
+	EVENT_NOTIFICATION EventType = "notification"
+	EVENT_COMMENT      EventType = "comment"
+	EVENT_LIKE_COUNT   EventType = "like_count"
+	// EVENT_ERROR is sent when a websocket message is rejected
+	EVENT_ERROR EventType = "error"
+	// EVENT_CLOSE is the last event of a sse stream the server ends,
+	// websocket streams get a close frame instead
+	EVENT_CLOSE EventType = "close"
+)
+
+// Topic is what a stream subscribes to, events are published to one topic.
+type Topic string
+
+// UserTopic gets the events meant for the user only, like notifications.
+func UserTopic(userId uint64) Topic {
+	return Topic(fmt.Sprintf("user:%v", userId))
+}
+
+// PhotoTopic gets the events of a photo anyone may watch.
+func PhotoTopic(photoId uint64) Topic {
+	return Topic(fmt.Sprintf("photo:%v", photoId))
+}
+
+// Event is what a stream receives, Data is the json of the payload.
+type Event struct {
+	Type  EventType       `json:"type"`
+	Topic Topic           `json:"topic,omitempty"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// NewEvent marshals data once, every subscriber shares the bytes.
+func NewEvent(topic Topic, eventType EventType, data interface{}) (event Event, err error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	event = Event{Type: eventType, Topic: topic, Data: raw}
+	return
+}
+
+// StreamQuery is bound from ?photo_id=1&photo_id=2 when a stream opens.
+type StreamQuery struct {
+	PhotoIDs []uint64 `form:"photo_id"`
+}
+
+type ClientAction string
+
+const (
+	ACTION_SUBSCRIBE   ClientAction = "subscribe"
+	ACTION_UNSUBSCRIBE ClientAction = "unsubscribe"
+)
+
+// ClientMessage is sent by websocket clients to change the photos they watch,
+// sse clients reconnect with other photo_id instead.
+type ClientMessage struct {
+	Action  ClientAction `json:"action"`
+	PhotoID uint64       `json:"photo_id"`
+}
+
+// CloseResponse tells why the server ended the stream, the client
+// reconnects and reloads over the rest api what it may have missed.
+type CloseResponse struct {
+	Reason string `json:"reason"`
+}
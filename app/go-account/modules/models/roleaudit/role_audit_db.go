@@ -0,0 +1,18 @@
+package roleaudit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RoleAudit struct {
+	ID uuid.UUID `json:"id" gorm:"column:id"`
+	// actor_user_id is empty when the role was granted by the bootstrap cli
+	ActorUserID  *uint64   `json:"actor_user_id" gorm:"column:actor_user_id"`
+	TargetUserID uint64    `json:"target_user_id" gorm:"column:target_user_id"`
+	OldRole      string    `json:"old_role" gorm:"column:old_role"`
+	NewRole      string    `json:"new_role" gorm:"column:new_role"`
+	Reason       string    `json:"reason" gorm:"column:reason"`
+	CreatedAt    time.Time `json:"created_at" gorm:"column:created_at"`
+}
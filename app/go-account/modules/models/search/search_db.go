@@ -0,0 +1,29 @@
+package search
+
+import (
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+)
+
+type SearchType string
+
+const (
+	TYPE_USER  SearchType = "user"
+	TYPE_PHOTO SearchType = "photo"
+)
+
+// Hit is what a search backend returns, the rows themselves are loaded
+// from the database so a stale index never shows deleted users or photos.
+type Hit struct {
+	ID   uint64  `gorm:"column:id"`
+	Rank float64 `gorm:"column:rank"`
+	// html escaped, matches are wrapped in <mark>
+	Highlight string `gorm:"column:highlight"`
+}
+
+// Result is a hit with its row, only the one of its type is set.
+type Result struct {
+	Type  SearchType
+	Hit   Hit
+	User  *accountmodel.User
+	Photo *accountmodel.Photo
+}
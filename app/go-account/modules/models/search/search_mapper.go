@@ -0,0 +1,44 @@
+package search
+
+import (
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+)
+
+func ToSearchResponse(result Result) SearchResponse {
+	res := SearchResponse{
+		Type:      result.Type,
+		ID:        result.Hit.ID,
+		Rank:      result.Hit.Rank,
+		Highlight: result.Hit.Highlight,
+	}
+	if result.User != nil {
+		res.User = &SearchUserResponse{
+			ID:       result.User.ID,
+			Username: result.User.Username,
+		}
+	}
+	if result.Photo != nil {
+		photo := accountmodel.ToPhotoResponse(*result.Photo)
+		res.Photo = &photo
+	}
+	return res
+}
+
+func ToSearchResponses(results []Result) []SearchResponse {
+	res := make([]SearchResponse, 0, len(results))
+	for _, result := range results {
+		res = append(res, ToSearchResponse(result))
+	}
+	return res
+}
+
+// PhotoIds is the photos of the results, to look up liked_by_me.
+func PhotoIds(res []SearchResponse) []uint64 {
+	ids := make([]uint64, 0, len(res))
+	for _, result := range res {
+		if result.Photo != nil {
+			ids = append(ids, result.Photo.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+const (
+	// ranked results are paged by offset, deep pages cost a full scan
+	MAX_OFFSET = 1000
+)
+
+// SearchQuery is bound from ?q=&type=&limit=&cursor=, type defaults to photo.
+type SearchQuery struct {
+	Q      string     `form:"q" binding:"required,max=256"`
+	Type   SearchType `form:"type" binding:"omitempty,oneof=user photo"`
+	Limit  int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string     `form:"cursor"`
+}
+
+// Params is a validated SearchQuery.
+type Params struct {
+	Q      string
+	Type   SearchType
+	Limit  int
+	Offset int
+}
+
+// Cursor is the offset of the next page, only valid for the type it was issued with.
+type Cursor struct {
+	Type   SearchType `json:"t"`
+	Offset int        `json:"o"`
+}
+
+func invalidParam(message string, err error) error {
+	return domainerr.WrapCode(domainerr.KIND_VALIDATION, response.CODE_INVALID_PARAM, message, err)
+}
+
+func (q SearchQuery) Params() (params Params, err error) {
+	params.Q = strings.TrimSpace(q.Q)
+	if params.Q == "" {
+		err = invalidParam("q must not be blank", nil)
+		return
+	}
+	params.Type = q.Type
+	if params.Type == "" {
+		params.Type = TYPE_PHOTO
+	}
+	params.Limit = q.Limit
+	if params.Limit == 0 {
+		params.Limit = pagination.DEFAULT_LIMIT
+	}
+
+	if q.Cursor == "" {
+		return
+	}
+	cursor, err := DecodeCursor(q.Cursor)
+	if err != nil {
+		return
+	}
+	if cursor.Type != params.Type || cursor.Offset < 0 || cursor.Offset > MAX_OFFSET {
+		err = invalidParam("cursor was issued for another search", nil)
+		return
+	}
+	params.Offset = cursor.Offset
+	return
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(raw string) (cursor Cursor, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		err = invalidParam("cursor is malformed", err)
+		return
+	}
+	if err = json.Unmarshal(b, &cursor); err != nil {
+		err = invalidParam("cursor is malformed", err)
+		return
+	}
+	return
+}
+
+// Paginate trims the extra hit fetched past the limit and points the
+// next page after the last one, there is none past MAX_OFFSET.
+func Paginate(hits []Hit, params Params) ([]Hit, response.Pagination) {
+	if len(hits) <= params.Limit {
+		return hits, response.Pagination{}
+	}
+	hits = hits[:params.Limit]
+	next := params.Offset + params.Limit
+	if next > MAX_OFFSET {
+		return hits, response.Pagination{}
+	}
+	return hits, response.Pagination{
+		NextCursor: Cursor{Type: params.Type, Offset: next}.Encode(),
+		HasMore:    true,
+	}
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchQueryParams(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   SearchQuery
+		want    Params
+		wantErr bool
+	}{
+		{
+			desc:  "defaults",
+			input: SearchQuery{Q: " sunset "},
+			want:  Params{Q: "sunset", Type: TYPE_PHOTO, Limit: pagination.DEFAULT_LIMIT},
+		},
+		{
+			desc:  "with cursor",
+			input: SearchQuery{Q: "ali", Type: TYPE_USER, Limit: 5, Cursor: Cursor{Type: TYPE_USER, Offset: 5}.Encode()},
+			want:  Params{Q: "ali", Type: TYPE_USER, Limit: 5, Offset: 5},
+		},
+		{
+			desc:    "blank query",
+			input:   SearchQuery{Q: "  "},
+			wantErr: true,
+		},
+		{
+			desc:    "cursor of another type",
+			input:   SearchQuery{Q: "ali", Type: TYPE_USER, Cursor: Cursor{Type: TYPE_PHOTO, Offset: 5}.Encode()},
+			wantErr: true,
+		},
+		{
+			desc:    "cursor past the last page",
+			input:   SearchQuery{Q: "ali", Cursor: Cursor{Type: TYPE_PHOTO, Offset: MAX_OFFSET + 1}.Encode()},
+			wantErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			params, err := tC.input.Params()
+			if tC.wantErr {
+				assert.ErrorIs(t, err, domainerr.ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, params)
+		})
+	}
+}
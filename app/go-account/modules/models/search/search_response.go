@@ -0,0 +1,27 @@
+package search
+
+import (
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+)
+
+type SearchUserResponse struct {
+	ID       uint64 `json:"id"`
+	Username string `json:"username"`
+}
+
+type SearchResponse struct {
+	Type SearchType `json:"type"`
+	ID   uint64     `json:"id"`
+	Rank float64    `json:"rank"`
+	// html escaped, matched words are wrapped in <mark>
+	Highlight string                      `json:"highlight"`
+	User      *SearchUserResponse         `json:"user,omitempty"`
+	Photo     *accountmodel.PhotoResponse `json:"photo,omitempty"`
+}
+
+// SetLikedByMe marks a photo result, user results are left alone.
+func (r *SearchResponse) SetLikedByMe(liked map[uint64]bool) {
+	if r.Photo != nil {
+		r.Photo.SetLikedByMe(liked)
+	}
+}
@@ -0,0 +1,59 @@
+package tag
+
+import (
+	"time"
+)
+
+// Hashtag is stored once per name, names are lowercase without the #.
+type Hashtag struct {
+	ID        uint64    `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
+	Name      string    `json:"name" gorm:"column:name"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+func (Hashtag) TableName() string {
+	return "hashtags"
+}
+
+// PhotoHashtag links a photo to a hashtag of its caption.
+type PhotoHashtag struct {
+	PhotoID   uint64 `gorm:"column:photo_id;primaryKey"`
+	HashtagID uint64 `gorm:"column:hashtag_id;primaryKey"`
+}
+
+func (PhotoHashtag) TableName() string {
+	return "photo_hashtags"
+}
+
+type MentionSource string
+
+const (
+	SOURCE_PHOTO   MentionSource = "photo"
+	SOURCE_COMMENT MentionSource = "comment"
+)
+
+// Mention is a user named with @ in a photo caption or a comment,
+// PhotoID is the photo of the comment for comment mentions.
+type Mention struct {
+	ID         uint64        `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
+	UserID     uint64        `json:"user_id" gorm:"column:user_id"`
+	AuthorID   uint64        `json:"author_id" gorm:"column:author_id"`
+	SourceType MentionSource `json:"source_type" gorm:"column:source_type"`
+	SourceID   uint64        `json:"source_id" gorm:"column:source_id"`
+	PhotoID    uint64        `json:"photo_id" gorm:"column:photo_id"`
+	CreatedAt  time.Time     `json:"created_at" gorm:"column:created_at"`
+}
+
+func (Mention) TableName() string {
+	return "mentions"
+}
+
+// TagUri is the :name of /tag/:name/photos, with or without the #.
+type TagUri struct {
+	Name string `uri:"name" binding:"required"`
+}
+
+// MentionUri is the :id of /user/:id/mentions.
+type MentionUri struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
@@ -0,0 +1,20 @@
+package tag
+
+func ToMentionResponse(mention Mention) MentionResponse {
+	return MentionResponse{
+		ID:         mention.ID,
+		AuthorID:   mention.AuthorID,
+		SourceType: mention.SourceType,
+		SourceID:   mention.SourceID,
+		PhotoID:    mention.PhotoID,
+		CreatedAt:  mention.CreatedAt,
+	}
+}
+
+func ToMentionResponses(mentions []Mention) []MentionResponse {
+	res := make([]MentionResponse, 0, len(mentions))
+	for _, mention := range mentions {
+		res = append(res, ToMentionResponse(mention))
+	}
+	return res
+}
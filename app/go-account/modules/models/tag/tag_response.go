@@ -0,0 +1,12 @@
+package tag
+
+import "time"
+
+type MentionResponse struct {
+	ID         uint64        `json:"id"`
+	AuthorID   uint64        `json:"author_id"`
+	SourceType MentionSource `json:"source_type"`
+	SourceID   uint64        `json:"source_id"`
+	PhotoID    uint64        `json:"photo_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
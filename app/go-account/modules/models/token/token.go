@@ -54,3 +54,7 @@ type AccessClaim struct {
 	Role   string `json:"role"`
 	UserID string `json:"user_id"`
 }
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
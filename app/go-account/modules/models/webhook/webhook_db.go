@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type EventType string
+
+const (
+	EVENT_PHOTO_CREATED        EventType = "photo.created"
+	EVENT_PHOTO_UPDATED        EventType = "photo.updated"
+	EVENT_PHOTO_DELETED        EventType = "photo.deleted"
+	EVENT_COMMENT_CREATED      EventType = "comment.created"
+	EVENT_COMMENT_UPDATED      EventType = "comment.updated"
+	EVENT_COMMENT_DELETED      EventType = "comment.deleted"
+	EVENT_SOCIAL_MEDIA_CREATED EventType = "social_media.created"
+	EVENT_SOCIAL_MEDIA_UPDATED EventType = "social_media.updated"
+	EVENT_SOCIAL_MEDIA_DELETED EventType = "social_media.deleted"
+)
+
+// EventTypes is every event a webhook can subscribe to.
+var EventTypes = []EventType{
+	EVENT_PHOTO_CREATED, EVENT_PHOTO_UPDATED, EVENT_PHOTO_DELETED,
+	EVENT_COMMENT_CREATED, EVENT_COMMENT_UPDATED, EVENT_COMMENT_DELETED,
+	EVENT_SOCIAL_MEDIA_CREATED, EVENT_SOCIAL_MEDIA_UPDATED, EVENT_SOCIAL_MEDIA_DELETED,
+}
+
+func (t EventType) Valid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// headers of every delivery, receivers verify the signature against
+// the timestamp and the raw body
+const (
+	HEADER_EVENT     = "X-Mygram-Event"
+	HEADER_DELIVERY  = "X-Mygram-Delivery"
+	HEADER_TIMESTAMP = "X-Mygram-Timestamp"
+	HEADER_SIGNATURE = "X-Mygram-Signature"
+)
+
+type Scope string
+
+const (
+	// SCOPE_USER gets the events of the content of its user
+	SCOPE_USER Scope = "user"
+	// SCOPE_APP gets the events of every user, only admins create it
+	SCOPE_APP Scope = "app"
+)
+
+// Webhook is a url that is sent the events it subscribed to, signed
+// with its secret.
+type Webhook struct {
+	ID        uint64         `json:"id" gorm:"column:id;type:integer;primaryKey;autoIncrement"`
+	UserID    uint64         `json:"user_id" gorm:"column:user_id"`
+	Scope     Scope          `json:"scope" gorm:"column:scope"`
+	URL       string         `json:"url" gorm:"column:url"`
+	Secret    string         `json:"-" gorm:"column:secret"`
+	Events    pq.StringArray `json:"events" gorm:"column:events;type:text[]"`
+	Active    bool           `json:"active" gorm:"column:active"`
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+type DeliveryStatus string
+
+const (
+	DELIVERY_PENDING   DeliveryStatus = "pending"
+	DELIVERY_SUCCEEDED DeliveryStatus = "succeeded"
+	// DELIVERY_FAILED is given up after the last attempt, it can be replayed
+	DELIVERY_FAILED DeliveryStatus = "failed"
+)
+
+// Delivery is one event queued for one webhook, the row is kept as
+// the delivery log once it succeeded or failed.
+type Delivery struct {
+	ID        uint64    `gorm:"column:id;type:integer;primaryKey;autoIncrement"`
+	WebhookID uint64    `gorm:"column:webhook_id"`
+	EventID   string    `gorm:"column:event_id"`
+	EventType EventType `gorm:"column:event_type"`
+	// the posted envelope, kept as sent so replays are identical
+	Payload        string         `gorm:"column:payload;type:jsonb"`
+	Status         DeliveryStatus `gorm:"column:status"`
+	Attempts       int            `gorm:"column:attempts"`
+	NextAttemptAt  time.Time      `gorm:"column:next_attempt_at"`
+	LastStatusCode *int           `gorm:"column:last_status_code"`
+	LastError      string         `gorm:"column:last_error"`
+	DeliveredAt    *time.Time     `gorm:"column:delivered_at"`
+	ReplayOf       *uint64        `gorm:"column:replay_of"`
+	CreatedAt      time.Time      `gorm:"column:created_at"`
+
+	Webhook *Webhook `gorm:"foreignKey:WebhookID"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// Envelope is the body posted to the webhook, ID is the same for every
+// attempt and replay of the event so receivers can dedupe.
+type Envelope struct {
+	ID        string          `json:"id"`
+	Type      EventType       `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import "encoding/json"
+
+func ToWebhookResponse(webhook Webhook) WebhookResponse {
+	events := make([]EventType, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, EventType(event))
+	}
+	return WebhookResponse{
+		ID:        webhook.ID,
+		Scope:     webhook.Scope,
+		URL:       webhook.URL,
+		Events:    events,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func ToWebhookResponses(webhooks []Webhook) []WebhookResponse {
+	res := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		res = append(res, ToWebhookResponse(webhook))
+	}
+	return res
+}
+
+func ToWebhookCreatedResponse(webhook Webhook) WebhookCreatedResponse {
+	return WebhookCreatedResponse{
+		WebhookResponse: ToWebhookResponse(webhook),
+		Secret:          webhook.Secret,
+	}
+}
+
+func ToDeliveryResponse(delivery Delivery) DeliveryResponse {
+	res := DeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		ReplayOf:       delivery.ReplayOf,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	// only pending deliveries are attempted again
+	if delivery.Status == DELIVERY_PENDING {
+		res.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return res
+}
+
+func ToDeliveryResponses(deliveries []Delivery) []DeliveryResponse {
+	res := make([]DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, ToDeliveryResponse(delivery))
+	}
+	return res
+}
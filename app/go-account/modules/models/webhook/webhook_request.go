@@ -0,0 +1,32 @@
+package webhook
+
+import "github.com/mygram/go-common/pkg/pagination"
+
+type WebhookRequest struct {
+	URL    string      `json:"url" binding:"required,url"`
+	Events []EventType `json:"events" binding:"required,min=1"`
+}
+
+func (r WebhookRequest) ToWebhook() Webhook {
+	events := make([]string, 0, len(r.Events))
+	for _, event := range r.Events {
+		events = append(events, string(event))
+	}
+	return Webhook{URL: r.URL, Events: events, Active: true}
+}
+
+// WebhookUri is the :id of /webhooks/:id.
+type WebhookUri struct {
+	ID uint64 `uri:"id" binding:"required"`
+}
+
+// DeliveryUri is /webhooks/:id/deliveries/:deliveryId.
+type DeliveryUri struct {
+	ID         uint64 `uri:"id" binding:"required"`
+	DeliveryID uint64 `uri:"deliveryId" binding:"required"`
+}
+
+// DeliveryQuery is bound from ?limit=&cursor=&sort= of the delivery log.
+type DeliveryQuery struct {
+	pagination.Query
+}
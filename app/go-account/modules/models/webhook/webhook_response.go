@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookResponse struct {
+	ID        uint64      `json:"id"`
+	Scope     Scope       `json:"scope"`
+	URL       string      `json:"url"`
+	Events    []EventType `json:"events"`
+	Active    bool        `json:"active"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// WebhookCreatedResponse is the only response with the secret,
+// it is needed to verify the signatures.
+type WebhookCreatedResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type DeliveryResponse struct {
+	ID             uint64          `json:"id"`
+	EventID        string          `json:"event_id"`
+	EventType      EventType       `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	ReplayOf       *uint64         `json:"replay_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -4,30 +4,50 @@ import (
 	"context"
 
 	accountmodel "github.com/mygram/go-account/modules/models/account"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
 )
 
 type IAccountRepo interface {
-	CreateAccount(ctx context.Context, acc accountmodel.Account) (created accountmodel.Account, err error)
-	GetAccountByUserName(ctx context.Context, username string) (account accountmodel.Account, err error)
-	GetAccountByUserID(ctx context.Context, userId string) (account accountmodel.Account, err error)
-
 	CreateUser(ctx context.Context, acc accountmodel.User) (created accountmodel.User, err error)
 	GetUserByUserName(ctx context.Context, username string) (account accountmodel.User, err error)
 	GetUserById(ctx context.Context, userId string) (account accountmodel.User, err error)
+	GetUserByLegacyAccountID(ctx context.Context, accountId string) (account accountmodel.User, err error)
+	UpdateUserRole(ctx context.Context, userId uint64, role accountmodel.AccountRole) (err error)
+	CountUsersByRole(ctx context.Context, role accountmodel.AccountRole) (count int64, err error)
+	// LockRoleGrants is held until the transaction on ctx ends, it does
+	// nothing outside of one
+	LockRoleGrants(ctx context.Context) (err error)
+	// GetUsersByIds leaves out the missing ids, the order is not kept
+	GetUsersByIds(ctx context.Context, userIds []uint64) (users []accountmodel.User, err error)
 
-	GetAllPhotos(ctx context.Context) (account []accountmodel.Photo, err error)
+	GetAllPhotos(ctx context.Context, filter accountmodel.PhotoFilter, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error)
 	GetPhotoById(ctx context.Context, photoId uint64) (account accountmodel.Photo, err error)
+	// GetPhotosByIds leaves out the missing ids, the order is not kept
+	GetPhotosByIds(ctx context.Context, photoIds []uint64) (photos []accountmodel.Photo, err error)
 	CreatePhoto(ctx context.Context, acc accountmodel.Photo) (account accountmodel.Photo, err error)
 	UpdatePhoto(ctx context.Context, acc accountmodel.Photo) (account accountmodel.Photo, err error)
 	DeletePhoto(ctx context.Context, photoId uint64) (account accountmodel.Photo, err error)
+	UpdatePhotoProcessingStatus(ctx context.Context, photoId uint64, status accountmodel.ProcessingStatus) (err error)
+	SavePhotoProcessing(ctx context.Context, pho accountmodel.Photo) (err error)
+	GetPhotoIdsByProcessingStatus(ctx context.Context, statuses ...accountmodel.ProcessingStatus) (photoIds []uint64, err error)
 
-	GetAllComments(ctx context.Context) (account []accountmodel.Comment, err error)
+	// GetAllComments is the top level comments, replies are left out
+	GetAllComments(ctx context.Context, filter accountmodel.CommentFilter, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error)
 	GetCommentById(ctx context.Context, commentId uint64) (comment accountmodel.Comment, err error)
+	// GetPhotoComments is the top level comments of a photo, not found when the photo is missing
+	GetPhotoComments(ctx context.Context, photoId uint64, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error)
+	// GetCommentReplies is the direct replies of a comment, not found when the comment is missing
+	GetCommentReplies(ctx context.Context, commentId uint64, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error)
+	// GetReplyPreviews is the first perParent replies of every parent, oldest first
+	GetReplyPreviews(ctx context.Context, parentIds []uint64, perParent int) (replies []accountmodel.Comment, err error)
 	CreateComment(ctx context.Context, com accountmodel.Comment) (comment accountmodel.Comment, err error)
 	UpdateComment(ctx context.Context, com accountmodel.Comment) (comment accountmodel.Comment, err error)
 	DeleteComment(ctx context.Context, commentId uint64) (account accountmodel.Comment, err error)
+	// DeletePhotoComments deletes every comment of the photo, replies included
+	DeletePhotoComments(ctx context.Context, photoId uint64) (deleted int64, err error)
 	
-	GetAllSocialMedias(ctx context.Context) (account []accountmodel.SocialMedia, err error)
+	GetAllSocialMedias(ctx context.Context, filter accountmodel.SocialMediaFilter, params pagination.Params) (socialMedias []accountmodel.SocialMedia, page response.Pagination, err error)
 	GetSocialMediaById(ctx context.Context, socialMediaId uint64) (socialMedia accountmodel.SocialMedia, err error)
 	CreateSocialMedia(ctx context.Context, soc accountmodel.SocialMedia) (socialMedia accountmodel.SocialMedia, err error)
 	UpdateSocialMedia(ctx context.Context, soc accountmodel.SocialMedia) (socialMedia accountmodel.SocialMedia, err error)
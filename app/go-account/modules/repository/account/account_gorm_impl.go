@@ -2,15 +2,22 @@ package account
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"time"
 
 	accountmodel "github.com/mygram/go-account/modules/models/account"
+	"github.com/mygram/go-common/pkg/domainerr"
 	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/mygram/go-common/pkg/unitofwork"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// ROLE_GRANT_LOCK_KEY is the pg_advisory_xact_lock of LockRoleGrants
+const ROLE_GRANT_LOCK_KEY int64 = 5081310451
+
 type AccountRepoGormImpl struct {
 	master *gorm.DB
 }
@@ -21,94 +28,145 @@ func NewAccountRepoGormImpl(master *gorm.DB) IAccountRepo {
 	}
 }
 
-// ACCOUNT SECTION
-func (a *AccountRepoGormImpl) CreateAccount(ctx context.Context, acc accountmodel.Account) (created accountmodel.Account, err error) {
+// db is the transaction on ctx when the service runs the write in
+// unitofwork.WithinTransaction, master otherwise.
+func (a *AccountRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, a.master)
+}
+
+func createdRange(r accountmodel.CreatedRange) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !r.CreatedFrom.IsZero() {
+			db = db.Where("created_at >= ?", r.CreatedFrom)
+		}
+		if !r.CreatedTo.IsZero() {
+			db = db.Where("created_at < ?", r.CreatedTo)
+		}
+		return db
+	}
+}
+
+// USER SECTION
+func (a *AccountRepoGormImpl) CreateUser(ctx context.Context, acc accountmodel.User) (created accountmodel.User, err error) {
 	logCtx := fmt.Sprintf("%T - CreateAccount", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
-		Table("accounts").
+	err = a.db(ctx).
+		Table("user").
 		Create(&acc).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "user")
 		return
 	}
 
 	return acc, err
 }
 
-func (a *AccountRepoGormImpl) GetAccountByUserName(ctx context.Context, username string) (account accountmodel.Account, err error) {
+func (a *AccountRepoGormImpl) GetUserByUserName(ctx context.Context, username string) (account accountmodel.User, err error) {
 	logCtx := fmt.Sprintf("%T - GetAccountByUserName", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
-		Table("accounts").
+	err = a.db(ctx).
+		Table("user").
 		Where("username = ?", username).
-		Find(&account).Error
+		First(&account).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "user")
 		return
 	}
 
 	return account, err
 }
 
-func (a *AccountRepoGormImpl) GetAccountByUserID(ctx context.Context, userId string) (account accountmodel.Account, err error) {
-	logCtx := fmt.Sprintf("%T - GetAccountByUserID", a)
+func (a *AccountRepoGormImpl) GetUserById(ctx context.Context, userId string) (account accountmodel.User, err error) {
+	logCtx := fmt.Sprintf("%T - GetUserById", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
-		Table("accounts").
+	err = a.db(ctx).
+		Table("user").
 		Where("id = ?", userId).
-		Find(&account).Error
-
+		First(&account).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "user")
 		return
 	}
+
 	return account, err
 }
 
-// USER SECTION
-func (a *AccountRepoGormImpl) CreateUser(ctx context.Context, acc accountmodel.User) (created accountmodel.User, err error) {
-	logCtx := fmt.Sprintf("%T - CreateAccount", a)
+// GetUserByLegacyAccountID finds a user by the uuid it had in the old accounts table,
+// access tokens minted before the merge still carry that uuid
+func (a *AccountRepoGormImpl) GetUserByLegacyAccountID(ctx context.Context, accountId string) (account accountmodel.User, err error) {
+	logCtx := fmt.Sprintf("%T - GetUserByLegacyAccountID", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	err = a.db(ctx).
 		Table("user").
-		Create(&acc).Error
+		Where("legacy_account_id = ?", accountId).
+		First(&account).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "user")
 		return
 	}
 
-	return acc, err
+	return account, err
 }
 
-func (a *AccountRepoGormImpl) GetUserByUserName(ctx context.Context, username string) (account accountmodel.User, err error) {
-	logCtx := fmt.Sprintf("%T - GetAccountByUserName", a)
+func (a *AccountRepoGormImpl) GetUsersByIds(ctx context.Context, userIds []uint64) (users []accountmodel.User, err error) {
+	logCtx := fmt.Sprintf("%T - GetUsersByIds", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	if len(userIds) == 0 {
+		return
+	}
+	err = a.db(ctx).
 		Table("user").
-		Where("username = ?", username).
-		Find(&account).Error
+		Where("id IN ?", userIds).
+		Find(&users).Error
 	if err != nil {
-		return
+		err = domainerr.FromDB(err, "user")
 	}
-
-	return account, err
+	return
 }
 
-func (a *AccountRepoGormImpl) GetUserById(ctx context.Context, userId string) (account accountmodel.User, err error) {
-	logCtx := fmt.Sprintf("%T - GetUserById", a)
+func (a *AccountRepoGormImpl) UpdateUserRole(ctx context.Context, userId uint64, role accountmodel.AccountRole) (err error) {
+	logCtx := fmt.Sprintf("%T - UpdateUserRole", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	tx := a.db(ctx).
 		Table("user").
 		Where("id = ?", userId).
-		Find(&account).Error
-	if err != nil {
+		Update("role", role)
+	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "user")
 		return
 	}
 
-	return account, err
+	if tx.RowsAffected <= 0 {
+		err = domainerr.NotFound("user")
+		return
+	}
+	return
+}
+
+func (a *AccountRepoGormImpl) CountUsersByRole(ctx context.Context, role accountmodel.AccountRole) (count int64, err error) {
+	logCtx := fmt.Sprintf("%T - CountUsersByRole", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = a.db(ctx).
+		Table("user").
+		Where("role = ? AND deleted_at IS NULL", role).
+		Count(&count).Error
+	return
+}
+
+func (a *AccountRepoGormImpl) LockRoleGrants(ctx context.Context) (err error) {
+	logCtx := fmt.Sprintf("%T - LockRoleGrants", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = a.db(ctx).
+		Exec("SELECT pg_advisory_xact_lock(?)", ROLE_GRANT_LOCK_KEY).Error
+	return
 }
 
 // func (u *UserGormRepoImpl) FindAllUsers(ctx context.Context) (users []model.User, err error) {
@@ -124,44 +182,71 @@ func (a *AccountRepoGormImpl) GetUserById(ctx context.Context, userId string) (a
 // 	return
 // }
 
-func (a *AccountRepoGormImpl) GetAllPhotos(ctx context.Context) (photo []accountmodel.Photo, err error) {
+func (a *AccountRepoGormImpl) GetAllPhotos(ctx context.Context, filter accountmodel.PhotoFilter, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error) {
 	logCtx := fmt.Sprintf("%T - GetAllPhotos", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	query := a.db(ctx).
 		Table("photo").
-		Limit(20).
-		Find(&photo).
-		Order("created_at DESC").Error
+		Scopes(createdRange(filter.CreatedRange))
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	err = query.
+		Scopes(params.Scope).
+		Find(&photos).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "photo")
 		return
 	}
 
-	return photo, err
+	photos, page = pagination.Paginate(photos, params, func(row accountmodel.Photo) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
 }
 
 func (a *AccountRepoGormImpl) GetPhotoById(ctx context.Context, photoId uint64) (photo accountmodel.Photo, err error) {
 	logCtx := fmt.Sprintf("%T - GetPhotoById", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	err = a.db(ctx).
 		Table("photo").
+		Preload("Variants").
 		Where("id = ?", photoId).
-		Find(&photo).Error
+		First(&photo).Error
 
 	if err != nil {
+		err = domainerr.FromDB(err, "photo")
 		return
 	}
 	return photo, err
 }
+func (a *AccountRepoGormImpl) GetPhotosByIds(ctx context.Context, photoIds []uint64) (photos []accountmodel.Photo, err error) {
+	logCtx := fmt.Sprintf("%T - GetPhotosByIds", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if len(photoIds) == 0 {
+		return
+	}
+	err = a.db(ctx).
+		Table("photo").
+		Where("id IN ?", photoIds).
+		Find(&photos).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "photo")
+	}
+	return
+}
 func (a *AccountRepoGormImpl) CreatePhoto(ctx context.Context, pho accountmodel.Photo) (photo accountmodel.Photo, err error){
 	logCtx := fmt.Sprintf("%T - CreatePhoto", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	err = a.db(ctx).
 		Table("photo").
 		Create(&pho).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "photo")
 		return
 	}
 
@@ -171,25 +256,26 @@ func (a *AccountRepoGormImpl) UpdatePhoto(ctx context.Context, pho accountmodel.
 	logCtx := fmt.Sprintf("%T - UpdatePhoto", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 	
-	tx := a.master.
+	tx := a.db(ctx).
 		Model(&photo).
 		Table("photo").
 		Where("id = ?", pho.ID).
 		Updates(&pho)
 
 	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "photo")
 		return
 	}
 
 	if tx.RowsAffected <= 0 {
-		err = errors.New("book is not found")
+		err = domainerr.NotFound("photo")
 		return
 	}
 
 	return
 }
 func (a *AccountRepoGormImpl) DeletePhoto(ctx context.Context, photoId uint64) (photo accountmodel.Photo, err error) {
-	tx := a.master.
+	tx := a.db(ctx).
 		Model(&photo).
 		Table("photo").
 		// clause to return data after delete
@@ -197,120 +283,371 @@ func (a *AccountRepoGormImpl) DeletePhoto(ctx context.Context, photoId uint64) (
 		Where("id = ?", photoId).
 		Delete(&photo)
 	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "photo")
+		return
+	}
+
+	if tx.RowsAffected <= 0 {
+		err = domainerr.NotFound("photo")
+		return
+	}
+
+	// the service deletes their files too
+	if err = a.db(ctx).Where("photo_id = ?", photoId).Find(&photo.Variants).Error; err != nil {
+		err = domainerr.FromDB(err, "photo variant")
+	}
+	return
+}
+
+func (a *AccountRepoGormImpl) UpdatePhotoProcessingStatus(ctx context.Context, photoId uint64, status accountmodel.ProcessingStatus) (err error) {
+	logCtx := fmt.Sprintf("%T - UpdatePhotoProcessingStatus", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	tx := a.db(ctx).
+		Table("photo").
+		Where("id = ?", photoId).
+		Update("processing_status", status)
+	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "photo")
 		return
 	}
 
 	if tx.RowsAffected <= 0 {
-		err = errors.New("book is not found")
+		err = domainerr.NotFound("photo")
 		return
 	}
 	return
 }
 
-func (a *AccountRepoGormImpl) GetAllComments(ctx context.Context) (comment []accountmodel.Comment, err error){
+// SavePhotoProcessing stores what was derived from the upload, variants
+// of an earlier attempt are replaced.
+func (a *AccountRepoGormImpl) SavePhotoProcessing(ctx context.Context, pho accountmodel.Photo) (err error) {
+	logCtx := fmt.Sprintf("%T - SavePhotoProcessing", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = a.db(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.
+			Table("photo").
+			Where("id = ?", pho.ID).
+			Updates(map[string]interface{}{
+				"width":             pho.Width,
+				"height":            pho.Height,
+				"dominant_color":    pho.DominantColor,
+				"blur_hash":         pho.BlurHash,
+				"processing_status": pho.ProcessingStatus,
+				"updated_at":        time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected <= 0 {
+			return domainerr.NotFound("photo")
+		}
+
+		if err := tx.Where("photo_id = ?", pho.ID).Delete(&accountmodel.PhotoVariant{}).Error; err != nil {
+			return err
+		}
+		if len(pho.Variants) == 0 {
+			return nil
+		}
+		for i := range pho.Variants {
+			pho.Variants[i].PhotoID = pho.ID
+		}
+		return tx.Create(&pho.Variants).Error
+	})
+	if err != nil {
+		err = domainerr.FromDB(err, "photo")
+	}
+	return
+}
+
+// GetPhotoIdsByProcessingStatus is used to pick up the uploads that were
+// not processed before the last shutdown.
+func (a *AccountRepoGormImpl) GetPhotoIdsByProcessingStatus(ctx context.Context, statuses ...accountmodel.ProcessingStatus) (photoIds []uint64, err error) {
+	logCtx := fmt.Sprintf("%T - GetPhotoIdsByProcessingStatus", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = a.db(ctx).
+		Table("photo").
+		Where("processing_status IN ? AND deleted_at IS NULL", statuses).
+		Order("id").
+		Pluck("id", &photoIds).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "photo")
+	}
+	return
+}
+
+func (a *AccountRepoGormImpl) GetAllComments(ctx context.Context, filter accountmodel.CommentFilter, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error) {
 	logCtx := fmt.Sprintf("%T - GetAllComments", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	// replies are listed under their comment, see GetCommentReplies
+	query := a.db(ctx).
 		Table("comment").
-		Limit(20).
-		Find(&comment).
-		Order("created_at DESC").Error
+		Where("parent_id IS NULL").
+		Scopes(createdRange(filter.CreatedRange))
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.PhotoID != 0 {
+		query = query.Where("photo_id = ?", filter.PhotoID)
+	}
+	err = query.
+		Scopes(params.Scope).
+		Find(&comments).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "comment")
 		return
 	}
 
-	return comment, err
+	comments, page = pagination.Paginate(comments, params, func(row accountmodel.Comment) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
 }
 func (a *AccountRepoGormImpl) GetCommentById(ctx context.Context, commentId uint64) (comment accountmodel.Comment, err error) {
 	logCtx := fmt.Sprintf("%T - GetCommentById", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	err = a.db(ctx).
 		Table("comment").
 		Where("id = ?", commentId).
-		Find(&comment).Error
+		First(&comment).Error
 
 	if err != nil {
+		err = domainerr.FromDB(err, "comment")
 		return
 	}
 	return comment, err
 }
+func (a *AccountRepoGormImpl) GetPhotoComments(ctx context.Context, photoId uint64, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetPhotoComments", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if err = exists(a.db(ctx), "photo", photoId); err != nil {
+		err = domainerr.FromDB(err, "photo")
+		return
+	}
+	err = a.db(ctx).
+		Table("comment").
+		Where("photo_id = ? AND parent_id IS NULL", photoId).
+		Scopes(params.Scope).
+		Find(&comments).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "comment")
+		return
+	}
+
+	comments, page = pagination.Paginate(comments, params, func(row accountmodel.Comment) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
+}
+
+func (a *AccountRepoGormImpl) GetCommentReplies(ctx context.Context, commentId uint64, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetCommentReplies", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if err = exists(a.db(ctx), "comment", commentId); err != nil {
+		err = domainerr.FromDB(err, "comment")
+		return
+	}
+	err = a.db(ctx).
+		Table("comment").
+		Where("parent_id = ?", commentId).
+		Scopes(params.Scope).
+		Find(&comments).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "comment")
+		return
+	}
+
+	comments, page = pagination.Paginate(comments, params, func(row accountmodel.Comment) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
+}
+
+func (a *AccountRepoGormImpl) GetReplyPreviews(ctx context.Context, parentIds []uint64, perParent int) (replies []accountmodel.Comment, err error) {
+	logCtx := fmt.Sprintf("%T - GetReplyPreviews", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if len(parentIds) == 0 || perParent <= 0 {
+		return
+	}
+	// one query for the whole page instead of one per comment
+	err = a.db(ctx).
+		Raw(`SELECT * FROM (
+				SELECT *, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY created_at, id) AS rn
+				FROM comment
+				WHERE parent_id IN ? AND deleted_at IS NULL
+			) AS r
+			WHERE rn <= ?
+			ORDER BY parent_id, created_at, id`, parentIds, perParent).
+		Scan(&replies).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "comment")
+	}
+	return
+}
+
+// CreateComment checks the photo and the parent in the same transaction,
+// photos and comments are soft deleted so the foreign keys do not.
 func (a *AccountRepoGormImpl) CreateComment(ctx context.Context, com accountmodel.Comment) (comment accountmodel.Comment, err error) {
 	logCtx := fmt.Sprintf("%T - CreateComment", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
-		Table("comment").
-		Create(&com).Error
+	err = a.db(ctx).Transaction(func(tx *gorm.DB) error {
+		// a share lock keeps the photo from being deleted meanwhile
+		err := tx.
+			Table("photo").
+			Clauses(clause.Locking{Strength: "SHARE"}).
+			Select("id").
+			Where("id = ? AND deleted_at IS NULL", com.PhotoID).
+			Take(&struct{ ID uint64 }{}).Error
+		if err != nil {
+			return domainerr.FromDB(err, "photo")
+		}
+
+		if com.ParentID != nil {
+			res := tx.
+				Table("comment").
+				Where("id = ? AND photo_id = ? AND deleted_at IS NULL", *com.ParentID, com.PhotoID).
+				UpdateColumn("reply_count", gorm.Expr("reply_count + 1"))
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected <= 0 {
+				return domainerr.NotFound("parent comment")
+			}
+		}
+
+		return tx.
+			Table("comment").
+			Create(&com).Error
+	})
 	if err != nil {
+		err = domainerr.FromDB(err, "comment")
 		return
 	}
 
 	return com, err
 }
+
+// exists is gorm.ErrRecordNotFound when the row is missing or soft deleted.
+func exists(db *gorm.DB, table string, id uint64) error {
+	var count int64
+	err := db.
+		Table(table).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Limit(1).
+		Count(&count).Error
+	if err == nil && count == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	return err
+}
 func (a *AccountRepoGormImpl) UpdateComment(ctx context.Context, com accountmodel.Comment) (comment accountmodel.Comment, err error) {
-	tx := a.master.
+	tx := a.db(ctx).
 		Model(&comment).
 		Table("comment").
 		Where("id = ?", com.ID).
 		Updates(&com)
 
 	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "comment")
 		return
 	}
 
 	if tx.RowsAffected <= 0 {
-		err = errors.New("book is not found")
+		err = domainerr.NotFound("comment")
 		return
 	}
 
 	return
 }
+// DeleteComment keeps the replies, they are only reachable through
+// the deleted comment and are no longer listed.
 func (a *AccountRepoGormImpl) DeleteComment(ctx context.Context, commentId uint64) (comment accountmodel.Comment, err error) {
-	tx := a.master.
-		Model(&comment).
-		Table("comment").
-		// clause to return data after delete
-		Clauses(clause.Returning{}).
-		Where("id = ?", commentId).
-		Delete(&comment)
-	if err = tx.Error; err != nil {
-		return
+	err = a.db(ctx).Transaction(func(db *gorm.DB) error {
+		tx := db.
+			Model(&comment).
+			Table("comment").
+			// clause to return data after delete
+			Clauses(clause.Returning{}).
+			Where("id = ?", commentId).
+			Delete(&comment)
+		if tx.Error != nil {
+			return tx.Error
+		}
+		if tx.RowsAffected <= 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if comment.ParentID == nil {
+			return nil
+		}
+		return db.
+			Table("comment").
+			Where("id = ?", *comment.ParentID).
+			UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count - 1, 0)")).Error
+	})
+	if err != nil {
+		err = domainerr.FromDB(err, "comment")
 	}
+	return
+}
 
-	if tx.RowsAffected <= 0 {
-		err = errors.New("book is not found")
+func (a *AccountRepoGormImpl) DeletePhotoComments(ctx context.Context, photoId uint64) (deleted int64, err error) {
+	logCtx := fmt.Sprintf("%T - DeletePhotoComments", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	tx := a.db(ctx).
+		Table("comment").
+		Where("photo_id = ?", photoId).
+		Delete(&accountmodel.Comment{})
+	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "comment")
 		return
 	}
-	return
+	return tx.RowsAffected, err
 }
 
-func (a *AccountRepoGormImpl) GetAllSocialMedias(ctx context.Context) (socialMedia []accountmodel.SocialMedia, err error) {
+func (a *AccountRepoGormImpl) GetAllSocialMedias(ctx context.Context, filter accountmodel.SocialMediaFilter, params pagination.Params) (socialMedias []accountmodel.SocialMedia, page response.Pagination, err error) {
 	logCtx := fmt.Sprintf("%T - GetAllSocialMedias", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	query := a.db(ctx).
 		Table("socialmedia").
-		Limit(20).
-		Find(&socialMedia).
-		Order("created_at DESC").Error
+		Scopes(createdRange(filter.CreatedRange))
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	err = query.
+		Scopes(params.Scope).
+		Find(&socialMedias).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "social media")
 		return
 	}
 
-	return socialMedia, err
+	socialMedias, page = pagination.Paginate(socialMedias, params, func(row accountmodel.SocialMedia) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
 }
 func (a *AccountRepoGormImpl) GetSocialMediaById(ctx context.Context, socialMediaId uint64) (socialMedia accountmodel.SocialMedia, err error){
 	logCtx := fmt.Sprintf("%T - GetSocialMediaById", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	err = a.db(ctx).
 		Table("socialmedia").
 		Where("id = ?", socialMediaId).
-		Find(&socialMedia).Error
+		First(&socialMedia).Error
 
 	if err != nil {
+		err = domainerr.FromDB(err, "social media")
 		return
 	}
 	return socialMedia, err
@@ -319,35 +656,37 @@ func (a *AccountRepoGormImpl) CreateSocialMedia(ctx context.Context, soc account
 	logCtx := fmt.Sprintf("%T - CreateSocialMedia", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	err = a.db(ctx).
 		Table("socialmedia").
 		Create(&soc).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "social media")
 		return
 	}
 
 	return soc, err
 }
 func (a *AccountRepoGormImpl) UpdateSocialMedia(ctx context.Context, soc accountmodel.SocialMedia) (socialMedia accountmodel.SocialMedia, err error) {
-	tx := a.master.
+	tx := a.db(ctx).
 		Model(&socialMedia).
 		Table("socialmedia").
 		Where("id = ?", soc.ID).
 		Updates(&soc)
 
 	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "social media")
 		return
 	}
 
 	if tx.RowsAffected <= 0 {
-		err = errors.New("book is not found")
+		err = domainerr.NotFound("social media")
 		return
 	}
 
 	return
 }
 func (a *AccountRepoGormImpl) DeleteSocialMedia(ctx context.Context, socialMediaId uint64) (socialMedia accountmodel.SocialMedia, err error) {
-	tx := a.master.
+	tx := a.db(ctx).
 		Model(&socialMedia).
 		Table("socialmedia").
 		// clause to return data after delete
@@ -355,11 +694,12 @@ func (a *AccountRepoGormImpl) DeleteSocialMedia(ctx context.Context, socialMedia
 		Where("id = ?", socialMediaId).
 		Delete(&socialMedia)
 	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "social media")
 		return
 	}
 
 	if tx.RowsAffected <= 0 {
-		err = errors.New("book is not found")
+		err = domainerr.NotFound("social media")
 		return
 	}
 	return
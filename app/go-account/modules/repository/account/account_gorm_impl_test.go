@@ -5,29 +5,32 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	accountmodel "github.com/mygram/go-account/modules/models/account"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/pagination"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func TestGetAccountByUserID(t *testing.T) {
+func TestGetUserByLegacyAccountID(t *testing.T) {
 	id := uuid.New()
 	query := `SELECT * 
-	FROM "accounts" 
-	WHERE id = $1 
-		AND "accounts"."deleted_at" IS NULL`
+	FROM "user" 
+	WHERE legacy_account_id = $1 
+		AND "user"."deleted_at" IS NULL`
 
 	type (
 		input struct {
-			userId string
+			accountId string
 		}
 		want struct {
 			err     error
-			account accountmodel.Account
+			account accountmodel.User
 		}
 	)
 
@@ -40,21 +43,22 @@ func TestGetAccountByUserID(t *testing.T) {
 		{
 			desc: "happy case",
 			input: input{
-				userId: id.String(),
+				accountId: id.String(),
 			},
 			want: want{
 				err: nil,
-				account: accountmodel.Account{
-					ID:       id,
-					Username: "this-is-username",
-					Password: "password",
-					Role:     accountmodel.ROLE_NORMAL,
+				account: accountmodel.User{
+					ID:              1,
+					Username:        "this-is-username",
+					Password:        "password",
+					Role:            accountmodel.ROLE_NORMAL,
+					LegacyAccountID: &id,
 				},
 			},
 			doMock: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows(
-					[]string{"id", "username", "password", "role"}).
-					AddRow(id, "this-is-username", "password", "normal")
+					[]string{"id", "username", "password", "role", "legacy_account_id"}).
+					AddRow(1, "this-is-username", "password", "normal", id)
 
 				mock.
 					ExpectQuery(
@@ -68,11 +72,11 @@ func TestGetAccountByUserID(t *testing.T) {
 		{
 			desc: "error case",
 			input: input{
-				userId: id.String(),
+				accountId: id.String(),
 			},
 			want: want{
 				err:     errors.New("some error"),
-				account: accountmodel.Account{},
+				account: accountmodel.User{},
 			},
 			doMock: func(mock sqlmock.Sqlmock) {
 				mock.
@@ -96,7 +100,7 @@ func TestGetAccountByUserID(t *testing.T) {
 				master: DB,
 			}
 
-			acc, err := repo.GetAccountByUserID(context.Background(), tC.input.userId)
+			acc, err := repo.GetUserByLegacyAccountID(context.Background(), tC.input.accountId)
 			if tC.want.err != nil {
 				assert.EqualError(t, err, tC.want.err.Error())
 			} else {
@@ -106,3 +110,341 @@ func TestGetAccountByUserID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPhotoById(t *testing.T) {
+	query := `SELECT * 
+	FROM "photo" 
+	WHERE id = $1 
+		AND "photo"."deleted_at" IS NULL`
+
+	testCases := []struct {
+		desc   string
+		want   error
+		doMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			desc: "happy case",
+			want: nil,
+			doMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(
+					[]string{"id", "user_id", "title"}).
+					AddRow(1, 1, "this-is-title")
+				mock.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1).
+					WillReturnRows(rows)
+				// variants are preloaded
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variant" WHERE "photo_variant"."photo_id" = $1`)).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id", "name", "url"}).
+						AddRow(1, 1, "thumbnail", "http://localhost:9090/media/photo/1/this-is-uuid_thumbnail.jpg"))
+			},
+		},
+		{
+			desc: "photo is not found",
+			want: domainerr.ErrNotFound,
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			tC.doMock(mock)
+
+			repo := AccountRepoGormImpl{
+				master: DB,
+			}
+
+			_, err := repo.GetPhotoById(context.Background(), 1)
+			if tC.want != nil {
+				assert.ErrorIs(t, err, tC.want)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetAllPhotos(t *testing.T) {
+	now := time.Now().UTC()
+	cursor := pagination.Cursor{Sort: "-created_at", CreatedAt: now, ID: 10}
+
+	type (
+		input struct {
+			filter accountmodel.PhotoFilter
+			params pagination.Params
+		}
+		want struct {
+			ids     []uint64
+			hasMore bool
+		}
+	)
+
+	testCases := []struct {
+		desc   string
+		input  input
+		want   want
+		doMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			desc: "first page has more",
+			input: input{
+				params: pagination.Params{Limit: 2, Sort: pagination.DefaultSort},
+			},
+			want: want{ids: []uint64{12, 11}, hasMore: true},
+			doMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "created_at"}).
+					AddRow(12, now).
+					AddRow(11, now).
+					AddRow(10, now)
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo" 
+					WHERE "photo"."deleted_at" IS NULL 
+					ORDER BY created_at DESC,id DESC LIMIT 3`)).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			desc: "filtered page after cursor is the last one",
+			input: input{
+				filter: accountmodel.PhotoFilter{
+					UserID:       1,
+					CreatedRange: accountmodel.CreatedRange{CreatedFrom: now.Add(-time.Hour)},
+				},
+				params: pagination.Params{Limit: 2, Sort: pagination.DefaultSort, Cursor: &cursor},
+			},
+			want: want{ids: []uint64{9}, hasMore: false},
+			doMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "created_at"}).
+					AddRow(9, now)
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo" 
+					WHERE user_id = $1 
+						AND created_at >= $2 
+						AND (created_at, id) < ($3, $4) 
+						AND "photo"."deleted_at" IS NULL 
+					ORDER BY created_at DESC,id DESC LIMIT 3`)).
+					WithArgs(1, now.Add(-time.Hour), now, 10).
+					WillReturnRows(rows)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			tC.doMock(mock)
+
+			repo := AccountRepoGormImpl{
+				master: DB,
+			}
+
+			photos, page, err := repo.GetAllPhotos(context.Background(), tC.input.filter, tC.input.params)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			var ids []uint64
+			for _, photo := range photos {
+				ids = append(ids, photo.ID)
+			}
+			assert.Equal(t, tC.want.ids, ids)
+			assert.Equal(t, tC.want.hasMore, page.HasMore)
+			if tC.want.hasMore {
+				next, err := pagination.DecodeCursor(page.NextCursor)
+				assert.NoError(t, err)
+				assert.Equal(t, tC.want.ids[len(tC.want.ids)-1], next.ID)
+			}
+		})
+	}
+}
+
+func TestGetAllComments(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	DB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	repo := AccountRepoGormImpl{
+		master: DB,
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "created_at"}).
+		AddRow(2, time.Now())
+	// replies are not listed
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "comment" 
+			WHERE parent_id IS NULL 
+				AND photo_id = $1 
+				AND "comment"."deleted_at" IS NULL 
+			ORDER BY created_at DESC,id DESC LIMIT 3`)).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	comments, page, err := repo.GetAllComments(context.Background(),
+		accountmodel.CommentFilter{PhotoID: 1},
+		pagination.Params{Limit: 2, Sort: pagination.DefaultSort})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, comments, 1)
+	assert.False(t, page.HasMore)
+}
+
+func TestDeletePhotoComments(t *testing.T) {
+	query := `UPDATE "comment" SET "deleted_at"=$1 WHERE photo_id = $2 AND "comment"."deleted_at" IS NULL`
+
+	testCases := []struct {
+		desc        string
+		doMock      func(mock sqlmock.Sqlmock)
+		wantDeleted int64
+		wantErr     error
+	}{
+		{
+			desc: "comments are soft deleted",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+			wantDeleted: 3,
+		},
+		{
+			desc: "a photo without comments is fine",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			desc: "error delete",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnError(errors.New("some error"))
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("some error"),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			tC.doMock(mock)
+
+			repo := AccountRepoGormImpl{
+				master: DB,
+			}
+
+			deleted, err := repo.DeletePhotoComments(context.Background(), 1)
+			if tC.wantErr != nil {
+				assert.EqualError(t, err, tC.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tC.wantDeleted, deleted)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLockRoleGrants(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	DB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	mock.
+		ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WithArgs(ROLE_GRANT_LOCK_KEY).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := AccountRepoGormImpl{
+		master: DB,
+	}
+
+	assert.NoError(t, repo.LockRoleGrants(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePhoto(t *testing.T) {
+	deleteQuery := `UPDATE "photo" SET "deleted_at"=$1 WHERE id = $2 AND "photo"."deleted_at" IS NULL RETURNING *`
+	variantQuery := `SELECT * FROM "photo_variant" WHERE photo_id = $1`
+
+	testCases := []struct {
+		desc         string
+		doMock       func(mock sqlmock.Sqlmock)
+		wantVariants int
+		wantErr      error
+	}{
+		{
+			desc: "variants come with the deleted photo",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "photo_key"}).AddRow(1, 3, "photo/3/a.png"))
+				mock.ExpectCommit()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(variantQuery)).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id", "key"}).AddRow(1, 1, "photo/3/a_small.jpg").AddRow(2, 1, "photo/3/a_medium.jpg"))
+			},
+			wantVariants: 2,
+		},
+		{
+			desc: "not found",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectCommit()
+			},
+			wantErr: domainerr.ErrNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			tC.doMock(mock)
+
+			repo := AccountRepoGormImpl{
+				master: DB,
+			}
+
+			photo, err := repo.DeletePhoto(context.Background(), 1)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, photo.Variants, tC.wantVariants)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
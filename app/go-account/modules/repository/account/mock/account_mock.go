@@ -8,8 +8,10 @@ import (
 	context "context"
 	reflect "reflect"
 
-	account "github.com/mygram/go-account/modules/models/account"
 	gomock "github.com/golang/mock/gomock"
+	account "github.com/mygram/go-account/modules/models/account"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
 )
 
 // MockIAccountRepo is a mock of IAccountRepo interface.
@@ -35,47 +37,473 @@ func (m *MockIAccountRepo) EXPECT() *MockIAccountRepoMockRecorder {
 	return m.recorder
 }
 
-// CreateAccount mocks base method.
-func (m *MockIAccountRepo) CreateAccount(ctx context.Context, acc account.Account) (account.Account, error) {
+// CreateUser mocks base method.
+func (m *MockIAccountRepo) CreateUser(ctx context.Context, acc account.User) (account.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, acc)
+	ret0, _ := ret[0].(account.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockIAccountRepoMockRecorder) CreateUser(ctx, acc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockIAccountRepo)(nil).CreateUser), ctx, acc)
+}
+
+// GetUserByUserName mocks base method.
+func (m *MockIAccountRepo) GetUserByUserName(ctx context.Context, username string) (account.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUserName", ctx, username)
+	ret0, _ := ret[0].(account.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUserName indicates an expected call of GetUserByUserName.
+func (mr *MockIAccountRepoMockRecorder) GetUserByUserName(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUserName", reflect.TypeOf((*MockIAccountRepo)(nil).GetUserByUserName), ctx, username)
+}
+
+// GetUserById mocks base method.
+func (m *MockIAccountRepo) GetUserById(ctx context.Context, userId string) (account.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", ctx, userId)
+	ret0, _ := ret[0].(account.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockIAccountRepoMockRecorder) GetUserById(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockIAccountRepo)(nil).GetUserById), ctx, userId)
+}
+
+// GetUserByLegacyAccountID mocks base method.
+func (m *MockIAccountRepo) GetUserByLegacyAccountID(ctx context.Context, accountId string) (account.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByLegacyAccountID", ctx, accountId)
+	ret0, _ := ret[0].(account.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByLegacyAccountID indicates an expected call of GetUserByLegacyAccountID.
+func (mr *MockIAccountRepoMockRecorder) GetUserByLegacyAccountID(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByLegacyAccountID", reflect.TypeOf((*MockIAccountRepo)(nil).GetUserByLegacyAccountID), ctx, accountId)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockIAccountRepo) UpdateUserRole(ctx context.Context, userId uint64, role account.AccountRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", ctx, userId, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockIAccountRepoMockRecorder) UpdateUserRole(ctx, userId, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockIAccountRepo)(nil).UpdateUserRole), ctx, userId, role)
+}
+
+// CountUsersByRole mocks base method.
+func (m *MockIAccountRepo) CountUsersByRole(ctx context.Context, role account.AccountRole) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsersByRole", ctx, role)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsersByRole indicates an expected call of CountUsersByRole.
+func (mr *MockIAccountRepoMockRecorder) CountUsersByRole(ctx, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsersByRole", reflect.TypeOf((*MockIAccountRepo)(nil).CountUsersByRole), ctx, role)
+}
+
+// LockRoleGrants mocks base method.
+func (m *MockIAccountRepo) LockRoleGrants(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockRoleGrants", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockRoleGrants indicates an expected call of LockRoleGrants.
+func (mr *MockIAccountRepoMockRecorder) LockRoleGrants(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockRoleGrants", reflect.TypeOf((*MockIAccountRepo)(nil).LockRoleGrants), ctx)
+}
+
+// GetUsersByIds mocks base method.
+func (m *MockIAccountRepo) GetUsersByIds(ctx context.Context, userIds []uint64) ([]account.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByIds", ctx, userIds)
+	ret0, _ := ret[0].([]account.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByIds indicates an expected call of GetUsersByIds.
+func (mr *MockIAccountRepoMockRecorder) GetUsersByIds(ctx, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIds", reflect.TypeOf((*MockIAccountRepo)(nil).GetUsersByIds), ctx, userIds)
+}
+
+// GetAllPhotos mocks base method.
+func (m *MockIAccountRepo) GetAllPhotos(ctx context.Context, filter account.PhotoFilter, params pagination.Params) ([]account.Photo, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllPhotos", ctx, filter, params)
+	ret0, _ := ret[0].([]account.Photo)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllPhotos indicates an expected call of GetAllPhotos.
+func (mr *MockIAccountRepoMockRecorder) GetAllPhotos(ctx, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPhotos", reflect.TypeOf((*MockIAccountRepo)(nil).GetAllPhotos), ctx, filter, params)
+}
+
+// GetPhotoById mocks base method.
+func (m *MockIAccountRepo) GetPhotoById(ctx context.Context, photoId uint64) (account.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPhotoById", ctx, photoId)
+	ret0, _ := ret[0].(account.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPhotoById indicates an expected call of GetPhotoById.
+func (mr *MockIAccountRepoMockRecorder) GetPhotoById(ctx, photoId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhotoById", reflect.TypeOf((*MockIAccountRepo)(nil).GetPhotoById), ctx, photoId)
+}
+
+// GetPhotosByIds mocks base method.
+func (m *MockIAccountRepo) GetPhotosByIds(ctx context.Context, photoIds []uint64) ([]account.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPhotosByIds", ctx, photoIds)
+	ret0, _ := ret[0].([]account.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPhotosByIds indicates an expected call of GetPhotosByIds.
+func (mr *MockIAccountRepoMockRecorder) GetPhotosByIds(ctx, photoIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhotosByIds", reflect.TypeOf((*MockIAccountRepo)(nil).GetPhotosByIds), ctx, photoIds)
+}
+
+// CreatePhoto mocks base method.
+func (m *MockIAccountRepo) CreatePhoto(ctx context.Context, acc account.Photo) (account.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePhoto", ctx, acc)
+	ret0, _ := ret[0].(account.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePhoto indicates an expected call of CreatePhoto.
+func (mr *MockIAccountRepoMockRecorder) CreatePhoto(ctx, acc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePhoto", reflect.TypeOf((*MockIAccountRepo)(nil).CreatePhoto), ctx, acc)
+}
+
+// UpdatePhoto mocks base method.
+func (m *MockIAccountRepo) UpdatePhoto(ctx context.Context, acc account.Photo) (account.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhoto", ctx, acc)
+	ret0, _ := ret[0].(account.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePhoto indicates an expected call of UpdatePhoto.
+func (mr *MockIAccountRepoMockRecorder) UpdatePhoto(ctx, acc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhoto", reflect.TypeOf((*MockIAccountRepo)(nil).UpdatePhoto), ctx, acc)
+}
+
+// DeletePhoto mocks base method.
+func (m *MockIAccountRepo) DeletePhoto(ctx context.Context, photoId uint64) (account.Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePhoto", ctx, photoId)
+	ret0, _ := ret[0].(account.Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePhoto indicates an expected call of DeletePhoto.
+func (mr *MockIAccountRepoMockRecorder) DeletePhoto(ctx, photoId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePhoto", reflect.TypeOf((*MockIAccountRepo)(nil).DeletePhoto), ctx, photoId)
+}
+
+// UpdatePhotoProcessingStatus mocks base method.
+func (m *MockIAccountRepo) UpdatePhotoProcessingStatus(ctx context.Context, photoId uint64, status account.ProcessingStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhotoProcessingStatus", ctx, photoId, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePhotoProcessingStatus indicates an expected call of UpdatePhotoProcessingStatus.
+func (mr *MockIAccountRepoMockRecorder) UpdatePhotoProcessingStatus(ctx, photoId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhotoProcessingStatus", reflect.TypeOf((*MockIAccountRepo)(nil).UpdatePhotoProcessingStatus), ctx, photoId, status)
+}
+
+// SavePhotoProcessing mocks base method.
+func (m *MockIAccountRepo) SavePhotoProcessing(ctx context.Context, pho account.Photo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePhotoProcessing", ctx, pho)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePhotoProcessing indicates an expected call of SavePhotoProcessing.
+func (mr *MockIAccountRepoMockRecorder) SavePhotoProcessing(ctx, pho interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePhotoProcessing", reflect.TypeOf((*MockIAccountRepo)(nil).SavePhotoProcessing), ctx, pho)
+}
+
+// GetPhotoIdsByProcessingStatus mocks base method.
+func (m *MockIAccountRepo) GetPhotoIdsByProcessingStatus(ctx context.Context, statuses ...account.ProcessingStatus) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range statuses {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetPhotoIdsByProcessingStatus", varargs...)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPhotoIdsByProcessingStatus indicates an expected call of GetPhotoIdsByProcessingStatus.
+func (mr *MockIAccountRepoMockRecorder) GetPhotoIdsByProcessingStatus(ctx interface{}, statuses ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, statuses...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhotoIdsByProcessingStatus", reflect.TypeOf((*MockIAccountRepo)(nil).GetPhotoIdsByProcessingStatus), varargs...)
+}
+
+// GetAllComments mocks base method.
+func (m *MockIAccountRepo) GetAllComments(ctx context.Context, filter account.CommentFilter, params pagination.Params) ([]account.Comment, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllComments", ctx, filter, params)
+	ret0, _ := ret[0].([]account.Comment)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllComments indicates an expected call of GetAllComments.
+func (mr *MockIAccountRepoMockRecorder) GetAllComments(ctx, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllComments", reflect.TypeOf((*MockIAccountRepo)(nil).GetAllComments), ctx, filter, params)
+}
+
+// GetCommentById mocks base method.
+func (m *MockIAccountRepo) GetCommentById(ctx context.Context, commentId uint64) (account.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentById", ctx, commentId)
+	ret0, _ := ret[0].(account.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentById indicates an expected call of GetCommentById.
+func (mr *MockIAccountRepoMockRecorder) GetCommentById(ctx, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentById", reflect.TypeOf((*MockIAccountRepo)(nil).GetCommentById), ctx, commentId)
+}
+
+// GetPhotoComments mocks base method.
+func (m *MockIAccountRepo) GetPhotoComments(ctx context.Context, photoId uint64, params pagination.Params) ([]account.Comment, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPhotoComments", ctx, photoId, params)
+	ret0, _ := ret[0].([]account.Comment)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPhotoComments indicates an expected call of GetPhotoComments.
+func (mr *MockIAccountRepoMockRecorder) GetPhotoComments(ctx, photoId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhotoComments", reflect.TypeOf((*MockIAccountRepo)(nil).GetPhotoComments), ctx, photoId, params)
+}
+
+// GetCommentReplies mocks base method.
+func (m *MockIAccountRepo) GetCommentReplies(ctx context.Context, commentId uint64, params pagination.Params) ([]account.Comment, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentReplies", ctx, commentId, params)
+	ret0, _ := ret[0].([]account.Comment)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCommentReplies indicates an expected call of GetCommentReplies.
+func (mr *MockIAccountRepoMockRecorder) GetCommentReplies(ctx, commentId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentReplies", reflect.TypeOf((*MockIAccountRepo)(nil).GetCommentReplies), ctx, commentId, params)
+}
+
+// GetReplyPreviews mocks base method.
+func (m *MockIAccountRepo) GetReplyPreviews(ctx context.Context, parentIds []uint64, perParent int) ([]account.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplyPreviews", ctx, parentIds, perParent)
+	ret0, _ := ret[0].([]account.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplyPreviews indicates an expected call of GetReplyPreviews.
+func (mr *MockIAccountRepoMockRecorder) GetReplyPreviews(ctx, parentIds, perParent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplyPreviews", reflect.TypeOf((*MockIAccountRepo)(nil).GetReplyPreviews), ctx, parentIds, perParent)
+}
+
+// CreateComment mocks base method.
+func (m *MockIAccountRepo) CreateComment(ctx context.Context, com account.Comment) (account.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, com)
+	ret0, _ := ret[0].(account.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockIAccountRepoMockRecorder) CreateComment(ctx, com interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockIAccountRepo)(nil).CreateComment), ctx, com)
+}
+
+// UpdateComment mocks base method.
+func (m *MockIAccountRepo) UpdateComment(ctx context.Context, com account.Comment) (account.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", ctx, com)
+	ret0, _ := ret[0].(account.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockIAccountRepoMockRecorder) UpdateComment(ctx, com interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockIAccountRepo)(nil).UpdateComment), ctx, com)
+}
+
+// DeleteComment mocks base method.
+func (m *MockIAccountRepo) DeleteComment(ctx context.Context, commentId uint64) (account.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, commentId)
+	ret0, _ := ret[0].(account.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockIAccountRepoMockRecorder) DeleteComment(ctx, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockIAccountRepo)(nil).DeleteComment), ctx, commentId)
+}
+
+// DeletePhotoComments mocks base method.
+func (m *MockIAccountRepo) DeletePhotoComments(ctx context.Context, photoId uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePhotoComments", ctx, photoId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePhotoComments indicates an expected call of DeletePhotoComments.
+func (mr *MockIAccountRepoMockRecorder) DeletePhotoComments(ctx, photoId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePhotoComments", reflect.TypeOf((*MockIAccountRepo)(nil).DeletePhotoComments), ctx, photoId)
+}
+
+// GetAllSocialMedias mocks base method.
+func (m *MockIAccountRepo) GetAllSocialMedias(ctx context.Context, filter account.SocialMediaFilter, params pagination.Params) ([]account.SocialMedia, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllSocialMedias", ctx, filter, params)
+	ret0, _ := ret[0].([]account.SocialMedia)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllSocialMedias indicates an expected call of GetAllSocialMedias.
+func (mr *MockIAccountRepoMockRecorder) GetAllSocialMedias(ctx, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSocialMedias", reflect.TypeOf((*MockIAccountRepo)(nil).GetAllSocialMedias), ctx, filter, params)
+}
+
+// GetSocialMediaById mocks base method.
+func (m *MockIAccountRepo) GetSocialMediaById(ctx context.Context, socialMediaId uint64) (account.SocialMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocialMediaById", ctx, socialMediaId)
+	ret0, _ := ret[0].(account.SocialMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocialMediaById indicates an expected call of GetSocialMediaById.
+func (mr *MockIAccountRepoMockRecorder) GetSocialMediaById(ctx, socialMediaId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocialMediaById", reflect.TypeOf((*MockIAccountRepo)(nil).GetSocialMediaById), ctx, socialMediaId)
+}
+
+// CreateSocialMedia mocks base method.
+func (m *MockIAccountRepo) CreateSocialMedia(ctx context.Context, soc account.SocialMedia) (account.SocialMedia, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", ctx, acc)
-	ret0, _ := ret[0].(account.Account)
+	ret := m.ctrl.Call(m, "CreateSocialMedia", ctx, soc)
+	ret0, _ := ret[0].(account.SocialMedia)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockIAccountRepoMockRecorder) CreateAccount(ctx, acc interface{}) *gomock.Call {
+// CreateSocialMedia indicates an expected call of CreateSocialMedia.
+func (mr *MockIAccountRepoMockRecorder) CreateSocialMedia(ctx, soc interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockIAccountRepo)(nil).CreateAccount), ctx, acc)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSocialMedia", reflect.TypeOf((*MockIAccountRepo)(nil).CreateSocialMedia), ctx, soc)
 }
 
-// GetAccountByUserID mocks base method.
-func (m *MockIAccountRepo) GetAccountByUserID(ctx context.Context, userId string) (account.Account, error) {
+// UpdateSocialMedia mocks base method.
+func (m *MockIAccountRepo) UpdateSocialMedia(ctx context.Context, soc account.SocialMedia) (account.SocialMedia, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByUserID", ctx, userId)
-	ret0, _ := ret[0].(account.Account)
+	ret := m.ctrl.Call(m, "UpdateSocialMedia", ctx, soc)
+	ret0, _ := ret[0].(account.SocialMedia)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountByUserID indicates an expected call of GetAccountByUserID.
-func (mr *MockIAccountRepoMockRecorder) GetAccountByUserID(ctx, userId interface{}) *gomock.Call {
+// UpdateSocialMedia indicates an expected call of UpdateSocialMedia.
+func (mr *MockIAccountRepoMockRecorder) UpdateSocialMedia(ctx, soc interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByUserID", reflect.TypeOf((*MockIAccountRepo)(nil).GetAccountByUserID), ctx, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSocialMedia", reflect.TypeOf((*MockIAccountRepo)(nil).UpdateSocialMedia), ctx, soc)
 }
 
-// GetAccountByUserName mocks base method.
-func (m *MockIAccountRepo) GetAccountByUserName(ctx context.Context, username string) (account.Account, error) {
+// DeleteSocialMedia mocks base method.
+func (m *MockIAccountRepo) DeleteSocialMedia(ctx context.Context, socialMediaId uint64) (account.SocialMedia, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByUserName", ctx, username)
-	ret0, _ := ret[0].(account.Account)
+	ret := m.ctrl.Call(m, "DeleteSocialMedia", ctx, socialMediaId)
+	ret0, _ := ret[0].(account.SocialMedia)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountByUserName indicates an expected call of GetAccountByUserName.
-func (mr *MockIAccountRepoMockRecorder) GetAccountByUserName(ctx, username interface{}) *gomock.Call {
+// DeleteSocialMedia indicates an expected call of DeleteSocialMedia.
+func (mr *MockIAccountRepoMockRecorder) DeleteSocialMedia(ctx, socialMediaId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByUserName", reflect.TypeOf((*MockIAccountRepo)(nil).GetAccountByUserName), ctx, username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSocialMedia", reflect.TypeOf((*MockIAccountRepo)(nil).DeleteSocialMedia), ctx, socialMediaId)
 }
@@ -2,11 +2,16 @@ package account
 
 import (
 	"context"
+	"time"
 
 	activitymodel "github.com/mygram/go-account/modules/models/accountactivity"
 )
 
 type IAccountActivityRepo interface {
-	CreateActivity(ctx context.Context, acc activitymodel.AccountActivity) (created activitymodel.AccountActivity, err error)
 	CreateUserActivity(ctx context.Context, acc activitymodel.UserActivity) (created activitymodel.UserActivity, err error)
+	GetUserActivityByID(ctx context.Context, activityId string) (activity activitymodel.UserActivity, err error)
+	RotateUserActivity(ctx context.Context, activityId string) (rotated bool, err error)
+	RevokeUserActivityFamily(ctx context.Context, familyId string) (err error)
+	FindActiveUserActivities(ctx context.Context, userId uint64, familyId string, since time.Time) (activities []activitymodel.UserActivity, err error)
+	RevokeUserActivitiesByUserID(ctx context.Context, userId uint64) (err error)
 }
@@ -3,9 +3,12 @@ package account
 import (
 	"context"
 	"fmt"
+	"time"
 
 	activitymodel "github.com/mygram/go-account/modules/models/accountactivity"
+	"github.com/mygram/go-common/pkg/domainerr"
 	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/unitofwork"
 	"gorm.io/gorm"
 )
 
@@ -19,12 +22,16 @@ func NewActivityRepoGormImpl(master *gorm.DB) IAccountActivityRepo {
 	}
 }
 
-func (a *ActivityRepoGormImpl) CreateActivity(ctx context.Context, acc activitymodel.AccountActivity) (created activitymodel.AccountActivity, err error) {
+func (a *ActivityRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, a.master)
+}
+
+func (a *ActivityRepoGormImpl) CreateUserActivity(ctx context.Context, acc activitymodel.UserActivity) (created activitymodel.UserActivity, err error) {
 	logCtx := fmt.Sprintf("%T - CreateActivity", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
-		Table("account_activities").
+	err = a.db(ctx).
+		Table("user_activities").
 		Create(&acc).Error
 	if err != nil {
 		return
@@ -33,16 +40,80 @@ func (a *ActivityRepoGormImpl) CreateActivity(ctx context.Context, acc activitym
 	return acc, err
 }
 
-func (a *ActivityRepoGormImpl) CreateUserActivity(ctx context.Context, acc activitymodel.UserActivity) (created activitymodel.UserActivity, err error) {
-	logCtx := fmt.Sprintf("%T - CreateActivity", a)
+func (a *ActivityRepoGormImpl) GetUserActivityByID(ctx context.Context, activityId string) (activity activitymodel.UserActivity, err error) {
+	logCtx := fmt.Sprintf("%T - GetUserActivityByID", a)
 	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
 
-	err = a.master.
+	err = a.db(ctx).
 		Table("user_activities").
-		Create(&acc).Error
+		Where("id = ?", activityId).
+		First(&activity).Error
 	if err != nil {
+		err = domainerr.FromDB(err, "user activity")
 		return
 	}
 
-	return acc, err
-}
\ No newline at end of file
+	return activity, err
+}
+
+// RotateUserActivity marks the refresh token bound to activityId as used.
+// rotated is false when the token was already rotated or revoked, which
+// means somebody is replaying an old refresh token.
+func (a *ActivityRepoGormImpl) RotateUserActivity(ctx context.Context, activityId string) (rotated bool, err error) {
+	logCtx := fmt.Sprintf("%T - RotateUserActivity", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	tx := a.db(ctx).
+		Table("user_activities").
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", activityId).
+		Update("rotated_at", time.Now())
+	if err = tx.Error; err != nil {
+		return
+	}
+
+	return tx.RowsAffected > 0, err
+}
+
+func (a *ActivityRepoGormImpl) RevokeUserActivityFamily(ctx context.Context, familyId string) (err error) {
+	logCtx := fmt.Sprintf("%T - RevokeUserActivityFamily", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = a.db(ctx).
+		Table("user_activities").
+		Where("family_id = ? AND revoked_at IS NULL", familyId).
+		Update("revoked_at", time.Now()).Error
+	return
+}
+
+// FindActiveUserActivities returns the login/refresh activities of a user
+// that were created after since and are not revoked yet. Every one of them
+// is the jti of tokens that may still be in use. An empty familyId means
+// all sessions of the user.
+func (a *ActivityRepoGormImpl) FindActiveUserActivities(ctx context.Context, userId uint64, familyId string, since time.Time) (activities []activitymodel.UserActivity, err error) {
+	logCtx := fmt.Sprintf("%T - FindActiveUserActivities", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	tx := a.db(ctx).
+		Table("user_activities").
+		Where("user_id = ? AND revoked_at IS NULL AND created_at > ?", userId, since).
+		Where("type IN ?", []activitymodel.ActivityType{
+			activitymodel.ACTIVITY_LOGIN,
+			activitymodel.ACTIVITY_REFRESH,
+		})
+	if familyId != "" {
+		tx = tx.Where("family_id = ?", familyId)
+	}
+	err = tx.Find(&activities).Error
+	return
+}
+
+func (a *ActivityRepoGormImpl) RevokeUserActivitiesByUserID(ctx context.Context, userId uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - RevokeUserActivitiesByUserID", a)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = a.db(ctx).
+		Table("user_activities").
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update("revoked_at", time.Now()).Error
+	return
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/accountactivity/account_activity.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	accountactivity "github.com/mygram/go-account/modules/models/accountactivity"
+)
+
+// MockIAccountActivityRepo is a mock of IAccountActivityRepo interface.
+type MockIAccountActivityRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccountActivityRepoMockRecorder
+}
+
+// MockIAccountActivityRepoMockRecorder is the mock recorder for MockIAccountActivityRepo.
+type MockIAccountActivityRepoMockRecorder struct {
+	mock *MockIAccountActivityRepo
+}
+
+// NewMockIAccountActivityRepo creates a new mock instance.
+func NewMockIAccountActivityRepo(ctrl *gomock.Controller) *MockIAccountActivityRepo {
+	mock := &MockIAccountActivityRepo{ctrl: ctrl}
+	mock.recorder = &MockIAccountActivityRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccountActivityRepo) EXPECT() *MockIAccountActivityRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateUserActivity mocks base method.
+func (m *MockIAccountActivityRepo) CreateUserActivity(ctx context.Context, acc accountactivity.UserActivity) (accountactivity.UserActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserActivity", ctx, acc)
+	ret0, _ := ret[0].(accountactivity.UserActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserActivity indicates an expected call of CreateUserActivity.
+func (mr *MockIAccountActivityRepoMockRecorder) CreateUserActivity(ctx, acc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserActivity", reflect.TypeOf((*MockIAccountActivityRepo)(nil).CreateUserActivity), ctx, acc)
+}
+
+// GetUserActivityByID mocks base method.
+func (m *MockIAccountActivityRepo) GetUserActivityByID(ctx context.Context, activityId string) (accountactivity.UserActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserActivityByID", ctx, activityId)
+	ret0, _ := ret[0].(accountactivity.UserActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserActivityByID indicates an expected call of GetUserActivityByID.
+func (mr *MockIAccountActivityRepoMockRecorder) GetUserActivityByID(ctx, activityId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserActivityByID", reflect.TypeOf((*MockIAccountActivityRepo)(nil).GetUserActivityByID), ctx, activityId)
+}
+
+// RotateUserActivity mocks base method.
+func (m *MockIAccountActivityRepo) RotateUserActivity(ctx context.Context, activityId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateUserActivity", ctx, activityId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateUserActivity indicates an expected call of RotateUserActivity.
+func (mr *MockIAccountActivityRepoMockRecorder) RotateUserActivity(ctx, activityId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateUserActivity", reflect.TypeOf((*MockIAccountActivityRepo)(nil).RotateUserActivity), ctx, activityId)
+}
+
+// RevokeUserActivityFamily mocks base method.
+func (m *MockIAccountActivityRepo) RevokeUserActivityFamily(ctx context.Context, familyId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserActivityFamily", ctx, familyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserActivityFamily indicates an expected call of RevokeUserActivityFamily.
+func (mr *MockIAccountActivityRepoMockRecorder) RevokeUserActivityFamily(ctx, familyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserActivityFamily", reflect.TypeOf((*MockIAccountActivityRepo)(nil).RevokeUserActivityFamily), ctx, familyId)
+}
+
+// FindActiveUserActivities mocks base method.
+func (m *MockIAccountActivityRepo) FindActiveUserActivities(ctx context.Context, userId uint64, familyId string, since time.Time) ([]accountactivity.UserActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveUserActivities", ctx, userId, familyId, since)
+	ret0, _ := ret[0].([]accountactivity.UserActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveUserActivities indicates an expected call of FindActiveUserActivities.
+func (mr *MockIAccountActivityRepoMockRecorder) FindActiveUserActivities(ctx, userId, familyId, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveUserActivities", reflect.TypeOf((*MockIAccountActivityRepo)(nil).FindActiveUserActivities), ctx, userId, familyId, since)
+}
+
+// RevokeUserActivitiesByUserID mocks base method.
+func (m *MockIAccountActivityRepo) RevokeUserActivitiesByUserID(ctx context.Context, userId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserActivitiesByUserID", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserActivitiesByUserID indicates an expected call of RevokeUserActivitiesByUserID.
+func (mr *MockIAccountActivityRepoMockRecorder) RevokeUserActivitiesByUserID(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserActivitiesByUserID", reflect.TypeOf((*MockIAccountActivityRepo)(nil).RevokeUserActivitiesByUserID), ctx, userId)
+}
@@ -0,0 +1,14 @@
+package blob
+
+import (
+	"context"
+	"io"
+)
+
+// IBlobStore keeps uploaded files. Keys are slash separated paths,
+// Put returns the public url the object is served from.
+type IBlobStore interface {
+	Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) (url string, err error)
+	Get(ctx context.Context, key string) (body io.ReadCloser, err error)
+	Delete(ctx context.Context, key string) (err error)
+}
@@ -0,0 +1,96 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+)
+
+// BlobStoreLocalImpl writes objects under dir, the dir has to be
+// served under baseURL (see servers.LOCAL_MEDIA_ROUTE).
+type BlobStoreLocalImpl struct {
+	dir     string
+	baseURL string
+}
+
+func NewBlobStoreLocalImpl(dir, baseURL string) IBlobStore {
+	return &BlobStoreLocalImpl{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// keys come from the service, still never let one escape dir
+func (b *BlobStoreLocalImpl) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", domainerr.Validation("invalid object key")
+	}
+	return filepath.Join(b.dir, filepath.FromSlash(clean)), nil
+}
+
+func (b *BlobStoreLocalImpl) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) (url string, err error) {
+	logCtx := fmt.Sprintf("%T - Put", b)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	filename, err := b.path(key)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return
+	}
+
+	// write aside and rename so a reader never sees half a file
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, body)
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return
+	}
+	if err = os.Rename(tmp.Name(), filename); err != nil {
+		return
+	}
+	return b.baseURL + "/" + key, nil
+}
+
+func (b *BlobStoreLocalImpl) Get(ctx context.Context, key string) (body io.ReadCloser, err error) {
+	filename, err := b.path(key)
+	if err != nil {
+		return
+	}
+	body, err = os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		err = domainerr.NotFound("object")
+	}
+	return
+}
+
+func (b *BlobStoreLocalImpl) Delete(ctx context.Context, key string) (err error) {
+	logCtx := fmt.Sprintf("%T - Delete", b)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	filename, err := b.path(key)
+	if err != nil {
+		return
+	}
+	if err = os.Remove(filename); errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,132 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+)
+
+// S3Config works with any s3 compatible storage (aws, minio, r2),
+// PublicURL is the url of the bucket behind a cdn, defaults to Endpoint/Bucket.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
+
+// BlobStoreS3Impl talks to any s3 compatible storage with plain http
+// requests signed with aws signature v4, objects are addressed path style.
+type BlobStoreS3Impl struct {
+	client    *http.Client
+	endpoint  string
+	bucket    string
+	publicURL string
+	signer    sigV4Signer
+	now       func() time.Time
+}
+
+func NewBlobStoreS3Impl(client *http.Client, conf S3Config) IBlobStore {
+	endpoint := strings.TrimSuffix(conf.Endpoint, "/")
+	publicURL := strings.TrimSuffix(conf.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = endpoint + "/" + conf.Bucket
+	}
+	return &BlobStoreS3Impl{
+		client:    client,
+		endpoint:  endpoint,
+		bucket:    conf.Bucket,
+		publicURL: publicURL,
+		signer: sigV4Signer{
+			accessKey: conf.AccessKey,
+			secretKey: conf.SecretKey,
+			region:    conf.Region,
+			service:   "s3",
+		},
+		now: time.Now,
+	}
+}
+
+func (b *BlobStoreS3Impl) objectURL(key string) string {
+	return b.endpoint + "/" + b.bucket + "/" + awsEscape(key, false)
+}
+
+func (b *BlobStoreS3Impl) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header, payloadHash string) (res *http.Response, err error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.objectURL(key), body)
+	if err != nil {
+		return
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	b.signer.sign(req, payloadHash, b.now())
+	return b.client.Do(req)
+}
+
+// s3 errors are xml, keep the start of it for the log
+func statusError(res *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return fmt.Errorf("s3 responded %v: %s", res.Status, msg)
+}
+
+func (b *BlobStoreS3Impl) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) (url string, err error) {
+	logCtx := fmt.Sprintf("%T - Put", b)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	res, err := b.do(ctx, http.MethodPut, key, body, size, header, unsignedPayload)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		err = statusError(res)
+		return
+	}
+	return b.publicURL + "/" + awsEscape(key, false), nil
+}
+
+func (b *BlobStoreS3Impl) Get(ctx context.Context, key string) (body io.ReadCloser, err error) {
+	res, err := b.do(ctx, http.MethodGet, key, nil, 0, nil, emptyPayload)
+	if err != nil {
+		return
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		return res.Body, nil
+	case http.StatusNotFound:
+		err = domainerr.NotFound("object")
+	default:
+		err = statusError(res)
+	}
+	res.Body.Close()
+	return
+}
+
+func (b *BlobStoreS3Impl) Delete(ctx context.Context, key string) (err error) {
+	logCtx := fmt.Sprintf("%T - Delete", b)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	res, err := b.do(ctx, http.MethodDelete, key, nil, 0, nil, emptyPayload)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	// deleting a missing key is not an error for s3 either
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		err = statusError(res)
+	}
+	return
+}
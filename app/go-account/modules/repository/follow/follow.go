@@ -0,0 +1,22 @@
+package follow
+
+import (
+	"context"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	followmodel "github.com/mygram/go-account/modules/models/follow"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type IFollowRepo interface {
+	// Follow copies the last backfill fanned out photos of the followee into the timeline of the follower
+	Follow(ctx context.Context, followIn followmodel.Follow, backfill int) (state followmodel.FollowState, err error)
+	Unfollow(ctx context.Context, followIn followmodel.Follow) (state followmodel.FollowState, err error)
+	GetFollowers(ctx context.Context, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error)
+	GetFollowing(ctx context.Context, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error)
+	// FanOutPhoto copies the photo into the timeline of every follower of its author,
+	// nothing is copied when the author has more than maxFollowers followers
+	FanOutPhoto(ctx context.Context, photoId uint64, authorId uint64, maxFollowers uint64) (fannedOut bool, err error)
+	GetFeed(ctx context.Context, userId uint64, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error)
+}
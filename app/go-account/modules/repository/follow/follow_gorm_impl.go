@@ -0,0 +1,254 @@
+package follow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	followmodel "github.com/mygram/go-account/modules/models/follow"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepoGormImpl struct {
+	master *gorm.DB
+}
+
+func NewFollowRepoGormImpl(master *gorm.DB) IFollowRepo {
+	return &FollowRepoGormImpl{
+		master: master,
+	}
+}
+
+func (r *FollowRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, r.master)
+}
+
+// Follow locks the followee row first, FanOutPhoto takes the same lock so
+// a photo posted during a follow is either backfilled or fanned out to
+// the new follower, never missed by both.
+func (r *FollowRepoGormImpl) Follow(ctx context.Context, follow followmodel.Follow, backfill int) (state followmodel.FollowState, err error) {
+	logCtx := fmt.Sprintf("%T - Follow", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := followerCount(tx, follow.FolloweeID, clause.Locking{Strength: "UPDATE"}, &state); err != nil {
+			return err
+		}
+		res := tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&follow)
+		if res.Error != nil || res.RowsAffected <= 0 {
+			return res.Error
+		}
+
+		err := tx.
+			Table("user").
+			Where("id = ?", follow.FolloweeID).
+			UpdateColumn("follower_count", gorm.Expr("follower_count + 1")).Error
+		if err != nil {
+			return err
+		}
+		state.FollowerCount++
+		state.Changed = true
+
+		// photos of authors above the fan-out limit are read from photo
+		return tx.Exec(`INSERT INTO timelines (user_id, photo_id, author_id, created_at)
+			SELECT ?, id, user_id, created_at FROM photo
+			WHERE user_id = ? AND fanned_out AND deleted_at IS NULL
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+			ON CONFLICT DO NOTHING`,
+			follow.FollowerID, follow.FolloweeID, backfill).Error
+	})
+	if err != nil {
+		err = domainerr.FromDB(err, "user")
+		return
+	}
+	state.Following = true
+	return
+}
+
+func (r *FollowRepoGormImpl) Unfollow(ctx context.Context, follow followmodel.Follow) (state followmodel.FollowState, err error) {
+	logCtx := fmt.Sprintf("%T - Unfollow", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := followerCount(tx, follow.FolloweeID, clause.Locking{Strength: "UPDATE"}, &state); err != nil {
+			return err
+		}
+		res := tx.
+			Where("follower_id = ? AND followee_id = ?", follow.FollowerID, follow.FolloweeID).
+			Delete(&followmodel.Follow{})
+		if res.Error != nil || res.RowsAffected <= 0 {
+			return res.Error
+		}
+
+		err := tx.
+			Table("user").
+			Where("id = ?", follow.FolloweeID).
+			UpdateColumn("follower_count", gorm.Expr("GREATEST(follower_count - 1, 0)")).Error
+		if err != nil {
+			return err
+		}
+		if state.FollowerCount > 0 {
+			state.FollowerCount--
+		}
+		state.Changed = true
+
+		return tx.
+			Where("user_id = ? AND author_id = ?", follow.FollowerID, follow.FolloweeID).
+			Delete(&followmodel.Timeline{}).Error
+	})
+	if err != nil {
+		err = domainerr.FromDB(err, "user")
+		return
+	}
+	state.Following = false
+	return
+}
+
+func followerCount(tx *gorm.DB, userId uint64, locking clause.Locking, state *followmodel.FollowState) error {
+	var row struct {
+		FollowerCount uint64
+	}
+	err := tx.
+		Table("user").
+		Clauses(locking).
+		Select("follower_count").
+		Where("id = ? AND deleted_at IS NULL", userId).
+		Take(&row).Error
+	state.UserID = userId
+	state.FollowerCount = row.FollowerCount
+	return err
+}
+
+func (r *FollowRepoGormImpl) GetFollowers(ctx context.Context, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetFollowers", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	return r.getFollows(ctx, "followee_id", "Follower", userId, params)
+}
+
+func (r *FollowRepoGormImpl) GetFollowing(ctx context.Context, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetFollowing", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	return r.getFollows(ctx, "follower_id", "Followee", userId, params)
+}
+
+func (r *FollowRepoGormImpl) getFollows(ctx context.Context, column string, preload string, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error) {
+	err = r.db(ctx).
+		Preload(preload).
+		Where(column+" = ?", userId).
+		Scopes(params.Scope).
+		Find(&follows).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "follow")
+		return
+	}
+
+	follows, page = pagination.Paginate(follows, params, func(row followmodel.Follow) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
+}
+
+// FanOutPhoto holds a share lock on the author row, see Follow. The photo
+// is only marked fanned_out once every timeline row is written, until
+// then GetFeed reads it from photo like the photos of large accounts.
+func (r *FollowRepoGormImpl) FanOutPhoto(ctx context.Context, photoId uint64, authorId uint64, maxFollowers uint64) (fannedOut bool, err error) {
+	logCtx := fmt.Sprintf("%T - FanOutPhoto", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		var state followmodel.FollowState
+		if err := followerCount(tx, authorId, clause.Locking{Strength: "SHARE"}, &state); err != nil {
+			return err
+		}
+		if state.FollowerCount > maxFollowers {
+			return nil
+		}
+
+		err := tx.Exec(`INSERT INTO timelines (user_id, photo_id, author_id, created_at)
+			SELECT f.follower_id, p.id, p.user_id, p.created_at FROM follows AS f
+			JOIN photo AS p ON p.user_id = f.followee_id
+			WHERE p.id = ? AND f.followee_id = ?
+			ON CONFLICT DO NOTHING`,
+			photoId, authorId).Error
+		if err != nil {
+			return err
+		}
+		err = tx.
+			Table("photo").
+			Where("id = ?", photoId).
+			UpdateColumn("fanned_out", true).Error
+		fannedOut = err == nil
+		return err
+	})
+	if err != nil {
+		fannedOut = false
+		err = domainerr.FromDB(err, "user")
+	}
+	return
+}
+
+// feedQuery pages the timeline of the user on idx_timelines_user_created_at
+// and the photos that were not fanned out on idx_photo_not_fanned_out,
+// each side stops after a page and only the merged page reads photo.
+// A soft deleted photo keeps its timeline rows, they are skipped before
+// the limit so a page is never short.
+const feedQuery = `SELECT photo.* FROM (
+	(SELECT t.photo_id AS id, t.created_at FROM timelines AS t
+	JOIN photo AS p ON p.id = t.photo_id AND p.deleted_at IS NULL
+	WHERE t.user_id = @user %v
+	ORDER BY t.created_at DESC, t.photo_id DESC
+	LIMIT @limit)
+	UNION ALL
+	(SELECT p.id, p.created_at FROM follows AS f
+	JOIN photo AS p ON p.user_id = f.followee_id
+	WHERE f.follower_id = @user AND NOT p.fanned_out AND p.deleted_at IS NULL %v
+	ORDER BY p.created_at DESC, p.id DESC
+	LIMIT @limit)
+) AS feed
+JOIN photo ON photo.id = feed.id
+ORDER BY feed.created_at DESC, feed.id DESC
+LIMIT @limit`
+
+// GetFeed merges the timeline of the user with the photos that were not
+// fanned out (fan-out on read), newest first whatever params.Sort is.
+func (r *FollowRepoGormImpl) GetFeed(ctx context.Context, userId uint64, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetFeed", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	args := map[string]interface{}{
+		"user": userId,
+		// one more than the page, see pagination.Paginate
+		"limit": params.Limit + 1,
+	}
+	var timelineAfter, photoAfter string
+	if params.Cursor != nil {
+		timelineAfter = "AND (t.created_at, t.photo_id) < (@created_at, @id)"
+		photoAfter = "AND (p.created_at, p.id) < (@created_at, @id)"
+		args["created_at"] = params.Cursor.CreatedAt
+		args["id"] = params.Cursor.ID
+	}
+	err = r.db(ctx).
+		Raw(fmt.Sprintf(feedQuery, timelineAfter, photoAfter), args).
+		Scan(&photos).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "photo")
+		return
+	}
+
+	photos, page = pagination.Paginate(photos, params, func(row accountmodel.Photo) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
+}
@@ -0,0 +1,212 @@
+package follow
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	followmodel "github.com/mygram/go-account/modules/models/follow"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestFollow(t *testing.T) {
+	lockFollowee := regexp.QuoteMeta(`SELECT "follower_count" FROM "user" WHERE id = $1 AND deleted_at IS NULL LIMIT 1 FOR UPDATE`)
+	insertFollow := regexp.QuoteMeta(`INSERT INTO "follows"`)
+	bumpCount := regexp.QuoteMeta(`UPDATE "user" SET "follower_count"=follower_count + 1 WHERE id = $1`)
+	backfill := regexp.QuoteMeta(`INSERT INTO timelines (user_id, photo_id, author_id, created_at)`)
+
+	testCases := []struct {
+		desc    string
+		doMock  func(mock sqlmock.Sqlmock)
+		want    followmodel.FollowState
+		wantErr error
+	}{
+		{
+			desc: "new follow is counted and backfilled",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockFollowee).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"follower_count"}).AddRow(4))
+				mock.ExpectQuery(insertFollow).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(bumpCount).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(backfill).
+					WithArgs(1, 2, 100).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+			want: followmodel.FollowState{UserID: 2, Following: true, FollowerCount: 5, Changed: true},
+		},
+		{
+			desc: "following twice changes nothing",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockFollowee).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"follower_count"}).AddRow(5))
+				mock.ExpectQuery(insertFollow).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectCommit()
+			},
+			want: followmodel.FollowState{UserID: 2, Following: true, FollowerCount: 5},
+		},
+		{
+			desc: "followee does not exist",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockFollowee).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"follower_count"}))
+				mock.ExpectRollback()
+			},
+			wantErr: domainerr.NotFound("user"),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := FollowRepoGormImpl{
+				master: DB,
+			}
+			tC.doMock(mock)
+
+			state, err := repo.Follow(context.Background(), followmodel.Follow{FollowerID: 1, FolloweeID: 2}, 100)
+			if tC.wantErr != nil {
+				assert.EqualError(t, err, tC.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tC.want, state)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFanOutPhoto(t *testing.T) {
+	lockAuthor := regexp.QuoteMeta(`SELECT "follower_count" FROM "user" WHERE id = $1 AND deleted_at IS NULL LIMIT 1 FOR SHARE`)
+
+	testCases := []struct {
+		desc          string
+		followerCount int
+		doMock        func(mock sqlmock.Sqlmock)
+		want          bool
+	}{
+		{
+			desc:          "copied to every follower",
+			followerCount: 10,
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO timelines (user_id, photo_id, author_id, created_at)`)).
+					WithArgs(7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 10))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "photo" SET "fanned_out"=$1 WHERE id = $2`)).
+					WithArgs(true, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			desc:          "large account is left for fan-out on read",
+			followerCount: 11,
+			doMock:        func(mock sqlmock.Sqlmock) {},
+			want:          false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := FollowRepoGormImpl{
+				master: DB,
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery(lockAuthor).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"follower_count"}).AddRow(tC.followerCount))
+			tC.doMock(mock)
+			mock.ExpectCommit()
+
+			fannedOut, err := repo.FanOutPhoto(context.Background(), 7, 1, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, fannedOut)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	photoRows := func(ids ...int) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "created_at"})
+		for _, id := range ids {
+			rows.AddRow(id, 2, createdAt)
+		}
+		return rows
+	}
+
+	testCases := []struct {
+		desc     string
+		cursor   *pagination.Cursor
+		doMock   func(mock sqlmock.Sqlmock)
+		wantIds  []uint64
+		wantMore bool
+	}{
+		{
+			desc: "first page",
+			doMock: func(mock sqlmock.Sqlmock) {
+				// no cursor, no keyset condition
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT photo.* FROM (`)).
+					WithArgs(1, 3, 1, 3, 3).
+					WillReturnRows(photoRows(9, 8, 7))
+			},
+			wantIds:  []uint64{9, 8},
+			wantMore: true,
+		},
+		{
+			desc:   "next page starts after the cursor on both sides",
+			cursor: &pagination.Cursor{Sort: "-created_at", CreatedAt: createdAt, ID: 8},
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`WHERE t.user_id = $1 AND (t.created_at, t.photo_id) < ($2, $3)`)).
+					WithArgs(1, createdAt, 8, 3, 1, createdAt, 8, 3, 3).
+					WillReturnRows(photoRows(7))
+			},
+			wantIds: []uint64{7},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := FollowRepoGormImpl{
+				master: DB,
+			}
+			tC.doMock(mock)
+
+			params := pagination.Params{Limit: 2, Sort: pagination.DefaultSort, Cursor: tC.cursor}
+			photos, page, err := repo.GetFeed(context.Background(), 1, params)
+			assert.NoError(t, err)
+			var ids []uint64
+			for _, photo := range photos {
+				ids = append(ids, photo.ID)
+			}
+			assert.Equal(t, tC.wantIds, ids)
+			assert.Equal(t, tC.wantMore, page.HasMore)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/follow/follow.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	account "github.com/mygram/go-account/modules/models/account"
+	follow "github.com/mygram/go-account/modules/models/follow"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockIFollowRepo is a mock of IFollowRepo interface.
+type MockIFollowRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFollowRepoMockRecorder
+}
+
+// MockIFollowRepoMockRecorder is the mock recorder for MockIFollowRepo.
+type MockIFollowRepoMockRecorder struct {
+	mock *MockIFollowRepo
+}
+
+// NewMockIFollowRepo creates a new mock instance.
+func NewMockIFollowRepo(ctrl *gomock.Controller) *MockIFollowRepo {
+	mock := &MockIFollowRepo{ctrl: ctrl}
+	mock.recorder = &MockIFollowRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFollowRepo) EXPECT() *MockIFollowRepoMockRecorder {
+	return m.recorder
+}
+
+// Follow mocks base method.
+func (m *MockIFollowRepo) Follow(ctx context.Context, followIn follow.Follow, backfill int) (follow.FollowState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, followIn, backfill)
+	ret0, _ := ret[0].(follow.FollowState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockIFollowRepoMockRecorder) Follow(ctx, followIn, backfill interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockIFollowRepo)(nil).Follow), ctx, followIn, backfill)
+}
+
+// Unfollow mocks base method.
+func (m *MockIFollowRepo) Unfollow(ctx context.Context, followIn follow.Follow) (follow.FollowState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, followIn)
+	ret0, _ := ret[0].(follow.FollowState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockIFollowRepoMockRecorder) Unfollow(ctx, followIn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockIFollowRepo)(nil).Unfollow), ctx, followIn)
+}
+
+// GetFollowers mocks base method.
+func (m *MockIFollowRepo) GetFollowers(ctx context.Context, userId uint64, params pagination.Params) ([]follow.Follow, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowers", ctx, userId, params)
+	ret0, _ := ret[0].([]follow.Follow)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFollowers indicates an expected call of GetFollowers.
+func (mr *MockIFollowRepoMockRecorder) GetFollowers(ctx, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowers", reflect.TypeOf((*MockIFollowRepo)(nil).GetFollowers), ctx, userId, params)
+}
+
+// GetFollowing mocks base method.
+func (m *MockIFollowRepo) GetFollowing(ctx context.Context, userId uint64, params pagination.Params) ([]follow.Follow, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowing", ctx, userId, params)
+	ret0, _ := ret[0].([]follow.Follow)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFollowing indicates an expected call of GetFollowing.
+func (mr *MockIFollowRepoMockRecorder) GetFollowing(ctx, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowing", reflect.TypeOf((*MockIFollowRepo)(nil).GetFollowing), ctx, userId, params)
+}
+
+// FanOutPhoto mocks base method.
+func (m *MockIFollowRepo) FanOutPhoto(ctx context.Context, photoId, authorId, maxFollowers uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FanOutPhoto", ctx, photoId, authorId, maxFollowers)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FanOutPhoto indicates an expected call of FanOutPhoto.
+func (mr *MockIFollowRepoMockRecorder) FanOutPhoto(ctx, photoId, authorId, maxFollowers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FanOutPhoto", reflect.TypeOf((*MockIFollowRepo)(nil).FanOutPhoto), ctx, photoId, authorId, maxFollowers)
+}
+
+// GetFeed mocks base method.
+func (m *MockIFollowRepo) GetFeed(ctx context.Context, userId uint64, params pagination.Params) ([]account.Photo, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, userId, params)
+	ret0, _ := ret[0].([]account.Photo)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockIFollowRepoMockRecorder) GetFeed(ctx, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockIFollowRepo)(nil).GetFeed), ctx, userId, params)
+}
@@ -0,0 +1,15 @@
+package like
+
+import (
+	"context"
+
+	likemodel "github.com/mygram/go-account/modules/models/like"
+)
+
+type ILikeRepo interface {
+	Like(ctx context.Context, likeIn likemodel.Like) (state likemodel.LikeState, err error)
+	Unlike(ctx context.Context, likeIn likemodel.Like) (state likemodel.LikeState, err error)
+	GetLikedTargetIds(ctx context.Context, userId uint64, target likemodel.LikeTarget, targetIds []uint64) (likedIds []uint64, err error)
+	GetMaxTargetId(ctx context.Context, target likemodel.LikeTarget) (maxId uint64, err error)
+	ReconcileLikeCounts(ctx context.Context, target likemodel.LikeTarget, afterId uint64, untilId uint64) (fixed int64, err error)
+}
@@ -0,0 +1,180 @@
+package like
+
+import (
+	"context"
+	"fmt"
+
+	likemodel "github.com/mygram/go-account/modules/models/like"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LikeRepoGormImpl struct {
+	master *gorm.DB
+}
+
+func NewLikeRepoGormImpl(master *gorm.DB) ILikeRepo {
+	return &LikeRepoGormImpl{
+		master: master,
+	}
+}
+
+func (r *LikeRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, r.master)
+}
+
+// Like inserts the like and bumps the counter in the same transaction,
+// the counter only moves when the like row did not exist yet.
+func (r *LikeRepoGormImpl) Like(ctx context.Context, like likemodel.Like) (state likemodel.LikeState, err error) {
+	logCtx := fmt.Sprintf("%T - Like", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&like)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			if err := incrementLikeCount(tx, like, "like_count + 1"); err != nil {
+				return err
+			}
+			state.Changed = true
+		}
+		return likeCount(tx, like, &state)
+	})
+	if err != nil {
+		err = domainerr.FromDB(err, string(like.TargetType))
+		return
+	}
+	state.Liked = true
+	return
+}
+
+func (r *LikeRepoGormImpl) Unlike(ctx context.Context, like likemodel.Like) (state likemodel.LikeState, err error) {
+	logCtx := fmt.Sprintf("%T - Unlike", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.
+			Where("user_id = ? AND target_type = ? AND target_id = ?", like.UserID, like.TargetType, like.TargetID).
+			Delete(&likemodel.Like{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			if err := incrementLikeCount(tx, like, "GREATEST(like_count - 1, 0)"); err != nil {
+				return err
+			}
+			state.Changed = true
+		}
+		return likeCount(tx, like, &state)
+	})
+	if err != nil {
+		err = domainerr.FromDB(err, string(like.TargetType))
+		return
+	}
+	state.Liked = false
+	return
+}
+
+// incrementLikeCount is atomic in the database, concurrent likes of the
+// same target queue on the row lock instead of overwriting each other.
+func incrementLikeCount(tx *gorm.DB, like likemodel.Like, expr string) error {
+	res := tx.
+		Table(like.TargetType.Table()).
+		Where("id = ? AND deleted_at IS NULL", like.TargetID).
+		UpdateColumn("like_count", gorm.Expr(expr))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected <= 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func likeCount(tx *gorm.DB, like likemodel.Like, state *likemodel.LikeState) error {
+	var row struct {
+		LikeCount uint64
+		UserID    uint64
+	}
+	err := tx.
+		Table(like.TargetType.Table()).
+		Select("like_count", "user_id").
+		Where("id = ? AND deleted_at IS NULL", like.TargetID).
+		Take(&row).Error
+	state.TargetType = like.TargetType
+	state.TargetID = like.TargetID
+	state.LikeCount = row.LikeCount
+	state.OwnerID = row.UserID
+	return err
+}
+
+func (r *LikeRepoGormImpl) GetLikedTargetIds(ctx context.Context, userId uint64, target likemodel.LikeTarget, targetIds []uint64) (likedIds []uint64, err error) {
+	logCtx := fmt.Sprintf("%T - GetLikedTargetIds", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if len(targetIds) == 0 {
+		return
+	}
+	err = r.db(ctx).
+		Model(&likemodel.Like{}).
+		Where("user_id = ? AND target_type = ? AND target_id IN ?", userId, target, targetIds).
+		Pluck("target_id", &likedIds).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "like")
+	}
+	return
+}
+
+func (r *LikeRepoGormImpl) GetMaxTargetId(ctx context.Context, target likemodel.LikeTarget) (maxId uint64, err error) {
+	logCtx := fmt.Sprintf("%T - GetMaxTargetId", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Table(target.Table()).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&maxId).Error
+	if err != nil {
+		err = domainerr.FromDB(err, string(target))
+	}
+	return
+}
+
+// ReconcileLikeCounts sets like_count of the targets with afterId < id <= untilId
+// to the number of likes rows. The targets are locked first so a like
+// committed during the count is either counted or applied after it.
+func (r *LikeRepoGormImpl) ReconcileLikeCounts(ctx context.Context, target likemodel.LikeTarget, afterId uint64, untilId uint64) (fixed int64, err error) {
+	logCtx := fmt.Sprintf("%T - ReconcileLikeCounts", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	// the table comes from the LikeTarget enum, never from the request
+	table := target.Table()
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Exec(fmt.Sprintf(`SELECT id FROM %q WHERE id > ? AND id <= ? FOR UPDATE`, table), afterId, untilId).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Exec(fmt.Sprintf(`UPDATE %[1]q AS t SET like_count = counted.n
+			FROM (
+				SELECT t2.id, COUNT(l.id) AS n FROM %[1]q AS t2
+				LEFT JOIN likes AS l ON l.target_type = ? AND l.target_id = t2.id
+				WHERE t2.id > ? AND t2.id <= ?
+				GROUP BY t2.id
+			) AS counted
+			WHERE t.id = counted.id AND t.like_count <> counted.n`, table),
+			target, afterId, untilId)
+		fixed = res.RowsAffected
+		return res.Error
+	})
+	if err != nil {
+		err = domainerr.FromDB(err, string(target))
+	}
+	return
+}
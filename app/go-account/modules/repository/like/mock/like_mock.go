@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/like/like.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	like "github.com/mygram/go-account/modules/models/like"
+)
+
+// MockILikeRepo is a mock of ILikeRepo interface.
+type MockILikeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockILikeRepoMockRecorder
+}
+
+// MockILikeRepoMockRecorder is the mock recorder for MockILikeRepo.
+type MockILikeRepoMockRecorder struct {
+	mock *MockILikeRepo
+}
+
+// NewMockILikeRepo creates a new mock instance.
+func NewMockILikeRepo(ctrl *gomock.Controller) *MockILikeRepo {
+	mock := &MockILikeRepo{ctrl: ctrl}
+	mock.recorder = &MockILikeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILikeRepo) EXPECT() *MockILikeRepoMockRecorder {
+	return m.recorder
+}
+
+// Like mocks base method.
+func (m *MockILikeRepo) Like(ctx context.Context, likeIn like.Like) (like.LikeState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Like", ctx, likeIn)
+	ret0, _ := ret[0].(like.LikeState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Like indicates an expected call of Like.
+func (mr *MockILikeRepoMockRecorder) Like(ctx, likeIn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Like", reflect.TypeOf((*MockILikeRepo)(nil).Like), ctx, likeIn)
+}
+
+// Unlike mocks base method.
+func (m *MockILikeRepo) Unlike(ctx context.Context, likeIn like.Like) (like.LikeState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlike", ctx, likeIn)
+	ret0, _ := ret[0].(like.LikeState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unlike indicates an expected call of Unlike.
+func (mr *MockILikeRepoMockRecorder) Unlike(ctx, likeIn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlike", reflect.TypeOf((*MockILikeRepo)(nil).Unlike), ctx, likeIn)
+}
+
+// GetLikedTargetIds mocks base method.
+func (m *MockILikeRepo) GetLikedTargetIds(ctx context.Context, userId uint64, target like.LikeTarget, targetIds []uint64) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLikedTargetIds", ctx, userId, target, targetIds)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLikedTargetIds indicates an expected call of GetLikedTargetIds.
+func (mr *MockILikeRepoMockRecorder) GetLikedTargetIds(ctx, userId, target, targetIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLikedTargetIds", reflect.TypeOf((*MockILikeRepo)(nil).GetLikedTargetIds), ctx, userId, target, targetIds)
+}
+
+// GetMaxTargetId mocks base method.
+func (m *MockILikeRepo) GetMaxTargetId(ctx context.Context, target like.LikeTarget) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxTargetId", ctx, target)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxTargetId indicates an expected call of GetMaxTargetId.
+func (mr *MockILikeRepoMockRecorder) GetMaxTargetId(ctx, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxTargetId", reflect.TypeOf((*MockILikeRepo)(nil).GetMaxTargetId), ctx, target)
+}
+
+// ReconcileLikeCounts mocks base method.
+func (m *MockILikeRepo) ReconcileLikeCounts(ctx context.Context, target like.LikeTarget, afterId, untilId uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileLikeCounts", ctx, target, afterId, untilId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileLikeCounts indicates an expected call of ReconcileLikeCounts.
+func (mr *MockILikeRepoMockRecorder) ReconcileLikeCounts(ctx, target, afterId, untilId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileLikeCounts", reflect.TypeOf((*MockILikeRepo)(nil).ReconcileLikeCounts), ctx, target, afterId, untilId)
+}
@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/notification/notification.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	notification "github.com/mygram/go-account/modules/models/notification"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockINotificationRepo is a mock of INotificationRepo interface.
+type MockINotificationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotificationRepoMockRecorder
+}
+
+// MockINotificationRepoMockRecorder is the mock recorder for MockINotificationRepo.
+type MockINotificationRepoMockRecorder struct {
+	mock *MockINotificationRepo
+}
+
+// NewMockINotificationRepo creates a new mock instance.
+func NewMockINotificationRepo(ctrl *gomock.Controller) *MockINotificationRepo {
+	mock := &MockINotificationRepo{ctrl: ctrl}
+	mock.recorder = &MockINotificationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotificationRepo) EXPECT() *MockINotificationRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockINotificationRepo) Create(ctx context.Context, notification notification.Notification) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, notification)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockINotificationRepoMockRecorder) Create(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockINotificationRepo)(nil).Create), ctx, notification)
+}
+
+// GetNotification mocks base method.
+func (m *MockINotificationRepo) GetNotification(ctx context.Context, userId, notificationId uint64) (notification.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotification", ctx, userId, notificationId)
+	ret0, _ := ret[0].(notification.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotification indicates an expected call of GetNotification.
+func (mr *MockINotificationRepoMockRecorder) GetNotification(ctx, userId, notificationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockINotificationRepo)(nil).GetNotification), ctx, userId, notificationId)
+}
+
+// GetNotifications mocks base method.
+func (m *MockINotificationRepo) GetNotifications(ctx context.Context, userId uint64, unreadOnly bool, params pagination.Params) ([]notification.Notification, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, userId, unreadOnly, params)
+	ret0, _ := ret[0].([]notification.Notification)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockINotificationRepoMockRecorder) GetNotifications(ctx, userId, unreadOnly, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockINotificationRepo)(nil).GetNotifications), ctx, userId, unreadOnly, params)
+}
+
+// CountUnread mocks base method.
+func (m *MockINotificationRepo) CountUnread(ctx context.Context, userId uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockINotificationRepoMockRecorder) CountUnread(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockINotificationRepo)(nil).CountUnread), ctx, userId)
+}
+
+// MarkRead mocks base method.
+func (m *MockINotificationRepo) MarkRead(ctx context.Context, userId, notificationId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, userId, notificationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockINotificationRepoMockRecorder) MarkRead(ctx, userId, notificationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockINotificationRepo)(nil).MarkRead), ctx, userId, notificationId)
+}
+
+// MarkAllRead mocks base method.
+func (m *MockINotificationRepo) MarkAllRead(ctx context.Context, userId uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllRead", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllRead indicates an expected call of MarkAllRead.
+func (mr *MockINotificationRepoMockRecorder) MarkAllRead(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllRead", reflect.TypeOf((*MockINotificationRepo)(nil).MarkAllRead), ctx, userId)
+}
+
+// GetPreferences mocks base method.
+func (m *MockINotificationRepo) GetPreferences(ctx context.Context, userId uint64) ([]notification.Preference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userId)
+	ret0, _ := ret[0].([]notification.Preference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockINotificationRepoMockRecorder) GetPreferences(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockINotificationRepo)(nil).GetPreferences), ctx, userId)
+}
+
+// SavePreferences mocks base method.
+func (m *MockINotificationRepo) SavePreferences(ctx context.Context, prefs []notification.Preference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePreferences", ctx, prefs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePreferences indicates an expected call of SavePreferences.
+func (mr *MockINotificationRepoMockRecorder) SavePreferences(ctx, prefs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreferences", reflect.TypeOf((*MockINotificationRepo)(nil).SavePreferences), ctx, prefs)
+}
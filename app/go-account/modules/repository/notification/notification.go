@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"context"
+
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type INotificationRepo interface {
+	// Create skips the notification when the user turned its type off,
+	// notificationId is 0 then
+	Create(ctx context.Context, notification notificationmodel.Notification) (notificationId uint64, err error)
+	GetNotification(ctx context.Context, userId uint64, notificationId uint64) (notification notificationmodel.Notification, err error)
+	GetNotifications(ctx context.Context, userId uint64, unreadOnly bool, params pagination.Params) (notifications []notificationmodel.Notification, page response.Pagination, err error)
+	CountUnread(ctx context.Context, userId uint64) (count int64, err error)
+	// MarkRead is not found when the notification is not one of the user
+	MarkRead(ctx context.Context, userId uint64, notificationId uint64) (err error)
+	MarkAllRead(ctx context.Context, userId uint64) (marked int64, err error)
+	GetPreferences(ctx context.Context, userId uint64) (prefs []notificationmodel.Preference, err error)
+	SavePreferences(ctx context.Context, prefs []notificationmodel.Preference) (err error)
+}
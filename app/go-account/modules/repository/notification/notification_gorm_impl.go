@@ -0,0 +1,170 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepoGormImpl struct {
+	master *gorm.DB
+}
+
+func NewNotificationRepoGormImpl(master *gorm.DB) INotificationRepo {
+	return &NotificationRepoGormImpl{
+		master: master,
+	}
+}
+
+func (r *NotificationRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, r.master)
+}
+
+// Create checks the preference in the same statement, a type turned off
+// while the notification is written never slips through.
+func (r *NotificationRepoGormImpl) Create(ctx context.Context, notification notificationmodel.Notification) (notificationId uint64, err error) {
+	logCtx := fmt.Sprintf("%T - Create", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).Raw(`INSERT INTO notifications (user_id, actor_id, type, photo_id, comment_id)
+		SELECT ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM notification_preferences
+			WHERE user_id = ? AND type = ? AND NOT enabled)
+		RETURNING id`,
+		notification.UserID, notification.ActorID, notification.Type, notification.PhotoID, notification.CommentID,
+		notification.UserID, notification.Type).
+		Scan(&notificationId).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "notification")
+	}
+	return
+}
+
+func (r *NotificationRepoGormImpl) GetNotification(ctx context.Context, userId uint64, notificationId uint64) (notification notificationmodel.Notification, err error) {
+	logCtx := fmt.Sprintf("%T - GetNotification", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Preload("Actor").
+		Where("id = ? AND user_id = ?", notificationId, userId).
+		First(&notification).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "notification")
+	}
+	return
+}
+
+func (r *NotificationRepoGormImpl) GetNotifications(ctx context.Context, userId uint64, unreadOnly bool, params pagination.Params) (notifications []notificationmodel.Notification, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetNotifications", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	query := r.db(ctx).
+		Preload("Actor").
+		Where("user_id = ?", userId)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err = query.
+		Scopes(params.Scope).
+		Find(&notifications).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "notification")
+		return
+	}
+
+	notifications, page = pagination.Paginate(notifications, params, func(row notificationmodel.Notification) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
+}
+
+func (r *NotificationRepoGormImpl) CountUnread(ctx context.Context, userId uint64) (count int64, err error) {
+	logCtx := fmt.Sprintf("%T - CountUnread", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Model(&notificationmodel.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Count(&count).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "notification")
+	}
+	return
+}
+
+// MarkRead keeps the first read_at, marking twice is a no-op.
+func (r *NotificationRepoGormImpl) MarkRead(ctx context.Context, userId uint64, notificationId uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - MarkRead", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	tx := r.db(ctx).
+		Model(&notificationmodel.Notification{}).
+		Where("id = ? AND user_id = ?", notificationId, userId).
+		UpdateColumn("read_at", gorm.Expr("COALESCE(read_at, now())"))
+	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "notification")
+		return
+	}
+	if tx.RowsAffected <= 0 {
+		err = domainerr.NotFound("notification")
+	}
+	return
+}
+
+func (r *NotificationRepoGormImpl) MarkAllRead(ctx context.Context, userId uint64) (marked int64, err error) {
+	logCtx := fmt.Sprintf("%T - MarkAllRead", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	tx := r.db(ctx).
+		Model(&notificationmodel.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		UpdateColumn("read_at", gorm.Expr("now()"))
+	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "notification")
+		return
+	}
+	marked = tx.RowsAffected
+	return
+}
+
+func (r *NotificationRepoGormImpl) GetPreferences(ctx context.Context, userId uint64) (prefs []notificationmodel.Preference, err error) {
+	logCtx := fmt.Sprintf("%T - GetPreferences", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Where("user_id = ?", userId).
+		Find(&prefs).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "notification preference")
+	}
+	return
+}
+
+func (r *NotificationRepoGormImpl) SavePreferences(ctx context.Context, prefs []notificationmodel.Preference) (err error) {
+	logCtx := fmt.Sprintf("%T - SavePreferences", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if len(prefs) == 0 {
+		return
+	}
+	err = r.db(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+		}).
+		Create(&prefs).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "notification preference")
+	}
+	return
+}
@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestCreate(t *testing.T) {
+	photoId := uint64(7)
+	testCases := []struct {
+		desc string
+		rows *sqlmock.Rows
+		want uint64
+	}{
+		{desc: "type is on", rows: sqlmock.NewRows([]string{"id"}).AddRow(5), want: 5},
+		{desc: "type is turned off", rows: sqlmock.NewRows([]string{"id"}), want: 0},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := NotificationRepoGormImpl{
+				master: DB,
+			}
+			mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO notifications (user_id, actor_id, type, photo_id, comment_id)`)).
+				WithArgs(1, 2, notificationmodel.TYPE_LIKE, photoId, nil, 1, notificationmodel.TYPE_LIKE).
+				WillReturnRows(tC.rows)
+
+			notificationId, err := repo.Create(context.Background(), notificationmodel.Notification{
+				UserID:  1,
+				ActorID: 2,
+				Type:    notificationmodel.TYPE_LIKE,
+				PhotoID: &photoId,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, notificationId)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	markRead := regexp.QuoteMeta(`UPDATE "notifications" SET "read_at"=COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2`)
+
+	testCases := []struct {
+		desc     string
+		affected int64
+		wantErr  error
+	}{
+		{desc: "own notification", affected: 1},
+		{desc: "notification of another user", affected: 0, wantErr: domainerr.ErrNotFound},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := NotificationRepoGormImpl{
+				master: DB,
+			}
+			mock.ExpectBegin()
+			mock.ExpectExec(markRead).
+				WithArgs(9, 1).
+				WillReturnResult(sqlmock.NewResult(0, tC.affected))
+			mock.ExpectCommit()
+
+			err := repo.MarkRead(context.Background(), 1, 9)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/outbox/outbox.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	outbox "github.com/mygram/go-account/modules/models/outbox"
+)
+
+// MockIOutboxRepo is a mock of IOutboxRepo interface.
+type MockIOutboxRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOutboxRepoMockRecorder
+}
+
+// MockIOutboxRepoMockRecorder is the mock recorder for MockIOutboxRepo.
+type MockIOutboxRepoMockRecorder struct {
+	mock *MockIOutboxRepo
+}
+
+// NewMockIOutboxRepo creates a new mock instance.
+func NewMockIOutboxRepo(ctrl *gomock.Controller) *MockIOutboxRepo {
+	mock := &MockIOutboxRepo{ctrl: ctrl}
+	mock.recorder = &MockIOutboxRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOutboxRepo) EXPECT() *MockIOutboxRepoMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockIOutboxRepo) Append(ctx context.Context, events ...outbox.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Append", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockIOutboxRepoMockRecorder) Append(ctx interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockIOutboxRepo)(nil).Append), varargs...)
+}
+
+// TryLockRelay mocks base method.
+func (m *MockIOutboxRepo) TryLockRelay(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLockRelay", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLockRelay indicates an expected call of TryLockRelay.
+func (mr *MockIOutboxRepoMockRecorder) TryLockRelay(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLockRelay", reflect.TypeOf((*MockIOutboxRepo)(nil).TryLockRelay), ctx)
+}
+
+// GetUnpublished mocks base method.
+func (m *MockIOutboxRepo) GetUnpublished(ctx context.Context, limit int) ([]outbox.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnpublished", ctx, limit)
+	ret0, _ := ret[0].([]outbox.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnpublished indicates an expected call of GetUnpublished.
+func (mr *MockIOutboxRepoMockRecorder) GetUnpublished(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnpublished", reflect.TypeOf((*MockIOutboxRepo)(nil).GetUnpublished), ctx, limit)
+}
+
+// MarkPublished mocks base method.
+func (m *MockIOutboxRepo) MarkPublished(ctx context.Context, ids []uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockIOutboxRepoMockRecorder) MarkPublished(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockIOutboxRepo)(nil).MarkPublished), ctx, ids)
+}
+
+// RecordFailure mocks base method.
+func (m *MockIOutboxRepo) RecordFailure(ctx context.Context, ids []uint64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, ids, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockIOutboxRepoMockRecorder) RecordFailure(ctx, ids, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockIOutboxRepo)(nil).RecordFailure), ctx, ids, reason)
+}
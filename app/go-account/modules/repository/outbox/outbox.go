@@ -0,0 +1,20 @@
+package outbox
+
+import (
+	"context"
+
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+)
+
+// IOutboxRepo joins the transaction on ctx, Append is meant to run in
+// the transaction of the change the events are about.
+type IOutboxRepo interface {
+	Append(ctx context.Context, events ...outboxmodel.Event) (err error)
+	// TryLockRelay is held until the transaction on ctx ends, only one
+	// relay publishes at a time so the events stay in order
+	TryLockRelay(ctx context.Context) (locked bool, err error)
+	// GetUnpublished is in the order the events were written
+	GetUnpublished(ctx context.Context, limit int) (events []outboxmodel.Event, err error)
+	MarkPublished(ctx context.Context, ids []uint64) (err error)
+	RecordFailure(ctx context.Context, ids []uint64, reason string) (err error)
+}
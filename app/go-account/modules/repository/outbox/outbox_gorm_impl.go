@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"gorm.io/gorm"
+)
+
+// RELAY_LOCK_KEY is the advisory lock of the relay, "outbox" in ascii.
+const RELAY_LOCK_KEY int64 = 0x6f7574626f78
+
+type OutboxRepoGormImpl struct {
+	master *gorm.DB
+}
+
+func NewOutboxRepoGormImpl(master *gorm.DB) IOutboxRepo {
+	return &OutboxRepoGormImpl{
+		master: master,
+	}
+}
+
+func (r *OutboxRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, r.master)
+}
+
+func (r *OutboxRepoGormImpl) Append(ctx context.Context, events ...outboxmodel.Event) (err error) {
+	logCtx := fmt.Sprintf("%T - Append", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if len(events) == 0 {
+		return
+	}
+	if err = r.db(ctx).Create(&events).Error; err != nil {
+		err = domainerr.FromDB(err, "outbox event")
+	}
+	return
+}
+
+func (r *OutboxRepoGormImpl) TryLockRelay(ctx context.Context) (locked bool, err error) {
+	logCtx := fmt.Sprintf("%T - TryLockRelay", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Raw("SELECT pg_try_advisory_xact_lock(?)", RELAY_LOCK_KEY).
+		Scan(&locked).Error
+	return
+}
+
+func (r *OutboxRepoGormImpl) GetUnpublished(ctx context.Context, limit int) (events []outboxmodel.Event, err error) {
+	logCtx := fmt.Sprintf("%T - GetUnpublished", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Where("published_at IS NULL").
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "outbox event")
+	}
+	return
+}
+
+func (r *OutboxRepoGormImpl) MarkPublished(ctx context.Context, ids []uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - MarkPublished", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if len(ids) == 0 {
+		return
+	}
+	err = r.db(ctx).
+		Model(&outboxmodel.Event{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"published_at": gorm.Expr("now()"),
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   "",
+		}).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "outbox event")
+	}
+	return
+}
+
+func (r *OutboxRepoGormImpl) RecordFailure(ctx context.Context, ids []uint64, reason string) (err error) {
+	logCtx := fmt.Sprintf("%T - RecordFailure", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if len(ids) == 0 {
+		return
+	}
+	err = r.db(ctx).
+		Model(&outboxmodel.Event{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "outbox event")
+	}
+	return
+}
@@ -0,0 +1,107 @@
+package outbox
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestAppendJoinsTransaction(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	DB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	repo := OutboxRepoGormImpl{
+		master: DB,
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "photo"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	event, err := outboxmodel.NewEvent(outboxmodel.AGGREGATE_PHOTO, 3, outboxmodel.EVENT_PHOTO_UPDATED, map[string]string{"title": "new"})
+	assert.NoError(t, err)
+
+	uow := unitofwork.NewUnitOfWork(repo.master)
+	err = uow.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := unitofwork.DB(ctx, repo.master).Exec(`UPDATE "photo" SET title = 'new' WHERE id = 3`).Error; err != nil {
+			return err
+		}
+		return repo.Append(ctx, event)
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTryLockRelay(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		locked bool
+	}{
+		{desc: "no other relay", locked: true},
+		{desc: "another relay is publishing", locked: false},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := OutboxRepoGormImpl{
+				master: DB,
+			}
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_xact_lock($1)`)).
+				WithArgs(RELAY_LOCK_KEY).
+				WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(tC.locked))
+
+			locked, err := repo.TryLockRelay(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tC.locked, locked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetUnpublished(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	DB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	repo := OutboxRepoGormImpl{
+		master: DB,
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox" WHERE published_at IS NULL ORDER BY id LIMIT 2`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id"}).AddRow(1, "a").AddRow(2, "b"))
+
+	events, err := repo.GetUnpublished(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkPublished(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	DB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	repo := OutboxRepoGormImpl{
+		master: DB,
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "attempts"=attempts + 1,"last_error"=$1,"published_at"=now() WHERE id IN ($2,$3)`)).
+		WithArgs("", 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.MarkPublished(context.Background(), []uint64{1, 2}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/publisher/publisher.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	outbox "github.com/mygram/go-account/modules/models/outbox"
+)
+
+// MockIPublisher is a mock of IPublisher interface.
+type MockIPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPublisherMockRecorder
+}
+
+// MockIPublisherMockRecorder is the mock recorder for MockIPublisher.
+type MockIPublisherMockRecorder struct {
+	mock *MockIPublisher
+}
+
+// NewMockIPublisher creates a new mock instance.
+func NewMockIPublisher(ctrl *gomock.Controller) *MockIPublisher {
+	mock := &MockIPublisher{ctrl: ctrl}
+	mock.recorder = &MockIPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPublisher) EXPECT() *MockIPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockIPublisher) Publish(ctx context.Context, events ...outbox.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Publish", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockIPublisherMockRecorder) Publish(ctx interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockIPublisher)(nil).Publish), varargs...)
+}
+
+// Close mocks base method.
+func (m *MockIPublisher) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIPublisherMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIPublisher)(nil).Close))
+}
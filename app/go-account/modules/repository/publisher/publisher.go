@@ -0,0 +1,20 @@
+package publisher
+
+import (
+	"context"
+
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+)
+
+const (
+	HEADER_EVENT_ID   = "event-id"
+	HEADER_EVENT_TYPE = "event-type"
+)
+
+// IPublisher sends outbox events to the event bus. An event is keyed by
+// its aggregate so the events of one aggregate stay in order, a failed
+// Publish may have sent part of the events and is published again.
+type IPublisher interface {
+	Publish(ctx context.Context, events ...outboxmodel.Event) (err error)
+	Close() (err error)
+}
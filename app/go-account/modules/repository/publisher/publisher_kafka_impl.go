@@ -0,0 +1,65 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+	"github.com/segmentio/kafka-go"
+)
+
+// PublisherKafkaImpl publishes an event to the topic of its aggregate,
+// keyed by the aggregate so all of its events land on one partition.
+type PublisherKafkaImpl struct {
+	writer      *kafka.Writer
+	topicPrefix string
+}
+
+func NewPublisherKafkaImpl(brokers []string, topicPrefix string) IPublisher {
+	return &PublisherKafkaImpl{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Balancer: &kafka.Hash{},
+			// written to every in sync replica before it counts as published
+			RequiredAcks: kafka.RequireAll,
+			// the relay already batches, do not wait for more
+			BatchTimeout:           10 * time.Millisecond,
+			AllowAutoTopicCreation: true,
+		},
+		topicPrefix: topicPrefix,
+	}
+}
+
+func (p *PublisherKafkaImpl) Publish(ctx context.Context, events ...outboxmodel.Event) (err error) {
+	messages := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		var message kafka.Message
+		if message, err = toMessage(p.topicPrefix, event); err != nil {
+			return
+		}
+		messages = append(messages, message)
+	}
+	return p.writer.WriteMessages(ctx, messages...)
+}
+
+func (p *PublisherKafkaImpl) Close() (err error) {
+	return p.writer.Close()
+}
+
+func toMessage(topicPrefix string, event outboxmodel.Event) (message kafka.Message, err error) {
+	value, err := json.Marshal(event.Envelope())
+	if err != nil {
+		return
+	}
+	return kafka.Message{
+		Topic: topicPrefix + string(event.AggregateType),
+		Key:   []byte(event.Key()),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: HEADER_EVENT_ID, Value: []byte(event.EventID)},
+			{Key: HEADER_EVENT_TYPE, Value: []byte(event.EventType)},
+		},
+		Time: event.CreatedAt,
+	}, err
+}
@@ -0,0 +1,48 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+)
+
+const DEFAULT_RETAIN = 1000
+
+// PublisherMemoryImpl keeps the last events published in this instance,
+// it is used in tests and when no event bus is configured.
+type PublisherMemoryImpl struct {
+	mu     sync.Mutex
+	retain int
+	events []outboxmodel.Event
+}
+
+func NewPublisherMemoryImpl(retain int) *PublisherMemoryImpl {
+	if retain <= 0 {
+		retain = DEFAULT_RETAIN
+	}
+	return &PublisherMemoryImpl{
+		retain: retain,
+	}
+}
+
+func (p *PublisherMemoryImpl) Publish(ctx context.Context, events ...outboxmodel.Event) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, events...)
+	if over := len(p.events) - p.retain; over > 0 {
+		p.events = append([]outboxmodel.Event(nil), p.events[over:]...)
+	}
+	return
+}
+
+// Events are in the order they were published, oldest first.
+func (p *PublisherMemoryImpl) Events() []outboxmodel.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]outboxmodel.Event(nil), p.events...)
+}
+
+func (p *PublisherMemoryImpl) Close() (err error) {
+	return
+}
@@ -0,0 +1,58 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublisherMemory(t *testing.T) {
+	publisher := NewPublisherMemoryImpl(3)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, publisher.Publish(context.Background(), outboxmodel.Event{EventID: id}))
+	}
+
+	ids := []string{}
+	for _, event := range publisher.Events() {
+		ids = append(ids, event.EventID)
+	}
+	assert.Equal(t, []string{"b", "c", "d"}, ids, "oldest events are dropped")
+}
+
+func TestToMessage(t *testing.T) {
+	createdAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	event := outboxmodel.Event{
+		ID:            7,
+		EventID:       "6f1c5a4e-2d1b-4f5e-9a43-8f0b1e2c3d4a",
+		EventType:     outboxmodel.EVENT_PHOTO_CREATED,
+		AggregateType: outboxmodel.AGGREGATE_PHOTO,
+		AggregateID:   "3",
+		Payload:       `{"id":3}`,
+		CreatedAt:     createdAt,
+	}
+
+	message, err := toMessage("mygram.", event)
+	assert.NoError(t, err)
+	assert.Equal(t, "mygram.photo", message.Topic)
+	assert.Equal(t, "photo:3", string(message.Key))
+	assert.Equal(t, createdAt, message.Time)
+
+	headers := map[string]string{}
+	for _, header := range message.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	assert.Equal(t, map[string]string{
+		HEADER_EVENT_ID:   event.EventID,
+		HEADER_EVENT_TYPE: string(outboxmodel.EVENT_PHOTO_CREATED),
+	}, headers)
+
+	var envelope outboxmodel.Envelope
+	assert.NoError(t, json.Unmarshal(message.Value, &envelope))
+	assert.Equal(t, event.EventID, envelope.ID)
+	assert.Equal(t, "3", envelope.AggregateID)
+	assert.JSONEq(t, `{"id":3}`, string(envelope.Data))
+}
@@ -0,0 +1,146 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	"github.com/mygram/go-common/pkg/logger"
+)
+
+// events queued for a subscription when no size is configured
+const DEFAULT_BUFFER_SIZE = 64
+
+type HubMemoryImpl struct {
+	bufferSize int
+
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	topics map[realtimemodel.Topic]map[*Subscription]struct{}
+	closed bool
+}
+
+func NewHubMemoryImpl(bufferSize int) IHub {
+	return newHubMemory(bufferSize)
+}
+
+func newHubMemory(bufferSize int) *HubMemoryImpl {
+	if bufferSize <= 0 {
+		bufferSize = DEFAULT_BUFFER_SIZE
+	}
+	return &HubMemoryImpl{
+		bufferSize: bufferSize,
+		subs:       map[*Subscription]struct{}{},
+		topics:     map[realtimemodel.Topic]map[*Subscription]struct{}{},
+	}
+}
+
+func (h *HubMemoryImpl) Publish(ctx context.Context, event realtimemodel.Event) (err error) {
+	logCtx := fmt.Sprintf("%T - Publish", h)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	h.mu.RLock()
+	var slow []*Subscription
+	for sub := range h.topics[event.Topic] {
+		if !sub.send(event) {
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	// closing takes the write lock, it has to wait for the fan out
+	for _, sub := range slow {
+		logger.Info(ctx, "dropping slow subscriber",
+			"logCtx", logCtx,
+			"topic", event.Topic)
+		sub.closeWith(ErrSlowConsumer)
+	}
+	return
+}
+
+func (h *HubMemoryImpl) Subscribe(topics ...realtimemodel.Topic) (sub *Subscription) {
+	sub = &Subscription{
+		hub:    h,
+		events: make(chan realtimemodel.Event, h.bufferSize),
+		done:   make(chan struct{}),
+		topics: map[realtimemodel.Topic]struct{}{},
+	}
+	if !h.register(sub) {
+		sub.closeWith(ErrHubClosed)
+		return
+	}
+	h.add(sub, topics)
+	return
+}
+
+func (h *HubMemoryImpl) Close() {
+	h.mu.Lock()
+	h.closed = true
+	subs := make([]*Subscription, 0, len(h.subs))
+	for sub := range h.subs {
+		subs = append(subs, sub)
+	}
+	h.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.closeWith(ErrHubClosed)
+	}
+}
+
+// register is false once the hub is closed.
+func (h *HubMemoryImpl) register(sub *Subscription) (ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+	h.subs[sub] = struct{}{}
+	return true
+}
+
+func (h *HubMemoryImpl) add(sub *Subscription, topics []realtimemodel.Topic) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// a closed subscription is never put back into the topics
+	if sub.closed {
+		return
+	}
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = map[*Subscription]struct{}{}
+		}
+		h.topics[topic][sub] = struct{}{}
+		sub.topics[topic] = struct{}{}
+	}
+}
+
+func (h *HubMemoryImpl) remove(sub *Subscription, topics []realtimemodel.Topic) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, topic := range topics {
+		h.unlink(sub, topic)
+	}
+}
+
+func (h *HubMemoryImpl) removeAll(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for topic := range sub.topics {
+		h.unlink(sub, topic)
+	}
+	sub.closed = true
+	delete(h.subs, sub)
+}
+
+func (h *HubMemoryImpl) unlink(sub *Subscription, topic realtimemodel.Topic) {
+	delete(h.topics[topic], sub)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+	delete(sub.topics, topic)
+}
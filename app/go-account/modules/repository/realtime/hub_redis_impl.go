@@ -0,0 +1,76 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	c "github.com/mygram/go-common/pkg/context"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const realtimeChannelPrefix = "realtime:"
+
+// HubRedisImpl publishes through redis so every instance gets the event,
+// each instance then fans it out to its own subscriptions.
+type HubRedisImpl struct {
+	client *redis.Client
+	pubsub *redis.PubSub
+	local  *HubMemoryImpl
+}
+
+func NewHubRedisImpl(client *redis.Client, bufferSize int) IHub {
+	ctx, _ := c.GetCorrelationID(context.Background())
+	h := &HubRedisImpl{
+		client: client,
+		pubsub: client.PSubscribe(ctx, realtimeChannelPrefix+"*"),
+		local:  newHubMemory(bufferSize),
+	}
+
+	// events published before the subscription is confirmed would be lost
+	if _, err := h.pubsub.Receive(ctx); err != nil {
+		panic(err)
+	}
+	go h.relay(ctx)
+	return h
+}
+
+func (h *HubRedisImpl) Publish(ctx context.Context, event realtimemodel.Event) (err error) {
+	logCtx := fmt.Sprintf("%T - Publish", h)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	err = h.client.Publish(ctx, realtimeChannelPrefix+string(event.Topic), payload).Err()
+	return
+}
+
+func (h *HubRedisImpl) Subscribe(topics ...realtimemodel.Topic) (sub *Subscription) {
+	return h.local.Subscribe(topics...)
+}
+
+func (h *HubRedisImpl) Close() {
+	h.pubsub.Close()
+	h.local.Close()
+}
+
+// relay runs until the pubsub is closed.
+func (h *HubRedisImpl) relay(ctx context.Context) {
+	logCtx := fmt.Sprintf("%T - relay", h)
+
+	for msg := range h.pubsub.Channel() {
+		var event realtimemodel.Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			logger.Error(ctx, "error Unmarshal",
+				"logCtx", logCtx,
+				"channel", msg.Channel,
+				"error", err)
+			continue
+		}
+		h.local.Publish(ctx, event)
+	}
+}
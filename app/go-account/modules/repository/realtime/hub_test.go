@@ -0,0 +1,112 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHubs(t *testing.T, bufferSize int) map[string]IHub {
+	mr := miniredis.RunT(t)
+	return map[string]IHub{
+		"memory": NewHubMemoryImpl(bufferSize),
+		"redis":  NewHubRedisImpl(redis.NewClient(&redis.Options{Addr: mr.Addr()}), bufferSize),
+	}
+}
+
+func receive(t *testing.T, sub *Subscription) (event realtimemodel.Event, ok bool) {
+	select {
+	case event = <-sub.Events():
+		return event, true
+	case <-time.After(time.Second):
+		return
+	}
+}
+
+func assertNoEvent(t *testing.T, sub *Subscription) {
+	select {
+	case event := <-sub.Events():
+		t.Errorf("unexpected event %v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	for name, hub := range newHubs(t, 8) {
+		t.Run(name, func(t *testing.T) {
+			defer hub.Close()
+			ctx := context.Background()
+
+			alice := hub.Subscribe(realtimemodel.UserTopic(1), realtimemodel.PhotoTopic(10))
+			bob := hub.Subscribe(realtimemodel.UserTopic(2))
+
+			event, err := realtimemodel.NewEvent(realtimemodel.PhotoTopic(10), realtimemodel.EVENT_LIKE_COUNT, map[string]int{"like_count": 3})
+			assert.NoError(t, err)
+			assert.NoError(t, hub.Publish(ctx, event))
+
+			got, ok := receive(t, alice)
+			assert.True(t, ok)
+			assert.Equal(t, realtimemodel.EVENT_LIKE_COUNT, got.Type)
+			assert.Equal(t, realtimemodel.PhotoTopic(10), got.Topic)
+			assert.JSONEq(t, `{"like_count":3}`, string(got.Data))
+			assertNoEvent(t, bob)
+
+			// watching the photo is changed on the fly
+			alice.Unsubscribe(realtimemodel.PhotoTopic(10))
+			bob.Subscribe(realtimemodel.PhotoTopic(10))
+			assert.Equal(t, 1, alice.Topics())
+			assert.Equal(t, 2, bob.Topics())
+
+			assert.NoError(t, hub.Publish(ctx, event))
+			_, ok = receive(t, bob)
+			assert.True(t, ok)
+			assertNoEvent(t, alice)
+		})
+	}
+}
+
+func TestHubSlowConsumer(t *testing.T) {
+	for name, hub := range newHubs(t, 1) {
+		t.Run(name, func(t *testing.T) {
+			defer hub.Close()
+			ctx := context.Background()
+
+			slow := hub.Subscribe(realtimemodel.UserTopic(1))
+			event := realtimemodel.Event{Type: realtimemodel.EVENT_NOTIFICATION, Topic: realtimemodel.UserTopic(1), Data: []byte(`{}`)}
+			assert.NoError(t, hub.Publish(ctx, event))
+			assert.NoError(t, hub.Publish(ctx, event))
+
+			select {
+			case <-slow.Done():
+			case <-time.After(time.Second):
+				t.Fatal("slow subscriber was not closed")
+			}
+			assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+
+			// the publisher is not held back by the dropped subscriber
+			assert.NoError(t, hub.Publish(ctx, event))
+		})
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	for name, hub := range newHubs(t, 8) {
+		t.Run(name, func(t *testing.T) {
+			sub := hub.Subscribe(realtimemodel.UserTopic(1))
+			closedByClient := hub.Subscribe(realtimemodel.UserTopic(2))
+			closedByClient.Close()
+			assert.NoError(t, closedByClient.Err())
+
+			hub.Close()
+			assert.ErrorIs(t, sub.Err(), ErrHubClosed)
+
+			late := hub.Subscribe(realtimemodel.UserTopic(1))
+			assert.ErrorIs(t, late.Err(), ErrHubClosed)
+		})
+	}
+}
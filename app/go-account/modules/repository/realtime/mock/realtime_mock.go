@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/realtime/realtime.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	realtime "github.com/mygram/go-account/modules/models/realtime"
+	realtime0 "github.com/mygram/go-account/modules/repository/realtime"
+)
+
+// MockIHub is a mock of IHub interface.
+type MockIHub struct {
+	ctrl     *gomock.Controller
+	recorder *MockIHubMockRecorder
+}
+
+// MockIHubMockRecorder is the mock recorder for MockIHub.
+type MockIHubMockRecorder struct {
+	mock *MockIHub
+}
+
+// NewMockIHub creates a new mock instance.
+func NewMockIHub(ctrl *gomock.Controller) *MockIHub {
+	mock := &MockIHub{ctrl: ctrl}
+	mock.recorder = &MockIHubMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIHub) EXPECT() *MockIHubMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockIHub) Publish(ctx context.Context, event realtime.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockIHubMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockIHub)(nil).Publish), ctx, event)
+}
+
+// Subscribe mocks base method.
+func (m *MockIHub) Subscribe(topics ...realtime.Topic) *realtime0.Subscription {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range topics {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Subscribe", varargs...)
+	ret0, _ := ret[0].(*realtime0.Subscription)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockIHubMockRecorder) Subscribe(topics ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockIHub)(nil).Subscribe), topics...)
+}
+
+// Close mocks base method.
+func (m *MockIHub) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIHubMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIHub)(nil).Close))
+}
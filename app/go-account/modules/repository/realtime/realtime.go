@@ -0,0 +1,26 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+)
+
+var (
+	// ErrSlowConsumer closes a subscription that let its buffer fill up,
+	// Publish never waits for a subscriber.
+	ErrSlowConsumer = errors.New("subscriber is too slow")
+	// ErrHubClosed closes every subscription on shutdown.
+	ErrHubClosed = errors.New("hub is closed")
+)
+
+// IHub fans events out to the subscriptions of their topic. The memory hub
+// only reaches subscriptions of this instance, the redis hub reaches the
+// subscriptions of every instance.
+type IHub interface {
+	Publish(ctx context.Context, event realtimemodel.Event) (err error)
+	Subscribe(topics ...realtimemodel.Topic) (sub *Subscription)
+	// Close ends every subscription with ErrHubClosed
+	Close()
+}
@@ -0,0 +1,72 @@
+package realtime
+
+import (
+	"sync"
+
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+)
+
+// Subscription receives the events of its topics until it is closed,
+// Err tells why once Done is closed.
+type Subscription struct {
+	hub    *HubMemoryImpl
+	events chan realtimemodel.Event
+	done   chan struct{}
+	once   sync.Once
+	err    error
+
+	// guarded by hub.mu
+	topics map[realtimemodel.Topic]struct{}
+	closed bool
+}
+
+func (s *Subscription) Events() <-chan realtimemodel.Event {
+	return s.events
+}
+
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err is nil when the subscriber closed the subscription itself.
+func (s *Subscription) Err() error {
+	<-s.done
+	return s.err
+}
+
+func (s *Subscription) Subscribe(topics ...realtimemodel.Topic) {
+	s.hub.add(s, topics)
+}
+
+func (s *Subscription) Unsubscribe(topics ...realtimemodel.Topic) {
+	s.hub.remove(s, topics)
+}
+
+// Topics is how many topics the subscription receives.
+func (s *Subscription) Topics() int {
+	s.hub.mu.RLock()
+	defer s.hub.mu.RUnlock()
+	return len(s.topics)
+}
+
+func (s *Subscription) Close() {
+	s.closeWith(nil)
+}
+
+func (s *Subscription) closeWith(err error) {
+	s.once.Do(func() {
+		s.hub.removeAll(s)
+		s.err = err
+		close(s.done)
+	})
+}
+
+// send drops the event when the buffer is full, ok is false then.
+func (s *Subscription) send(event realtimemodel.Event) (ok bool) {
+	select {
+	case s.events <- event:
+		return true
+	default:
+		return false
+	}
+}
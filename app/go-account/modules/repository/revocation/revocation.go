@@ -0,0 +1,13 @@
+package revocation
+
+import (
+	"context"
+	"time"
+)
+
+// IRevocationStore keeps the jti of tokens that must not be accepted
+// anymore even though their signature is still valid.
+type IRevocationStore interface {
+	Revoke(ctx context.Context, jti string, ttl time.Duration) (err error)
+	IsRevoked(ctx context.Context, jti string) (revoked bool, err error)
+}
@@ -0,0 +1,50 @@
+package revocation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mygram/go-common/pkg/logger"
+)
+
+type RevocationStoreMemoryImpl struct {
+	mu      sync.RWMutex
+	entries map[string]time.Time
+}
+
+func NewRevocationStoreMemoryImpl() IRevocationStore {
+	return &RevocationStoreMemoryImpl{
+		entries: map[string]time.Time{},
+	}
+}
+
+func (r *RevocationStoreMemoryImpl) Revoke(ctx context.Context, jti string, ttl time.Duration) (err error) {
+	logCtx := fmt.Sprintf("%T - Revoke", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// drop expired entries so the map does not grow forever
+	now := time.Now()
+	for key, expiredAt := range r.entries {
+		if now.After(expiredAt) {
+			delete(r.entries, key)
+		}
+	}
+	r.entries[jti] = now.Add(ttl)
+	return
+}
+
+func (r *RevocationStoreMemoryImpl) IsRevoked(ctx context.Context, jti string) (revoked bool, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expiredAt, ok := r.entries[jti]
+	if !ok {
+		return
+	}
+	return time.Now().Before(expiredAt), err
+}
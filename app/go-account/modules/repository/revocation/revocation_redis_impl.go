@@ -0,0 +1,39 @@
+package revocation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const revokedKeyPrefix = "revoked:jti:"
+
+type RevocationStoreRedisImpl struct {
+	client *redis.Client
+}
+
+func NewRevocationStoreRedisImpl(client *redis.Client) IRevocationStore {
+	return &RevocationStoreRedisImpl{
+		client: client,
+	}
+}
+
+func (r *RevocationStoreRedisImpl) Revoke(ctx context.Context, jti string, ttl time.Duration) (err error) {
+	logCtx := fmt.Sprintf("%T - Revoke", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	// redis drops the key by itself once the token would have expired anyway
+	err = r.client.Set(ctx, revokedKeyPrefix+jti, 1, ttl).Err()
+	return
+}
+
+func (r *RevocationStoreRedisImpl) IsRevoked(ctx context.Context, jti string) (revoked bool, err error) {
+	count, err := r.client.Exists(ctx, revokedKeyPrefix+jti).Result()
+	if err != nil {
+		return
+	}
+	return count > 0, err
+}
@@ -0,0 +1,66 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	stores := map[string]IRevocationStore{
+		"memory": NewRevocationStoreMemoryImpl(),
+		"redis":  NewRevocationStoreRedisImpl(redis.NewClient(&redis.Options{Addr: mr.Addr()})),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			revoked, err := store.IsRevoked(ctx, "this-is-jti")
+			assert.NoError(t, err)
+			assert.False(t, revoked)
+
+			err = store.Revoke(ctx, "this-is-jti", time.Minute)
+			assert.NoError(t, err)
+
+			revoked, err = store.IsRevoked(ctx, "this-is-jti")
+			assert.NoError(t, err)
+			assert.True(t, revoked)
+
+			revoked, err = store.IsRevoked(ctx, "other-jti")
+			assert.NoError(t, err)
+			assert.False(t, revoked)
+		})
+	}
+}
+
+func TestRevocationStoreExpiry(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	t.Run("memory", func(t *testing.T) {
+		store := NewRevocationStoreMemoryImpl()
+		assert.NoError(t, store.Revoke(ctx, "this-is-jti", time.Millisecond))
+		time.Sleep(5 * time.Millisecond)
+
+		revoked, err := store.IsRevoked(ctx, "this-is-jti")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		store := NewRevocationStoreRedisImpl(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+		assert.NoError(t, store.Revoke(ctx, "this-is-jti", time.Minute))
+		mr.FastForward(2 * time.Minute)
+
+		revoked, err := store.IsRevoked(ctx, "this-is-jti")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
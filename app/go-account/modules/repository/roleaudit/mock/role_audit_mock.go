@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/roleaudit/role_audit.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	roleaudit "github.com/mygram/go-account/modules/models/roleaudit"
+)
+
+// MockIRoleAuditRepo is a mock of IRoleAuditRepo interface.
+type MockIRoleAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRoleAuditRepoMockRecorder
+}
+
+// MockIRoleAuditRepoMockRecorder is the mock recorder for MockIRoleAuditRepo.
+type MockIRoleAuditRepoMockRecorder struct {
+	mock *MockIRoleAuditRepo
+}
+
+// NewMockIRoleAuditRepo creates a new mock instance.
+func NewMockIRoleAuditRepo(ctrl *gomock.Controller) *MockIRoleAuditRepo {
+	mock := &MockIRoleAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockIRoleAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRoleAuditRepo) EXPECT() *MockIRoleAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateRoleAudit mocks base method.
+func (m *MockIRoleAuditRepo) CreateRoleAudit(ctx context.Context, audit roleaudit.RoleAudit) (roleaudit.RoleAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRoleAudit", ctx, audit)
+	ret0, _ := ret[0].(roleaudit.RoleAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRoleAudit indicates an expected call of CreateRoleAudit.
+func (mr *MockIRoleAuditRepoMockRecorder) CreateRoleAudit(ctx, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRoleAudit", reflect.TypeOf((*MockIRoleAuditRepo)(nil).CreateRoleAudit), ctx, audit)
+}
+
+// GetRoleAudits mocks base method.
+func (m *MockIRoleAuditRepo) GetRoleAudits(ctx context.Context) ([]roleaudit.RoleAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleAudits", ctx)
+	ret0, _ := ret[0].([]roleaudit.RoleAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleAudits indicates an expected call of GetRoleAudits.
+func (mr *MockIRoleAuditRepoMockRecorder) GetRoleAudits(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleAudits", reflect.TypeOf((*MockIRoleAuditRepo)(nil).GetRoleAudits), ctx)
+}
@@ -0,0 +1,12 @@
+package roleaudit
+
+import (
+	"context"
+
+	roleauditmodel "github.com/mygram/go-account/modules/models/roleaudit"
+)
+
+type IRoleAuditRepo interface {
+	CreateRoleAudit(ctx context.Context, audit roleauditmodel.RoleAudit) (created roleauditmodel.RoleAudit, err error)
+	GetRoleAudits(ctx context.Context) (audits []roleauditmodel.RoleAudit, err error)
+}
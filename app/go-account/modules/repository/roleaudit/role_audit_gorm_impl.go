@@ -0,0 +1,51 @@
+package roleaudit
+
+import (
+	"context"
+	"fmt"
+
+	roleauditmodel "github.com/mygram/go-account/modules/models/roleaudit"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"gorm.io/gorm"
+)
+
+type RoleAuditRepoGormImpl struct {
+	master *gorm.DB
+}
+
+func NewRoleAuditRepoGormImpl(master *gorm.DB) IRoleAuditRepo {
+	return &RoleAuditRepoGormImpl{
+		master: master,
+	}
+}
+
+func (r *RoleAuditRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, r.master)
+}
+
+func (r *RoleAuditRepoGormImpl) CreateRoleAudit(ctx context.Context, audit roleauditmodel.RoleAudit) (created roleauditmodel.RoleAudit, err error) {
+	logCtx := fmt.Sprintf("%T - CreateRoleAudit", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Table("role_audits").
+		Create(&audit).Error
+	if err != nil {
+		return
+	}
+
+	return audit, err
+}
+
+func (r *RoleAuditRepoGormImpl) GetRoleAudits(ctx context.Context) (audits []roleauditmodel.RoleAudit, err error) {
+	logCtx := fmt.Sprintf("%T - GetRoleAudits", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Table("role_audits").
+		Order("created_at DESC").
+		Limit(100).
+		Find(&audits).Error
+	return
+}
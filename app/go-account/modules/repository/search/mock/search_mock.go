@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/search/search.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	search "github.com/mygram/go-account/modules/models/search"
+)
+
+// MockISearchBackend is a mock of ISearchBackend interface.
+type MockISearchBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockISearchBackendMockRecorder
+}
+
+// MockISearchBackendMockRecorder is the mock recorder for MockISearchBackend.
+type MockISearchBackendMockRecorder struct {
+	mock *MockISearchBackend
+}
+
+// NewMockISearchBackend creates a new mock instance.
+func NewMockISearchBackend(ctrl *gomock.Controller) *MockISearchBackend {
+	mock := &MockISearchBackend{ctrl: ctrl}
+	mock.recorder = &MockISearchBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISearchBackend) EXPECT() *MockISearchBackendMockRecorder {
+	return m.recorder
+}
+
+// SearchUsers mocks base method.
+func (m *MockISearchBackend) SearchUsers(ctx context.Context, params search.Params) ([]search.Hit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, params)
+	ret0, _ := ret[0].([]search.Hit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockISearchBackendMockRecorder) SearchUsers(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockISearchBackend)(nil).SearchUsers), ctx, params)
+}
+
+// SearchPhotos mocks base method.
+func (m *MockISearchBackend) SearchPhotos(ctx context.Context, params search.Params) ([]search.Hit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPhotos", ctx, params)
+	ret0, _ := ret[0].([]search.Hit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPhotos indicates an expected call of SearchPhotos.
+func (mr *MockISearchBackendMockRecorder) SearchPhotos(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPhotos", reflect.TypeOf((*MockISearchBackend)(nil).SearchPhotos), ctx, params)
+}
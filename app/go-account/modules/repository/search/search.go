@@ -0,0 +1,16 @@
+package search
+
+import (
+	"context"
+
+	searchmodel "github.com/mygram/go-account/modules/models/search"
+)
+
+// ISearchBackend ranks users and photos for a query, an external engine
+// only has to return the same hits, the rows are loaded by the service.
+type ISearchBackend interface {
+	// SearchUsers matches usernames by prefix, an exact username ranks first
+	SearchUsers(ctx context.Context, params searchmodel.Params) (hits []searchmodel.Hit, err error)
+	// SearchPhotos matches the words of titles and captions, titles weigh more
+	SearchPhotos(ctx context.Context, params searchmodel.Params) (hits []searchmodel.Hit, err error)
+}
@@ -0,0 +1,136 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	searchmodel "github.com/mygram/go-account/modules/models/search"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"gorm.io/gorm"
+)
+
+// ts_headline does not escape the document, matches are delimited with
+// control characters and turned into <mark> once the rest is escaped
+const (
+	markStart = "\x02"
+	markStop  = "\x03"
+)
+
+var headlineOptions = fmt.Sprintf("StartSel=%v, StopSel=%v, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" ... \"", markStart, markStop)
+
+type SearchBackendPostgresImpl struct {
+	master *gorm.DB
+}
+
+func NewSearchBackendPostgresImpl(master *gorm.DB) ISearchBackend {
+	return &SearchBackendPostgresImpl{
+		master: master,
+	}
+}
+
+func (s *SearchBackendPostgresImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, s.master)
+}
+
+// 'simple' is the text search config of the search_vector columns, it
+// does not stem so it works the same for every language. The headline is
+// only computed for the rows of the page.
+const searchUsersSql = `
+SELECT id, rank, ts_headline('simple', translate(username, @marks, ''), query, @options) AS highlight
+FROM (
+	SELECT u.id, u.username, q.query,
+		ts_rank(u.search_vector, q.query) + CASE WHEN lower(u.username) = @exact THEN 1 ELSE 0 END AS rank
+	FROM "user" AS u, to_tsquery('simple', @query) AS q(query)
+	WHERE u.search_vector @@ q.query AND u.deleted_at IS NULL
+	ORDER BY rank DESC, u.id
+	LIMIT @limit OFFSET @offset
+) AS hits
+ORDER BY rank DESC, id`
+
+func (s *SearchBackendPostgresImpl) SearchUsers(ctx context.Context, params searchmodel.Params) (hits []searchmodel.Hit, err error) {
+	logCtx := fmt.Sprintf("%T - SearchUsers", s)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	query := prefixQuery(params.Q)
+	if query == "" {
+		return
+	}
+	err = s.db(ctx).
+		Raw(searchUsersSql, map[string]interface{}{
+			"marks":   markStart + markStop,
+			"options": headlineOptions,
+			"exact":   strings.ToLower(params.Q),
+			"query":   query,
+			"limit":   params.Limit,
+			"offset":  params.Offset,
+		}).
+		Scan(&hits).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "user")
+		return
+	}
+	markHighlights(hits)
+	return
+}
+
+const searchPhotosSql = `
+SELECT id, rank, ts_headline('simple', document, query, @options) AS highlight
+FROM (
+	SELECT p.id, p.created_at, q.query,
+		translate(concat_ws(' ', p.title, p.caption), @marks, '') AS document,
+		ts_rank_cd(p.search_vector, q.query) AS rank
+	FROM photo AS p, websearch_to_tsquery('simple', @query) AS q(query)
+	WHERE p.search_vector @@ q.query AND p.deleted_at IS NULL
+	ORDER BY rank DESC, p.created_at DESC, p.id DESC
+	LIMIT @limit OFFSET @offset
+) AS hits
+ORDER BY rank DESC, created_at DESC, id DESC`
+
+func (s *SearchBackendPostgresImpl) SearchPhotos(ctx context.Context, params searchmodel.Params) (hits []searchmodel.Hit, err error) {
+	logCtx := fmt.Sprintf("%T - SearchPhotos", s)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = s.db(ctx).
+		Raw(searchPhotosSql, map[string]interface{}{
+			"marks":   markStart + markStop,
+			"options": headlineOptions,
+			"query":   params.Q,
+			"limit":   params.Limit,
+			"offset":  params.Offset,
+		}).
+		Scan(&hits).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "photo")
+		return
+	}
+	markHighlights(hits)
+	return
+}
+
+// prefixQuery turns "ali wo" into "ali:* & wo:*", anything but letters
+// and digits separates words so the input can not break the tsquery syntax.
+func prefixQuery(q string) string {
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+func markHighlights(hits []searchmodel.Hit) {
+	for i := range hits {
+		hits[i].Highlight = toMarkedHTML(hits[i].Highlight)
+	}
+}
+
+func toMarkedHTML(headline string) string {
+	return strings.NewReplacer(markStart, "<mark>", markStop, "</mark>").
+		Replace(html.EscapeString(headline))
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	searchmodel "github.com/mygram/go-account/modules/models/search"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSearchUsers(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		q      string
+		doMock func(mock sqlmock.Sqlmock)
+		want   []searchmodel.Hit
+	}{
+		{
+			desc: "words are matched by prefix",
+			q:    "Ali_W",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`to_tsquery('simple', $4)`)).
+					WithArgs("\x02\x03", headlineOptions, "ali_w", "ali:* & w:*", 21, 40).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "rank", "highlight"}).
+						AddRow(3, 1.1, "\x02ali\x03_\x02w\x03"))
+			},
+			want: []searchmodel.Hit{{ID: 3, Rank: 1.1, Highlight: "<mark>ali</mark>_<mark>w</mark>"}},
+		},
+		{
+			desc:   "nothing to match",
+			q:      "@#!",
+			doMock: func(mock sqlmock.Sqlmock) {},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			backend := SearchBackendPostgresImpl{
+				master: DB,
+			}
+			tC.doMock(mock)
+
+			hits, err := backend.SearchUsers(context.Background(), searchmodel.Params{Q: tC.q, Limit: 21, Offset: 40})
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, hits)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSearchPhotos(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	DB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	backend := SearchBackendPostgresImpl{
+		master: DB,
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`websearch_to_tsquery('simple', $3)`)).
+		WithArgs(headlineOptions, "\x02\x03", `"golden hour" -beach`, 21, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rank", "highlight"}).
+			AddRow(7, 0.5, "<b>\x02golden\x03 \x02hour\x03</b>"))
+
+	hits, err := backend.SearchPhotos(context.Background(), searchmodel.Params{Q: `"golden hour" -beach`, Limit: 21})
+	assert.NoError(t, err)
+	// the document is escaped, only the matches are markup
+	assert.Equal(t, []searchmodel.Hit{{ID: 7, Rank: 0.5, Highlight: "&lt;b&gt;<mark>golden</mark> <mark>hour</mark>&lt;/b&gt;"}}, hits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/tag/tag.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	account "github.com/mygram/go-account/modules/models/account"
+	tag "github.com/mygram/go-account/modules/models/tag"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockITagRepo is a mock of ITagRepo interface.
+type MockITagRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockITagRepoMockRecorder
+}
+
+// MockITagRepoMockRecorder is the mock recorder for MockITagRepo.
+type MockITagRepoMockRecorder struct {
+	mock *MockITagRepo
+}
+
+// NewMockITagRepo creates a new mock instance.
+func NewMockITagRepo(ctrl *gomock.Controller) *MockITagRepo {
+	mock := &MockITagRepo{ctrl: ctrl}
+	mock.recorder = &MockITagRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITagRepo) EXPECT() *MockITagRepoMockRecorder {
+	return m.recorder
+}
+
+// SyncPhotoHashtags mocks base method.
+func (m *MockITagRepo) SyncPhotoHashtags(ctx context.Context, photoId uint64, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncPhotoHashtags", ctx, photoId, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncPhotoHashtags indicates an expected call of SyncPhotoHashtags.
+func (mr *MockITagRepoMockRecorder) SyncPhotoHashtags(ctx, photoId, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPhotoHashtags", reflect.TypeOf((*MockITagRepo)(nil).SyncPhotoHashtags), ctx, photoId, names)
+}
+
+// SyncMentions mocks base method.
+func (m *MockITagRepo) SyncMentions(ctx context.Context, source tag.Mention, usernames []string) ([]tag.Mention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncMentions", ctx, source, usernames)
+	ret0, _ := ret[0].([]tag.Mention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncMentions indicates an expected call of SyncMentions.
+func (mr *MockITagRepoMockRecorder) SyncMentions(ctx, source, usernames interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncMentions", reflect.TypeOf((*MockITagRepo)(nil).SyncMentions), ctx, source, usernames)
+}
+
+// GetPhotosByHashtag mocks base method.
+func (m *MockITagRepo) GetPhotosByHashtag(ctx context.Context, name string, params pagination.Params) ([]account.Photo, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPhotosByHashtag", ctx, name, params)
+	ret0, _ := ret[0].([]account.Photo)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPhotosByHashtag indicates an expected call of GetPhotosByHashtag.
+func (mr *MockITagRepoMockRecorder) GetPhotosByHashtag(ctx, name, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhotosByHashtag", reflect.TypeOf((*MockITagRepo)(nil).GetPhotosByHashtag), ctx, name, params)
+}
+
+// GetMentions mocks base method.
+func (m *MockITagRepo) GetMentions(ctx context.Context, userId uint64, params pagination.Params) ([]tag.Mention, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMentions", ctx, userId, params)
+	ret0, _ := ret[0].([]tag.Mention)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMentions indicates an expected call of GetMentions.
+func (mr *MockITagRepoMockRecorder) GetMentions(ctx, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMentions", reflect.TypeOf((*MockITagRepo)(nil).GetMentions), ctx, userId, params)
+}
@@ -0,0 +1,19 @@
+package tag
+
+import (
+	"context"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	tagmodel "github.com/mygram/go-account/modules/models/tag"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type ITagRepo interface {
+	// SyncPhotoHashtags links the photo to exactly names, creating the missing hashtags
+	SyncPhotoHashtags(ctx context.Context, photoId uint64, names []string) (err error)
+	// SyncMentions makes the mentions of source exactly the users named, unknown usernames are ignored
+	SyncMentions(ctx context.Context, source tagmodel.Mention, usernames []string) (added []tagmodel.Mention, err error)
+	GetPhotosByHashtag(ctx context.Context, name string, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error)
+	GetMentions(ctx context.Context, userId uint64, params pagination.Params) (mentions []tagmodel.Mention, page response.Pagination, err error)
+}
@@ -0,0 +1,190 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	tagmodel "github.com/mygram/go-account/modules/models/tag"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepoGormImpl struct {
+	master *gorm.DB
+}
+
+func NewTagRepoGormImpl(master *gorm.DB) ITagRepo {
+	return &TagRepoGormImpl{
+		master: master,
+	}
+}
+
+func (r *TagRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, r.master)
+}
+
+func (r *TagRepoGormImpl) SyncPhotoHashtags(ctx context.Context, photoId uint64, names []string) (err error) {
+	logCtx := fmt.Sprintf("%T - SyncPhotoHashtags", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		var hashtagIds []uint64
+		if len(names) > 0 {
+			hashtags := make([]tagmodel.Hashtag, 0, len(names))
+			for _, name := range names {
+				hashtags = append(hashtags, tagmodel.Hashtag{Name: name})
+			}
+			err := tx.
+				Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+				Create(&hashtags).Error
+			if err != nil {
+				return err
+			}
+			// ids of the existing ones are not returned by the insert
+			err = tx.
+				Model(&tagmodel.Hashtag{}).
+				Where("name IN ?", names).
+				Pluck("id", &hashtagIds).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		unlink := tx.Where("photo_id = ?", photoId)
+		if len(hashtagIds) > 0 {
+			unlink = unlink.Where("hashtag_id NOT IN ?", hashtagIds)
+		}
+		if err := unlink.Delete(&tagmodel.PhotoHashtag{}).Error; err != nil {
+			return err
+		}
+		if len(hashtagIds) == 0 {
+			return nil
+		}
+
+		links := make([]tagmodel.PhotoHashtag, 0, len(hashtagIds))
+		for _, id := range hashtagIds {
+			links = append(links, tagmodel.PhotoHashtag{PhotoID: photoId, HashtagID: id})
+		}
+		return tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&links).Error
+	})
+	if err != nil {
+		err = domainerr.FromDB(err, "hashtag")
+	}
+	return
+}
+
+// SyncMentions leaves the mentions that did not change alone, added is
+// only the users that were not mentioned by source before. Authors do
+// not mention themselves.
+func (r *TagRepoGormImpl) SyncMentions(ctx context.Context, source tagmodel.Mention, usernames []string) (added []tagmodel.Mention, err error) {
+	logCtx := fmt.Sprintf("%T - SyncMentions", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		var userIds []uint64
+		if len(usernames) > 0 {
+			err := tx.
+				Table("user").
+				Where("username IN ? AND id <> ? AND deleted_at IS NULL", usernames, source.AuthorID).
+				Pluck("id", &userIds).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		ofSource := func() *gorm.DB {
+			return tx.
+				Model(&tagmodel.Mention{}).
+				Where("source_type = ? AND source_id = ?", source.SourceType, source.SourceID)
+		}
+		unlink := ofSource()
+		if len(userIds) > 0 {
+			unlink = unlink.Where("user_id NOT IN ?", userIds)
+		}
+		if err := unlink.Delete(&tagmodel.Mention{}).Error; err != nil {
+			return err
+		}
+		if len(userIds) == 0 {
+			return nil
+		}
+
+		var kept []uint64
+		if err := ofSource().Pluck("user_id", &kept).Error; err != nil {
+			return err
+		}
+		existing := make(map[uint64]bool, len(kept))
+		for _, id := range kept {
+			existing[id] = true
+		}
+		for _, id := range userIds {
+			if !existing[id] {
+				mention := source
+				mention.ID = 0
+				mention.UserID = id
+				added = append(added, mention)
+			}
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		return tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&added).Error
+	})
+	if err != nil {
+		added = nil
+		err = domainerr.FromDB(err, "mention")
+	}
+	return
+}
+
+func (r *TagRepoGormImpl) GetPhotosByHashtag(ctx context.Context, name string, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetPhotosByHashtag", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Table("photo").
+		Where(`id IN (
+			SELECT ph.photo_id FROM photo_hashtags AS ph
+			JOIN hashtags AS h ON h.id = ph.hashtag_id
+			WHERE h.name = ?)`, name).
+		Scopes(params.Scope).
+		Find(&photos).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "photo")
+		return
+	}
+
+	photos, page = pagination.Paginate(photos, params, func(row accountmodel.Photo) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
+}
+
+func (r *TagRepoGormImpl) GetMentions(ctx context.Context, userId uint64, params pagination.Params) (mentions []tagmodel.Mention, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetMentions", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Where("user_id = ?", userId).
+		Scopes(params.Scope).
+		Find(&mentions).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "mention")
+		return
+	}
+
+	mentions, page = pagination.Paginate(mentions, params, func(row tagmodel.Mention) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
+}
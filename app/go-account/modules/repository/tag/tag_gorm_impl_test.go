@@ -0,0 +1,141 @@
+package tag
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	tagmodel "github.com/mygram/go-account/modules/models/tag"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSyncPhotoHashtags(t *testing.T) {
+	insertHashtags := regexp.QuoteMeta(`INSERT INTO "hashtags"`)
+	selectIds := regexp.QuoteMeta(`SELECT "id" FROM "hashtags" WHERE name IN ($1,$2)`)
+	unlink := regexp.QuoteMeta(`DELETE FROM "photo_hashtags" WHERE photo_id = $1`)
+	link := regexp.QuoteMeta(`INSERT INTO "photo_hashtags"`)
+
+	testCases := []struct {
+		desc   string
+		names  []string
+		doMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			desc:  "hashtags are created and linked",
+			names: []string{"go", "cats"},
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insertHashtags).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(selectIds).
+					WithArgs("go", "cats").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				mock.ExpectExec(unlink).
+					WithArgs(7, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(link).
+					WithArgs(7, 1, 7, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			desc: "caption without hashtags unlinks every hashtag",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(unlink).
+					WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := TagRepoGormImpl{
+				master: DB,
+			}
+			mock.ExpectBegin()
+			tC.doMock(mock)
+			mock.ExpectCommit()
+
+			err := repo.SyncPhotoHashtags(context.Background(), 7, tC.names)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSyncMentions(t *testing.T) {
+	selectUsers := regexp.QuoteMeta(`SELECT "id" FROM "user" WHERE username IN ($1,$2) AND id <> $3 AND deleted_at IS NULL`)
+	unlink := regexp.QuoteMeta(`DELETE FROM "mentions" WHERE`)
+	selectKept := regexp.QuoteMeta(`SELECT "user_id" FROM "mentions" WHERE source_type = $1 AND source_id = $2`)
+	insertMentions := regexp.QuoteMeta(`INSERT INTO "mentions"`)
+
+	source := tagmodel.Mention{AuthorID: 1, SourceType: tagmodel.SOURCE_COMMENT, SourceID: 9, PhotoID: 7}
+
+	testCases := []struct {
+		desc      string
+		usernames []string
+		doMock    func(mock sqlmock.Sqlmock)
+		want      []uint64
+	}{
+		{
+			desc:      "only new mentions are added and unknown users are ignored",
+			usernames: []string{"alice", "ghost"},
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectUsers).
+					WithArgs("alice", "ghost", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec(unlink).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(selectKept).
+					WithArgs(tagmodel.SOURCE_COMMENT, 9).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+				mock.ExpectQuery(insertMentions).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			want: []uint64{2},
+		},
+		{
+			desc:      "mentions that did not change are not added again",
+			usernames: []string{"alice", "bob"},
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectUsers).
+					WithArgs("alice", "bob", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+				mock.ExpectExec(unlink).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(selectKept).
+					WithArgs(tagmodel.SOURCE_COMMENT, 9).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := TagRepoGormImpl{
+				master: DB,
+			}
+			mock.ExpectBegin()
+			tC.doMock(mock)
+			mock.ExpectCommit()
+
+			added, err := repo.SyncMentions(context.Background(), source, tC.usernames)
+			assert.NoError(t, err)
+			var got []uint64
+			for _, mention := range added {
+				got = append(got, mention.UserID)
+			}
+			assert.Equal(t, tC.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
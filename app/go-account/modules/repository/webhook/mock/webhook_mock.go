@@ -0,0 +1,187 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/repository/webhook/webhook.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	webhook "github.com/mygram/go-account/modules/models/webhook"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockIWebhookRepo is a mock of IWebhookRepo interface.
+type MockIWebhookRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebhookRepoMockRecorder
+}
+
+// MockIWebhookRepoMockRecorder is the mock recorder for MockIWebhookRepo.
+type MockIWebhookRepoMockRecorder struct {
+	mock *MockIWebhookRepo
+}
+
+// NewMockIWebhookRepo creates a new mock instance.
+func NewMockIWebhookRepo(ctrl *gomock.Controller) *MockIWebhookRepo {
+	mock := &MockIWebhookRepo{ctrl: ctrl}
+	mock.recorder = &MockIWebhookRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebhookRepo) EXPECT() *MockIWebhookRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockIWebhookRepo) CreateWebhook(ctx context.Context, newWebhook webhook.Webhook) (webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, newWebhook)
+	ret0, _ := ret[0].(webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockIWebhookRepoMockRecorder) CreateWebhook(ctx, newWebhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockIWebhookRepo)(nil).CreateWebhook), ctx, newWebhook)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockIWebhookRepo) GetWebhooks(ctx context.Context, userId uint64) ([]webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, userId)
+	ret0, _ := ret[0].([]webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockIWebhookRepoMockRecorder) GetWebhooks(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockIWebhookRepo)(nil).GetWebhooks), ctx, userId)
+}
+
+// GetWebhook mocks base method.
+func (m *MockIWebhookRepo) GetWebhook(ctx context.Context, userId, webhookId uint64) (webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, userId, webhookId)
+	ret0, _ := ret[0].(webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockIWebhookRepoMockRecorder) GetWebhook(ctx, userId, webhookId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockIWebhookRepo)(nil).GetWebhook), ctx, userId, webhookId)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockIWebhookRepo) DeleteWebhook(ctx context.Context, userId, webhookId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, userId, webhookId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockIWebhookRepoMockRecorder) DeleteWebhook(ctx, userId, webhookId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockIWebhookRepo)(nil).DeleteWebhook), ctx, userId, webhookId)
+}
+
+// GetSubscribers mocks base method.
+func (m *MockIWebhookRepo) GetSubscribers(ctx context.Context, ownerId uint64, eventType webhook.EventType) ([]webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribers", ctx, ownerId, eventType)
+	ret0, _ := ret[0].([]webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribers indicates an expected call of GetSubscribers.
+func (mr *MockIWebhookRepoMockRecorder) GetSubscribers(ctx, ownerId, eventType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribers", reflect.TypeOf((*MockIWebhookRepo)(nil).GetSubscribers), ctx, ownerId, eventType)
+}
+
+// CreateDeliveries mocks base method.
+func (m *MockIWebhookRepo) CreateDeliveries(ctx context.Context, deliveries []webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeliveries indicates an expected call of CreateDeliveries.
+func (mr *MockIWebhookRepoMockRecorder) CreateDeliveries(ctx, deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveries", reflect.TypeOf((*MockIWebhookRepo)(nil).CreateDeliveries), ctx, deliveries)
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockIWebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockIWebhookRepoMockRecorder) ClaimDueDeliveries(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockIWebhookRepo)(nil).ClaimDueDeliveries), ctx, limit, lease)
+}
+
+// SaveAttempt mocks base method.
+func (m *MockIWebhookRepo) SaveAttempt(ctx context.Context, delivery webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttempt", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttempt indicates an expected call of SaveAttempt.
+func (mr *MockIWebhookRepoMockRecorder) SaveAttempt(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttempt", reflect.TypeOf((*MockIWebhookRepo)(nil).SaveAttempt), ctx, delivery)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockIWebhookRepo) GetDeliveries(ctx context.Context, webhookId uint64, params pagination.Params) ([]webhook.Delivery, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, webhookId, params)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockIWebhookRepoMockRecorder) GetDeliveries(ctx, webhookId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockIWebhookRepo)(nil).GetDeliveries), ctx, webhookId, params)
+}
+
+// GetDelivery mocks base method.
+func (m *MockIWebhookRepo) GetDelivery(ctx context.Context, webhookId, deliveryId uint64) (webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, webhookId, deliveryId)
+	ret0, _ := ret[0].(webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockIWebhookRepoMockRecorder) GetDelivery(ctx, webhookId, deliveryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockIWebhookRepo)(nil).GetDelivery), ctx, webhookId, deliveryId)
+}
@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type IWebhookRepo interface {
+	CreateWebhook(ctx context.Context, newWebhook webhookmodel.Webhook) (created webhookmodel.Webhook, err error)
+	GetWebhooks(ctx context.Context, userId uint64) (webhooks []webhookmodel.Webhook, err error)
+	// GetWebhook is not found when the webhook is not one of the user
+	GetWebhook(ctx context.Context, userId uint64, webhookId uint64) (webhook webhookmodel.Webhook, err error)
+	DeleteWebhook(ctx context.Context, userId uint64, webhookId uint64) (err error)
+	// GetSubscribers are the active webhooks of the owner and of the app
+	// that subscribed to the event
+	GetSubscribers(ctx context.Context, ownerId uint64, eventType webhookmodel.EventType) (webhooks []webhookmodel.Webhook, err error)
+
+	CreateDeliveries(ctx context.Context, deliveries []webhookmodel.Delivery) (err error)
+	// ClaimDueDeliveries leases pending deliveries that are due, other
+	// instances skip them until the lease runs out
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) (deliveries []webhookmodel.Delivery, err error)
+	SaveAttempt(ctx context.Context, delivery webhookmodel.Delivery) (err error)
+	GetDeliveries(ctx context.Context, webhookId uint64, params pagination.Params) (deliveries []webhookmodel.Delivery, page response.Pagination, err error)
+	GetDelivery(ctx context.Context, webhookId uint64, deliveryId uint64) (delivery webhookmodel.Delivery, err error)
+}
@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/mygram/go-common/pkg/unitofwork"
+	"gorm.io/gorm"
+)
+
+type WebhookRepoGormImpl struct {
+	master *gorm.DB
+}
+
+func NewWebhookRepoGormImpl(master *gorm.DB) IWebhookRepo {
+	return &WebhookRepoGormImpl{
+		master: master,
+	}
+}
+
+func (r *WebhookRepoGormImpl) db(ctx context.Context) *gorm.DB {
+	return unitofwork.DB(ctx, r.master)
+}
+
+func (r *WebhookRepoGormImpl) CreateWebhook(ctx context.Context, newWebhook webhookmodel.Webhook) (created webhookmodel.Webhook, err error) {
+	logCtx := fmt.Sprintf("%T - CreateWebhook", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if err = r.db(ctx).Create(&newWebhook).Error; err != nil {
+		err = domainerr.FromDB(err, "webhook")
+		return
+	}
+	return newWebhook, err
+}
+
+func (r *WebhookRepoGormImpl) GetWebhooks(ctx context.Context, userId uint64) (webhooks []webhookmodel.Webhook, err error) {
+	logCtx := fmt.Sprintf("%T - GetWebhooks", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Where("user_id = ?", userId).
+		Order("id").
+		Find(&webhooks).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook")
+	}
+	return
+}
+
+func (r *WebhookRepoGormImpl) GetWebhook(ctx context.Context, userId uint64, webhookId uint64) (webhook webhookmodel.Webhook, err error) {
+	logCtx := fmt.Sprintf("%T - GetWebhook", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Where("id = ? AND user_id = ?", webhookId, userId).
+		First(&webhook).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook")
+	}
+	return
+}
+
+// DeleteWebhook drops its delivery log with it.
+func (r *WebhookRepoGormImpl) DeleteWebhook(ctx context.Context, userId uint64, webhookId uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - DeleteWebhook", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	tx := r.db(ctx).
+		Where("id = ? AND user_id = ?", webhookId, userId).
+		Delete(&webhookmodel.Webhook{})
+	if err = tx.Error; err != nil {
+		err = domainerr.FromDB(err, "webhook")
+		return
+	}
+	if tx.RowsAffected <= 0 {
+		err = domainerr.NotFound("webhook")
+	}
+	return
+}
+
+func (r *WebhookRepoGormImpl) GetSubscribers(ctx context.Context, ownerId uint64, eventType webhookmodel.EventType) (webhooks []webhookmodel.Webhook, err error) {
+	logCtx := fmt.Sprintf("%T - GetSubscribers", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Where("active AND ? = ANY(events)", string(eventType)).
+		Where("(scope = ? AND user_id = ?) OR scope = ?", webhookmodel.SCOPE_USER, ownerId, webhookmodel.SCOPE_APP).
+		Order("id").
+		Find(&webhooks).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook")
+	}
+	return
+}
+
+func (r *WebhookRepoGormImpl) CreateDeliveries(ctx context.Context, deliveries []webhookmodel.Delivery) (err error) {
+	logCtx := fmt.Sprintf("%T - CreateDeliveries", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	if len(deliveries) == 0 {
+		return
+	}
+	err = r.db(ctx).
+		Omit("Webhook").
+		Create(&deliveries).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook delivery")
+	}
+	return
+}
+
+// ClaimDueDeliveries pushes next_attempt_at of the claimed rows past the
+// lease, a delivery of an instance that died is picked up once it ends.
+func (r *WebhookRepoGormImpl) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) (deliveries []webhookmodel.Delivery, err error) {
+	logCtx := fmt.Sprintf("%T - ClaimDueDeliveries", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	var ids []uint64
+	err = r.db(ctx).Raw(`UPDATE webhook_deliveries
+		SET next_attempt_at = now() + make_interval(secs => ?)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id`,
+		lease.Seconds(), webhookmodel.DELIVERY_PENDING, limit).
+		Scan(&ids).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook delivery")
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	err = r.db(ctx).
+		Preload("Webhook").
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&deliveries).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook delivery")
+	}
+	return
+}
+
+func (r *WebhookRepoGormImpl) SaveAttempt(ctx context.Context, delivery webhookmodel.Delivery) (err error) {
+	logCtx := fmt.Sprintf("%T - SaveAttempt", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Model(&webhookmodel.Delivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":           delivery.Status,
+			"attempts":         delivery.Attempts,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+			"delivered_at":     delivery.DeliveredAt,
+		}).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook delivery")
+	}
+	return
+}
+
+func (r *WebhookRepoGormImpl) GetDeliveries(ctx context.Context, webhookId uint64, params pagination.Params) (deliveries []webhookmodel.Delivery, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetDeliveries", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Where("webhook_id = ?", webhookId).
+		Scopes(params.Scope).
+		Find(&deliveries).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook delivery")
+		return
+	}
+
+	deliveries, page = pagination.Paginate(deliveries, params, func(row webhookmodel.Delivery) (uint64, time.Time) {
+		return row.ID, row.CreatedAt
+	})
+	return
+}
+
+func (r *WebhookRepoGormImpl) GetDelivery(ctx context.Context, webhookId uint64, deliveryId uint64) (delivery webhookmodel.Delivery, err error) {
+	logCtx := fmt.Sprintf("%T - GetDelivery", r)
+	logger.Info(ctx, "%v invoked", "logCtx", logCtx)
+
+	err = r.db(ctx).
+		Where("id = ? AND webhook_id = ?", deliveryId, webhookId).
+		First(&delivery).Error
+	if err != nil {
+		err = domainerr.FromDB(err, "webhook delivery")
+	}
+	return
+}
@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestDeleteWebhook(t *testing.T) {
+	deleteWebhook := regexp.QuoteMeta(`DELETE FROM "webhooks" WHERE id = $1 AND user_id = $2`)
+
+	testCases := []struct {
+		desc     string
+		affected int64
+		wantErr  error
+	}{
+		{desc: "own webhook", affected: 1},
+		{desc: "webhook of another user", affected: 0, wantErr: domainerr.ErrNotFound},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			DB, _ := gorm.Open(postgres.New(postgres.Config{
+				Conn: db,
+			}), &gorm.Config{})
+			repo := WebhookRepoGormImpl{
+				master: DB,
+			}
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteWebhook).
+				WithArgs(4, 1).
+				WillReturnResult(sqlmock.NewResult(0, tC.affected))
+			mock.ExpectCommit()
+
+			err := repo.DeleteWebhook(context.Background(), 1, 4)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetSubscribers(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	DB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	repo := WebhookRepoGormImpl{
+		master: DB,
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhooks" WHERE (active AND $1 = ANY(events)) AND ((scope = $2 AND user_id = $3) OR scope = $4) ORDER BY id`)).
+		WithArgs(string(webhookmodel.EVENT_PHOTO_CREATED), webhookmodel.SCOPE_USER, 1, webhookmodel.SCOPE_APP).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "scope"}).
+			AddRow(2, 1, webhookmodel.SCOPE_USER).
+			AddRow(3, 9, webhookmodel.SCOPE_APP))
+
+	webhooks, err := repo.GetSubscribers(context.Background(), 1, webhookmodel.EVENT_PHOTO_CREATED)
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimDueDeliveries(t *testing.T) {
+	claim := regexp.QuoteMeta(`UPDATE webhook_deliveries`)
+
+	t.Run("nothing is due", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		DB, _ := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		repo := WebhookRepoGormImpl{
+			master: DB,
+		}
+		mock.ExpectQuery(claim).
+			WithArgs(float64(60), webhookmodel.DELIVERY_PENDING, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		deliveries, err := repo.ClaimDueDeliveries(context.Background(), 10, time.Minute)
+		assert.NoError(t, err)
+		assert.Empty(t, deliveries)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("claimed deliveries come with their webhook", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		DB, _ := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		repo := WebhookRepoGormImpl{
+			master: DB,
+		}
+		mock.ExpectQuery(claim).
+			WithArgs(float64(60), webhookmodel.DELIVERY_PENDING, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE id IN ($1) ORDER BY id`)).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id"}).AddRow(5, 2))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhooks" WHERE "webhooks"."id" = $1`)).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url"}).AddRow(2, "http://example.com/hook"))
+
+		deliveries, err := repo.ClaimDueDeliveries(context.Background(), 10, time.Minute)
+		assert.NoError(t, err)
+		if assert.Len(t, deliveries, 1) && assert.NotNil(t, deliveries[0].Webhook) {
+			assert.Equal(t, "http://example.com/hook", deliveries[0].Webhook.URL)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -3,19 +3,26 @@ package account
 import (
 	"github.com/gin-gonic/gin"
 	accounthandler "github.com/mygram/go-account/modules/handler/account"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
 	"github.com/mygram/go-account/pkg/middleware"
+	"github.com/mygram/go-account/pkg/upload"
 )
 
-func NewAccountRouter(v1 *gin.RouterGroup, accountHdl accounthandler.IAccountHandler) {
+func NewAccountRouter(v1 *gin.RouterGroup, accountHdl accounthandler.IAccountHandler, revocationStore revocationrepo.IRevocationStore, uploadPolicy upload.Policy) {
+	// deprecated, accounts are users now, kept until clients move to /user
 	gAccount := v1.Group("/account")
 
 	// register all router
 	gAccount.POST("",
+		middleware.Deprecated("/api/v1/user/register"),
 		accountHdl.CreateAccount)
 	gAccount.POST("/login",
+		middleware.Deprecated("/api/v1/user/login"),
 		accountHdl.LoginAccount)
 	gAccount.GET("",
-		middleware.BearerOAuth(),
+		middleware.Deprecated("/api/v1/user"),
+		middleware.BearerOAuth(revocationStore),
 		accountHdl.GetAccount)
 
 	
@@ -23,43 +30,72 @@ func NewAccountRouter(v1 *gin.RouterGroup, accountHdl accounthandler.IAccountHan
 
 	gUser.POST("/register", accountHdl.RegisterUserHdl)
 	gUser.POST("/login", accountHdl.LoginUserHdl)
+	gUser.POST("/token/refresh", accountHdl.RefreshTokenHdl)
+	gUser.POST("/logout",
+		middleware.BearerOAuth(revocationStore),
+		accountHdl.LogoutUserHdl)
+	gUser.POST("/logout/all",
+		middleware.BearerOAuth(revocationStore),
+		accountHdl.LogoutAllUserHdl)
 	gUser.GET("",
-		middleware.BearerOAuth(),
+		middleware.BearerOAuth(revocationStore),
 		accountHdl.GetUser)
 
 	gPhoto := v1.Group("/photo")
 
-	gPhoto.GET("/all", accountHdl.GetAllPhotos)
-	gPhoto.GET("", accountHdl.GetPhotoById)
+	// reads are public, a token only adds liked_by_me
+
+	gPhoto.GET("/all", middleware.OptionalBearerOAuth(revocationStore), accountHdl.GetAllPhotos)
+	gPhoto.GET("", middleware.OptionalBearerOAuth(revocationStore), accountHdl.GetPhotoById)
+	gPhoto.GET("/:id/comments", middleware.OptionalBearerOAuth(revocationStore), accountHdl.GetPhotoComments)
+	// multipart with the photo file
 	gPhoto.POST("", 
-		middleware.BearerOAuth(), accountHdl.CreatePhoto)
+		middleware.BearerOAuth(revocationStore),
+		middleware.MaxBodySize(uploadPolicy.MaxBodySize()),
+		accountHdl.CreatePhoto)
 	gPhoto.PUT("/:id", 
-		middleware.BearerOAuth(), accountHdl.UpdatePhoto)
+		middleware.BearerOAuth(revocationStore), accountHdl.UpdatePhoto)
 	gPhoto.DELETE("/:id", 
-		middleware.BearerOAuth(), accountHdl.DeletePhoto)
+		middleware.BearerOAuth(revocationStore), accountHdl.DeletePhoto)
 
 		
 	gComment := v1.Group("/comment")
 
-	gComment.GET("/all", accountHdl.GetAllComments)
-	gComment.GET("", accountHdl.GetCommentById)
+	gComment.GET("/all", middleware.OptionalBearerOAuth(revocationStore), accountHdl.GetAllComments)
+	gComment.GET("", middleware.OptionalBearerOAuth(revocationStore), accountHdl.GetCommentById)
+	gComment.GET("/:id/replies", middleware.OptionalBearerOAuth(revocationStore), accountHdl.GetCommentReplies)
 	gComment.POST("", 
-		middleware.BearerOAuth(), accountHdl.CreateComment)
+		middleware.BearerOAuth(revocationStore), accountHdl.CreateComment)
 	gComment.PUT("/:id", 
-		middleware.BearerOAuth(), accountHdl.UpdateComment)
+		middleware.BearerOAuth(revocationStore), accountHdl.UpdateComment)
 	gComment.DELETE("/:id", 
-		middleware.BearerOAuth(), accountHdl.DeleteComment)
+		middleware.BearerOAuth(revocationStore), accountHdl.DeleteComment)
 		
 	gSocialMedia := v1.Group("/socmed")
 
 	gSocialMedia.GET("/all", accountHdl.GetAllSocialMedias)
 	gSocialMedia.GET("", accountHdl.GetSocialMediaById)
 	gSocialMedia.POST("", 
-		middleware.BearerOAuth(), accountHdl.CreateSocialMedia)
+		middleware.BearerOAuth(revocationStore), accountHdl.CreateSocialMedia)
 	gSocialMedia.PUT("/:id", 
-		middleware.BearerOAuth(), accountHdl.UpdateSocialMedia)
+		middleware.BearerOAuth(revocationStore), accountHdl.UpdateSocialMedia)
 	gSocialMedia.DELETE("/:id", 
-		middleware.BearerOAuth(), accountHdl.DeleteSocialMedia)
+		middleware.BearerOAuth(revocationStore), accountHdl.DeleteSocialMedia)
+
+	// admin can manage content of any user, ownership check is skipped
+	// by the handlers for roles with PERMISSION_MANAGE_ANY_CONTENT
+	gAdmin := v1.Group("/admin",
+		middleware.BearerOAuth(revocationStore),
+		middleware.RequireRole(accountmodel.ROLE_ADMIN))
+
+	gAdmin.PUT("/photo/:id", accountHdl.UpdatePhoto)
+	gAdmin.DELETE("/photo/:id", accountHdl.DeletePhoto)
+	gAdmin.PUT("/comment/:id", accountHdl.UpdateComment)
+	gAdmin.DELETE("/comment/:id", accountHdl.DeleteComment)
+	gAdmin.PUT("/socmed/:id", accountHdl.UpdateSocialMedia)
+	gAdmin.DELETE("/socmed/:id", accountHdl.DeleteSocialMedia)
+	gAdmin.PUT("/user/:id/role", accountHdl.UpdateUserRoleHdl)
+	gAdmin.GET("/audit/roles", accountHdl.GetRoleAuditsHdl)
 	// register all router
 	// gUser.GET("/all", accountHdl.FindAllUsersHdl)
 	// gUser.GET("", accountHdl.FindUserByIdHdl)
@@ -0,0 +1,12 @@
+package feed
+
+import (
+	"github.com/gin-gonic/gin"
+	feedhandler "github.com/mygram/go-account/modules/handler/feed"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/middleware"
+)
+
+func NewFeedRouter(v1 *gin.RouterGroup, feedHdl feedhandler.IFeedHandler, revocationStore revocationrepo.IRevocationStore) {
+	v1.GET("/feed", middleware.BearerOAuth(revocationStore), feedHdl.GetFeed)
+}
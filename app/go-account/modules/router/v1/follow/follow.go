@@ -0,0 +1,19 @@
+package follow
+
+import (
+	"github.com/gin-gonic/gin"
+	followhandler "github.com/mygram/go-account/modules/handler/follow"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/middleware"
+)
+
+func NewFollowRouter(v1 *gin.RouterGroup, followHdl followhandler.IFollowHandler, revocationStore revocationrepo.IRevocationStore) {
+	gUser := v1.Group("/user")
+
+	gUser.POST("/:id/follow",
+		middleware.BearerOAuth(revocationStore), followHdl.Follow)
+	gUser.DELETE("/:id/follow",
+		middleware.BearerOAuth(revocationStore), followHdl.Unfollow)
+	gUser.GET("/:id/followers", followHdl.GetFollowers)
+	gUser.GET("/:id/following", followHdl.GetFollowing)
+}
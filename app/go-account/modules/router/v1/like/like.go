@@ -0,0 +1,18 @@
+package like
+
+import (
+	"github.com/gin-gonic/gin"
+	likehandler "github.com/mygram/go-account/modules/handler/like"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/middleware"
+)
+
+func NewLikeRouter(v1 *gin.RouterGroup, likeHdl likehandler.ILikeHandler, revocationStore revocationrepo.IRevocationStore) {
+	gPhoto := v1.Group("/photo", middleware.BearerOAuth(revocationStore))
+	gPhoto.PUT("/:id/like", likeHdl.LikePhoto)
+	gPhoto.DELETE("/:id/like", likeHdl.UnlikePhoto)
+
+	gComment := v1.Group("/comment", middleware.BearerOAuth(revocationStore))
+	gComment.PUT("/:id/like", likeHdl.LikeComment)
+	gComment.DELETE("/:id/like", likeHdl.UnlikeComment)
+}
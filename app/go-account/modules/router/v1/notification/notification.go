@@ -0,0 +1,18 @@
+package notification
+
+import (
+	"github.com/gin-gonic/gin"
+	notificationhandler "github.com/mygram/go-account/modules/handler/notification"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/middleware"
+)
+
+func NewNotificationRouter(v1 *gin.RouterGroup, notificationHdl notificationhandler.INotificationHandler, revocationStore revocationrepo.IRevocationStore) {
+	gNotification := v1.Group("/notifications", middleware.BearerOAuth(revocationStore))
+
+	gNotification.GET("", notificationHdl.GetNotifications)
+	gNotification.POST("/read-all", notificationHdl.MarkAllRead)
+	gNotification.POST("/:id/read", notificationHdl.MarkRead)
+	gNotification.GET("/preferences", notificationHdl.GetPreferences)
+	gNotification.PUT("/preferences", notificationHdl.UpdatePreferences)
+}
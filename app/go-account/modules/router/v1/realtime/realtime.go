@@ -0,0 +1,15 @@
+package realtime
+
+import (
+	"github.com/gin-gonic/gin"
+	realtimehandler "github.com/mygram/go-account/modules/handler/realtime"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/middleware"
+)
+
+func NewRealtimeRouter(v1 *gin.RouterGroup, realtimeHdl realtimehandler.IRealtimeHandler, revocationStore revocationrepo.IRevocationStore) {
+	gRealtime := v1.Group("/realtime", middleware.StreamBearerOAuth(revocationStore))
+
+	gRealtime.GET("/ws", realtimeHdl.WebSocket)
+	gRealtime.GET("/sse", realtimeHdl.EventStream)
+}
@@ -0,0 +1,13 @@
+package search
+
+import (
+	"github.com/gin-gonic/gin"
+	searchhandler "github.com/mygram/go-account/modules/handler/search"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/middleware"
+)
+
+func NewSearchRouter(v1 *gin.RouterGroup, searchHdl searchhandler.ISearchHandler, revocationStore revocationrepo.IRevocationStore) {
+	v1.GET("/search",
+		middleware.OptionalBearerOAuth(revocationStore), searchHdl.Search)
+}
@@ -0,0 +1,14 @@
+package tag
+
+import (
+	"github.com/gin-gonic/gin"
+	taghandler "github.com/mygram/go-account/modules/handler/tag"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/middleware"
+)
+
+func NewTagRouter(v1 *gin.RouterGroup, tagHdl taghandler.ITagHandler, revocationStore revocationrepo.IRevocationStore) {
+	v1.GET("/tag/:name/photos",
+		middleware.OptionalBearerOAuth(revocationStore), tagHdl.GetTagPhotos)
+	v1.GET("/user/:id/mentions", tagHdl.GetUserMentions)
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"github.com/gin-gonic/gin"
+	webhookhandler "github.com/mygram/go-account/modules/handler/webhook"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/middleware"
+)
+
+func NewWebhookRouter(v1 *gin.RouterGroup, webhookHdl webhookhandler.IWebhookHandler, revocationStore revocationrepo.IRevocationStore) {
+	gWebhook := v1.Group("/webhooks", middleware.BearerOAuth(revocationStore))
+
+	gWebhook.POST("", webhookHdl.CreateWebhook)
+	gWebhook.POST("/app", middleware.RequirePermission(accountmodel.PERMISSION_MANAGE_APP_WEBHOOKS), webhookHdl.CreateAppWebhook)
+	gWebhook.GET("", webhookHdl.GetWebhooks)
+	gWebhook.DELETE("/:id", webhookHdl.DeleteWebhook)
+	gWebhook.GET("/:id/deliveries", webhookHdl.GetDeliveries)
+	gWebhook.POST("/:id/deliveries/:deliveryId/replay", webhookHdl.ReplayDelivery)
+}
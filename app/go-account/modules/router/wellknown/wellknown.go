@@ -0,0 +1,14 @@
+package wellknown
+
+import (
+	"github.com/gin-gonic/gin"
+	accounthandler "github.com/mygram/go-account/modules/handler/account"
+)
+
+func NewWellKnownRouter(r gin.IRouter, accountHdl accounthandler.IAccountHandler) {
+	gWellKnown := r.Group("/.well-known")
+
+	// register all router
+	gWellKnown.GET("/jwks.json",
+		accountHdl.GetJWKS)
+}
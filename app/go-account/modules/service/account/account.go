@@ -2,9 +2,13 @@ package account
 
 import (
 	"context"
+	"io"
 
 	accountmodel "github.com/mygram/go-account/modules/models/account"
+	roleauditmodel "github.com/mygram/go-account/modules/models/roleaudit"
 	token "github.com/mygram/go-account/modules/models/token"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
 )
 
 type IAccountService interface {
@@ -13,22 +17,32 @@ type IAccountService interface {
 	GetAccount(ctx context.Context, userId string) (account accountmodel.AccountResponse, err error)
 
 	LoginUser(ctx context.Context, loginAcc accountmodel.LoginUser) (tokens token.Tokens, err error)
+	RefreshUserToken(ctx context.Context, refreshToken string) (tokens token.Tokens, err error)
+	LogoutUser(ctx context.Context, userId string, jti string, all bool) (err error)
 	RegisterUser(ctx context.Context, acc accountmodel.RegisterUser) (created accountmodel.UserRegisterResponse, err error)
 	GetUser(ctx context.Context, userId string) (user accountmodel.User, err error)
+	BootstrapAdmin(ctx context.Context, acc accountmodel.RegisterUser) (created accountmodel.UserRegisterResponse, err error)
+	UpdateUserRole(ctx context.Context, actorId string, targetId uint64, req accountmodel.UpdateUserRole) (user accountmodel.User, err error)
+	GetRoleAudits(ctx context.Context) (audits []roleauditmodel.RoleAudit, err error)
 
-	GetAllPhotos(ctx context.Context) (photos []accountmodel.Photo, err error)
+	GetAllPhotos(ctx context.Context, filter accountmodel.PhotoFilter, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error)
 	GetPhotoById(ctx context.Context, photoId uint64) (photo accountmodel.Photo, err error)
-	CreatePhoto(ctx context.Context, acc accountmodel.Photo) (photo accountmodel.Photo, err error)
+	UploadPhoto(ctx context.Context, acc accountmodel.Photo, file io.Reader, size int64) (photo accountmodel.Photo, err error)
 	UpdatePhoto(ctx context.Context, acc accountmodel.Photo) (photo accountmodel.Photo, err error)
 	DeletePhoto(ctx context.Context, photoId uint64) (photo accountmodel.Photo, err error)
 
-	GetAllComments(ctx context.Context) (comments []accountmodel.Comment, err error)
+	// GetAllComments is the top level comments, replies are left out
+	GetAllComments(ctx context.Context, filter accountmodel.CommentFilter, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error)
 	GetCommentById(ctx context.Context, commentId uint64) (comment accountmodel.Comment, err error)
+	// GetPhotoComments is the top level comments of a photo, each with its first replies
+	GetPhotoComments(ctx context.Context, photoId uint64, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error)
+	// GetCommentReplies is the direct replies of a comment, each with its first replies
+	GetCommentReplies(ctx context.Context, commentId uint64, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error)
 	CreateComment(ctx context.Context, com accountmodel.Comment) (comment accountmodel.Comment, err error)
 	UpdateComment(ctx context.Context, com accountmodel.Comment) (comment accountmodel.Comment, err error)
 	DeleteComment(ctx context.Context, commentId uint64) (comment accountmodel.Comment, err error)
 	
-	GetAllSocialMedias(ctx context.Context) (socialMedia []accountmodel.SocialMedia, err error)
+	GetAllSocialMedias(ctx context.Context, filter accountmodel.SocialMediaFilter, params pagination.Params) (socialMedias []accountmodel.SocialMedia, page response.Pagination, err error)
 	GetSocialMediaById(ctx context.Context, socialMediaId uint64) (socialMedia accountmodel.SocialMedia, err error)
 	CreateSocialMedia(ctx context.Context, soc accountmodel.SocialMedia) (socialMedia accountmodel.SocialMedia, err error)
 	UpdateSocialMedia(ctx context.Context, soc accountmodel.SocialMedia) (socialMedia accountmodel.SocialMedia, err error)
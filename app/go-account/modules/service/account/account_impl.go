@@ -1,38 +1,141 @@
 package account
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mygram/go-common/config"
+	"github.com/mygram/go-common/pkg/domainerr"
 	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/mygram/go-common/pkg/unitofwork"
 
 	accountmodel "github.com/mygram/go-account/modules/models/account"
 	"github.com/mygram/go-account/modules/models/accountactivity"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
+	roleauditmodel "github.com/mygram/go-account/modules/models/roleaudit"
 	token "github.com/mygram/go-account/modules/models/token"
 	accountrepo "github.com/mygram/go-account/modules/repository/account"
 	activityrepo "github.com/mygram/go-account/modules/repository/accountactivity"
+	blobrepo "github.com/mygram/go-account/modules/repository/blob"
+	outboxrepo "github.com/mygram/go-account/modules/repository/outbox"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	roleauditrepo "github.com/mygram/go-account/modules/repository/roleaudit"
+	feedsvc "github.com/mygram/go-account/modules/service/feed"
+	notificationsvc "github.com/mygram/go-account/modules/service/notification"
+	realtimesvc "github.com/mygram/go-account/modules/service/realtime"
+	photoprocessingsvc "github.com/mygram/go-account/modules/service/photoprocessing"
+	tagsvc "github.com/mygram/go-account/modules/service/tag"
+	webhooksvc "github.com/mygram/go-account/modules/service/webhook"
 	crypto "github.com/mygram/go-account/pkg/crypto"
+	"github.com/mygram/go-account/pkg/imaging"
+	"github.com/mygram/go-account/pkg/upload"
+)
+
+const (
+	idTokenTTL      = 24 * time.Hour
+	accessTokenTTL  = 20 * time.Minute
+	refreshTokenTTL = time.Hour
+)
+
+var (
+	ErrInvalidCredential   = domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_INVALID_CREDENTIALS, "invalid username or password")
+	ErrInvalidRefreshToken = domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "invalid refresh token")
+	ErrRefreshTokenReused  = domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_REFRESH_TOKEN_REUSED, "refresh token reuse detected")
+	ErrAdminAlreadyExists  = domainerr.New(domainerr.KIND_CONFLICT, response.CODE_ALREADY_EXISTS, "admin already exists")
+	ErrForbiddenRoleGrant  = domainerr.New(domainerr.KIND_FORBIDDEN, response.CODE_ROLE_NOT_ALLOWED, "only admin can grant roles")
+	ErrInvalidRole         = domainerr.Validation("invalid role")
+	ErrReplyOtherPhoto     = domainerr.Validation("a reply must be on the photo of its parent")
+)
+
+const (
+	DEFAULT_COMMENT_MAX_DEPTH = 2
+	// replies shown with every comment of a list
+	REPLY_PREVIEW_SIZE = 2
 )
 
 type AccountServiceImpl struct {
-	accountRepo  accountrepo.IAccountRepo
-	activityRepo activityrepo.IAccountActivityRepo
+	accountRepo     accountrepo.IAccountRepo
+	activityRepo    activityrepo.IAccountActivityRepo
+	revocationStore revocationrepo.IRevocationStore
+	roleAuditRepo   roleauditrepo.IRoleAuditRepo
+	uow             unitofwork.UnitOfWorkInterface
+	outboxRepo      outboxrepo.IOutboxRepo
+	photoStore      blobrepo.IBlobStore
+	uploadPolicy    upload.Policy
+	photoProcessing photoprocessingsvc.IPhotoProcessingService
+	feedSvc         feedsvc.IFeedService
+	tagSvc          tagsvc.ITagService
+	notificationSvc notificationsvc.INotificationService
+	realtimeSvc     realtimesvc.IRealtimeService
+	webhookSvc      webhooksvc.IWebhookService
+	commentConf     config.CommentConfig
 }
 
 func NewAccountServiceImpl(
 	accountRepo accountrepo.IAccountRepo,
 	activityRepo activityrepo.IAccountActivityRepo,
+	revocationStore revocationrepo.IRevocationStore,
+	roleAuditRepo roleauditrepo.IRoleAuditRepo,
+	uow unitofwork.UnitOfWorkInterface,
+	outboxRepo outboxrepo.IOutboxRepo,
+	photoStore blobrepo.IBlobStore,
+	uploadPolicy upload.Policy,
+	photoProcessing photoprocessingsvc.IPhotoProcessingService,
+	feedSvc feedsvc.IFeedService,
+	tagSvc tagsvc.ITagService,
+	notificationSvc notificationsvc.INotificationService,
+	realtimeSvc realtimesvc.IRealtimeService,
+	webhookSvc webhooksvc.IWebhookService,
+	commentConf config.CommentConfig,
 ) IAccountService {
+	if commentConf.MaxDepth == 0 {
+		commentConf.MaxDepth = DEFAULT_COMMENT_MAX_DEPTH
+	}
 	return &AccountServiceImpl{
-		accountRepo:  accountRepo,
-		activityRepo: activityRepo,
+		accountRepo:     accountRepo,
+		activityRepo:    activityRepo,
+		revocationStore: revocationStore,
+		roleAuditRepo:   roleAuditRepo,
+		uow:             uow,
+		outboxRepo:      outboxRepo,
+		photoStore:      photoStore,
+		uploadPolicy:    uploadPolicy,
+		photoProcessing: photoProcessing,
+		feedSvc:         feedSvc,
+		tagSvc:          tagSvc,
+		notificationSvc: notificationSvc,
+		realtimeSvc:     realtimeSvc,
+		webhookSvc:      webhookSvc,
+		commentConf:     commentConf,
 	}
 }
 
+// record writes an event to the outbox, call it in the transaction of
+// the change so the event is only published when the change is saved.
+func (a *AccountServiceImpl) record(ctx context.Context, aggregateType outboxmodel.AggregateType, aggregateId uint64, eventType outboxmodel.EventType, data interface{}) (err error) {
+	event, err := outboxmodel.NewEvent(aggregateType, aggregateId, eventType, data)
+	if err != nil {
+		return
+	}
+	return a.outboxRepo.Append(ctx, event)
+}
+
+// ACCOUNT SECTION
+// accounts were merged into user, the methods below only adapt the
+// deprecated /account payloads to the user flow
+
 func (a *AccountServiceImpl) CreateAccount(ctx context.Context, acc accountmodel.CreateAccount) (created accountmodel.AccountResponse, err error) {
 	logCtx := fmt.Sprintf("%T - CreatedAccount", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
@@ -45,14 +148,18 @@ func (a *AccountServiceImpl) CreateAccount(ctx context.Context, acc accountmodel
 			"error", err)
 		return
 	}
-	// update passowrd with hashed password
-	acc.Password = hashedPassowrd
-	// store to db
-	createdAcc, err := a.accountRepo.CreateAccount(ctx, accountmodel.Account{
-		ID:       uuid.New(),
-		Username: acc.Username,
-		Password: acc.Password,
-		Role:     acc.Role,
+	// store to db, roles are only granted by admin through UpdateUserRole
+	var createdUser accountmodel.User
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		createdUser, err = a.accountRepo.CreateUser(ctx, accountmodel.User{
+			Username: acc.Username,
+			Password: hashedPassowrd,
+			Role:     accountmodel.ROLE_NORMAL,
+		})
+		if err != nil {
+			return
+		}
+		return a.record(ctx, outboxmodel.AGGREGATE_USER, createdUser.ID, outboxmodel.EVENT_USER_REGISTERED, accountmodel.ToUserResponse(createdUser))
 	})
 	if err != nil {
 		logger.Error(ctx, "error when storing account",
@@ -61,110 +168,52 @@ func (a *AccountServiceImpl) CreateAccount(ctx context.Context, acc accountmodel
 		return
 	}
 
-	return accountmodel.AccountResponse{
-		ID:        createdAcc.ID,
-		Username:  createdAcc.Username,
-		Role:      createdAcc.Role,
-		CreatedAt: createdAcc.CreatedAt,
-	}, err
+	return accountmodel.ToAccountResponse(createdUser), err
 }
 
 func (a *AccountServiceImpl) LoginAccountByUserName(ctx context.Context, loginAcc accountmodel.LoginAccount) (tokens token.Tokens, err error) {
 	logCtx := fmt.Sprintf("%T - LoginAccountByUserName", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
 
-	// get account by username
-	acc, err := a.getAccountWithPassword(ctx, loginAcc.Username)
-	if err != nil {
-		logger.Error(ctx, "error when fetching account by username",
-			"logCtx", logCtx,
-			"error", err)
-		return
-	}
-
-	// compare password
-	// password acc -> hashed password
-	// password login acc -> plain password
-	if err = crypto.CompareHash(acc.Password, loginAcc.Password); err != nil {
-		logger.Error(ctx, "error when comparing password",
-			"logCtx", logCtx,
-			"error", err)
-		return
-	}
-
-	// record activity
-	createdActivity, err := a.activityRepo.CreateActivity(ctx, accountactivity.AccountActivity{
-		ID:     uuid.New(),
-		UserID: acc.ID,
-		Type:   accountactivity.ACTIVITY_LOGIN,
-	})
-	if err != nil {
-		logger.Error(ctx, "error when creating activity",
-			"logCtx", logCtx,
-			"error", err)
-		return
-	}
-
-	idToken, accessToken, refreshToken, err := a.generateAllTokensConcurrent(ctx,
-		acc.ID.String(),
-		acc.Username,
-		string(acc.Role),
-		createdActivity.ID.String())
-	if err != nil {
-		return
-	}
-
-	return token.Tokens{
-		IDToken:      (idToken),
-		AccessToken:  (accessToken),
-		RefreshToken: (refreshToken),
-	}, err
+	return a.LoginUser(ctx, accountmodel.LoginUser(loginAcc))
 }
 
 func (a *AccountServiceImpl) GetAccount(ctx context.Context, userId string) (account accountmodel.AccountResponse, err error) {
 	logCtx := fmt.Sprintf("%T - GetAccount", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	// get account from database
-	acc, err := a.accountRepo.GetAccountByUserID(ctx, userId)
+
+	// tokens minted by the old account login carry the account uuid
+	var user accountmodel.User
+	if _, errParse := uuid.Parse(userId); errParse == nil {
+		user, err = a.accountRepo.GetUserByLegacyAccountID(ctx, userId)
+	} else {
+		user, err = a.accountRepo.GetUserById(ctx, userId)
+	}
 	if err != nil {
+		logger.Error(ctx, "error when fetching user",
+			"logCtx", logCtx,
+			"error", err)
 		return
 	}
-	return accountmodel.AccountResponse{
-		ID:        acc.ID,
-		Username:  acc.Username,
-		Role:      acc.Role,
-		CreatedAt: acc.CreatedAt,
-	}, err
+	return accountmodel.ToAccountResponse(user), err
 }
 
-func (a *AccountServiceImpl) getAccountWithPassword(ctx context.Context, username string) (account accountmodel.AccountResponseWithPassword, err error) {
-	logCtx := fmt.Sprintf("%T - getAccountWithPassword", a)
-	logger.Info(ctx, "invoked", "logCtx", logCtx)
-
-	// get account from database
-	acc, err := a.accountRepo.GetAccountByUserName(ctx, username)
-	if err != nil {
-		return
-	}
-	return accountmodel.AccountResponseWithPassword{
-		AccountResponse: accountmodel.AccountResponse{
-			ID:        acc.ID,
-			Username:  acc.Username,
-			Role:      acc.Role,
-			CreatedAt: acc.CreatedAt,
-		},
-		Password: acc.Password,
-	}, err
+// accessTokenExpiry is when the access token minted for activity stops
+// being accepted, tokens are issued at the created_at of their activity.
+func accessTokenExpiry(activity accountactivity.UserActivity) time.Time {
+	// exp is in seconds and the token is still accepted during that second
+	return time.Unix(activity.CreatedAt.Add(accessTokenTTL).Unix()+1, 0)
 }
 
-func (a *AccountServiceImpl) generateAllTokensConcurrent(ctx context.Context, userid, username, role, jti string) (idToken, accessToken, refreshToken string, err error) {
+// generateAllTokensConcurrent mints the token triplet of an activity,
+// jti and timeNow are its id and created_at.
+func (a *AccountServiceImpl) generateAllTokensConcurrent(ctx context.Context, userid, username, role, jti string, timeNow time.Time) (idToken, accessToken, refreshToken string, err error) {
 	logCtx := fmt.Sprintf("%T - generateAllTokens", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
 
-	// https://github.com/kataras/jwt
-	timeNow := time.Now()
+	// https://github.com/kataras/jwt, every time claim is in unix seconds
 	defaultClaim := token.DefaultClaim{
-		Expired:   int(timeNow.Add(24 * time.Hour).Unix()),
+		Expired:   int(timeNow.Add(idTokenTTL).Unix()),
 		NotBefore: int(timeNow.Unix()),
 		IssuedAt:  int(timeNow.Unix()),
 		Issuer:    "http://go-account",
@@ -201,7 +250,7 @@ func (a *AccountServiceImpl) generateAllTokensConcurrent(ctx context.Context, us
 	go func(defaultClaim_ token.DefaultClaim) {
 		defer wg.Done()
 		// generate access token
-		defaultClaim_.Expired = int(timeNow.Add(20 * time.Minute).UnixMilli())
+		defaultClaim_.Expired = int(timeNow.Add(accessTokenTTL).Unix())
 		defaultClaim_.Type = token.ACCESS_TOKEN
 		accessTokenClaim := struct {
 			token.DefaultClaim
@@ -225,7 +274,7 @@ func (a *AccountServiceImpl) generateAllTokensConcurrent(ctx context.Context, us
 	go func(defaultClaim_ token.DefaultClaim) {
 		defer wg.Done()
 		// generate refresh token
-		defaultClaim_.Expired = int(timeNow.Add(time.Hour).UnixMilli())
+		defaultClaim_.Expired = int(timeNow.Add(refreshTokenTTL).Unix())
 		defaultClaim_.Type = token.REFRESH_TOKEN
 		refreshTokenClaim := struct {
 			token.DefaultClaim
@@ -245,14 +294,13 @@ func (a *AccountServiceImpl) generateAllTokensConcurrent(ctx context.Context, us
 	return
 }
 
-func (a *AccountServiceImpl) generateAllTokens(ctx context.Context, userid, username, role, jti string) (idToken, accessToken, refreshToken string, err error) {
+func (a *AccountServiceImpl) generateAllTokens(ctx context.Context, userid, username, role, jti string, timeNow time.Time) (idToken, accessToken, refreshToken string, err error) {
 	logCtx := fmt.Sprintf("%T - generateAllTokens", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
 
-	// https://github.com/kataras/jwt
-	timeNow := time.Now()
+	// https://github.com/kataras/jwt, every time claim is in unix seconds
 	defaultClaim_ := token.DefaultClaim{
-		Expired:   int(timeNow.Add(24 * time.Hour).Unix()),
+		Expired:   int(timeNow.Add(idTokenTTL).Unix()),
 		NotBefore: int(timeNow.Unix()),
 		IssuedAt:  int(timeNow.Unix()),
 		Issuer:    "http://go-account",
@@ -284,7 +332,7 @@ func (a *AccountServiceImpl) generateAllTokens(ctx context.Context, userid, user
 	}
 
 	// generate access token
-	defaultClaim_.Expired = int(timeNow.Add(20 * time.Minute).UnixMilli())
+	defaultClaim_.Expired = int(timeNow.Add(accessTokenTTL).Unix())
 	defaultClaim_.Type = token.ACCESS_TOKEN
 	accessTokenClaim := struct {
 		token.DefaultClaim
@@ -305,7 +353,7 @@ func (a *AccountServiceImpl) generateAllTokens(ctx context.Context, userid, user
 	}
 
 	// generate refresh token
-	defaultClaim_.Expired = int(timeNow.Add(time.Hour).UnixMilli())
+	defaultClaim_.Expired = int(timeNow.Add(refreshTokenTTL).Unix())
 	defaultClaim_.Type = token.REFRESH_TOKEN
 	refreshTokenClaim := struct {
 		token.DefaultClaim
@@ -347,6 +395,10 @@ func (a *AccountServiceImpl) LoginUser(ctx context.Context, loginAcc accountmode
 		logger.Error(ctx, "error when fetching account by username",
 			"logCtx", logCtx,
 			"error", err)
+		// do not tell which of username or password is wrong
+		if errors.Is(err, domainerr.ErrNotFound) {
+			err = ErrInvalidCredential
+		}
 		return
 	}
 
@@ -357,15 +409,161 @@ func (a *AccountServiceImpl) LoginUser(ctx context.Context, loginAcc accountmode
 		logger.Error(ctx, "error when comparing password",
 			"logCtx", logCtx,
 			"error", err)
-		logger.Error(ctx, "acc pass: ", acc.Password, "login pass: ", loginAcc.Password)
+		err = ErrInvalidCredential
+		return
+	}
+
+	// record activity, a login starts a new refresh token family.
+	// the tokens are minted in the transaction, an activity without
+	// tokens would only be a login that never happened
+	activityId := uuid.New()
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		createdActivity, err := a.activityRepo.CreateUserActivity(ctx, accountactivity.UserActivity{
+			ID:        activityId,
+			UserID:    acc.ID,
+			Type:      accountactivity.ACTIVITY_LOGIN,
+			FamilyID:  activityId,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			logger.Error(ctx, "error when creating activity",
+				"logCtx", logCtx,
+				"error", err)
+			return
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_USER, acc.ID, outboxmodel.EVENT_USER_LOGGED_IN, outboxmodel.UserLoggedIn{
+			UserID:     acc.ID,
+			ActivityID: activityId.String(),
+		}); err != nil {
+			return
+		}
+
+		idToken, accessToken, refreshToken, err := a.generateAllTokensConcurrent(ctx,
+			strconv.FormatUint(acc.ID, 10),
+			acc.Username,
+			string(acc.EffectiveRole()),
+			createdActivity.ID.String(),
+			createdActivity.CreatedAt)
+		if err != nil {
+			return
+		}
+		tokens = token.Tokens{
+			IDToken:      idToken,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		}
+		return
+	})
+	if err != nil {
+		logger.Error(ctx, "error when logging in",
+			"logCtx", logCtx,
+			"error", err)
+		return token.Tokens{}, err
+	}
+	return
+}
+
+func (a *AccountServiceImpl) RefreshUserToken(ctx context.Context, refreshToken string) (tokens token.Tokens, err error) {
+	logCtx := fmt.Sprintf("%T - RefreshUserToken", a)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	var claim token.DefaultClaim
+	if err = crypto.ParseJWT(refreshToken, &claim); err != nil {
+		logger.Error(ctx, "error when parsing refresh token",
+			"logCtx", logCtx,
+			"error", err)
+		err = ErrInvalidRefreshToken
+		return
+	}
+	if claim.Type != token.REFRESH_TOKEN || time.Now().Unix() > int64(claim.Expired) {
+		err = ErrInvalidRefreshToken
+		return
+	}
+
+	// jti is the id of the user activity that minted this refresh token
+	activity, err := a.activityRepo.GetUserActivityByID(ctx, claim.JTI)
+	if err != nil {
+		logger.Error(ctx, "error when fetching activity",
+			"logCtx", logCtx,
+			"error", err)
+		if errors.Is(err, domainerr.ErrNotFound) {
+			err = ErrInvalidRefreshToken
+		}
+		return
+	}
+	if activity.RevokedAt != nil {
+		err = ErrInvalidRefreshToken
+		return
+	}
+
+	// rotating and minting the next token go together, a failed mint
+	// must not use up the refresh token
+	rotated := false
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if rotated, err = a.activityRepo.RotateUserActivity(ctx, activity.ID.String()); err != nil || !rotated {
+			return
+		}
+		tokens, err = a.rotateUserTokens(ctx, activity)
+		return
+	})
+	if err != nil {
+		logger.Error(ctx, "error when rotating activity",
+			"logCtx", logCtx,
+			"error", err)
+		return token.Tokens{}, err
+	}
+	if !rotated {
+		// the refresh token was already used, treat the whole family as stolen
+		logger.Error(ctx, "refresh token reuse detected",
+			"logCtx", logCtx,
+			"familyId", activity.FamilyID.String())
+		// the access tokens the family already got have to stop too
+		var activities []accountactivity.UserActivity
+		activities, err = a.activityRepo.FindActiveUserActivities(ctx, activity.UserID, activity.FamilyID.String(), time.Now().Add(-accessTokenTTL-time.Second))
+		if err != nil {
+			logger.Error(ctx, "error when fetching active activities",
+				"logCtx", logCtx,
+				"error", err)
+			return
+		}
+		if err = a.revokeAccessTokens(ctx, activities); err != nil {
+			logger.Error(ctx, "error when revoking token",
+				"logCtx", logCtx,
+				"error", err)
+			return
+		}
+		if err = a.activityRepo.RevokeUserActivityFamily(ctx, activity.FamilyID.String()); err != nil {
+			logger.Error(ctx, "error when revoking activity family",
+				"logCtx", logCtx,
+				"error", err)
+			return
+		}
+		err = ErrRefreshTokenReused
+		return
+	}
+	return
+}
+
+// rotateUserTokens mints the tokens following the rotated activity,
+// the new refresh token stays in the same family.
+func (a *AccountServiceImpl) rotateUserTokens(ctx context.Context, rotated accountactivity.UserActivity) (tokens token.Tokens, err error) {
+	logCtx := fmt.Sprintf("%T - rotateUserTokens", a)
+
+	user, err := a.accountRepo.GetUserById(ctx, strconv.FormatUint(rotated.UserID, 10))
+	if err != nil {
+		logger.Error(ctx, "error when fetching user",
+			"logCtx", logCtx,
+			"error", err)
 		return
 	}
 
 	// record activity
 	createdActivity, err := a.activityRepo.CreateUserActivity(ctx, accountactivity.UserActivity{
-		ID:     uuid.New(),
-		UserID: acc.ID,
-		Type:   accountactivity.ACTIVITY_LOGIN,
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Type:      accountactivity.ACTIVITY_REFRESH,
+		FamilyID:  rotated.FamilyID,
+		CreatedAt: time.Now(),
 	})
 	if err != nil {
 		logger.Error(ctx, "error when creating activity",
@@ -375,21 +573,123 @@ func (a *AccountServiceImpl) LoginUser(ctx context.Context, loginAcc accountmode
 	}
 
 	idToken, accessToken, refreshToken, err := a.generateAllTokensConcurrent(ctx,
-		strconv.FormatUint(acc.ID, 10),
-		acc.Username,
-		string("normal"),
-		createdActivity.ID.String())
+		strconv.FormatUint(user.ID, 10),
+		user.Username,
+		string(user.EffectiveRole()),
+		createdActivity.ID.String(),
+		createdActivity.CreatedAt)
 	if err != nil {
 		return
 	}
 
 	return token.Tokens{
-		IDToken:      (idToken),
-		AccessToken:  (accessToken),
-		RefreshToken: (refreshToken),
+		IDToken:      idToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}, err
 }
 
+// revokeAccessTokens keeps the access tokens of activities on the
+// revocation list until they expire, refresh tokens are revoked in
+// user_activities instead.
+func (a *AccountServiceImpl) revokeAccessTokens(ctx context.Context, activities []accountactivity.UserActivity) (err error) {
+	for _, activity := range activities {
+		ttl := time.Until(accessTokenExpiry(activity))
+		if ttl <= 0 {
+			continue
+		}
+		if err = a.revocationStore.Revoke(ctx, activity.ID.String(), ttl); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// revokeAllSessions ends every session of userId, no token minted
+// before it is accepted anymore.
+func (a *AccountServiceImpl) revokeAllSessions(ctx context.Context, userId uint64) (err error) {
+	activities, err := a.activityRepo.FindActiveUserActivities(ctx, userId, "", time.Now().Add(-accessTokenTTL-time.Second))
+	if err != nil {
+		return
+	}
+	if err = a.activityRepo.RevokeUserActivitiesByUserID(ctx, userId); err != nil {
+		return
+	}
+	return a.revokeAccessTokens(ctx, activities)
+}
+
+func (a *AccountServiceImpl) LogoutUser(ctx context.Context, userId string, jti string, all bool) (err error) {
+	logCtx := fmt.Sprintf("%T - LogoutUser", a)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	uid, err := strconv.ParseUint(userId, 10, 64)
+	if err != nil {
+		logger.Error(ctx, "error when parsing user id",
+			"logCtx", logCtx,
+			"error", err)
+		err = domainerr.Wrap(domainerr.KIND_VALIDATION, "invalid user id", err)
+		return
+	}
+
+	current, err := a.activityRepo.GetUserActivityByID(ctx, jti)
+	if err != nil {
+		logger.Error(ctx, "error when fetching activity",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	// every login/refresh activity is the jti of a token triplet,
+	// revoke all of them that may still be alive
+	familyId := current.FamilyID.String()
+	if all {
+		familyId = ""
+	}
+	activities, err := a.activityRepo.FindActiveUserActivities(ctx, uid, familyId, time.Now().Add(-accessTokenTTL-time.Second))
+	if err != nil {
+		logger.Error(ctx, "error when fetching active activities",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if err = a.revokeAccessTokens(ctx, append(activities, current)); err != nil {
+		logger.Error(ctx, "error when revoking token",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		// refresh tokens of the revoked sessions must not rotate anymore
+		if all {
+			err = a.activityRepo.RevokeUserActivitiesByUserID(ctx, uid)
+		} else {
+			err = a.activityRepo.RevokeUserActivityFamily(ctx, familyId)
+		}
+		if err != nil {
+			logger.Error(ctx, "error when revoking activities",
+				"logCtx", logCtx,
+				"error", err)
+			return
+		}
+
+		// record activity
+		_, err = a.activityRepo.CreateUserActivity(ctx, accountactivity.UserActivity{
+			ID:       uuid.New(),
+			UserID:   uid,
+			Type:     accountactivity.ACTIVITY_LOGOUT,
+			FamilyID: current.FamilyID,
+		})
+		if err != nil {
+			logger.Error(ctx, "error when creating activity",
+				"logCtx", logCtx,
+				"error", err)
+		}
+		return
+	})
+	return
+}
+
 func (a *AccountServiceImpl) RegisterUser(ctx context.Context, acc accountmodel.RegisterUser) (created accountmodel.UserRegisterResponse, err error) {
 	logCtx := fmt.Sprintf("%T - CreatedAccount", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
@@ -412,15 +712,24 @@ func (a *AccountServiceImpl) RegisterUser(ctx context.Context, acc accountmodel.
 		logger.Error(ctx, "error when transforming age string to uint64",
 			"logCtx", logCtx,
 			"error", err)
+		err = domainerr.Wrap(domainerr.KIND_VALIDATION, "age must be a number", err)
 		return
 	}
 
-	createdAcc, err := a.accountRepo.CreateUser(ctx, accountmodel.User{
-		// ID:       uuid.New(),
-		Username: acc.Username,
-		Email: acc.Email,
-		Password: acc.Password,
-		Age:     accAge,
+	var createdAcc accountmodel.User
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		createdAcc, err = a.accountRepo.CreateUser(ctx, accountmodel.User{
+			// ID:       uuid.New(),
+			Username: acc.Username,
+			Email:    acc.Email,
+			Password: acc.Password,
+			Age:      accAge,
+			Role:     accountmodel.ROLE_NORMAL,
+		})
+		if err != nil {
+			return
+		}
+		return a.record(ctx, outboxmodel.AGGREGATE_USER, createdAcc.ID, outboxmodel.EVENT_USER_REGISTERED, accountmodel.ToUserResponse(createdAcc))
 	})
 	if err != nil {
 		logger.Error(ctx, "error when storing account",
@@ -429,13 +738,157 @@ func (a *AccountServiceImpl) RegisterUser(ctx context.Context, acc accountmodel.
 		return
 	}
 
-	return accountmodel.UserRegisterResponse{
-		ID:        createdAcc.ID,
-		Username:  createdAcc.Username,
-		Email:  createdAcc.Email,
-		Age  :  createdAcc.Age  ,
-		CreatedAt: createdAcc.CreatedAt,
-	}, err
+	return accountmodel.ToUserRegisterResponse(createdAcc), err
+}
+
+// BootstrapAdmin registers the very first admin, it refuses to run
+// once any admin exists so it cannot be used to escalate later on.
+// The user is only kept when it became admin.
+func (a *AccountServiceImpl) BootstrapAdmin(ctx context.Context, acc accountmodel.RegisterUser) (created accountmodel.UserRegisterResponse, err error) {
+	logCtx := fmt.Sprintf("%T - BootstrapAdmin", a)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		created, err = a.bootstrapAdmin(ctx, acc)
+		return
+	})
+	if err != nil {
+		return accountmodel.UserRegisterResponse{}, err
+	}
+	return
+}
+
+func (a *AccountServiceImpl) bootstrapAdmin(ctx context.Context, acc accountmodel.RegisterUser) (created accountmodel.UserRegisterResponse, err error) {
+	logCtx := fmt.Sprintf("%T - bootstrapAdmin", a)
+
+	// under read committed two bootstraps would both count no admin
+	if err = a.accountRepo.LockRoleGrants(ctx); err != nil {
+		logger.Error(ctx, "error when locking role grants",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	count, err := a.accountRepo.CountUsersByRole(ctx, accountmodel.ROLE_ADMIN)
+	if err != nil {
+		logger.Error(ctx, "error when counting admin",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if count > 0 {
+		err = ErrAdminAlreadyExists
+		return
+	}
+
+	if created, err = a.RegisterUser(ctx, acc); err != nil {
+		return
+	}
+	if err = a.accountRepo.UpdateUserRole(ctx, created.ID, accountmodel.ROLE_ADMIN); err != nil {
+		logger.Error(ctx, "error when granting admin",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	// record audit
+	_, err = a.roleAuditRepo.CreateRoleAudit(ctx, roleauditmodel.RoleAudit{
+		ID:           uuid.New(),
+		TargetUserID: created.ID,
+		OldRole:      string(accountmodel.ROLE_NORMAL),
+		NewRole:      string(accountmodel.ROLE_ADMIN),
+		Reason:       "bootstrap",
+	})
+	if err != nil {
+		logger.Error(ctx, "error when creating role audit",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	return
+}
+
+func (a *AccountServiceImpl) UpdateUserRole(ctx context.Context, actorId string, targetId uint64, req accountmodel.UpdateUserRole) (user accountmodel.User, err error) {
+	logCtx := fmt.Sprintf("%T - UpdateUserRole", a)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if req.Role != accountmodel.ROLE_ADMIN && req.Role != accountmodel.ROLE_NORMAL {
+		err = ErrInvalidRole
+		return
+	}
+
+	// the role in the token may be stale, check the actor from db
+	actor, err := a.accountRepo.GetUserById(ctx, actorId)
+	if err != nil {
+		logger.Error(ctx, "error when fetching actor",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if actor.Role != accountmodel.ROLE_ADMIN {
+		err = ErrForbiddenRoleGrant
+		return
+	}
+
+	user, err = a.accountRepo.GetUserById(ctx, strconv.FormatUint(targetId, 10))
+	if err != nil {
+		logger.Error(ctx, "error when fetching user",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	oldRole := user.EffectiveRole()
+
+	// a role is never changed without its audit
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if err = a.accountRepo.UpdateUserRole(ctx, targetId, req.Role); err != nil {
+			logger.Error(ctx, "error when updating role",
+				"logCtx", logCtx,
+				"error", err)
+			return
+		}
+		// the tokens of the target carry the old role, every session
+		// has to log in again to get the new one
+		if oldRole != req.Role {
+			if err = a.revokeAllSessions(ctx, targetId); err != nil {
+				logger.Error(ctx, "error when revoking sessions",
+					"logCtx", logCtx,
+					"error", err)
+				return
+			}
+		}
+
+		// record audit
+		_, err = a.roleAuditRepo.CreateRoleAudit(ctx, roleauditmodel.RoleAudit{
+			ID:           uuid.New(),
+			ActorUserID:  &actor.ID,
+			TargetUserID: targetId,
+			OldRole:      string(oldRole),
+			NewRole:      string(req.Role),
+			Reason:       req.Reason,
+		})
+		if err != nil {
+			logger.Error(ctx, "error when creating role audit",
+				"logCtx", logCtx,
+				"error", err)
+		}
+		return
+	})
+	if err != nil {
+		return
+	}
+	user.Role = req.Role
+	return
+}
+
+func (a *AccountServiceImpl) GetRoleAudits(ctx context.Context) (audits []roleauditmodel.RoleAudit, err error) {
+	logCtx := fmt.Sprintf("%T - GetRoleAudits", a)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+	if audits, err = a.roleAuditRepo.GetRoleAudits(ctx); err != nil {
+		logger.Error(ctx, "error GetRoleAudits",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
 }
 
 func (a *AccountServiceImpl) GetUser(ctx context.Context, userId string) (user accountmodel.User, err error) {
@@ -450,10 +903,10 @@ func (a *AccountServiceImpl) GetUser(ctx context.Context, userId string) (user a
 }
 
 
-func (a *AccountServiceImpl) GetAllPhotos(ctx context.Context) (photos []accountmodel.Photo, err error){
+func (a *AccountServiceImpl) GetAllPhotos(ctx context.Context, filter accountmodel.PhotoFilter, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error) {
 	logCtx := fmt.Sprintf("%T - GetAllPhotos", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if photos, err = a.accountRepo.GetAllPhotos(ctx); err != nil {
+	if photos, page, err = a.accountRepo.GetAllPhotos(ctx, filter, params); err != nil {
 		logger.Error(ctx, "error GetAllPhotos",
 			"logCtx", logCtx,
 			"error", err)
@@ -470,41 +923,154 @@ func (a *AccountServiceImpl) GetPhotoById(ctx context.Context, photoId uint64) (
 	}
 	return
 }
-func (a *AccountServiceImpl) CreatePhoto(ctx context.Context, acc accountmodel.Photo) (photo accountmodel.Photo, err error) {
-	logCtx := fmt.Sprintf("%T - CreatePhoto", a)
+// UploadPhoto stores the file and creates the photo pointing at it,
+// the content type is taken from the magic bytes, not from the client.
+// Variants and the rest are added once the photo is processed.
+func (a *AccountServiceImpl) UploadPhoto(ctx context.Context, acc accountmodel.Photo, file io.Reader, size int64) (photo accountmodel.Photo, err error) {
+	logCtx := fmt.Sprintf("%T - UploadPhoto", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if photo, err = a.accountRepo.CreatePhoto(ctx, acc); err != nil {
+
+	if err = a.uploadPolicy.CheckSize(size); err != nil {
+		return
+	}
+	contentType, body, err := upload.Sniff(file)
+	if err != nil {
+		return
+	}
+	// the gps position must never reach the store, not even until the
+	// worker gets to the photo
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return
+	}
+	stripped, err := imaging.StripMetadata(contentType, data)
+	if err != nil {
+		return photo, domainerr.Validation(fmt.Sprintf("file is not a valid %v", contentType))
+	}
+
+	key := upload.NewKey("photo", acc.UserID, contentType)
+	acc.PhotoKey = key
+	acc.ProcessingStatus = accountmodel.PROCESSING_PENDING
+	if acc.PhotoUrl, err = a.photoStore.Put(ctx, key, contentType, bytes.NewReader(stripped), int64(len(stripped))); err != nil {
+		logger.Error(ctx, "error when storing photo file",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if photo, err = a.accountRepo.CreatePhoto(ctx, acc); err != nil {
+			return
+		}
+		if err = a.tagSvc.SyncPhoto(ctx, photo); err != nil {
+			return
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_PHOTO, photo.ID, outboxmodel.EVENT_PHOTO_CREATED, accountmodel.ToPhotoResponse(photo)); err != nil {
+			return
+		}
+		return a.webhookSvc.Emit(ctx, photo.UserID, webhookmodel.EVENT_PHOTO_CREATED, accountmodel.ToPhotoResponse(photo))
+	})
+	if err != nil {
 		logger.Error(ctx, "error CreatePhoto",
 			"logCtx", logCtx,
 			"error", err)
+		// the file is useless without its row
+		if errDelete := a.photoStore.Delete(ctx, key); errDelete != nil {
+			logger.Error(ctx, "error when deleting orphan photo file",
+				"logCtx", logCtx,
+				"key", key,
+				"error", errDelete)
+		}
+		return
 	}
+
+	// a full queue leaves it pending, the upload itself went fine
+	a.photoProcessing.Enqueue(ctx, photo.ID)
+	a.feedSvc.Enqueue(ctx, photo)
 	return
 }
 func (a *AccountServiceImpl) UpdatePhoto(ctx context.Context, acc accountmodel.Photo) (photo accountmodel.Photo, err error) {
 	logCtx := fmt.Sprintf("%T - UpdatePhoto", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if photo, err = a.accountRepo.UpdatePhoto(ctx, acc); err != nil {
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if photo, err = a.accountRepo.UpdatePhoto(ctx, acc); err != nil {
+			return
+		}
+		// an empty caption is not written by the update, so neither are its tags
+		if acc.Caption != "" {
+			if err = a.tagSvc.SyncPhoto(ctx, acc); err != nil {
+				return
+			}
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_PHOTO, acc.ID, outboxmodel.EVENT_PHOTO_UPDATED, accountmodel.ToPhotoResponse(acc)); err != nil {
+			return
+		}
+		// the update only returns what changed, acc has the id and owner
+		return a.webhookSvc.Emit(ctx, acc.UserID, webhookmodel.EVENT_PHOTO_UPDATED, accountmodel.ToPhotoResponse(acc))
+	})
+	if err != nil {
 		logger.Error(ctx, "error UpdatePhoto",
 			"logCtx", logCtx,
 			"error", err)
 	}
 	return
 }
+// DeletePhoto takes its comments and tags along, either all of them are
+// gone or the photo is still there.
 func (a *AccountServiceImpl) DeletePhoto(ctx context.Context, photoId uint64) (photo accountmodel.Photo, err error){
 	logCtx := fmt.Sprintf("%T - DeletePhoto", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if photo, err = a.accountRepo.DeletePhoto(ctx, photoId); err != nil {
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if photo, err = a.accountRepo.DeletePhoto(ctx, photoId); err != nil {
+			return
+		}
+		if _, err = a.accountRepo.DeletePhotoComments(ctx, photoId); err != nil {
+			return
+		}
+		if err = a.tagSvc.RemovePhoto(ctx, photoId); err != nil {
+			return
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_PHOTO, photoId, outboxmodel.EVENT_PHOTO_DELETED, accountmodel.ToPhotoResponse(photo)); err != nil {
+			return
+		}
+		return a.webhookSvc.Emit(ctx, photo.UserID, webhookmodel.EVENT_PHOTO_DELETED, accountmodel.ToPhotoResponse(photo))
+	})
+	if err != nil {
 		logger.Error(ctx, "error DeletePhoto",
 			"logCtx", logCtx,
 			"error", err)
+		return
 	}
+	a.deletePhotoFiles(ctx, photo)
 	return
 }
 
-func (a *AccountServiceImpl) GetAllComments(ctx context.Context) (comments []accountmodel.Comment, err error) {
+// deletePhotoFiles runs once the photo is deleted, a file that is left
+// behind is only logged. Photos created from an url have no file.
+func (a *AccountServiceImpl) deletePhotoFiles(ctx context.Context, photo accountmodel.Photo) {
+	logCtx := fmt.Sprintf("%T - deletePhotoFiles", a)
+
+	keys := make([]string, 0, len(photo.Variants)+1)
+	if photo.PhotoKey != "" {
+		keys = append(keys, photo.PhotoKey)
+	}
+	for _, variant := range photo.Variants {
+		keys = append(keys, variant.Key)
+	}
+	for _, key := range keys {
+		if err := a.photoStore.Delete(ctx, key); err != nil {
+			logger.Error(ctx, "error when deleting photo file",
+				"logCtx", logCtx,
+				"key", key,
+				"error", err)
+		}
+	}
+}
+
+func (a *AccountServiceImpl) GetAllComments(ctx context.Context, filter accountmodel.CommentFilter, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error) {
 	logCtx := fmt.Sprintf("%T - GetAllComments", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if comments, err = a.accountRepo.GetAllComments(ctx); err != nil {
+	if comments, page, err = a.accountRepo.GetAllComments(ctx, filter, params); err != nil {
 		logger.Error(ctx, "error GetAllComments",
 			"logCtx", logCtx,
 			"error", err)
@@ -521,20 +1087,153 @@ func (a *AccountServiceImpl) GetCommentById(ctx context.Context, commentId uint6
 	}
 	return
 }
+func (a *AccountServiceImpl) GetPhotoComments(ctx context.Context, photoId uint64, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetPhotoComments", a)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+	if comments, page, err = a.accountRepo.GetPhotoComments(ctx, photoId, params); err != nil {
+		logger.Error(ctx, "error GetPhotoComments",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	comments, err = a.withReplyPreviews(ctx, comments)
+	return
+}
+func (a *AccountServiceImpl) GetCommentReplies(ctx context.Context, commentId uint64, params pagination.Params) (comments []accountmodel.Comment, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetCommentReplies", a)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+	if comments, page, err = a.accountRepo.GetCommentReplies(ctx, commentId, params); err != nil {
+		logger.Error(ctx, "error GetCommentReplies",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	comments, err = a.withReplyPreviews(ctx, comments)
+	return
+}
+
+// withReplyPreviews loads the first replies of the comments that have some.
+func (a *AccountServiceImpl) withReplyPreviews(ctx context.Context, comments []accountmodel.Comment) ([]accountmodel.Comment, error) {
+	logCtx := fmt.Sprintf("%T - withReplyPreviews", a)
+
+	parentIds := make([]uint64, 0, len(comments))
+	for _, comment := range comments {
+		if comment.ReplyCount > 0 {
+			parentIds = append(parentIds, comment.ID)
+		}
+	}
+	replies, err := a.accountRepo.GetReplyPreviews(ctx, parentIds, REPLY_PREVIEW_SIZE)
+	if err != nil {
+		logger.Error(ctx, "error GetReplyPreviews",
+			"logCtx", logCtx,
+			"error", err)
+		return nil, err
+	}
+
+	byParent := make(map[uint64][]accountmodel.Comment, len(parentIds))
+	for _, reply := range replies {
+		byParent[*reply.ParentID] = append(byParent[*reply.ParentID], reply)
+	}
+	for i := range comments {
+		comments[i].Replies = byParent[comments[i].ID]
+	}
+	return comments, nil
+}
+
+// CreateComment places a reply one level under its parent and on the
+// same photo, the repository checks both still exist when inserting.
 func (a *AccountServiceImpl) CreateComment(ctx context.Context, com accountmodel.Comment) (comment accountmodel.Comment, err error) {
 	logCtx := fmt.Sprintf("%T - CreateComment", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if comment, err = a.accountRepo.CreateComment(ctx, com); err != nil {
+
+	com.Depth = 0
+	if com.ParentID != nil {
+		parent, errParent := a.accountRepo.GetCommentById(ctx, *com.ParentID)
+		if errParent != nil {
+			logger.Error(ctx, "error GetCommentById",
+				"logCtx", logCtx,
+				"error", errParent)
+			err = errParent
+			if errors.Is(err, domainerr.ErrNotFound) {
+				err = domainerr.NotFound("parent comment")
+			}
+			return
+		}
+		if com.PhotoID == 0 {
+			com.PhotoID = parent.PhotoID
+		}
+		if com.PhotoID != parent.PhotoID {
+			err = ErrReplyOtherPhoto
+			return
+		}
+		if com.Depth = parent.Depth + 1; com.Depth > a.commentConf.MaxDepth {
+			err = domainerr.Validation(fmt.Sprintf("replies can not be nested more than %v deep", a.commentConf.MaxDepth))
+			return
+		}
+	}
+
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if comment, err = a.accountRepo.CreateComment(ctx, com); err != nil {
+			return
+		}
+		if err = a.tagSvc.SyncComment(ctx, comment); err != nil {
+			return
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_COMMENT, comment.ID, outboxmodel.EVENT_COMMENT_CREATED, accountmodel.ToCommentResponse(comment)); err != nil {
+			return
+		}
+		return a.webhookSvc.Emit(ctx, comment.UserID, webhookmodel.EVENT_COMMENT_CREATED, accountmodel.ToCommentResponse(comment))
+	})
+	if err != nil {
 		logger.Error(ctx, "error CreateComment",
 			"logCtx", logCtx,
 			"error", err)
+		return
 	}
+	a.notifyPhotoOwner(ctx, comment)
+	a.realtimeSvc.Publish(ctx, realtimemodel.PhotoTopic(comment.PhotoID), realtimemodel.EVENT_COMMENT, accountmodel.ToCommentResponse(comment))
 	return
 }
+
+// notifyPhotoOwner tells the owner of the photo about a new comment,
+// the comment is saved even when this fails.
+func (a *AccountServiceImpl) notifyPhotoOwner(ctx context.Context, comment accountmodel.Comment) {
+	logCtx := fmt.Sprintf("%T - notifyPhotoOwner", a)
+
+	photo, err := a.accountRepo.GetPhotoById(ctx, comment.PhotoID)
+	if err != nil {
+		logger.Error(ctx, "error GetPhotoById",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	a.notificationSvc.Notify(ctx, notificationmodel.Notification{
+		UserID:    photo.UserID,
+		ActorID:   comment.UserID,
+		Type:      notificationmodel.TYPE_COMMENT,
+		PhotoID:   &comment.PhotoID,
+		CommentID: &comment.ID,
+	})
+}
 func (a *AccountServiceImpl) UpdateComment(ctx context.Context, com accountmodel.Comment) (comment accountmodel.Comment, err error) {
 	logCtx := fmt.Sprintf("%T - UpdateComment", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if comment, err = a.accountRepo.UpdateComment(ctx, com); err != nil {
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if comment, err = a.accountRepo.UpdateComment(ctx, com); err != nil {
+			return
+		}
+		// an empty message is not written by the update, see UpdatePhoto
+		if com.Message != "" {
+			if err = a.tagSvc.SyncComment(ctx, com); err != nil {
+				return
+			}
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_COMMENT, com.ID, outboxmodel.EVENT_COMMENT_UPDATED, accountmodel.ToCommentResponse(com)); err != nil {
+			return
+		}
+		return a.webhookSvc.Emit(ctx, com.UserID, webhookmodel.EVENT_COMMENT_UPDATED, accountmodel.ToCommentResponse(com))
+	})
+	if err != nil {
 		logger.Error(ctx, "error UpdateComment",
 			"logCtx", logCtx,
 			"error", err)
@@ -544,7 +1243,19 @@ func (a *AccountServiceImpl) UpdateComment(ctx context.Context, com accountmodel
 func (a *AccountServiceImpl) DeleteComment(ctx context.Context, commentId uint64) (account accountmodel.Comment, err error){
 	logCtx := fmt.Sprintf("%T - DeleteComment", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if account, err = a.accountRepo.DeleteComment(ctx, commentId); err != nil {
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if account, err = a.accountRepo.DeleteComment(ctx, commentId); err != nil {
+			return
+		}
+		if err = a.tagSvc.RemoveComment(ctx, commentId); err != nil {
+			return
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_COMMENT, commentId, outboxmodel.EVENT_COMMENT_DELETED, accountmodel.ToCommentResponse(account)); err != nil {
+			return
+		}
+		return a.webhookSvc.Emit(ctx, account.UserID, webhookmodel.EVENT_COMMENT_DELETED, accountmodel.ToCommentResponse(account))
+	})
+	if err != nil {
 		logger.Error(ctx, "error DeleteComment",
 			"logCtx", logCtx,
 			"error", err)
@@ -552,10 +1263,10 @@ func (a *AccountServiceImpl) DeleteComment(ctx context.Context, commentId uint64
 	return
 }
 
-func (a *AccountServiceImpl) GetAllSocialMedias(ctx context.Context) (socialMedias []accountmodel.SocialMedia, err error) {
+func (a *AccountServiceImpl) GetAllSocialMedias(ctx context.Context, filter accountmodel.SocialMediaFilter, params pagination.Params) (socialMedias []accountmodel.SocialMedia, page response.Pagination, err error) {
 	logCtx := fmt.Sprintf("%T - GetAllSocialMedias", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if socialMedias, err = a.accountRepo.GetAllSocialMedias(ctx); err != nil {
+	if socialMedias, page, err = a.accountRepo.GetAllSocialMedias(ctx, filter, params); err != nil {
 		logger.Error(ctx, "error GetAllSocialMedias",
 			"logCtx", logCtx,
 			"error", err)
@@ -575,7 +1286,16 @@ func (a *AccountServiceImpl) GetSocialMediaById(ctx context.Context, socialMedia
 func (a *AccountServiceImpl) CreateSocialMedia(ctx context.Context, soc accountmodel.SocialMedia) (socialMedia accountmodel.SocialMedia, err error){
 	logCtx := fmt.Sprintf("%T - CreateSocialMedia", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if socialMedia, err = a.accountRepo.CreateSocialMedia(ctx, soc); err != nil {
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if socialMedia, err = a.accountRepo.CreateSocialMedia(ctx, soc); err != nil {
+			return
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_SOCIAL_MEDIA, socialMedia.ID, outboxmodel.EVENT_SOCIAL_MEDIA_CREATED, accountmodel.ToSocialMediaResponse(socialMedia)); err != nil {
+			return
+		}
+		return a.webhookSvc.Emit(ctx, socialMedia.UserID, webhookmodel.EVENT_SOCIAL_MEDIA_CREATED, accountmodel.ToSocialMediaResponse(socialMedia))
+	})
+	if err != nil {
 		logger.Error(ctx, "error CreateSocialMedia",
 			"logCtx", logCtx,
 			"error", err)
@@ -585,7 +1305,16 @@ func (a *AccountServiceImpl) CreateSocialMedia(ctx context.Context, soc accountm
 func (a *AccountServiceImpl) UpdateSocialMedia(ctx context.Context, soc accountmodel.SocialMedia) (socialMedia accountmodel.SocialMedia, err error){
 	logCtx := fmt.Sprintf("%T - UpdateSocialMedia", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if socialMedia, err = a.accountRepo.UpdateSocialMedia(ctx, soc); err != nil {
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if socialMedia, err = a.accountRepo.UpdateSocialMedia(ctx, soc); err != nil {
+			return
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_SOCIAL_MEDIA, soc.ID, outboxmodel.EVENT_SOCIAL_MEDIA_UPDATED, accountmodel.ToSocialMediaResponse(soc)); err != nil {
+			return
+		}
+		return a.webhookSvc.Emit(ctx, soc.UserID, webhookmodel.EVENT_SOCIAL_MEDIA_UPDATED, accountmodel.ToSocialMediaResponse(soc))
+	})
+	if err != nil {
 		logger.Error(ctx, "error UpdateSocialMedia",
 			"logCtx", logCtx,
 			"error", err)
@@ -595,7 +1324,16 @@ func (a *AccountServiceImpl) UpdateSocialMedia(ctx context.Context, soc accountm
 func (a *AccountServiceImpl) DeleteSocialMedia(ctx context.Context, socialMediaId uint64) (socialMedia accountmodel.SocialMedia, err error){
 	logCtx := fmt.Sprintf("%T - DeleteSocialMedia", a)
 	logger.Info(ctx, "invoked", "logCtx", logCtx)
-	if socialMedia, err = a.accountRepo.DeleteSocialMedia(ctx, socialMediaId); err != nil {
+	err = a.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if socialMedia, err = a.accountRepo.DeleteSocialMedia(ctx, socialMediaId); err != nil {
+			return
+		}
+		if err = a.record(ctx, outboxmodel.AGGREGATE_SOCIAL_MEDIA, socialMediaId, outboxmodel.EVENT_SOCIAL_MEDIA_DELETED, accountmodel.ToSocialMediaResponse(socialMedia)); err != nil {
+			return
+		}
+		return a.webhookSvc.Emit(ctx, socialMedia.UserID, webhookmodel.EVENT_SOCIAL_MEDIA_DELETED, accountmodel.ToSocialMediaResponse(socialMedia))
+	})
+	if err != nil {
 		logger.Error(ctx, "error DeleteSocialMedia",
 			"logCtx", logCtx,
 			"error", err)
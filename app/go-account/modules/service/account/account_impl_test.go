@@ -1,21 +1,78 @@
 package account
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"image"
+	"image/png"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	accountmodel "github.com/mygram/go-account/modules/models/account"
+	"github.com/mygram/go-account/modules/models/accountactivity"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	roleauditmodel "github.com/mygram/go-account/modules/models/roleaudit"
+	"github.com/mygram/go-account/modules/models/token"
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
 	repomock "github.com/mygram/go-account/modules/repository/account/mock"
+	activitymock "github.com/mygram/go-account/modules/repository/accountactivity/mock"
+	blobmock "github.com/mygram/go-account/modules/repository/blob/mock"
+	outboxmock "github.com/mygram/go-account/modules/repository/outbox/mock"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	roleauditmock "github.com/mygram/go-account/modules/repository/roleaudit/mock"
+	feedmock "github.com/mygram/go-account/modules/service/feed/mock"
+	notificationmock "github.com/mygram/go-account/modules/service/notification/mock"
+	processingmock "github.com/mygram/go-account/modules/service/photoprocessing/mock"
+	realtimemock "github.com/mygram/go-account/modules/service/realtime/mock"
+	tagmock "github.com/mygram/go-account/modules/service/tag/mock"
+	webhookmock "github.com/mygram/go-account/modules/service/webhook/mock"
+	"github.com/mygram/go-account/pkg/crypto"
+	"github.com/mygram/go-account/pkg/upload"
+	"github.com/mygram/go-common/config"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+	uowmock "github.com/mygram/go-common/pkg/unitofwork/mock"
 	"github.com/stretchr/testify/assert"
 )
 
+// passThroughUow runs the work without a database, the repositories
+// it calls are mocked
+func passThroughUow(ctrl *gomock.Controller) *uowmock.MockUnitOfWorkInterface {
+	uow := uowmock.NewMockUnitOfWorkInterface(ctrl)
+	uow.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+	return uow
+}
+
+// expectEvent checks every appended event is eventType about aggregateId
+func expectEvent(t *testing.T, outboxMock *outboxmock.MockIOutboxRepo, eventType outboxmodel.EventType, aggregateId string, err error) {
+	outboxMock.EXPECT().
+		Append(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, events ...outboxmodel.Event) error {
+			for _, event := range events {
+				assert.Equal(t, eventType, event.EventType)
+				assert.Equal(t, aggregateId, event.AggregateID)
+			}
+			return err
+		}).
+		AnyTimes()
+}
+
 func TestCreateAccount(t *testing.T) {
 	createdAt := time.Now()
-	id := uuid.New()
+	id := uint64(1)
 
 	type (
 		input struct {
@@ -55,8 +112,8 @@ func TestCreateAccount(t *testing.T) {
 			doMock: func(repoMock *repomock.MockIAccountRepo) {
 				repoMock.
 					EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
-					Return(accountmodel.Account{
+					CreateUser(gomock.Any(), gomock.Any()).
+					Return(accountmodel.User{
 						ID:        id,
 						Username:  "test",
 						Role:      accountmodel.ROLE_NORMAL,
@@ -82,8 +139,8 @@ func TestCreateAccount(t *testing.T) {
 			doMock: func(repoMock *repomock.MockIAccountRepo) {
 				repoMock.
 					EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
-					Return(accountmodel.Account{}, errors.New("some error")).
+					CreateUser(gomock.Any(), gomock.Any()).
+					Return(accountmodel.User{}, errors.New("some error")).
 					MaxTimes(1).
 					AnyTimes()
 			},
@@ -96,9 +153,13 @@ func TestCreateAccount(t *testing.T) {
 
 			repoMock := repomock.NewMockIAccountRepo(ctrl)
 			tC.doMock(repoMock)
+			outboxMock := outboxmock.NewMockIOutboxRepo(ctrl)
+			expectEvent(t, outboxMock, outboxmodel.EVENT_USER_REGISTERED, "1", nil)
 
 			svc := AccountServiceImpl{
 				accountRepo: repoMock,
+				uow:         passThroughUow(ctrl),
+				outboxRepo:  outboxMock,
 			}
 			created, err := svc.CreateAccount(context.Background(), tC.input.acc)
 			if tC.want.err != nil {
@@ -110,3 +171,1116 @@ func TestCreateAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAccount(t *testing.T) {
+	legacyId := uuid.New()
+
+	testCases := []struct {
+		desc   string
+		userId string
+		doMock func(repoMock *repomock.MockIAccountRepo)
+		want   accountmodel.AccountResponse
+	}{
+		{
+			desc:   "user id",
+			userId: "1",
+			doMock: func(repoMock *repomock.MockIAccountRepo) {
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "1").
+					Return(accountmodel.User{ID: 1, Username: "test"}, nil)
+			},
+			want: accountmodel.AccountResponse{ID: 1, Username: "test", Role: accountmodel.ROLE_NORMAL},
+		},
+		{
+			desc:   "legacy account id",
+			userId: legacyId.String(),
+			doMock: func(repoMock *repomock.MockIAccountRepo) {
+				repoMock.EXPECT().
+					GetUserByLegacyAccountID(gomock.Any(), legacyId.String()).
+					Return(accountmodel.User{ID: 2, Username: "legacy", Role: accountmodel.ROLE_ADMIN, LegacyAccountID: &legacyId}, nil)
+			},
+			want: accountmodel.AccountResponse{ID: 2, LegacyID: &legacyId, Username: "legacy", Role: accountmodel.ROLE_ADMIN},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			tC.doMock(repoMock)
+
+			svc := AccountServiceImpl{
+				accountRepo: repoMock,
+			}
+			account, err := svc.GetAccount(context.Background(), tC.userId)
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, account)
+		})
+	}
+}
+
+func TestLoginUser(t *testing.T) {
+	hashed, err := crypto.GenerateHash("password")
+	assert.NoError(t, err)
+	user := accountmodel.User{ID: 1, Username: "test", Password: hashed, Role: accountmodel.ROLE_NORMAL}
+	errOutbox := errors.New("some error")
+
+	testCases := []struct {
+		desc     string
+		password string
+		doMock   func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, outboxMock *outboxmock.MockIOutboxRepo)
+		wantErr  error
+	}{
+		{
+			desc:     "happy case",
+			password: "password",
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, outboxMock *outboxmock.MockIOutboxRepo) {
+				repoMock.EXPECT().
+					GetUserByUserName(gomock.Any(), "test").
+					Return(user, nil)
+				activityMock.EXPECT().
+					CreateUserActivity(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, act accountactivity.UserActivity) (accountactivity.UserActivity, error) {
+						assert.Equal(t, act.ID, act.FamilyID)
+						return act, nil
+					})
+				expectEvent(t, outboxMock, outboxmodel.EVENT_USER_LOGGED_IN, "1", nil)
+			},
+		},
+		{
+			desc:     "wrong password writes nothing",
+			password: "wrong",
+			wantErr:  ErrInvalidCredential,
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, outboxMock *outboxmock.MockIOutboxRepo) {
+				repoMock.EXPECT().
+					GetUserByUserName(gomock.Any(), "test").
+					Return(user, nil)
+			},
+		},
+		{
+			desc:     "no tokens when the event is not written",
+			password: "password",
+			wantErr:  errOutbox,
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, outboxMock *outboxmock.MockIOutboxRepo) {
+				repoMock.EXPECT().
+					GetUserByUserName(gomock.Any(), "test").
+					Return(user, nil)
+				activityMock.EXPECT().
+					CreateUserActivity(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, act accountactivity.UserActivity) (accountactivity.UserActivity, error) {
+						return act, nil
+					})
+				expectEvent(t, outboxMock, outboxmodel.EVENT_USER_LOGGED_IN, "1", errOutbox)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			activityMock := activitymock.NewMockIAccountActivityRepo(ctrl)
+			outboxMock := outboxmock.NewMockIOutboxRepo(ctrl)
+			tC.doMock(repoMock, activityMock, outboxMock)
+
+			svc := AccountServiceImpl{
+				accountRepo:  repoMock,
+				activityRepo: activityMock,
+				uow:          passThroughUow(ctrl),
+				outboxRepo:   outboxMock,
+			}
+			tokens, err := svc.LoginUser(context.Background(), accountmodel.LoginUser{Username: "test", Password: tC.password})
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+				assert.Empty(t, tokens)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tokens.RefreshToken)
+
+				// exp is checked in unix seconds
+				var claim token.DefaultClaim
+				assert.NoError(t, crypto.ParseJWT(tokens.AccessToken, &claim))
+				assert.InDelta(t, time.Now().Add(accessTokenTTL).Unix(), claim.Expired, 2)
+			}
+		})
+	}
+}
+
+func TestRefreshUserToken(t *testing.T) {
+	activityId := uuid.New()
+	familyId := uuid.New()
+	errActivity := errors.New("some error")
+
+	signRefresh := func(claim token.DefaultClaim) string {
+		tkn, _ := crypto.SignJWT(claim)
+		return tkn
+	}
+	validClaim := token.DefaultClaim{
+		Expired: int(time.Now().Add(time.Hour).Unix()),
+		JTI:     activityId.String(),
+		Type:    token.REFRESH_TOKEN,
+	}
+	accessClaim := validClaim
+	accessClaim.Type = token.ACCESS_TOKEN
+	expiredClaim := validClaim
+	// rotated by whoever used the refresh token first
+	thiefActivity := accountactivity.UserActivity{ID: uuid.New(), UserID: 1, FamilyID: familyId, CreatedAt: time.Now()}
+	expiredClaim.Expired = int(time.Now().Add(-time.Minute).Unix())
+
+	type (
+		input struct {
+			refreshToken string
+		}
+		want struct {
+			err error
+		}
+	)
+
+	testCases := []struct {
+		desc   string
+		doMock func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo)
+		input  input
+		want   want
+		// jtis on the revocation list afterwards
+		wantRevoked []string
+	}{
+		{
+			desc:  "happy case",
+			input: input{refreshToken: signRefresh(validClaim)},
+			want:  want{err: nil},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo) {
+				activityMock.EXPECT().
+					GetUserActivityByID(gomock.Any(), activityId.String()).
+					Return(accountactivity.UserActivity{ID: activityId, UserID: 1, FamilyID: familyId}, nil)
+				activityMock.EXPECT().
+					RotateUserActivity(gomock.Any(), activityId.String()).
+					Return(true, nil)
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "1").
+					Return(accountmodel.User{ID: 1, Username: "test"}, nil)
+				activityMock.EXPECT().
+					CreateUserActivity(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, act accountactivity.UserActivity) (accountactivity.UserActivity, error) {
+						assert.Equal(t, familyId, act.FamilyID)
+						assert.Equal(t, accountactivity.ACTIVITY_REFRESH, act.Type)
+						return act, nil
+					})
+			},
+		},
+		{
+			desc:  "failed activity returns no tokens",
+			input: input{refreshToken: signRefresh(validClaim)},
+			want:  want{err: errActivity},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo) {
+				activityMock.EXPECT().
+					GetUserActivityByID(gomock.Any(), activityId.String()).
+					Return(accountactivity.UserActivity{ID: activityId, UserID: 1, FamilyID: familyId}, nil)
+				activityMock.EXPECT().
+					RotateUserActivity(gomock.Any(), activityId.String()).
+					Return(true, nil)
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "1").
+					Return(accountmodel.User{ID: 1, Username: "test"}, nil)
+				activityMock.EXPECT().
+					CreateUserActivity(gomock.Any(), gomock.Any()).
+					Return(accountactivity.UserActivity{}, errActivity)
+			},
+		},
+		{
+			desc:        "reused refresh token revokes family",
+			input:       input{refreshToken: signRefresh(validClaim)},
+			want:        want{err: ErrRefreshTokenReused},
+			wantRevoked: []string{thiefActivity.ID.String()},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo) {
+				activityMock.EXPECT().
+					GetUserActivityByID(gomock.Any(), activityId.String()).
+					Return(accountactivity.UserActivity{ID: activityId, UserID: 1, FamilyID: familyId}, nil)
+				activityMock.EXPECT().
+					RotateUserActivity(gomock.Any(), activityId.String()).
+					Return(false, nil)
+				activityMock.EXPECT().
+					FindActiveUserActivities(gomock.Any(), uint64(1), familyId.String(), gomock.Any()).
+					Return([]accountactivity.UserActivity{thiefActivity}, nil)
+				activityMock.EXPECT().
+					RevokeUserActivityFamily(gomock.Any(), familyId.String()).
+					Return(nil)
+			},
+		},
+		{
+			desc:  "revoked family",
+			input: input{refreshToken: signRefresh(validClaim)},
+			want:  want{err: ErrInvalidRefreshToken},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo) {
+				revokedAt := time.Now()
+				activityMock.EXPECT().
+					GetUserActivityByID(gomock.Any(), activityId.String()).
+					Return(accountactivity.UserActivity{ID: activityId, UserID: 1, FamilyID: familyId, RevokedAt: &revokedAt}, nil)
+			},
+		},
+		{
+			desc:   "access token is not a refresh token",
+			input:  input{refreshToken: signRefresh(accessClaim)},
+			want:   want{err: ErrInvalidRefreshToken},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo) {},
+		},
+		{
+			desc:   "expired refresh token",
+			input:  input{refreshToken: signRefresh(expiredClaim)},
+			want:   want{err: ErrInvalidRefreshToken},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo) {},
+		},
+		{
+			desc:   "malformed refresh token",
+			input:  input{refreshToken: "not-a-jwt"},
+			want:   want{err: ErrInvalidRefreshToken},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo) {},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			activityMock := activitymock.NewMockIAccountActivityRepo(ctrl)
+			tC.doMock(repoMock, activityMock)
+
+			revocationStore := revocationrepo.NewRevocationStoreMemoryImpl()
+			svc := AccountServiceImpl{
+				accountRepo:     repoMock,
+				activityRepo:    activityMock,
+				revocationStore: revocationStore,
+				uow:             passThroughUow(ctrl),
+			}
+			tokens, err := svc.RefreshUserToken(context.Background(), tC.input.refreshToken)
+			if tC.want.err != nil {
+				assert.ErrorIs(t, err, tC.want.err)
+				assert.Empty(t, tokens.RefreshToken)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tokens.RefreshToken)
+			}
+			for _, jti := range tC.wantRevoked {
+				revoked, _ := revocationStore.IsRevoked(context.Background(), jti)
+				assert.True(t, revoked, jti)
+			}
+		})
+	}
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	type (
+		input struct {
+			actorId  string
+			targetId uint64
+			req      accountmodel.UpdateUserRole
+		}
+		want struct {
+			err error
+		}
+	)
+
+	errRevoke := errors.New("some error")
+	liveActivity := accountactivity.UserActivity{ID: uuid.New(), UserID: 2, CreatedAt: time.Now().Add(-time.Minute)}
+	expiredActivity := accountactivity.UserActivity{ID: uuid.New(), UserID: 2, CreatedAt: time.Now().Add(-time.Hour)}
+
+	testCases := []struct {
+		desc   string
+		doMock func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, auditMock *roleauditmock.MockIRoleAuditRepo)
+		input  input
+		want   want
+		// jtis on the revocation list after the change, and the ones not on it
+		wantRevoked  []string
+		wantNotFound []string
+	}{
+		{
+			desc: "happy case",
+			input: input{
+				actorId:  "1",
+				targetId: 2,
+				req:      accountmodel.UpdateUserRole{Role: accountmodel.ROLE_ADMIN, Reason: "moderator"},
+			},
+			want: want{err: nil},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "1").
+					Return(accountmodel.User{ID: 1, Role: accountmodel.ROLE_ADMIN}, nil)
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "2").
+					Return(accountmodel.User{ID: 2, Role: accountmodel.ROLE_NORMAL}, nil)
+				repoMock.EXPECT().
+					UpdateUserRole(gomock.Any(), uint64(2), accountmodel.ROLE_ADMIN).
+					Return(nil)
+				activityMock.EXPECT().
+					FindActiveUserActivities(gomock.Any(), uint64(2), "", gomock.Any()).
+					Return(nil, nil)
+				activityMock.EXPECT().
+					RevokeUserActivitiesByUserID(gomock.Any(), uint64(2)).
+					Return(nil)
+				auditMock.EXPECT().
+					CreateRoleAudit(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, audit roleauditmodel.RoleAudit) (roleauditmodel.RoleAudit, error) {
+						assert.Equal(t, uint64(1), *audit.ActorUserID)
+						assert.Equal(t, uint64(2), audit.TargetUserID)
+						assert.Equal(t, string(accountmodel.ROLE_NORMAL), audit.OldRole)
+						assert.Equal(t, string(accountmodel.ROLE_ADMIN), audit.NewRole)
+						assert.Equal(t, "moderator", audit.Reason)
+						return audit, nil
+					})
+			},
+		},
+		{
+			desc: "demoted admin loses its live tokens",
+			input: input{
+				actorId:  "1",
+				targetId: 2,
+				req:      accountmodel.UpdateUserRole{Role: accountmodel.ROLE_NORMAL},
+			},
+			want:         want{err: nil},
+			wantRevoked:  []string{liveActivity.ID.String()},
+			wantNotFound: []string{expiredActivity.ID.String()},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "1").
+					Return(accountmodel.User{ID: 1, Role: accountmodel.ROLE_ADMIN}, nil)
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "2").
+					Return(accountmodel.User{ID: 2, Role: accountmodel.ROLE_ADMIN}, nil)
+				repoMock.EXPECT().
+					UpdateUserRole(gomock.Any(), uint64(2), accountmodel.ROLE_NORMAL).
+					Return(nil)
+				activityMock.EXPECT().
+					FindActiveUserActivities(gomock.Any(), uint64(2), "", gomock.Any()).
+					Return([]accountactivity.UserActivity{liveActivity, expiredActivity}, nil)
+				activityMock.EXPECT().
+					RevokeUserActivitiesByUserID(gomock.Any(), uint64(2)).
+					Return(nil)
+				auditMock.EXPECT().
+					CreateRoleAudit(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, audit roleauditmodel.RoleAudit) (roleauditmodel.RoleAudit, error) {
+						return audit, nil
+					})
+			},
+		},
+		{
+			desc: "role is not changed when the tokens are not revoked",
+			input: input{
+				actorId:  "1",
+				targetId: 2,
+				req:      accountmodel.UpdateUserRole{Role: accountmodel.ROLE_NORMAL},
+			},
+			want: want{err: errRevoke},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "1").
+					Return(accountmodel.User{ID: 1, Role: accountmodel.ROLE_ADMIN}, nil)
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "2").
+					Return(accountmodel.User{ID: 2, Role: accountmodel.ROLE_ADMIN}, nil)
+				repoMock.EXPECT().
+					UpdateUserRole(gomock.Any(), uint64(2), accountmodel.ROLE_NORMAL).
+					Return(nil)
+				activityMock.EXPECT().
+					FindActiveUserActivities(gomock.Any(), uint64(2), "", gomock.Any()).
+					Return(nil, nil)
+				activityMock.EXPECT().
+					RevokeUserActivitiesByUserID(gomock.Any(), uint64(2)).
+					Return(errRevoke)
+			},
+		},
+		{
+			desc: "actor is not admin",
+			input: input{
+				actorId:  "1",
+				targetId: 1,
+				req:      accountmodel.UpdateUserRole{Role: accountmodel.ROLE_ADMIN},
+			},
+			want: want{err: ErrForbiddenRoleGrant},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+				repoMock.EXPECT().
+					GetUserById(gomock.Any(), "1").
+					Return(accountmodel.User{ID: 1, Role: accountmodel.ROLE_NORMAL}, nil)
+			},
+		},
+		{
+			desc: "unknown role",
+			input: input{
+				actorId:  "1",
+				targetId: 2,
+				req:      accountmodel.UpdateUserRole{Role: "superuser"},
+			},
+			want: want{err: ErrInvalidRole},
+			doMock: func(repoMock *repomock.MockIAccountRepo, activityMock *activitymock.MockIAccountActivityRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			activityMock := activitymock.NewMockIAccountActivityRepo(ctrl)
+			auditMock := roleauditmock.NewMockIRoleAuditRepo(ctrl)
+			revocationStore := revocationrepo.NewRevocationStoreMemoryImpl()
+			tC.doMock(repoMock, activityMock, auditMock)
+
+			svc := AccountServiceImpl{
+				accountRepo:     repoMock,
+				activityRepo:    activityMock,
+				revocationStore: revocationStore,
+				roleAuditRepo:   auditMock,
+				uow:             passThroughUow(ctrl),
+			}
+			user, err := svc.UpdateUserRole(context.Background(), tC.input.actorId, tC.input.targetId, tC.input.req)
+			if tC.want.err != nil {
+				assert.ErrorIs(t, err, tC.want.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tC.input.req.Role, user.Role)
+			}
+			for _, jti := range tC.wantRevoked {
+				revoked, _ := revocationStore.IsRevoked(context.Background(), jti)
+				assert.True(t, revoked, jti)
+			}
+			for _, jti := range tC.wantNotFound {
+				revoked, _ := revocationStore.IsRevoked(context.Background(), jti)
+				assert.False(t, revoked, jti)
+			}
+		})
+	}
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	errDB := errors.New("some error")
+	register := accountmodel.RegisterUser{Username: "admin", Email: "admin@mygram.id", Password: "secret123", Age: "20"}
+
+	testCases := []struct {
+		desc    string
+		doMock  func(repoMock *repomock.MockIAccountRepo, auditMock *roleauditmock.MockIRoleAuditRepo)
+		wantErr error
+	}{
+		{
+			desc: "first admin",
+			doMock: func(repoMock *repomock.MockIAccountRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+				gomock.InOrder(
+					repoMock.EXPECT().LockRoleGrants(gomock.Any()).Return(nil),
+					repoMock.EXPECT().CountUsersByRole(gomock.Any(), accountmodel.ROLE_ADMIN).Return(int64(0), nil),
+					repoMock.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(accountmodel.User{ID: 1, Username: "admin"}, nil),
+					repoMock.EXPECT().UpdateUserRole(gomock.Any(), uint64(1), accountmodel.ROLE_ADMIN).Return(nil),
+					auditMock.EXPECT().CreateRoleAudit(gomock.Any(), gomock.Any()).Return(roleauditmodel.RoleAudit{}, nil),
+				)
+			},
+		},
+		{
+			desc: "admin already exists",
+			doMock: func(repoMock *repomock.MockIAccountRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+				repoMock.EXPECT().LockRoleGrants(gomock.Any()).Return(nil)
+				repoMock.EXPECT().CountUsersByRole(gomock.Any(), accountmodel.ROLE_ADMIN).Return(int64(1), nil)
+			},
+			wantErr: ErrAdminAlreadyExists,
+		},
+		{
+			desc: "nothing is counted without the lock",
+			doMock: func(repoMock *repomock.MockIAccountRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+				repoMock.EXPECT().LockRoleGrants(gomock.Any()).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			desc: "failed audit fails the bootstrap",
+			doMock: func(repoMock *repomock.MockIAccountRepo, auditMock *roleauditmock.MockIRoleAuditRepo) {
+				repoMock.EXPECT().LockRoleGrants(gomock.Any()).Return(nil)
+				repoMock.EXPECT().CountUsersByRole(gomock.Any(), accountmodel.ROLE_ADMIN).Return(int64(0), nil)
+				repoMock.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(accountmodel.User{ID: 1, Username: "admin"}, nil)
+				repoMock.EXPECT().UpdateUserRole(gomock.Any(), uint64(1), accountmodel.ROLE_ADMIN).Return(nil)
+				auditMock.EXPECT().CreateRoleAudit(gomock.Any(), gomock.Any()).Return(roleauditmodel.RoleAudit{}, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			auditMock := roleauditmock.NewMockIRoleAuditRepo(ctrl)
+			outboxMock := outboxmock.NewMockIOutboxRepo(ctrl)
+			outboxMock.EXPECT().Append(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			tC.doMock(repoMock, auditMock)
+
+			svc := AccountServiceImpl{
+				accountRepo:   repoMock,
+				roleAuditRepo: auditMock,
+				uow:           passThroughUow(ctrl),
+				outboxRepo:    outboxMock,
+			}
+			_, err := svc.BootstrapAdmin(context.Background(), register)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// pngWithText is a 1x1 png carrying a text chunk, stored is the same
+// file without it
+func pngWithText(t *testing.T) (uploaded, stored string) {
+	var encoded bytes.Buffer
+	assert.NoError(t, png.Encode(&encoded, image.NewGray(image.Rect(0, 0, 1, 1))))
+	stored = encoded.String()
+	// signature and IHDR, then length, type, data and crc
+	afterHeader := 8 + 25
+	text := "\x00\x00\x00\x07tEXtgps=1,2\x00\x00\x00\x00"
+	return stored[:afterHeader] + text + stored[afterHeader:], stored
+}
+
+func TestUploadPhoto(t *testing.T) {
+	uploaded, stored := pngWithText(t)
+	storedURL := "http://localhost:9090/media/photo/1/this-is-uuid.png"
+
+	type (
+		input struct {
+			content string
+			size    int64
+		}
+		want struct {
+			err error
+			url string
+		}
+	)
+
+	testCases := []struct {
+		desc   string
+		doMock func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore, processingMock *processingmock.MockIPhotoProcessingService, feedMock *feedmock.MockIFeedService)
+		input  input
+		// returned by the outbox
+		appendErr error
+		want      want
+	}{
+		{
+			desc:  "happy case",
+			input: input{content: uploaded, size: int64(len(uploaded))},
+			want:  want{url: storedURL},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore, processingMock *processingmock.MockIPhotoProcessingService, feedMock *feedmock.MockIFeedService) {
+				blobMock.EXPECT().
+					Put(gomock.Any(), gomock.Any(), "image/png", gomock.Any(), int64(len(stored))).
+					DoAndReturn(func(_ context.Context, key string, _ string, body io.Reader, _ int64) (string, error) {
+						assert.Regexp(t, `^photo/1/.+\.png$`, key)
+						read, _ := io.ReadAll(body)
+						// stored without the text chunk
+						assert.Equal(t, stored, string(read))
+						return storedURL, nil
+					})
+				repoMock.EXPECT().
+					CreatePhoto(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, photo accountmodel.Photo) (accountmodel.Photo, error) {
+						assert.Regexp(t, `^photo/1/.+\.png$`, photo.PhotoKey)
+						assert.Equal(t, accountmodel.PROCESSING_PENDING, photo.ProcessingStatus)
+						photo.ID = 1
+						return photo, nil
+					})
+				processingMock.EXPECT().
+					Enqueue(gomock.Any(), uint64(1)).
+					Return(nil)
+				feedMock.EXPECT().
+					Enqueue(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+		},
+		{
+			desc:  "not an image",
+			input: input{content: "<html></html>", size: 13},
+			want:  want{err: domainerr.ErrValidation},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore, processingMock *processingmock.MockIPhotoProcessingService, feedMock *feedmock.MockIFeedService) {
+			},
+		},
+		{
+			desc:  "broken image is not stored",
+			input: input{content: uploaded[:20], size: 20},
+			want:  want{err: domainerr.ErrValidation},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore, processingMock *processingmock.MockIPhotoProcessingService, feedMock *feedmock.MockIFeedService) {
+			},
+		},
+		{
+			desc:  "too large",
+			input: input{content: uploaded, size: upload.DEFAULT_MAX_SIZE + 1},
+			want:  want{err: domainerr.ErrValidation},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore, processingMock *processingmock.MockIPhotoProcessingService, feedMock *feedmock.MockIFeedService) {
+			},
+		},
+		{
+			desc:      "file is deleted when the event is not recorded",
+			input:     input{content: uploaded, size: int64(len(uploaded))},
+			appendErr: errors.New("some error"),
+			want:      want{err: errors.New("some error")},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore, processingMock *processingmock.MockIPhotoProcessingService, feedMock *feedmock.MockIFeedService) {
+				blobMock.EXPECT().
+					Put(gomock.Any(), gomock.Any(), "image/png", gomock.Any(), int64(len(stored))).
+					Return(storedURL, nil)
+				repoMock.EXPECT().
+					CreatePhoto(gomock.Any(), gomock.Any()).
+					Return(accountmodel.Photo{ID: 1, UserID: 1}, nil)
+				blobMock.EXPECT().
+					Delete(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+		},
+		{
+			desc:  "file is deleted when the row is not created",
+			input: input{content: uploaded, size: int64(len(uploaded))},
+			want:  want{err: errors.New("some error")},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore, processingMock *processingmock.MockIPhotoProcessingService, feedMock *feedmock.MockIFeedService) {
+				var storedKey string
+				blobMock.EXPECT().
+					Put(gomock.Any(), gomock.Any(), "image/png", gomock.Any(), int64(len(stored))).
+					DoAndReturn(func(_ context.Context, key string, _ string, _ io.Reader, _ int64) (string, error) {
+						storedKey = key
+						return storedURL, nil
+					})
+				repoMock.EXPECT().
+					CreatePhoto(gomock.Any(), gomock.Any()).
+					Return(accountmodel.Photo{}, errors.New("some error"))
+				blobMock.EXPECT().
+					Delete(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key string) error {
+						assert.Equal(t, storedKey, key)
+						return nil
+					})
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			blobMock := blobmock.NewMockIBlobStore(ctrl)
+			processingMock := processingmock.NewMockIPhotoProcessingService(ctrl)
+			feedMock := feedmock.NewMockIFeedService(ctrl)
+			tC.doMock(repoMock, blobMock, processingMock, feedMock)
+			tagMock := tagmock.NewMockITagService(ctrl)
+			tagMock.EXPECT().SyncPhoto(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			webhookMock := webhookmock.NewMockIWebhookService(ctrl)
+			webhookMock.EXPECT().Emit(gomock.Any(), uint64(1), webhookmodel.EVENT_PHOTO_CREATED, gomock.Any()).Return(nil).AnyTimes()
+			outboxMock := outboxmock.NewMockIOutboxRepo(ctrl)
+			outboxMock.EXPECT().Append(gomock.Any(), gomock.Any()).Return(tC.appendErr).AnyTimes()
+
+			svc := AccountServiceImpl{
+				accountRepo:     repoMock,
+				uow:             passThroughUow(ctrl),
+				outboxRepo:      outboxMock,
+				photoStore:      blobMock,
+				uploadPolicy:    upload.NewPolicy(0),
+				photoProcessing: processingMock,
+				feedSvc:         feedMock,
+				tagSvc:          tagMock,
+				webhookSvc:      webhookMock,
+			}
+			photo, err := svc.UploadPhoto(context.Background(),
+				accountmodel.Photo{UserID: 1, Title: "this-is-title"},
+				strings.NewReader(tC.input.content), tC.input.size)
+			if tC.want.err != nil {
+				if errors.Is(tC.want.err, domainerr.ErrValidation) {
+					assert.ErrorIs(t, err, tC.want.err)
+				} else {
+					assert.EqualError(t, err, tC.want.err.Error())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want.url, photo.PhotoUrl)
+			assert.Equal(t, "this-is-title", photo.Title)
+		})
+	}
+}
+
+func TestCreateComment(t *testing.T) {
+	parentId := uint64(5)
+	errSync := errors.New("some error")
+	testCases := []struct {
+		desc      string
+		input     accountmodel.Comment
+		doMock    func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService)
+		wantDepth uint64
+		wantErr   error
+	}{
+		{
+			desc:  "top level comment",
+			input: accountmodel.Comment{UserID: 1, PhotoID: 2, Message: "nice"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					CreateComment(gomock.Any(), accountmodel.Comment{UserID: 1, PhotoID: 2, Message: "nice"}).
+					DoAndReturn(func(_ context.Context, com accountmodel.Comment) (accountmodel.Comment, error) {
+						return com, nil
+					})
+				tagMock.EXPECT().
+					SyncComment(gomock.Any(), gomock.Any()).
+					Return(nil)
+				repoMock.EXPECT().
+					GetPhotoById(gomock.Any(), uint64(2)).
+					Return(accountmodel.Photo{ID: 2, UserID: 3}, nil)
+				notificationMock.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, notification notificationmodel.Notification) error {
+						assert.Equal(t, uint64(3), notification.UserID)
+						assert.Equal(t, notificationmodel.TYPE_COMMENT, notification.Type)
+						return nil
+					})
+				realtimeMock.EXPECT().
+					Publish(gomock.Any(), realtimemodel.PhotoTopic(2), realtimemodel.EVENT_COMMENT, gomock.Any()).
+					Return(nil)
+			},
+		},
+		{
+			desc:  "reply takes the photo of its parent",
+			input: accountmodel.Comment{UserID: 1, ParentID: &parentId, Message: "thanks"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					GetCommentById(gomock.Any(), parentId).
+					Return(accountmodel.Comment{ID: parentId, PhotoID: 2, Depth: 1}, nil)
+				repoMock.EXPECT().
+					CreateComment(gomock.Any(), accountmodel.Comment{UserID: 1, PhotoID: 2, ParentID: &parentId, Depth: 2, Message: "thanks"}).
+					DoAndReturn(func(_ context.Context, com accountmodel.Comment) (accountmodel.Comment, error) {
+						return com, nil
+					})
+				tagMock.EXPECT().
+					SyncComment(gomock.Any(), gomock.Any()).
+					Return(nil)
+				repoMock.EXPECT().
+					GetPhotoById(gomock.Any(), uint64(2)).
+					Return(accountmodel.Photo{ID: 2, UserID: 3}, nil)
+				notificationMock.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, notification notificationmodel.Notification) error {
+						assert.Equal(t, uint64(3), notification.UserID)
+						assert.Equal(t, notificationmodel.TYPE_COMMENT, notification.Type)
+						return nil
+					})
+				realtimeMock.EXPECT().
+					Publish(gomock.Any(), realtimemodel.PhotoTopic(2), realtimemodel.EVENT_COMMENT, gomock.Any()).
+					Return(nil)
+			},
+			wantDepth: 2,
+		},
+		{
+			desc:  "too deep",
+			input: accountmodel.Comment{UserID: 1, ParentID: &parentId, Message: "thanks"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					GetCommentById(gomock.Any(), parentId).
+					Return(accountmodel.Comment{ID: parentId, PhotoID: 2, Depth: 2}, nil)
+			},
+			wantErr: domainerr.ErrValidation,
+		},
+		{
+			desc:  "reply on another photo",
+			input: accountmodel.Comment{UserID: 1, PhotoID: 3, ParentID: &parentId, Message: "thanks"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					GetCommentById(gomock.Any(), parentId).
+					Return(accountmodel.Comment{ID: parentId, PhotoID: 2}, nil)
+			},
+			wantErr: domainerr.ErrValidation,
+		},
+		{
+			desc:  "parent does not exist",
+			input: accountmodel.Comment{UserID: 1, ParentID: &parentId, Message: "thanks"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					GetCommentById(gomock.Any(), parentId).
+					Return(accountmodel.Comment{}, domainerr.NotFound("comment"))
+			},
+			wantErr: domainerr.ErrNotFound,
+		},
+		{
+			desc:  "comment is not kept without its mentions",
+			input: accountmodel.Comment{UserID: 1, PhotoID: 2, Message: "@bob nice"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Return(accountmodel.Comment{ID: 4, UserID: 1, PhotoID: 2, Message: "@bob nice"}, nil)
+				tagMock.EXPECT().
+					SyncComment(gomock.Any(), gomock.Any()).
+					Return(errSync)
+			},
+			wantErr: errSync,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			tagMock := tagmock.NewMockITagService(ctrl)
+			notificationMock := notificationmock.NewMockINotificationService(ctrl)
+			realtimeMock := realtimemock.NewMockIRealtimeService(ctrl)
+			tC.doMock(repoMock, tagMock, notificationMock, realtimeMock)
+			webhookMock := webhookmock.NewMockIWebhookService(ctrl)
+			webhookMock.EXPECT().Emit(gomock.Any(), uint64(1), webhookmodel.EVENT_COMMENT_CREATED, gomock.Any()).Return(nil).AnyTimes()
+			outboxMock := outboxmock.NewMockIOutboxRepo(ctrl)
+			outboxMock.EXPECT().Append(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+			svc := AccountServiceImpl{
+				accountRepo:     repoMock,
+				uow:             passThroughUow(ctrl),
+				outboxRepo:      outboxMock,
+				tagSvc:          tagMock,
+				notificationSvc: notificationMock,
+				realtimeSvc:     realtimeMock,
+				webhookSvc:      webhookMock,
+				commentConf:     config.CommentConfig{MaxDepth: 2},
+			}
+			comment, err := svc.CreateComment(context.Background(), tC.input)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.wantDepth, comment.Depth)
+		})
+	}
+}
+
+func TestUpdatePhoto(t *testing.T) {
+	errSync := errors.New("some error")
+	testCases := []struct {
+		desc    string
+		input   accountmodel.Photo
+		doMock  func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService)
+		wantErr error
+	}{
+		{
+			desc:  "new caption re-syncs the tags",
+			input: accountmodel.Photo{ID: 1, UserID: 1, Caption: "#sunset"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService) {
+				repoMock.EXPECT().
+					UpdatePhoto(gomock.Any(), gomock.Any()).
+					Return(accountmodel.Photo{}, nil)
+				tagMock.EXPECT().
+					SyncPhoto(gomock.Any(), accountmodel.Photo{ID: 1, UserID: 1, Caption: "#sunset"}).
+					Return(nil)
+			},
+		},
+		{
+			desc:  "caption left out keeps the tags",
+			input: accountmodel.Photo{ID: 1, UserID: 1, Title: "title"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService) {
+				repoMock.EXPECT().
+					UpdatePhoto(gomock.Any(), gomock.Any()).
+					Return(accountmodel.Photo{}, nil)
+			},
+		},
+		{
+			desc:  "caption is not kept without its tags",
+			input: accountmodel.Photo{ID: 1, UserID: 1, Caption: "#sunset"},
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService) {
+				repoMock.EXPECT().
+					UpdatePhoto(gomock.Any(), gomock.Any()).
+					Return(accountmodel.Photo{}, nil)
+				tagMock.EXPECT().
+					SyncPhoto(gomock.Any(), gomock.Any()).
+					Return(errSync)
+			},
+			wantErr: errSync,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			tagMock := tagmock.NewMockITagService(ctrl)
+			tC.doMock(repoMock, tagMock)
+			webhookMock := webhookmock.NewMockIWebhookService(ctrl)
+			outboxMock := outboxmock.NewMockIOutboxRepo(ctrl)
+			if tC.wantErr == nil {
+				webhookMock.EXPECT().
+					Emit(gomock.Any(), uint64(1), webhookmodel.EVENT_PHOTO_UPDATED, accountmodel.ToPhotoResponse(tC.input)).
+					Return(nil)
+				expectEvent(t, outboxMock, outboxmodel.EVENT_PHOTO_UPDATED, "1", nil)
+			}
+
+			svc := AccountServiceImpl{
+				accountRepo: repoMock,
+				uow:         passThroughUow(ctrl),
+				outboxRepo:  outboxMock,
+				tagSvc:      tagMock,
+				webhookSvc:  webhookMock,
+			}
+			_, err := svc.UpdatePhoto(context.Background(), tC.input)
+			assert.Equal(t, tC.wantErr, err)
+		})
+	}
+}
+
+func TestDeletePhoto(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		doMock  func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, webhookMock *webhookmock.MockIWebhookService, blobMock *blobmock.MockIBlobStore)
+		wantErr error
+	}{
+		{
+			desc: "webhooks of the owner get the deleted photo",
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, webhookMock *webhookmock.MockIWebhookService, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					DeletePhoto(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, UserID: 3}, nil)
+				repoMock.EXPECT().
+					DeletePhotoComments(gomock.Any(), uint64(1)).
+					Return(int64(2), nil)
+				tagMock.EXPECT().
+					RemovePhoto(gomock.Any(), uint64(1)).
+					Return(nil)
+				webhookMock.EXPECT().
+					Emit(gomock.Any(), uint64(3), webhookmodel.EVENT_PHOTO_DELETED, accountmodel.ToPhotoResponse(accountmodel.Photo{ID: 1, UserID: 3})).
+					Return(nil)
+			},
+		},
+		{
+			desc: "the file and its variants are deleted with the photo",
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, webhookMock *webhookmock.MockIWebhookService, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					DeletePhoto(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, UserID: 3, PhotoKey: "photo/3/a.png", Variants: []accountmodel.PhotoVariant{{Key: "photo/3/a_small.jpg"}}}, nil)
+				repoMock.EXPECT().
+					DeletePhotoComments(gomock.Any(), uint64(1)).
+					Return(int64(0), nil)
+				tagMock.EXPECT().
+					RemovePhoto(gomock.Any(), uint64(1)).
+					Return(nil)
+				blobMock.EXPECT().
+					Delete(gomock.Any(), "photo/3/a.png").
+					Return(nil)
+				// a file left behind does not fail the delete
+				blobMock.EXPECT().
+					Delete(gomock.Any(), "photo/3/a_small.jpg").
+					Return(errors.New("some error"))
+				webhookMock.EXPECT().
+					Emit(gomock.Any(), uint64(3), webhookmodel.EVENT_PHOTO_DELETED, gomock.Any()).
+					Return(nil)
+			},
+		},
+		{
+			desc:    "the photo stays when its comments are not deleted",
+			wantErr: domainerr.ErrConflict,
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, webhookMock *webhookmock.MockIWebhookService, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					DeletePhoto(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, UserID: 3}, nil)
+				repoMock.EXPECT().
+					DeletePhotoComments(gomock.Any(), uint64(1)).
+					Return(int64(0), domainerr.New(domainerr.KIND_CONFLICT, response.CODE_ALREADY_EXISTS, "some error"))
+			},
+		},
+		{
+			desc:    "the photo stays when its tags are not removed",
+			wantErr: domainerr.ErrConflict,
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, webhookMock *webhookmock.MockIWebhookService, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					DeletePhoto(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, UserID: 3}, nil)
+				repoMock.EXPECT().
+					DeletePhotoComments(gomock.Any(), uint64(1)).
+					Return(int64(0), nil)
+				tagMock.EXPECT().
+					RemovePhoto(gomock.Any(), uint64(1)).
+					Return(domainerr.New(domainerr.KIND_CONFLICT, response.CODE_ALREADY_EXISTS, "some error"))
+			},
+		},
+		{
+			desc:    "the photo and its files stay when the deliveries are not queued",
+			wantErr: domainerr.ErrConflict,
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, webhookMock *webhookmock.MockIWebhookService, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					DeletePhoto(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, UserID: 3, PhotoKey: "photo/3/a.png"}, nil)
+				repoMock.EXPECT().
+					DeletePhotoComments(gomock.Any(), uint64(1)).
+					Return(int64(0), nil)
+				tagMock.EXPECT().
+					RemovePhoto(gomock.Any(), uint64(1)).
+					Return(nil)
+				webhookMock.EXPECT().
+					Emit(gomock.Any(), uint64(3), webhookmodel.EVENT_PHOTO_DELETED, gomock.Any()).
+					Return(domainerr.New(domainerr.KIND_CONFLICT, response.CODE_ALREADY_EXISTS, "some error"))
+			},
+		},
+		{
+			desc:    "nothing is emitted when the photo is not deleted",
+			wantErr: domainerr.ErrNotFound,
+			doMock: func(repoMock *repomock.MockIAccountRepo, tagMock *tagmock.MockITagService, webhookMock *webhookmock.MockIWebhookService, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					DeletePhoto(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{}, domainerr.NotFound("photo"))
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			tagMock := tagmock.NewMockITagService(ctrl)
+			webhookMock := webhookmock.NewMockIWebhookService(ctrl)
+			blobMock := blobmock.NewMockIBlobStore(ctrl)
+			tC.doMock(repoMock, tagMock, webhookMock, blobMock)
+			outboxMock := outboxmock.NewMockIOutboxRepo(ctrl)
+			expectEvent(t, outboxMock, outboxmodel.EVENT_PHOTO_DELETED, "1", nil)
+
+			svc := AccountServiceImpl{
+				accountRepo: repoMock,
+				uow:         passThroughUow(ctrl),
+				outboxRepo:  outboxMock,
+				photoStore:  blobMock,
+				tagSvc:      tagMock,
+				webhookSvc:  webhookMock,
+			}
+			_, err := svc.DeletePhoto(context.Background(), 1)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetPhotoComments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first, second := uint64(1), uint64(2)
+	repoMock := repomock.NewMockIAccountRepo(ctrl)
+	repoMock.EXPECT().
+		GetPhotoComments(gomock.Any(), uint64(9), gomock.Any()).
+		Return([]accountmodel.Comment{{ID: first, ReplyCount: 3}, {ID: second}}, response.Pagination{}, nil)
+	// comments without replies are not looked up
+	repoMock.EXPECT().
+		GetReplyPreviews(gomock.Any(), []uint64{first}, REPLY_PREVIEW_SIZE).
+		Return([]accountmodel.Comment{{ID: 3, ParentID: &first}, {ID: 4, ParentID: &first}}, nil)
+
+	svc := AccountServiceImpl{accountRepo: repoMock}
+	comments, _, err := svc.GetPhotoComments(context.Background(), 9, pagination.Params{Limit: 20, Sort: pagination.DefaultSort})
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 2) {
+		assert.Len(t, comments[0].Replies, 2)
+		assert.Empty(t, comments[1].Replies)
+	}
+
+	// the cursor continues after the shown replies
+	res := accountmodel.ToCommentResponse(comments[0])
+	cursor, err := pagination.DecodeCursor(res.RepliesCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), cursor.ID)
+	assert.Equal(t, accountmodel.RepliesSort.String(), cursor.Sort)
+}
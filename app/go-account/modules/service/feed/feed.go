@@ -0,0 +1,23 @@
+package feed
+
+import (
+	"context"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type IFeedService interface {
+	// GetFeed is the photos of the users userId follows, newest first
+	GetFeed(ctx context.Context, userId uint64, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error)
+	// Start runs the fan-out workers
+	Start(ctx context.Context) (err error)
+	Stop()
+	// Enqueue fans the photo out in the background, a full queue leaves
+	// it to be read from photo by GetFeed
+	Enqueue(ctx context.Context, photo accountmodel.Photo) (err error)
+	// FanOutPhoto pushes a new photo into the timelines of the followers of its author,
+	// a failure is not fatal, the photo is then read from photo by GetFeed
+	FanOutPhoto(ctx context.Context, photo accountmodel.Photo) (err error)
+}
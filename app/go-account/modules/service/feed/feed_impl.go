@@ -0,0 +1,113 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	followrepo "github.com/mygram/go-account/modules/repository/follow"
+	"github.com/mygram/go-account/pkg/worker"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// above it a photo is not copied to every follower, their feeds read
+// it from photo instead (fan-out on read)
+const DEFAULT_FANOUT_MAX_FOLLOWERS = 10000
+
+type Config struct {
+	FanOutMaxFollowers uint64
+	// copying a photo to every follower is too slow for the upload
+	// request, the workers do it instead
+	Worker worker.Config
+}
+
+type FeedServiceImpl struct {
+	followRepo followrepo.IFollowRepo
+	conf       Config
+	pool       *worker.Pool[accountmodel.Photo]
+}
+
+func NewFeedServiceImpl(followRepo followrepo.IFollowRepo, conf Config) IFeedService {
+	if conf.FanOutMaxFollowers == 0 {
+		conf.FanOutMaxFollowers = DEFAULT_FANOUT_MAX_FOLLOWERS
+	}
+	f := &FeedServiceImpl{
+		followRepo: followRepo,
+		conf:       conf,
+	}
+	f.pool = worker.NewPool("feed-fan-out", conf.Worker, f.fanOutJob, nil)
+	return f
+}
+
+func (f *FeedServiceImpl) GetFeed(ctx context.Context, userId uint64, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetFeed", f)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if photos, page, err = f.followRepo.GetFeed(ctx, userId, params); err != nil {
+		logger.Error(ctx, "error GetFeed",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (f *FeedServiceImpl) Start(ctx context.Context) (err error) {
+	logCtx := fmt.Sprintf("%T - Start", f)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	// photos queued when the last instance stopped are read from photo
+	f.pool.Start()
+	return
+}
+
+func (f *FeedServiceImpl) Stop() {
+	f.pool.Stop()
+}
+
+func (f *FeedServiceImpl) Enqueue(ctx context.Context, photo accountmodel.Photo) (err error) {
+	logCtx := fmt.Sprintf("%T - Enqueue", f)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if err = f.pool.Enqueue(photo); err != nil {
+		logger.Error(ctx, "photo left for fan-out on read",
+			"logCtx", logCtx,
+			"photoId", photo.ID,
+			"error", err)
+	}
+	return
+}
+
+// fanOutJob gives up on a photo after its last attempt, GetFeed then
+// keeps reading it from photo
+func (f *FeedServiceImpl) fanOutJob(ctx context.Context, photo accountmodel.Photo, attempt int) error {
+	err := f.FanOutPhoto(ctx, photo)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		// the author was deleted in the meantime
+		return worker.Permanent(err)
+	}
+	return err
+}
+
+func (f *FeedServiceImpl) FanOutPhoto(ctx context.Context, photo accountmodel.Photo) (err error) {
+	logCtx := fmt.Sprintf("%T - FanOutPhoto", f)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	fannedOut, err := f.followRepo.FanOutPhoto(ctx, photo.ID, photo.UserID, f.conf.FanOutMaxFollowers)
+	if err != nil {
+		logger.Error(ctx, "error FanOutPhoto",
+			"logCtx", logCtx,
+			"photoId", photo.ID,
+			"error", err)
+		return
+	}
+	if !fannedOut {
+		logger.Info(ctx, "photo left for fan-out on read",
+			"logCtx", logCtx,
+			"photoId", photo.ID)
+	}
+	return
+}
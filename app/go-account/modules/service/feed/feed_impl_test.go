@@ -0,0 +1,66 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	followmock "github.com/mygram/go-account/modules/repository/follow/mock"
+	"github.com/mygram/go-account/pkg/worker"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanOutJob(t *testing.T) {
+	type want struct {
+		err       error
+		permanent bool
+	}
+
+	testCases := []struct {
+		desc   string
+		repoOk bool
+		err    error
+		want   want
+	}{
+		{
+			desc:   "copied to the timelines",
+			repoOk: true,
+		},
+		{
+			desc: "database error is retried",
+			err:  errors.New("connection refused"),
+			want: want{err: errors.New("connection refused")},
+		},
+		{
+			desc: "deleted author is not retried",
+			err:  domainerr.NotFound("user"),
+			want: want{err: domainerr.NotFound("user"), permanent: true},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := followmock.NewMockIFollowRepo(ctrl)
+			repoMock.EXPECT().
+				FanOutPhoto(gomock.Any(), uint64(7), uint64(1), uint64(DEFAULT_FANOUT_MAX_FOLLOWERS)).
+				Return(tC.repoOk, tC.err)
+
+			svc := FeedServiceImpl{
+				followRepo: repoMock,
+				conf:       Config{FanOutMaxFollowers: DEFAULT_FANOUT_MAX_FOLLOWERS},
+			}
+			err := svc.fanOutJob(context.Background(), accountmodel.Photo{ID: 7, UserID: 1}, 1)
+			if tC.want.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tC.want.err.Error())
+			assert.Equal(t, tC.want.permanent, worker.IsPermanent(err))
+		})
+	}
+}
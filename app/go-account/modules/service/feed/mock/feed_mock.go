@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/feed/feed.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	account "github.com/mygram/go-account/modules/models/account"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockIFeedService is a mock of IFeedService interface.
+type MockIFeedService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFeedServiceMockRecorder
+}
+
+// MockIFeedServiceMockRecorder is the mock recorder for MockIFeedService.
+type MockIFeedServiceMockRecorder struct {
+	mock *MockIFeedService
+}
+
+// NewMockIFeedService creates a new mock instance.
+func NewMockIFeedService(ctrl *gomock.Controller) *MockIFeedService {
+	mock := &MockIFeedService{ctrl: ctrl}
+	mock.recorder = &MockIFeedServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFeedService) EXPECT() *MockIFeedServiceMockRecorder {
+	return m.recorder
+}
+
+// GetFeed mocks base method.
+func (m *MockIFeedService) GetFeed(ctx context.Context, userId uint64, params pagination.Params) ([]account.Photo, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, userId, params)
+	ret0, _ := ret[0].([]account.Photo)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockIFeedServiceMockRecorder) GetFeed(ctx, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockIFeedService)(nil).GetFeed), ctx, userId, params)
+}
+
+// Start mocks base method.
+func (m *MockIFeedService) Start(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockIFeedServiceMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockIFeedService)(nil).Start), ctx)
+}
+
+// Stop mocks base method.
+func (m *MockIFeedService) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockIFeedServiceMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockIFeedService)(nil).Stop))
+}
+
+// Enqueue mocks base method.
+func (m *MockIFeedService) Enqueue(ctx context.Context, photo account.Photo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, photo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockIFeedServiceMockRecorder) Enqueue(ctx, photo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockIFeedService)(nil).Enqueue), ctx, photo)
+}
+
+// FanOutPhoto mocks base method.
+func (m *MockIFeedService) FanOutPhoto(ctx context.Context, photo account.Photo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FanOutPhoto", ctx, photo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FanOutPhoto indicates an expected call of FanOutPhoto.
+func (mr *MockIFeedServiceMockRecorder) FanOutPhoto(ctx, photo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FanOutPhoto", reflect.TypeOf((*MockIFeedService)(nil).FanOutPhoto), ctx, photo)
+}
@@ -0,0 +1,16 @@
+package follow
+
+import (
+	"context"
+
+	followmodel "github.com/mygram/go-account/modules/models/follow"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type IFollowService interface {
+	Follow(ctx context.Context, followerId uint64, followeeId uint64) (state followmodel.FollowState, err error)
+	Unfollow(ctx context.Context, followerId uint64, followeeId uint64) (state followmodel.FollowState, err error)
+	GetFollowers(ctx context.Context, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error)
+	GetFollowing(ctx context.Context, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error)
+}
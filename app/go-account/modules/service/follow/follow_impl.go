@@ -0,0 +1,95 @@
+package follow
+
+import (
+	"context"
+	"fmt"
+
+	followmodel "github.com/mygram/go-account/modules/models/follow"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	followrepo "github.com/mygram/go-account/modules/repository/follow"
+	notificationsvc "github.com/mygram/go-account/modules/service/notification"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// photos of the followee copied into the timeline on follow, older ones
+// are not in the feed
+const BACKFILL_SIZE = 100
+
+var ErrFollowSelf = domainerr.Validation("can not follow yourself")
+
+type FollowServiceImpl struct {
+	followRepo      followrepo.IFollowRepo
+	notificationSvc notificationsvc.INotificationService
+}
+
+func NewFollowServiceImpl(followRepo followrepo.IFollowRepo, notificationSvc notificationsvc.INotificationService) IFollowService {
+	return &FollowServiceImpl{
+		followRepo:      followRepo,
+		notificationSvc: notificationSvc,
+	}
+}
+
+func (f *FollowServiceImpl) Follow(ctx context.Context, followerId uint64, followeeId uint64) (state followmodel.FollowState, err error) {
+	logCtx := fmt.Sprintf("%T - Follow", f)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if followerId == followeeId {
+		err = ErrFollowSelf
+		return
+	}
+	follow := followmodel.Follow{FollowerID: followerId, FolloweeID: followeeId}
+	if state, err = f.followRepo.Follow(ctx, follow, BACKFILL_SIZE); err != nil {
+		logger.Error(ctx, "error Follow",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if state.Changed {
+		f.notificationSvc.Notify(ctx, notificationmodel.Notification{UserID: followeeId, ActorID: followerId, Type: notificationmodel.TYPE_FOLLOW})
+	}
+	return
+}
+
+func (f *FollowServiceImpl) Unfollow(ctx context.Context, followerId uint64, followeeId uint64) (state followmodel.FollowState, err error) {
+	logCtx := fmt.Sprintf("%T - Unfollow", f)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if followerId == followeeId {
+		err = ErrFollowSelf
+		return
+	}
+	follow := followmodel.Follow{FollowerID: followerId, FolloweeID: followeeId}
+	if state, err = f.followRepo.Unfollow(ctx, follow); err != nil {
+		logger.Error(ctx, "error Unfollow",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (f *FollowServiceImpl) GetFollowers(ctx context.Context, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetFollowers", f)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if follows, page, err = f.followRepo.GetFollowers(ctx, userId, params); err != nil {
+		logger.Error(ctx, "error GetFollowers",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (f *FollowServiceImpl) GetFollowing(ctx context.Context, userId uint64, params pagination.Params) (follows []followmodel.Follow, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetFollowing", f)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if follows, page, err = f.followRepo.GetFollowing(ctx, userId, params); err != nil {
+		logger.Error(ctx, "error GetFollowing",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package follow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	followmodel "github.com/mygram/go-account/modules/models/follow"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	repomock "github.com/mygram/go-account/modules/repository/follow/mock"
+	notificationmock "github.com/mygram/go-account/modules/service/notification/mock"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollow(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		followeeId uint64
+		doMock     func(repoMock *repomock.MockIFollowRepo, notificationMock *notificationmock.MockINotificationService)
+		want       followmodel.FollowState
+		wantErr    error
+	}{
+		{
+			desc:       "happy case",
+			followeeId: 2,
+			doMock: func(repoMock *repomock.MockIFollowRepo, notificationMock *notificationmock.MockINotificationService) {
+				repoMock.EXPECT().
+					Follow(gomock.Any(), followmodel.Follow{FollowerID: 1, FolloweeID: 2}, BACKFILL_SIZE).
+					Return(followmodel.FollowState{UserID: 2, Following: true, FollowerCount: 1, Changed: true}, nil)
+				notificationMock.EXPECT().
+					Notify(gomock.Any(), notificationmodel.Notification{UserID: 2, ActorID: 1, Type: notificationmodel.TYPE_FOLLOW}).
+					Return(nil)
+			},
+			want: followmodel.FollowState{UserID: 2, Following: true, FollowerCount: 1, Changed: true},
+		},
+		{
+			desc:       "following again does not notify again",
+			followeeId: 2,
+			doMock: func(repoMock *repomock.MockIFollowRepo, notificationMock *notificationmock.MockINotificationService) {
+				repoMock.EXPECT().
+					Follow(gomock.Any(), followmodel.Follow{FollowerID: 1, FolloweeID: 2}, BACKFILL_SIZE).
+					Return(followmodel.FollowState{UserID: 2, Following: true, FollowerCount: 1}, nil)
+			},
+			want: followmodel.FollowState{UserID: 2, Following: true, FollowerCount: 1},
+		},
+		{
+			desc:       "can not follow yourself",
+			followeeId: 1,
+			doMock: func(repoMock *repomock.MockIFollowRepo, notificationMock *notificationmock.MockINotificationService) {
+			},
+			wantErr: ErrFollowSelf,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIFollowRepo(ctrl)
+			notificationMock := notificationmock.NewMockINotificationService(ctrl)
+			tC.doMock(repoMock, notificationMock)
+
+			svc := FollowServiceImpl{followRepo: repoMock, notificationSvc: notificationMock}
+			state, err := svc.Follow(context.Background(), 1, tC.followeeId)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, domainerr.ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, state)
+		})
+	}
+}
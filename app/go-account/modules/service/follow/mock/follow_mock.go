@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/follow/follow.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	follow "github.com/mygram/go-account/modules/models/follow"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockIFollowService is a mock of IFollowService interface.
+type MockIFollowService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFollowServiceMockRecorder
+}
+
+// MockIFollowServiceMockRecorder is the mock recorder for MockIFollowService.
+type MockIFollowServiceMockRecorder struct {
+	mock *MockIFollowService
+}
+
+// NewMockIFollowService creates a new mock instance.
+func NewMockIFollowService(ctrl *gomock.Controller) *MockIFollowService {
+	mock := &MockIFollowService{ctrl: ctrl}
+	mock.recorder = &MockIFollowServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFollowService) EXPECT() *MockIFollowServiceMockRecorder {
+	return m.recorder
+}
+
+// Follow mocks base method.
+func (m *MockIFollowService) Follow(ctx context.Context, followerId, followeeId uint64) (follow.FollowState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, followerId, followeeId)
+	ret0, _ := ret[0].(follow.FollowState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockIFollowServiceMockRecorder) Follow(ctx, followerId, followeeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockIFollowService)(nil).Follow), ctx, followerId, followeeId)
+}
+
+// Unfollow mocks base method.
+func (m *MockIFollowService) Unfollow(ctx context.Context, followerId, followeeId uint64) (follow.FollowState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, followerId, followeeId)
+	ret0, _ := ret[0].(follow.FollowState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockIFollowServiceMockRecorder) Unfollow(ctx, followerId, followeeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockIFollowService)(nil).Unfollow), ctx, followerId, followeeId)
+}
+
+// GetFollowers mocks base method.
+func (m *MockIFollowService) GetFollowers(ctx context.Context, userId uint64, params pagination.Params) ([]follow.Follow, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowers", ctx, userId, params)
+	ret0, _ := ret[0].([]follow.Follow)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFollowers indicates an expected call of GetFollowers.
+func (mr *MockIFollowServiceMockRecorder) GetFollowers(ctx, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowers", reflect.TypeOf((*MockIFollowService)(nil).GetFollowers), ctx, userId, params)
+}
+
+// GetFollowing mocks base method.
+func (m *MockIFollowService) GetFollowing(ctx context.Context, userId uint64, params pagination.Params) ([]follow.Follow, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowing", ctx, userId, params)
+	ret0, _ := ret[0].([]follow.Follow)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFollowing indicates an expected call of GetFollowing.
+func (mr *MockIFollowServiceMockRecorder) GetFollowing(ctx, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowing", reflect.TypeOf((*MockIFollowService)(nil).GetFollowing), ctx, userId, params)
+}
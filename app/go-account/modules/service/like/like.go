@@ -0,0 +1,17 @@
+package like
+
+import (
+	"context"
+
+	likemodel "github.com/mygram/go-account/modules/models/like"
+)
+
+type ILikeService interface {
+	Like(ctx context.Context, userId uint64, target likemodel.LikeTarget, targetId uint64) (state likemodel.LikeState, err error)
+	Unlike(ctx context.Context, userId uint64, target likemodel.LikeTarget, targetId uint64) (state likemodel.LikeState, err error)
+	// LikedByMe tells which of targetIds userId liked
+	LikedByMe(ctx context.Context, userId uint64, target likemodel.LikeTarget, targetIds []uint64) (liked map[uint64]bool, err error)
+	// ReconcileLikeCounts recomputes every like_count from the likes table,
+	// it is safe to run again and while likes come in
+	ReconcileLikeCounts(ctx context.Context) (fixed int64, err error)
+}
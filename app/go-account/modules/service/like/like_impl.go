@@ -0,0 +1,144 @@
+package like
+
+import (
+	"context"
+	"fmt"
+
+	likemodel "github.com/mygram/go-account/modules/models/like"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	likerepo "github.com/mygram/go-account/modules/repository/like"
+	notificationsvc "github.com/mygram/go-account/modules/service/notification"
+	realtimesvc "github.com/mygram/go-account/modules/service/realtime"
+	"github.com/mygram/go-common/pkg/logger"
+)
+
+// rows locked at once while reconciling
+const RECONCILE_BATCH_SIZE = 1000
+
+var likeTargets = []likemodel.LikeTarget{
+	likemodel.TARGET_PHOTO,
+	likemodel.TARGET_COMMENT,
+}
+
+type LikeServiceImpl struct {
+	likeRepo        likerepo.ILikeRepo
+	notificationSvc notificationsvc.INotificationService
+	realtimeSvc     realtimesvc.IRealtimeService
+}
+
+func NewLikeServiceImpl(likeRepo likerepo.ILikeRepo, notificationSvc notificationsvc.INotificationService, realtimeSvc realtimesvc.IRealtimeService) ILikeService {
+	return &LikeServiceImpl{
+		likeRepo:        likeRepo,
+		notificationSvc: notificationSvc,
+		realtimeSvc:     realtimeSvc,
+	}
+}
+
+func (l *LikeServiceImpl) Like(ctx context.Context, userId uint64, target likemodel.LikeTarget, targetId uint64) (state likemodel.LikeState, err error) {
+	logCtx := fmt.Sprintf("%T - Like", l)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	like := likemodel.Like{UserID: userId, TargetType: target, TargetID: targetId}
+	if state, err = l.likeRepo.Like(ctx, like); err != nil {
+		logger.Error(ctx, "error Like",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	// liking again does not notify again
+	if state.Changed {
+		notification := notificationmodel.Notification{UserID: state.OwnerID, ActorID: userId, Type: notificationmodel.TYPE_LIKE}
+		switch target {
+		case likemodel.TARGET_PHOTO:
+			notification.PhotoID = &targetId
+		case likemodel.TARGET_COMMENT:
+			notification.CommentID = &targetId
+		}
+		l.notificationSvc.Notify(ctx, notification)
+		l.pushLikeCount(ctx, state)
+	}
+	return
+}
+
+func (l *LikeServiceImpl) Unlike(ctx context.Context, userId uint64, target likemodel.LikeTarget, targetId uint64) (state likemodel.LikeState, err error) {
+	logCtx := fmt.Sprintf("%T - Unlike", l)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	like := likemodel.Like{UserID: userId, TargetType: target, TargetID: targetId}
+	if state, err = l.likeRepo.Unlike(ctx, like); err != nil {
+		logger.Error(ctx, "error Unlike",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if state.Changed {
+		l.pushLikeCount(ctx, state)
+	}
+	return
+}
+
+// pushLikeCount tells the streams watching a photo about its new count,
+// comments are not watched on their own so their counts are not pushed.
+func (l *LikeServiceImpl) pushLikeCount(ctx context.Context, state likemodel.LikeState) {
+	if state.TargetType != likemodel.TARGET_PHOTO {
+		return
+	}
+	l.realtimeSvc.Publish(ctx, realtimemodel.PhotoTopic(state.TargetID), realtimemodel.EVENT_LIKE_COUNT, likemodel.LikeCountResponse{
+		TargetType: state.TargetType,
+		TargetID:   state.TargetID,
+		LikeCount:  state.LikeCount,
+	})
+}
+
+func (l *LikeServiceImpl) LikedByMe(ctx context.Context, userId uint64, target likemodel.LikeTarget, targetIds []uint64) (liked map[uint64]bool, err error) {
+	logCtx := fmt.Sprintf("%T - LikedByMe", l)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	likedIds, err := l.likeRepo.GetLikedTargetIds(ctx, userId, target, targetIds)
+	if err != nil {
+		logger.Error(ctx, "error GetLikedTargetIds",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	liked = make(map[uint64]bool, len(targetIds))
+	for _, id := range targetIds {
+		liked[id] = false
+	}
+	for _, id := range likedIds {
+		liked[id] = true
+	}
+	return
+}
+
+func (l *LikeServiceImpl) ReconcileLikeCounts(ctx context.Context) (fixed int64, err error) {
+	logCtx := fmt.Sprintf("%T - ReconcileLikeCounts", l)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	for _, target := range likeTargets {
+		maxId, err := l.likeRepo.GetMaxTargetId(ctx, target)
+		if err != nil {
+			logger.Error(ctx, "error GetMaxTargetId",
+				"logCtx", logCtx,
+				"target", target,
+				"error", err)
+			return fixed, err
+		}
+		// one short transaction per batch, likes are only blocked for a batch
+		for afterId := uint64(0); afterId < maxId; afterId += RECONCILE_BATCH_SIZE {
+			n, err := l.likeRepo.ReconcileLikeCounts(ctx, target, afterId, afterId+RECONCILE_BATCH_SIZE)
+			if err != nil {
+				logger.Error(ctx, "error ReconcileLikeCounts",
+					"logCtx", logCtx,
+					"target", target,
+					"afterId", afterId,
+					"error", err)
+				return fixed, err
+			}
+			fixed += n
+		}
+	}
+	return
+}
@@ -0,0 +1,190 @@
+package like
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	likemodel "github.com/mygram/go-account/modules/models/like"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	repomock "github.com/mygram/go-account/modules/repository/like/mock"
+	notificationmock "github.com/mygram/go-account/modules/service/notification/mock"
+	realtimemock "github.com/mygram/go-account/modules/service/realtime/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLike(t *testing.T) {
+	photoId := uint64(3)
+	testCases := []struct {
+		desc   string
+		doMock func(repoMock *repomock.MockILikeRepo, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService)
+	}{
+		{
+			desc: "owner is notified of a new like",
+			doMock: func(repoMock *repomock.MockILikeRepo, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					Like(gomock.Any(), likemodel.Like{UserID: 1, TargetType: likemodel.TARGET_PHOTO, TargetID: photoId}).
+					Return(likemodel.LikeState{TargetType: likemodel.TARGET_PHOTO, TargetID: photoId, Liked: true, LikeCount: 1, Changed: true, OwnerID: 2}, nil)
+				notificationMock.EXPECT().
+					Notify(gomock.Any(), notificationmodel.Notification{UserID: 2, ActorID: 1, Type: notificationmodel.TYPE_LIKE, PhotoID: &photoId}).
+					Return(nil)
+				realtimeMock.EXPECT().
+					Publish(gomock.Any(), realtimemodel.PhotoTopic(photoId), realtimemodel.EVENT_LIKE_COUNT, likemodel.LikeCountResponse{TargetType: likemodel.TARGET_PHOTO, TargetID: photoId, LikeCount: 1}).
+					Return(nil)
+			},
+		},
+		{
+			desc: "liking again does not notify again",
+			doMock: func(repoMock *repomock.MockILikeRepo, notificationMock *notificationmock.MockINotificationService, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					Like(gomock.Any(), gomock.Any()).
+					Return(likemodel.LikeState{TargetType: likemodel.TARGET_PHOTO, TargetID: photoId, Liked: true, LikeCount: 1, OwnerID: 2}, nil)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockILikeRepo(ctrl)
+			notificationMock := notificationmock.NewMockINotificationService(ctrl)
+			realtimeMock := realtimemock.NewMockIRealtimeService(ctrl)
+			tC.doMock(repoMock, notificationMock, realtimeMock)
+
+			svc := LikeServiceImpl{likeRepo: repoMock, notificationSvc: notificationMock, realtimeSvc: realtimeMock}
+			state, err := svc.Like(context.Background(), 1, likemodel.TARGET_PHOTO, photoId)
+			assert.NoError(t, err)
+			assert.True(t, state.Liked)
+		})
+	}
+}
+
+func TestUnlike(t *testing.T) {
+	targetId := uint64(9)
+	testCases := []struct {
+		desc   string
+		target likemodel.LikeTarget
+		doMock func(repoMock *repomock.MockILikeRepo, realtimeMock *realtimemock.MockIRealtimeService)
+	}{
+		{
+			desc:   "watchers of the photo get the new count",
+			target: likemodel.TARGET_PHOTO,
+			doMock: func(repoMock *repomock.MockILikeRepo, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					Unlike(gomock.Any(), likemodel.Like{UserID: 1, TargetType: likemodel.TARGET_PHOTO, TargetID: targetId}).
+					Return(likemodel.LikeState{TargetType: likemodel.TARGET_PHOTO, TargetID: targetId, LikeCount: 4, Changed: true}, nil)
+				realtimeMock.EXPECT().
+					Publish(gomock.Any(), realtimemodel.PhotoTopic(targetId), realtimemodel.EVENT_LIKE_COUNT, likemodel.LikeCountResponse{TargetType: likemodel.TARGET_PHOTO, TargetID: targetId, LikeCount: 4}).
+					Return(nil)
+			},
+		},
+		{
+			desc:   "comment counts are not pushed",
+			target: likemodel.TARGET_COMMENT,
+			doMock: func(repoMock *repomock.MockILikeRepo, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					Unlike(gomock.Any(), likemodel.Like{UserID: 1, TargetType: likemodel.TARGET_COMMENT, TargetID: targetId}).
+					Return(likemodel.LikeState{TargetType: likemodel.TARGET_COMMENT, TargetID: targetId, LikeCount: 4, Changed: true}, nil)
+			},
+		},
+		{
+			desc:   "unliking again pushes nothing",
+			target: likemodel.TARGET_PHOTO,
+			doMock: func(repoMock *repomock.MockILikeRepo, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					Unlike(gomock.Any(), gomock.Any()).
+					Return(likemodel.LikeState{TargetType: likemodel.TARGET_PHOTO, TargetID: targetId, LikeCount: 4}, nil)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockILikeRepo(ctrl)
+			realtimeMock := realtimemock.NewMockIRealtimeService(ctrl)
+			tC.doMock(repoMock, realtimeMock)
+
+			svc := LikeServiceImpl{likeRepo: repoMock, realtimeSvc: realtimeMock}
+			state, err := svc.Unlike(context.Background(), 1, tC.target, targetId)
+			assert.NoError(t, err)
+			assert.False(t, state.Liked)
+		})
+	}
+}
+
+func TestLikedByMe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := repomock.NewMockILikeRepo(ctrl)
+	repoMock.EXPECT().
+		GetLikedTargetIds(gomock.Any(), uint64(1), likemodel.TARGET_PHOTO, []uint64{3, 4, 5}).
+		Return([]uint64{4}, nil)
+
+	svc := LikeServiceImpl{likeRepo: repoMock}
+	liked, err := svc.LikedByMe(context.Background(), 1, likemodel.TARGET_PHOTO, []uint64{3, 4, 5})
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint64]bool{3: false, 4: true, 5: false}, liked)
+}
+
+func TestReconcileLikeCounts(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		doMock    func(repoMock *repomock.MockILikeRepo)
+		wantFixed int64
+		wantErr   error
+	}{
+		{
+			desc: "every target in batches",
+			doMock: func(repoMock *repomock.MockILikeRepo) {
+				repoMock.EXPECT().GetMaxTargetId(gomock.Any(), likemodel.TARGET_PHOTO).Return(uint64(2500), nil)
+				repoMock.EXPECT().ReconcileLikeCounts(gomock.Any(), likemodel.TARGET_PHOTO, uint64(0), uint64(1000)).Return(int64(1), nil)
+				repoMock.EXPECT().ReconcileLikeCounts(gomock.Any(), likemodel.TARGET_PHOTO, uint64(1000), uint64(2000)).Return(int64(0), nil)
+				repoMock.EXPECT().ReconcileLikeCounts(gomock.Any(), likemodel.TARGET_PHOTO, uint64(2000), uint64(3000)).Return(int64(2), nil)
+				repoMock.EXPECT().GetMaxTargetId(gomock.Any(), likemodel.TARGET_COMMENT).Return(uint64(10), nil)
+				repoMock.EXPECT().ReconcileLikeCounts(gomock.Any(), likemodel.TARGET_COMMENT, uint64(0), uint64(1000)).Return(int64(4), nil)
+			},
+			wantFixed: 7,
+		},
+		{
+			desc: "nothing to reconcile",
+			doMock: func(repoMock *repomock.MockILikeRepo) {
+				repoMock.EXPECT().GetMaxTargetId(gomock.Any(), likemodel.TARGET_PHOTO).Return(uint64(0), nil)
+				repoMock.EXPECT().GetMaxTargetId(gomock.Any(), likemodel.TARGET_COMMENT).Return(uint64(0), nil)
+			},
+		},
+		{
+			desc: "stops at the first error",
+			doMock: func(repoMock *repomock.MockILikeRepo) {
+				repoMock.EXPECT().GetMaxTargetId(gomock.Any(), likemodel.TARGET_PHOTO).Return(uint64(2500), nil)
+				repoMock.EXPECT().ReconcileLikeCounts(gomock.Any(), likemodel.TARGET_PHOTO, uint64(0), uint64(1000)).Return(int64(1), nil)
+				repoMock.EXPECT().ReconcileLikeCounts(gomock.Any(), likemodel.TARGET_PHOTO, uint64(1000), uint64(2000)).Return(int64(0), errors.New("some error"))
+			},
+			wantFixed: 1,
+			wantErr:   errors.New("some error"),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockILikeRepo(ctrl)
+			tC.doMock(repoMock)
+
+			svc := LikeServiceImpl{likeRepo: repoMock}
+			fixed, err := svc.ReconcileLikeCounts(context.Background())
+			assert.Equal(t, tC.wantFixed, fixed)
+			if tC.wantErr != nil {
+				assert.EqualError(t, err, tC.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/like/like.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	like "github.com/mygram/go-account/modules/models/like"
+)
+
+// MockILikeService is a mock of ILikeService interface.
+type MockILikeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockILikeServiceMockRecorder
+}
+
+// MockILikeServiceMockRecorder is the mock recorder for MockILikeService.
+type MockILikeServiceMockRecorder struct {
+	mock *MockILikeService
+}
+
+// NewMockILikeService creates a new mock instance.
+func NewMockILikeService(ctrl *gomock.Controller) *MockILikeService {
+	mock := &MockILikeService{ctrl: ctrl}
+	mock.recorder = &MockILikeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILikeService) EXPECT() *MockILikeServiceMockRecorder {
+	return m.recorder
+}
+
+// Like mocks base method.
+func (m *MockILikeService) Like(ctx context.Context, userId uint64, target like.LikeTarget, targetId uint64) (like.LikeState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Like", ctx, userId, target, targetId)
+	ret0, _ := ret[0].(like.LikeState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Like indicates an expected call of Like.
+func (mr *MockILikeServiceMockRecorder) Like(ctx, userId, target, targetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Like", reflect.TypeOf((*MockILikeService)(nil).Like), ctx, userId, target, targetId)
+}
+
+// Unlike mocks base method.
+func (m *MockILikeService) Unlike(ctx context.Context, userId uint64, target like.LikeTarget, targetId uint64) (like.LikeState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlike", ctx, userId, target, targetId)
+	ret0, _ := ret[0].(like.LikeState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unlike indicates an expected call of Unlike.
+func (mr *MockILikeServiceMockRecorder) Unlike(ctx, userId, target, targetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlike", reflect.TypeOf((*MockILikeService)(nil).Unlike), ctx, userId, target, targetId)
+}
+
+// LikedByMe mocks base method.
+func (m *MockILikeService) LikedByMe(ctx context.Context, userId uint64, target like.LikeTarget, targetIds []uint64) (map[uint64]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LikedByMe", ctx, userId, target, targetIds)
+	ret0, _ := ret[0].(map[uint64]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LikedByMe indicates an expected call of LikedByMe.
+func (mr *MockILikeServiceMockRecorder) LikedByMe(ctx, userId, target, targetIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LikedByMe", reflect.TypeOf((*MockILikeService)(nil).LikedByMe), ctx, userId, target, targetIds)
+}
+
+// ReconcileLikeCounts mocks base method.
+func (m *MockILikeService) ReconcileLikeCounts(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileLikeCounts", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileLikeCounts indicates an expected call of ReconcileLikeCounts.
+func (mr *MockILikeServiceMockRecorder) ReconcileLikeCounts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileLikeCounts", reflect.TypeOf((*MockILikeService)(nil).ReconcileLikeCounts), ctx)
+}
@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/notification/notification.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	notification "github.com/mygram/go-account/modules/models/notification"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockINotificationService is a mock of INotificationService interface.
+type MockINotificationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotificationServiceMockRecorder
+}
+
+// MockINotificationServiceMockRecorder is the mock recorder for MockINotificationService.
+type MockINotificationServiceMockRecorder struct {
+	mock *MockINotificationService
+}
+
+// NewMockINotificationService creates a new mock instance.
+func NewMockINotificationService(ctrl *gomock.Controller) *MockINotificationService {
+	mock := &MockINotificationService{ctrl: ctrl}
+	mock.recorder = &MockINotificationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotificationService) EXPECT() *MockINotificationServiceMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockINotificationService) Notify(ctx context.Context, notification notification.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockINotificationServiceMockRecorder) Notify(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockINotificationService)(nil).Notify), ctx, notification)
+}
+
+// GetNotifications mocks base method.
+func (m *MockINotificationService) GetNotifications(ctx context.Context, userId uint64, unreadOnly bool, params pagination.Params) ([]notification.Notification, response.Pagination, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, userId, unreadOnly, params)
+	ret0, _ := ret[0].([]notification.Notification)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(int64)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockINotificationServiceMockRecorder) GetNotifications(ctx, userId, unreadOnly, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockINotificationService)(nil).GetNotifications), ctx, userId, unreadOnly, params)
+}
+
+// MarkRead mocks base method.
+func (m *MockINotificationService) MarkRead(ctx context.Context, userId, notificationId uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, userId, notificationId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockINotificationServiceMockRecorder) MarkRead(ctx, userId, notificationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockINotificationService)(nil).MarkRead), ctx, userId, notificationId)
+}
+
+// MarkAllRead mocks base method.
+func (m *MockINotificationService) MarkAllRead(ctx context.Context, userId uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllRead", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllRead indicates an expected call of MarkAllRead.
+func (mr *MockINotificationServiceMockRecorder) MarkAllRead(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllRead", reflect.TypeOf((*MockINotificationService)(nil).MarkAllRead), ctx, userId)
+}
+
+// GetPreferences mocks base method.
+func (m *MockINotificationService) GetPreferences(ctx context.Context, userId uint64) (notification.Preferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userId)
+	ret0, _ := ret[0].(notification.Preferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockINotificationServiceMockRecorder) GetPreferences(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockINotificationService)(nil).GetPreferences), ctx, userId)
+}
+
+// UpdatePreferences mocks base method.
+func (m *MockINotificationService) UpdatePreferences(ctx context.Context, userId uint64, changes notification.Preferences) (notification.Preferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreferences", ctx, userId, changes)
+	ret0, _ := ret[0].(notification.Preferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePreferences indicates an expected call of UpdatePreferences.
+func (mr *MockINotificationServiceMockRecorder) UpdatePreferences(ctx, userId, changes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreferences", reflect.TypeOf((*MockINotificationService)(nil).UpdatePreferences), ctx, userId, changes)
+}
@@ -0,0 +1,21 @@
+package notification
+
+import (
+	"context"
+
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type INotificationService interface {
+	// Notify tells the user about something another user did, the
+	// preferences of the user decide if it is kept
+	Notify(ctx context.Context, notification notificationmodel.Notification) (err error)
+	GetNotifications(ctx context.Context, userId uint64, unreadOnly bool, params pagination.Params) (notifications []notificationmodel.Notification, page response.Pagination, unread int64, err error)
+	MarkRead(ctx context.Context, userId uint64, notificationId uint64) (unread int64, err error)
+	MarkAllRead(ctx context.Context, userId uint64) (marked int64, err error)
+	GetPreferences(ctx context.Context, userId uint64) (prefs notificationmodel.Preferences, err error)
+	// UpdatePreferences changes the types given and keeps the others
+	UpdatePreferences(ctx context.Context, userId uint64, changes notificationmodel.Preferences) (prefs notificationmodel.Preferences, err error)
+}
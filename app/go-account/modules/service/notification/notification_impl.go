@@ -0,0 +1,158 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	notificationrepo "github.com/mygram/go-account/modules/repository/notification"
+	realtimesvc "github.com/mygram/go-account/modules/service/realtime"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type NotificationServiceImpl struct {
+	notificationRepo notificationrepo.INotificationRepo
+	realtimeSvc      realtimesvc.IRealtimeService
+}
+
+func NewNotificationServiceImpl(notificationRepo notificationrepo.INotificationRepo, realtimeSvc realtimesvc.IRealtimeService) INotificationService {
+	return &NotificationServiceImpl{
+		notificationRepo: notificationRepo,
+		realtimeSvc:      realtimeSvc,
+	}
+}
+
+func (n *NotificationServiceImpl) Notify(ctx context.Context, notification notificationmodel.Notification) (err error) {
+	logCtx := fmt.Sprintf("%T - Notify", n)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	// nobody is told about what they did themselves
+	if notification.UserID == 0 || notification.UserID == notification.ActorID {
+		return
+	}
+	notificationId, err := n.notificationRepo.Create(ctx, notification)
+	if err != nil {
+		logger.Error(ctx, "error Create",
+			"logCtx", logCtx,
+			"type", notification.Type,
+			"error", err)
+		return
+	}
+	if notificationId != 0 {
+		n.push(ctx, notification.UserID, notificationId)
+	}
+	return
+}
+
+// push sends a new notification to the open streams of the user,
+// the notification is kept when this fails.
+func (n *NotificationServiceImpl) push(ctx context.Context, userId uint64, notificationId uint64) {
+	logCtx := fmt.Sprintf("%T - push", n)
+
+	notification, err := n.notificationRepo.GetNotification(ctx, userId, notificationId)
+	if err != nil {
+		logger.Error(ctx, "error GetNotification",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	unread, err := n.notificationRepo.CountUnread(ctx, userId)
+	if err != nil {
+		logger.Error(ctx, "error CountUnread",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	n.realtimeSvc.Publish(ctx, realtimemodel.UserTopic(userId), realtimemodel.EVENT_NOTIFICATION, notificationmodel.NotificationEventResponse{
+		UnreadCount:  unread,
+		Notification: notificationmodel.ToNotificationResponse(notification),
+	})
+}
+
+func (n *NotificationServiceImpl) GetNotifications(ctx context.Context, userId uint64, unreadOnly bool, params pagination.Params) (notifications []notificationmodel.Notification, page response.Pagination, unread int64, err error) {
+	logCtx := fmt.Sprintf("%T - GetNotifications", n)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if notifications, page, err = n.notificationRepo.GetNotifications(ctx, userId, unreadOnly, params); err != nil {
+		logger.Error(ctx, "error GetNotifications",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if unread, err = n.notificationRepo.CountUnread(ctx, userId); err != nil {
+		logger.Error(ctx, "error CountUnread",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (n *NotificationServiceImpl) MarkRead(ctx context.Context, userId uint64, notificationId uint64) (unread int64, err error) {
+	logCtx := fmt.Sprintf("%T - MarkRead", n)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if err = n.notificationRepo.MarkRead(ctx, userId, notificationId); err != nil {
+		logger.Error(ctx, "error MarkRead",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if unread, err = n.notificationRepo.CountUnread(ctx, userId); err != nil {
+		logger.Error(ctx, "error CountUnread",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (n *NotificationServiceImpl) MarkAllRead(ctx context.Context, userId uint64) (marked int64, err error) {
+	logCtx := fmt.Sprintf("%T - MarkAllRead", n)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if marked, err = n.notificationRepo.MarkAllRead(ctx, userId); err != nil {
+		logger.Error(ctx, "error MarkAllRead",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (n *NotificationServiceImpl) GetPreferences(ctx context.Context, userId uint64) (prefs notificationmodel.Preferences, err error) {
+	logCtx := fmt.Sprintf("%T - GetPreferences", n)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	stored, err := n.notificationRepo.GetPreferences(ctx, userId)
+	if err != nil {
+		logger.Error(ctx, "error GetPreferences",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	prefs = notificationmodel.ToPreferences(stored)
+	return
+}
+
+func (n *NotificationServiceImpl) UpdatePreferences(ctx context.Context, userId uint64, changes notificationmodel.Preferences) (prefs notificationmodel.Preferences, err error) {
+	logCtx := fmt.Sprintf("%T - UpdatePreferences", n)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	stored := make([]notificationmodel.Preference, 0, len(changes))
+	for t, enabled := range changes {
+		if !t.Valid() {
+			err = domainerr.Validation(fmt.Sprintf("unknown notification type %v", t))
+			return
+		}
+		stored = append(stored, notificationmodel.Preference{UserID: userId, Type: t, Enabled: enabled})
+	}
+	if err = n.notificationRepo.SavePreferences(ctx, stored); err != nil {
+		logger.Error(ctx, "error SavePreferences",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	return n.GetPreferences(ctx, userId)
+}
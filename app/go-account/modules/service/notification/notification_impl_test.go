@@ -0,0 +1,127 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	repomock "github.com/mygram/go-account/modules/repository/notification/mock"
+	realtimemock "github.com/mygram/go-account/modules/service/realtime/mock"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotify(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  notificationmodel.Notification
+		doMock func(repoMock *repomock.MockINotificationRepo, realtimeMock *realtimemock.MockIRealtimeService)
+	}{
+		{
+			desc:  "another user",
+			input: notificationmodel.Notification{UserID: 1, ActorID: 2, Type: notificationmodel.TYPE_FOLLOW},
+			doMock: func(repoMock *repomock.MockINotificationRepo, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					Create(gomock.Any(), notificationmodel.Notification{UserID: 1, ActorID: 2, Type: notificationmodel.TYPE_FOLLOW}).
+					Return(uint64(5), nil)
+				repoMock.EXPECT().
+					GetNotification(gomock.Any(), uint64(1), uint64(5)).
+					Return(notificationmodel.Notification{ID: 5, UserID: 1, ActorID: 2, Type: notificationmodel.TYPE_FOLLOW}, nil)
+				repoMock.EXPECT().
+					CountUnread(gomock.Any(), uint64(1)).
+					Return(int64(3), nil)
+				realtimeMock.EXPECT().
+					Publish(gomock.Any(), realtimemodel.UserTopic(1), realtimemodel.EVENT_NOTIFICATION, notificationmodel.NotificationEventResponse{
+						UnreadCount:  3,
+						Notification: notificationmodel.NotificationResponse{ID: 5, Type: notificationmodel.TYPE_FOLLOW},
+					}).
+					Return(nil)
+			},
+		},
+		{
+			desc:  "type is turned off",
+			input: notificationmodel.Notification{UserID: 1, ActorID: 2, Type: notificationmodel.TYPE_LIKE},
+			doMock: func(repoMock *repomock.MockINotificationRepo, realtimeMock *realtimemock.MockIRealtimeService) {
+				repoMock.EXPECT().
+					Create(gomock.Any(), notificationmodel.Notification{UserID: 1, ActorID: 2, Type: notificationmodel.TYPE_LIKE}).
+					Return(uint64(0), nil)
+			},
+		},
+		{
+			desc:   "own action",
+			input:  notificationmodel.Notification{UserID: 1, ActorID: 1, Type: notificationmodel.TYPE_LIKE},
+			doMock: func(repoMock *repomock.MockINotificationRepo, realtimeMock *realtimemock.MockIRealtimeService) {},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockINotificationRepo(ctrl)
+			realtimeMock := realtimemock.NewMockIRealtimeService(ctrl)
+			tC.doMock(repoMock, realtimeMock)
+
+			svc := NotificationServiceImpl{notificationRepo: repoMock, realtimeSvc: realtimeMock}
+			assert.NoError(t, svc.Notify(context.Background(), tC.input))
+		})
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   notificationmodel.Preferences
+		doMock  func(repoMock *repomock.MockINotificationRepo)
+		want    notificationmodel.Preferences
+		wantErr error
+	}{
+		{
+			desc:  "types left out keep their value",
+			input: notificationmodel.Preferences{notificationmodel.TYPE_LIKE: false},
+			doMock: func(repoMock *repomock.MockINotificationRepo) {
+				repoMock.EXPECT().
+					SavePreferences(gomock.Any(), []notificationmodel.Preference{{UserID: 1, Type: notificationmodel.TYPE_LIKE, Enabled: false}}).
+					Return(nil)
+				repoMock.EXPECT().
+					GetPreferences(gomock.Any(), uint64(1)).
+					Return([]notificationmodel.Preference{
+						{UserID: 1, Type: notificationmodel.TYPE_LIKE, Enabled: false},
+						{UserID: 1, Type: notificationmodel.TYPE_FOLLOW, Enabled: false},
+					}, nil)
+			},
+			want: notificationmodel.Preferences{
+				notificationmodel.TYPE_COMMENT: true,
+				notificationmodel.TYPE_LIKE:    false,
+				notificationmodel.TYPE_FOLLOW:  false,
+				notificationmodel.TYPE_MENTION: true,
+			},
+		},
+		{
+			desc:    "unknown type",
+			input:   notificationmodel.Preferences{"poke": false},
+			doMock:  func(repoMock *repomock.MockINotificationRepo) {},
+			wantErr: domainerr.ErrValidation,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockINotificationRepo(ctrl)
+			tC.doMock(repoMock)
+
+			svc := NotificationServiceImpl{notificationRepo: repoMock}
+			prefs, err := svc.UpdatePreferences(context.Background(), 1, tC.input)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, prefs)
+		})
+	}
+}
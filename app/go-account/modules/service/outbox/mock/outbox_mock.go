@@ -0,0 +1,76 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/outbox/outbox.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIOutboxService is a mock of IOutboxService interface.
+type MockIOutboxService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOutboxServiceMockRecorder
+}
+
+// MockIOutboxServiceMockRecorder is the mock recorder for MockIOutboxService.
+type MockIOutboxServiceMockRecorder struct {
+	mock *MockIOutboxService
+}
+
+// NewMockIOutboxService creates a new mock instance.
+func NewMockIOutboxService(ctrl *gomock.Controller) *MockIOutboxService {
+	mock := &MockIOutboxService{ctrl: ctrl}
+	mock.recorder = &MockIOutboxServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOutboxService) EXPECT() *MockIOutboxServiceMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method.
+func (m *MockIOutboxService) Start(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockIOutboxServiceMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockIOutboxService)(nil).Start), ctx)
+}
+
+// Stop mocks base method.
+func (m *MockIOutboxService) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockIOutboxServiceMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockIOutboxService)(nil).Stop))
+}
+
+// Relay mocks base method.
+func (m *MockIOutboxService) Relay(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Relay", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Relay indicates an expected call of Relay.
+func (mr *MockIOutboxServiceMockRecorder) Relay(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Relay", reflect.TypeOf((*MockIOutboxService)(nil).Relay), ctx)
+}
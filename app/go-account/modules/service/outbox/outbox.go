@@ -0,0 +1,14 @@
+package outbox
+
+import "context"
+
+// IOutboxService is the relay publishing the events of the outbox,
+// an event is published at least once and consumers dedupe on its id.
+type IOutboxService interface {
+	// Start runs the relay, events left by the last instance go first
+	Start(ctx context.Context) (err error)
+	// Stop waits for the batch being published and closes the publisher
+	Stop()
+	// Relay publishes the unpublished events until none is left
+	Relay(ctx context.Context) (published int, err error)
+}
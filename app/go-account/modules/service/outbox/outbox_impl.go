@@ -0,0 +1,175 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+	outboxrepo "github.com/mygram/go-account/modules/repository/outbox"
+	publisherrepo "github.com/mygram/go-account/modules/repository/publisher"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/unitofwork"
+)
+
+const (
+	DEFAULT_BATCH_SIZE    = 100
+	DEFAULT_POLL_INTERVAL = time.Second
+	// the reason kept on the events of a failed batch is cut to this
+	MAX_ERROR_LENGTH = 512
+)
+
+type Config struct {
+	BatchSize    int
+	PollInterval time.Duration
+}
+
+func (conf Config) withDefaults() Config {
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = DEFAULT_BATCH_SIZE
+	}
+	if conf.PollInterval <= 0 {
+		conf.PollInterval = DEFAULT_POLL_INTERVAL
+	}
+	return conf
+}
+
+type OutboxServiceImpl struct {
+	uow        unitofwork.UnitOfWorkInterface
+	outboxRepo outboxrepo.IOutboxRepo
+	publisher  publisherrepo.IPublisher
+	conf       Config
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewOutboxServiceImpl(
+	uow unitofwork.UnitOfWorkInterface,
+	outboxRepo outboxrepo.IOutboxRepo,
+	publisher publisherrepo.IPublisher,
+	conf Config,
+) IOutboxService {
+	return &OutboxServiceImpl{
+		uow:        uow,
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+		conf:       conf.withDefaults(),
+	}
+}
+
+func (o *OutboxServiceImpl) Start(ctx context.Context) (err error) {
+	logCtx := fmt.Sprintf("%T - Start", o)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.cancel != nil {
+		return
+	}
+	ctx, o.cancel = context.WithCancel(ctx)
+	o.wg.Add(1)
+	go o.run(ctx)
+	return
+}
+
+func (o *OutboxServiceImpl) Stop() {
+	o.mu.Lock()
+	cancel := o.cancel
+	o.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		o.wg.Wait()
+	}
+	if err := o.publisher.Close(); err != nil {
+		logger.Error(context.Background(), "error Close publisher", "error", err)
+	}
+}
+
+func (o *OutboxServiceImpl) run(ctx context.Context) {
+	defer o.wg.Done()
+	ticker := time.NewTicker(o.conf.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := o.Relay(ctx); err != nil && ctx.Err() == nil {
+			logger.Error(ctx, "error Relay", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *OutboxServiceImpl) Relay(ctx context.Context) (published int, err error) {
+	for ctx.Err() == nil {
+		var batch int
+		if batch, err = o.relayBatch(ctx); err != nil {
+			return
+		}
+		published += batch
+		if batch < o.conf.BatchSize {
+			return
+		}
+	}
+	return
+}
+
+// relayBatch publishes the oldest events in one transaction. The batch is
+// marked published only when all of it was, otherwise all of it is sent
+// again in the same order, so no event overtakes an older one of its
+// aggregate. Events published before a crash are published again too.
+func (o *OutboxServiceImpl) relayBatch(ctx context.Context) (published int, err error) {
+	logCtx := fmt.Sprintf("%T - relayBatch", o)
+
+	var publishErr error
+	err = o.uow.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		locked, err := o.outboxRepo.TryLockRelay(ctx)
+		if err != nil || !locked {
+			// another instance is relaying
+			return
+		}
+		events, err := o.outboxRepo.GetUnpublished(ctx, o.conf.BatchSize)
+		if err != nil || len(events) == 0 {
+			return
+		}
+
+		ids := eventIds(events)
+		if publishErr = o.publisher.Publish(ctx, events...); publishErr != nil {
+			return o.outboxRepo.RecordFailure(ctx, ids, truncate(publishErr.Error()))
+		}
+		if err = o.outboxRepo.MarkPublished(ctx, ids); err != nil {
+			return
+		}
+		published = len(events)
+		return
+	})
+	if err == nil {
+		err = publishErr
+	}
+	if err != nil {
+		published = 0
+		logger.Error(ctx, "error relay outbox",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func eventIds(events []outboxmodel.Event) []uint64 {
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func truncate(s string) string {
+	if len(s) > MAX_ERROR_LENGTH {
+		return s[:MAX_ERROR_LENGTH]
+	}
+	return s
+}
@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	outboxmodel "github.com/mygram/go-account/modules/models/outbox"
+	repomock "github.com/mygram/go-account/modules/repository/outbox/mock"
+	publisherrepo "github.com/mygram/go-account/modules/repository/publisher"
+	publishermock "github.com/mygram/go-account/modules/repository/publisher/mock"
+	uowmock "github.com/mygram/go-common/pkg/unitofwork/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUowMock runs the work without a database.
+func newUowMock(ctrl *gomock.Controller) *uowmock.MockUnitOfWorkInterface {
+	uowMock := uowmock.NewMockUnitOfWorkInterface(ctrl)
+	uowMock.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+	return uowMock
+}
+
+func events(ids ...uint64) []outboxmodel.Event {
+	res := make([]outboxmodel.Event, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, outboxmodel.Event{ID: id, AggregateType: outboxmodel.AGGREGATE_PHOTO, AggregateID: "1"})
+	}
+	return res
+}
+
+func TestRelay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := repomock.NewMockIOutboxRepo(ctrl)
+	gomock.InOrder(
+		repoMock.EXPECT().TryLockRelay(gomock.Any()).Return(true, nil),
+		repoMock.EXPECT().GetUnpublished(gomock.Any(), 2).Return(events(1, 2), nil),
+		repoMock.EXPECT().MarkPublished(gomock.Any(), []uint64{1, 2}).Return(nil),
+		repoMock.EXPECT().TryLockRelay(gomock.Any()).Return(true, nil),
+		repoMock.EXPECT().GetUnpublished(gomock.Any(), 2).Return(events(3), nil),
+		repoMock.EXPECT().MarkPublished(gomock.Any(), []uint64{3}).Return(nil),
+	)
+	publisher := publisherrepo.NewPublisherMemoryImpl(0)
+
+	svc := OutboxServiceImpl{
+		uow:        newUowMock(ctrl),
+		outboxRepo: repoMock,
+		publisher:  publisher,
+		conf:       Config{BatchSize: 2}.withDefaults(),
+	}
+	published, err := svc.Relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, published)
+	assert.Equal(t, events(1, 2, 3), publisher.Events(), "published in the order they were written")
+}
+
+func TestRelayLockedByAnotherInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := repomock.NewMockIOutboxRepo(ctrl)
+	repoMock.EXPECT().TryLockRelay(gomock.Any()).Return(false, nil)
+
+	svc := OutboxServiceImpl{
+		uow:        newUowMock(ctrl),
+		outboxRepo: repoMock,
+		publisher:  publishermock.NewMockIPublisher(ctrl),
+		conf:       Config{}.withDefaults(),
+	}
+	published, err := svc.Relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+}
+
+func TestRelayPublishFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := repomock.NewMockIOutboxRepo(ctrl)
+	publisherMock := publishermock.NewMockIPublisher(ctrl)
+	gomock.InOrder(
+		repoMock.EXPECT().TryLockRelay(gomock.Any()).Return(true, nil),
+		repoMock.EXPECT().GetUnpublished(gomock.Any(), gomock.Any()).Return(events(1, 2), nil),
+		publisherMock.EXPECT().Publish(gomock.Any(), events(1, 2)).Return(errors.New("broker is down")),
+		// kept unpublished, the whole batch is sent again
+		repoMock.EXPECT().RecordFailure(gomock.Any(), []uint64{1, 2}, "broker is down").Return(nil),
+	)
+
+	svc := OutboxServiceImpl{
+		uow:        newUowMock(ctrl),
+		outboxRepo: repoMock,
+		publisher:  publisherMock,
+		conf:       Config{}.withDefaults(),
+	}
+	published, err := svc.Relay(context.Background())
+	assert.EqualError(t, err, "broker is down")
+	assert.Equal(t, 0, published)
+}
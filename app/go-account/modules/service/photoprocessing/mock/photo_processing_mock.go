@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/photoprocessing/photo_processing.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIPhotoProcessingService is a mock of IPhotoProcessingService interface.
+type MockIPhotoProcessingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPhotoProcessingServiceMockRecorder
+}
+
+// MockIPhotoProcessingServiceMockRecorder is the mock recorder for MockIPhotoProcessingService.
+type MockIPhotoProcessingServiceMockRecorder struct {
+	mock *MockIPhotoProcessingService
+}
+
+// NewMockIPhotoProcessingService creates a new mock instance.
+func NewMockIPhotoProcessingService(ctrl *gomock.Controller) *MockIPhotoProcessingService {
+	mock := &MockIPhotoProcessingService{ctrl: ctrl}
+	mock.recorder = &MockIPhotoProcessingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPhotoProcessingService) EXPECT() *MockIPhotoProcessingServiceMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method.
+func (m *MockIPhotoProcessingService) Start(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockIPhotoProcessingServiceMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockIPhotoProcessingService)(nil).Start), ctx)
+}
+
+// Stop mocks base method.
+func (m *MockIPhotoProcessingService) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockIPhotoProcessingServiceMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockIPhotoProcessingService)(nil).Stop))
+}
+
+// Enqueue mocks base method.
+func (m *MockIPhotoProcessingService) Enqueue(ctx context.Context, photoId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, photoId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockIPhotoProcessingServiceMockRecorder) Enqueue(ctx, photoId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockIPhotoProcessingService)(nil).Enqueue), ctx, photoId)
+}
+
+// Process mocks base method.
+func (m *MockIPhotoProcessingService) Process(ctx context.Context, photoId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Process", ctx, photoId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Process indicates an expected call of Process.
+func (mr *MockIPhotoProcessingServiceMockRecorder) Process(ctx, photoId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockIPhotoProcessingService)(nil).Process), ctx, photoId)
+}
@@ -0,0 +1,17 @@
+package photoprocessing
+
+import (
+	"context"
+)
+
+// IPhotoProcessingService derives the variants, dimensions, dominant
+// color and blurhash of uploaded photos in the background.
+type IPhotoProcessingService interface {
+	// Start runs the workers and picks up the uploads left unprocessed
+	Start(ctx context.Context) (err error)
+	Stop()
+	// Enqueue leaves the photo pending when the queue is full,
+	// it is picked up again on the next start
+	Enqueue(ctx context.Context, photoId uint64) (err error)
+	Process(ctx context.Context, photoId uint64) (err error)
+}
@@ -0,0 +1,227 @@
+package photoprocessing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"path"
+	"strings"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	accountrepo "github.com/mygram/go-account/modules/repository/account"
+	blobrepo "github.com/mygram/go-account/modules/repository/blob"
+	"github.com/mygram/go-account/pkg/imaging"
+	"github.com/mygram/go-account/pkg/worker"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+)
+
+// VariantSpec is one resized copy made of every upload.
+type VariantSpec struct {
+	Name    string
+	MaxSide int
+	// center cropped to a square before resizing
+	Square bool
+	Format string
+}
+
+// every size comes as jpeg and webp, specs of the same name must
+// have the same size
+var VARIANTS = []VariantSpec{
+	{Name: accountmodel.VARIANT_THUMBNAIL, MaxSide: 320, Square: true, Format: imaging.FORMAT_JPEG},
+	{Name: accountmodel.VARIANT_THUMBNAIL, MaxSide: 320, Square: true, Format: imaging.FORMAT_WEBP},
+	{Name: accountmodel.VARIANT_FEED, MaxSide: 1080, Format: imaging.FORMAT_JPEG},
+	{Name: accountmodel.VARIANT_FEED, MaxSide: 1080, Format: imaging.FORMAT_WEBP},
+}
+
+var variantExtensions = map[string]string{
+	imaging.FORMAT_JPEG: ".jpg",
+	imaging.FORMAT_WEBP: ".webp",
+}
+
+type PhotoProcessingServiceImpl struct {
+	accountRepo accountrepo.IAccountRepo
+	photoStore  blobrepo.IBlobStore
+	variants    []VariantSpec
+	pool        *worker.Pool[uint64]
+}
+
+func NewPhotoProcessingServiceImpl(
+	accountRepo accountrepo.IAccountRepo,
+	photoStore blobrepo.IBlobStore,
+	conf worker.Config,
+) IPhotoProcessingService {
+	p := &PhotoProcessingServiceImpl{
+		accountRepo: accountRepo,
+		photoStore:  photoStore,
+		variants:    VARIANTS,
+	}
+	p.pool = worker.NewPool("photo-processing", conf, p.processJob, p.giveUp)
+	return p
+}
+
+func (p *PhotoProcessingServiceImpl) Start(ctx context.Context) (err error) {
+	logCtx := fmt.Sprintf("%T - Start", p)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	p.pool.Start()
+
+	// uploads that were queued or running when the last instance stopped
+	photoIds, err := p.accountRepo.GetPhotoIdsByProcessingStatus(ctx,
+		accountmodel.PROCESSING_PENDING, accountmodel.PROCESSING_RUNNING)
+	if err != nil {
+		logger.Error(ctx, "error GetPhotoIdsByProcessingStatus",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	for _, photoId := range photoIds {
+		if err = p.Enqueue(ctx, photoId); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (p *PhotoProcessingServiceImpl) Stop() {
+	p.pool.Stop()
+}
+
+func (p *PhotoProcessingServiceImpl) Enqueue(ctx context.Context, photoId uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - Enqueue", p)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if err = p.pool.Enqueue(photoId); err != nil {
+		logger.Error(ctx, "photo left pending",
+			"logCtx", logCtx,
+			"photoId", photoId,
+			"error", err)
+	}
+	return
+}
+
+func (p *PhotoProcessingServiceImpl) processJob(ctx context.Context, photoId uint64, attempt int) error {
+	return p.Process(ctx, photoId)
+}
+
+func (p *PhotoProcessingServiceImpl) giveUp(ctx context.Context, photoId uint64, err error) {
+	logCtx := fmt.Sprintf("%T - giveUp", p)
+	if errStatus := p.accountRepo.UpdatePhotoProcessingStatus(ctx, photoId, accountmodel.PROCESSING_FAILED); errStatus != nil {
+		logger.Error(ctx, "error UpdatePhotoProcessingStatus",
+			"logCtx", logCtx,
+			"photoId", photoId,
+			"error", errStatus)
+	}
+}
+
+// Process decodes the original, then stores the variants and what was
+// measured on the photo. Errors that a retry can not fix are
+// worker.Permanent.
+func (p *PhotoProcessingServiceImpl) Process(ctx context.Context, photoId uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - Process", p)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	photo, err := p.accountRepo.GetPhotoById(ctx, photoId)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		// deleted in the meantime
+		return worker.Permanent(err)
+	}
+	if err != nil {
+		return
+	}
+	if photo.PhotoKey == "" {
+		// created from an url, nothing of ours to process
+		return
+	}
+	if err = p.accountRepo.UpdatePhotoProcessingStatus(ctx, photoId, accountmodel.PROCESSING_RUNNING); err != nil {
+		return
+	}
+
+	data, err := p.original(ctx, photo.PhotoKey)
+	if err != nil {
+		logger.Error(ctx, "error when reading photo file",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	// uploads only accept formats that decode, anything else is broken
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return worker.Permanent(err)
+	}
+	photo.Width = uint64(img.Bounds().Dx())
+	photo.Height = uint64(img.Bounds().Dy())
+	photo.DominantColor = imaging.DominantColor(img)
+	photo.BlurHash = imaging.BlurHash(img)
+	if photo.Variants, err = p.storeVariants(ctx, photo.PhotoKey, img); err != nil {
+		logger.Error(ctx, "error when storing variants",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	photo.ProcessingStatus = accountmodel.PROCESSING_DONE
+	if err = p.accountRepo.SavePhotoProcessing(ctx, photo); err != nil {
+		logger.Error(ctx, "error SavePhotoProcessing",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+// original reads the uploaded file, its metadata was stripped before
+// it was stored.
+func (p *PhotoProcessingServiceImpl) original(ctx context.Context, key string) (data []byte, err error) {
+	body, err := p.photoStore.Get(ctx, key)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return nil, worker.Permanent(err)
+	}
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func (p *PhotoProcessingServiceImpl) storeVariants(ctx context.Context, key string, img image.Image) (variants []accountmodel.PhotoVariant, err error) {
+	// the formats of a size are encoded from the same resize
+	sizes := map[string]image.Image{}
+	for _, spec := range p.variants {
+		resized, ok := sizes[spec.Name]
+		if !ok {
+			src := img
+			if spec.Square {
+				src = imaging.CropSquare(src)
+			}
+			resized = imaging.Fit(src, spec.MaxSide)
+			sizes[spec.Name] = resized
+		}
+
+		var buf bytes.Buffer
+		if err = imaging.Encode(&buf, resized, spec.Format); err != nil {
+			return
+		}
+		variant := accountmodel.PhotoVariant{
+			Name:   spec.Name,
+			Format: spec.Format,
+			Width:  uint64(resized.Bounds().Dx()),
+			Height: uint64(resized.Bounds().Dy()),
+			Key:    variantKey(key, spec),
+		}
+		// keys do not change between attempts, a retry overwrites
+		if variant.Url, err = p.photoStore.Put(ctx, variant.Key, imaging.ContentType(spec.Format), &buf, int64(buf.Len())); err != nil {
+			return
+		}
+		variants = append(variants, variant)
+	}
+	return
+}
+
+// variantKey is photo/1/<uuid>_thumbnail.jpg for photo/1/<uuid>.png
+func variantKey(key string, spec VariantSpec) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "_" + spec.Name + variantExtensions[spec.Format]
+}
@@ -0,0 +1,200 @@
+package photoprocessing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	repomock "github.com/mygram/go-account/modules/repository/account/mock"
+	blobmock "github.com/mygram/go-account/modules/repository/blob/mock"
+	"github.com/mygram/go-account/pkg/imaging"
+	"github.com/mygram/go-account/pkg/worker"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// grayJPEG is a w x h gray jpeg
+func grayJPEG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	var encoded bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&encoded, img, nil))
+	return encoded.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	photoKey := "photo/1/this-is-uuid.jpg"
+	uploaded := grayJPEG(t, 200, 100)
+	var webp bytes.Buffer
+	assert.NoError(t, imaging.Encode(&webp, image.NewGray(image.Rect(0, 0, 30, 20)), imaging.FORMAT_WEBP))
+
+	type want struct {
+		err       error
+		permanent bool
+	}
+
+	testCases := []struct {
+		desc   string
+		doMock func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore)
+		want   want
+	}{
+		{
+			desc: "happy case",
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					GetPhotoById(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, PhotoKey: photoKey}, nil)
+				repoMock.EXPECT().
+					UpdatePhotoProcessingStatus(gomock.Any(), uint64(1), accountmodel.PROCESSING_RUNNING).
+					Return(nil)
+				blobMock.EXPECT().
+					Get(gomock.Any(), photoKey).
+					Return(io.NopCloser(bytes.NewReader(uploaded)), nil)
+				blobMock.EXPECT().
+					Put(gomock.Any(), "photo/1/this-is-uuid_thumbnail.jpg", "image/jpeg", gomock.Any(), gomock.Any()).
+					Return("http://localhost:9090/media/photo/1/this-is-uuid_thumbnail.jpg", nil)
+				blobMock.EXPECT().
+					Put(gomock.Any(), "photo/1/this-is-uuid_thumbnail.webp", "image/webp", gomock.Any(), gomock.Any()).
+					Return("http://localhost:9090/media/photo/1/this-is-uuid_thumbnail.webp", nil)
+				blobMock.EXPECT().
+					Put(gomock.Any(), "photo/1/this-is-uuid_feed.jpg", "image/jpeg", gomock.Any(), gomock.Any()).
+					Return("http://localhost:9090/media/photo/1/this-is-uuid_feed.jpg", nil)
+				blobMock.EXPECT().
+					Put(gomock.Any(), "photo/1/this-is-uuid_feed.webp", "image/webp", gomock.Any(), gomock.Any()).
+					Return("http://localhost:9090/media/photo/1/this-is-uuid_feed.webp", nil)
+				repoMock.EXPECT().
+					SavePhotoProcessing(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, photo accountmodel.Photo) error {
+						assert.Equal(t, accountmodel.PROCESSING_DONE, photo.ProcessingStatus)
+						assert.Equal(t, uint64(200), photo.Width)
+						assert.Equal(t, uint64(100), photo.Height)
+						assert.Equal(t, "#808080", photo.DominantColor)
+						assert.Len(t, photo.BlurHash, 28)
+						if assert.Len(t, photo.Variants, 4) {
+							thumbnail, thumbnailWebP, feed := photo.Variants[0], photo.Variants[1], photo.Variants[2]
+							assert.Equal(t, accountmodel.VARIANT_THUMBNAIL, thumbnail.Name)
+							assert.Equal(t, [2]uint64{100, 100}, [2]uint64{thumbnail.Width, thumbnail.Height})
+							assert.Equal(t, "http://localhost:9090/media/photo/1/this-is-uuid_thumbnail.jpg", thumbnail.Url)
+							assert.Equal(t, accountmodel.VARIANT_THUMBNAIL, thumbnailWebP.Name)
+							assert.Equal(t, imaging.FORMAT_WEBP, thumbnailWebP.Format)
+							assert.Equal(t, [2]uint64{100, 100}, [2]uint64{thumbnailWebP.Width, thumbnailWebP.Height})
+							assert.Equal(t, accountmodel.VARIANT_FEED, feed.Name)
+							assert.Equal(t, [2]uint64{200, 100}, [2]uint64{feed.Width, feed.Height})
+						}
+						return nil
+					})
+			},
+		},
+		{
+			desc: "webp upload gets variants",
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					GetPhotoById(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, PhotoKey: "photo/1/this-is-uuid.webp"}, nil)
+				repoMock.EXPECT().
+					UpdatePhotoProcessingStatus(gomock.Any(), uint64(1), accountmodel.PROCESSING_RUNNING).
+					Return(nil)
+				blobMock.EXPECT().
+					Get(gomock.Any(), "photo/1/this-is-uuid.webp").
+					Return(io.NopCloser(bytes.NewReader(webp.Bytes())), nil)
+				blobMock.EXPECT().
+					Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return("http://localhost:9090/media/variant", nil).
+					Times(len(VARIANTS))
+				repoMock.EXPECT().
+					SavePhotoProcessing(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, photo accountmodel.Photo) error {
+						assert.Equal(t, accountmodel.PROCESSING_DONE, photo.ProcessingStatus)
+						assert.Equal(t, [2]uint64{30, 20}, [2]uint64{photo.Width, photo.Height})
+						assert.Len(t, photo.Variants, len(VARIANTS))
+						return nil
+					})
+			},
+		},
+		{
+			desc: "broken file is not retried",
+			want: want{err: errors.New("unexpected EOF"), permanent: true},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					GetPhotoById(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, PhotoKey: photoKey}, nil)
+				repoMock.EXPECT().
+					UpdatePhotoProcessingStatus(gomock.Any(), uint64(1), accountmodel.PROCESSING_RUNNING).
+					Return(nil)
+				blobMock.EXPECT().
+					Get(gomock.Any(), photoKey).
+					Return(io.NopCloser(bytes.NewReader(uploaded[:40])), nil)
+			},
+		},
+		{
+			desc: "deleted photo is not retried",
+			want: want{err: domainerr.NotFound("photo"), permanent: true},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					GetPhotoById(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{}, domainerr.NotFound("photo"))
+			},
+		},
+		{
+			desc: "store error is retried",
+			want: want{err: errors.New("connection refused")},
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					GetPhotoById(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, PhotoKey: photoKey}, nil)
+				repoMock.EXPECT().
+					UpdatePhotoProcessingStatus(gomock.Any(), uint64(1), accountmodel.PROCESSING_RUNNING).
+					Return(nil)
+				blobMock.EXPECT().
+					Get(gomock.Any(), photoKey).
+					Return(nil, errors.New("connection refused"))
+			},
+		},
+		{
+			desc: "photo created from an url",
+			doMock: func(repoMock *repomock.MockIAccountRepo, blobMock *blobmock.MockIBlobStore) {
+				repoMock.EXPECT().
+					GetPhotoById(gomock.Any(), uint64(1)).
+					Return(accountmodel.Photo{ID: 1, PhotoUrl: "https://example.com/a.jpg"}, nil)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIAccountRepo(ctrl)
+			blobMock := blobmock.NewMockIBlobStore(ctrl)
+			tC.doMock(repoMock, blobMock)
+
+			svc := PhotoProcessingServiceImpl{
+				accountRepo: repoMock,
+				photoStore:  blobMock,
+				variants:    VARIANTS,
+			}
+			err := svc.Process(context.Background(), 1)
+			if tC.want.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tC.want.err.Error())
+			assert.Equal(t, tC.want.permanent, worker.IsPermanent(err))
+		})
+	}
+}
+
+func TestVariantKey(t *testing.T) {
+	feed := VariantSpec{Name: accountmodel.VARIANT_FEED, Format: imaging.FORMAT_JPEG}
+	thumbnail := VariantSpec{Name: accountmodel.VARIANT_THUMBNAIL, Format: imaging.FORMAT_WEBP}
+	assert.Equal(t, "photo/1/this-is-uuid_feed.jpg", variantKey("photo/1/this-is-uuid.png", feed))
+	assert.Equal(t, "photo/1/this-is-uuid_thumbnail.webp", variantKey("photo/1/this-is-uuid.jpg", thumbnail))
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/realtime/realtime.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	realtime "github.com/mygram/go-account/modules/models/realtime"
+	realtime0 "github.com/mygram/go-account/modules/repository/realtime"
+)
+
+// MockIRealtimeService is a mock of IRealtimeService interface.
+type MockIRealtimeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRealtimeServiceMockRecorder
+}
+
+// MockIRealtimeServiceMockRecorder is the mock recorder for MockIRealtimeService.
+type MockIRealtimeServiceMockRecorder struct {
+	mock *MockIRealtimeService
+}
+
+// NewMockIRealtimeService creates a new mock instance.
+func NewMockIRealtimeService(ctrl *gomock.Controller) *MockIRealtimeService {
+	mock := &MockIRealtimeService{ctrl: ctrl}
+	mock.recorder = &MockIRealtimeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRealtimeService) EXPECT() *MockIRealtimeServiceMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockIRealtimeService) Publish(ctx context.Context, topic realtime.Topic, eventType realtime.EventType, data interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, topic, eventType, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockIRealtimeServiceMockRecorder) Publish(ctx, topic, eventType, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockIRealtimeService)(nil).Publish), ctx, topic, eventType, data)
+}
+
+// Subscribe mocks base method.
+func (m *MockIRealtimeService) Subscribe(ctx context.Context, userId uint64, photoIds []uint64) (*realtime0.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, userId, photoIds)
+	ret0, _ := ret[0].(*realtime0.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockIRealtimeServiceMockRecorder) Subscribe(ctx, userId, photoIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockIRealtimeService)(nil).Subscribe), ctx, userId, photoIds)
+}
+
+// WatchPhoto mocks base method.
+func (m *MockIRealtimeService) WatchPhoto(ctx context.Context, sub *realtime0.Subscription, photoId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchPhoto", ctx, sub, photoId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchPhoto indicates an expected call of WatchPhoto.
+func (mr *MockIRealtimeServiceMockRecorder) WatchPhoto(ctx, sub, photoId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchPhoto", reflect.TypeOf((*MockIRealtimeService)(nil).WatchPhoto), ctx, sub, photoId)
+}
+
+// UnwatchPhoto mocks base method.
+func (m *MockIRealtimeService) UnwatchPhoto(ctx context.Context, sub *realtime0.Subscription, photoId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnwatchPhoto", ctx, sub, photoId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnwatchPhoto indicates an expected call of UnwatchPhoto.
+func (mr *MockIRealtimeServiceMockRecorder) UnwatchPhoto(ctx, sub, photoId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnwatchPhoto", reflect.TypeOf((*MockIRealtimeService)(nil).UnwatchPhoto), ctx, sub, photoId)
+}
@@ -0,0 +1,17 @@
+package realtime
+
+import (
+	"context"
+
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	realtimerepo "github.com/mygram/go-account/modules/repository/realtime"
+)
+
+type IRealtimeService interface {
+	// Publish is best effort, callers log the error and keep their write
+	Publish(ctx context.Context, topic realtimemodel.Topic, eventType realtimemodel.EventType, data interface{}) (err error)
+	// Subscribe opens a stream of the events of the user and of the photos
+	Subscribe(ctx context.Context, userId uint64, photoIds []uint64) (sub *realtimerepo.Subscription, err error)
+	WatchPhoto(ctx context.Context, sub *realtimerepo.Subscription, photoId uint64) (err error)
+	UnwatchPhoto(ctx context.Context, sub *realtimerepo.Subscription, photoId uint64) (err error)
+}
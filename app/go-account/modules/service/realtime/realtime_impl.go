@@ -0,0 +1,82 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	realtimerepo "github.com/mygram/go-account/modules/repository/realtime"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+)
+
+var ErrTooManyPhotos = domainerr.Validation(fmt.Sprintf("a stream can not watch more than %v photos", realtimemodel.MAX_PHOTO_SUBSCRIPTIONS))
+
+type RealtimeServiceImpl struct {
+	hub realtimerepo.IHub
+}
+
+func NewRealtimeServiceImpl(hub realtimerepo.IHub) IRealtimeService {
+	return &RealtimeServiceImpl{
+		hub: hub,
+	}
+}
+
+func (r *RealtimeServiceImpl) Publish(ctx context.Context, topic realtimemodel.Topic, eventType realtimemodel.EventType, data interface{}) (err error) {
+	logCtx := fmt.Sprintf("%T - Publish", r)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	event, err := realtimemodel.NewEvent(topic, eventType, data)
+	if err != nil {
+		logger.Error(ctx, "error NewEvent",
+			"logCtx", logCtx,
+			"type", eventType,
+			"error", err)
+		return
+	}
+	if err = r.hub.Publish(ctx, event); err != nil {
+		logger.Error(ctx, "error Publish",
+			"logCtx", logCtx,
+			"type", eventType,
+			"error", err)
+	}
+	return
+}
+
+func (r *RealtimeServiceImpl) Subscribe(ctx context.Context, userId uint64, photoIds []uint64) (sub *realtimerepo.Subscription, err error) {
+	logCtx := fmt.Sprintf("%T - Subscribe", r)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if len(photoIds) > realtimemodel.MAX_PHOTO_SUBSCRIPTIONS {
+		err = ErrTooManyPhotos
+		return
+	}
+	topics := make([]realtimemodel.Topic, 0, len(photoIds)+1)
+	topics = append(topics, realtimemodel.UserTopic(userId))
+	for _, photoId := range photoIds {
+		topics = append(topics, realtimemodel.PhotoTopic(photoId))
+	}
+	sub = r.hub.Subscribe(topics...)
+	return
+}
+
+func (r *RealtimeServiceImpl) WatchPhoto(ctx context.Context, sub *realtimerepo.Subscription, photoId uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - WatchPhoto", r)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	// the user topic is not one of the photos
+	if sub.Topics()-1 >= realtimemodel.MAX_PHOTO_SUBSCRIPTIONS {
+		err = ErrTooManyPhotos
+		return
+	}
+	sub.Subscribe(realtimemodel.PhotoTopic(photoId))
+	return
+}
+
+func (r *RealtimeServiceImpl) UnwatchPhoto(ctx context.Context, sub *realtimerepo.Subscription, photoId uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - UnwatchPhoto", r)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	sub.Unsubscribe(realtimemodel.PhotoTopic(photoId))
+	return
+}
@@ -0,0 +1,80 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	realtimemodel "github.com/mygram/go-account/modules/models/realtime"
+	realtimerepo "github.com/mygram/go-account/modules/repository/realtime"
+	hubmock "github.com/mygram/go-account/modules/repository/realtime/mock"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		doMock  func(hubMock *hubmock.MockIHub)
+		wantErr bool
+	}{
+		{
+			desc: "payload is sent as json",
+			doMock: func(hubMock *hubmock.MockIHub) {
+				hubMock.EXPECT().
+					Publish(gomock.Any(), realtimemodel.Event{
+						Type:  realtimemodel.EVENT_LIKE_COUNT,
+						Topic: realtimemodel.PhotoTopic(3),
+						Data:  []byte(`{"like_count":1}`),
+					}).
+					Return(nil)
+			},
+		},
+		{
+			desc: "hub is down",
+			doMock: func(hubMock *hubmock.MockIHub) {
+				hubMock.EXPECT().
+					Publish(gomock.Any(), gomock.Any()).
+					Return(errors.New("some error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hubMock := hubmock.NewMockIHub(ctrl)
+			tC.doMock(hubMock)
+
+			svc := RealtimeServiceImpl{hub: hubMock}
+			err := svc.Publish(context.Background(), realtimemodel.PhotoTopic(3), realtimemodel.EVENT_LIKE_COUNT, map[string]int{"like_count": 1})
+			assert.Equal(t, tC.wantErr, err != nil)
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ctx := context.Background()
+	svc := RealtimeServiceImpl{hub: realtimerepo.NewHubMemoryImpl(8)}
+
+	sub, err := svc.Subscribe(ctx, 1, []uint64{2, 3})
+	assert.NoError(t, err)
+	defer sub.Close()
+	assert.Equal(t, 3, sub.Topics())
+
+	assert.NoError(t, svc.UnwatchPhoto(ctx, sub, 2))
+	assert.Equal(t, 2, sub.Topics())
+
+	tooMany := make([]uint64, realtimemodel.MAX_PHOTO_SUBSCRIPTIONS+1)
+	_, err = svc.Subscribe(ctx, 1, tooMany)
+	assert.ErrorIs(t, err, domainerr.ErrValidation)
+
+	for photoId := uint64(4); sub.Topics() <= realtimemodel.MAX_PHOTO_SUBSCRIPTIONS; photoId++ {
+		assert.NoError(t, svc.WatchPhoto(ctx, sub, photoId))
+	}
+	err = svc.WatchPhoto(ctx, sub, 1000)
+	assert.ErrorIs(t, err, domainerr.ErrValidation)
+}
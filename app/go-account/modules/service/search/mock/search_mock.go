@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/search/search.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	search "github.com/mygram/go-account/modules/models/search"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockISearchService is a mock of ISearchService interface.
+type MockISearchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISearchServiceMockRecorder
+}
+
+// MockISearchServiceMockRecorder is the mock recorder for MockISearchService.
+type MockISearchServiceMockRecorder struct {
+	mock *MockISearchService
+}
+
+// NewMockISearchService creates a new mock instance.
+func NewMockISearchService(ctrl *gomock.Controller) *MockISearchService {
+	mock := &MockISearchService{ctrl: ctrl}
+	mock.recorder = &MockISearchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISearchService) EXPECT() *MockISearchServiceMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockISearchService) Search(ctx context.Context, params search.Params) ([]search.Result, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, params)
+	ret0, _ := ret[0].([]search.Result)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockISearchServiceMockRecorder) Search(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockISearchService)(nil).Search), ctx, params)
+}
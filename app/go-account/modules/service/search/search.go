@@ -0,0 +1,12 @@
+package search
+
+import (
+	"context"
+
+	searchmodel "github.com/mygram/go-account/modules/models/search"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type ISearchService interface {
+	Search(ctx context.Context, params searchmodel.Params) (results []searchmodel.Result, page response.Pagination, err error)
+}
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	searchmodel "github.com/mygram/go-account/modules/models/search"
+	accountrepo "github.com/mygram/go-account/modules/repository/account"
+	searchrepo "github.com/mygram/go-account/modules/repository/search"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type SearchServiceImpl struct {
+	searchBackend searchrepo.ISearchBackend
+	accountRepo   accountrepo.IAccountRepo
+}
+
+func NewSearchServiceImpl(searchBackend searchrepo.ISearchBackend, accountRepo accountrepo.IAccountRepo) ISearchService {
+	return &SearchServiceImpl{
+		searchBackend: searchBackend,
+		accountRepo:   accountRepo,
+	}
+}
+
+// Search asks the backend for one hit more than the limit to know if
+// there is a next page, then loads the rows of the hits in rank order.
+func (s *SearchServiceImpl) Search(ctx context.Context, params searchmodel.Params) (results []searchmodel.Result, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - Search", s)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	query := params
+	query.Limit++
+
+	var hits []searchmodel.Hit
+	switch params.Type {
+	case searchmodel.TYPE_USER:
+		hits, err = s.searchBackend.SearchUsers(ctx, query)
+	default:
+		hits, err = s.searchBackend.SearchPhotos(ctx, query)
+	}
+	if err != nil {
+		logger.Error(ctx, "error Search",
+			"logCtx", logCtx,
+			"type", params.Type,
+			"error", err)
+		return
+	}
+	hits, page = searchmodel.Paginate(hits, params)
+
+	ids := make([]uint64, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	switch params.Type {
+	case searchmodel.TYPE_USER:
+		results, err = s.withUsers(ctx, hits, ids)
+	default:
+		results, err = s.withPhotos(ctx, hits, ids)
+	}
+	if err != nil {
+		logger.Error(ctx, "error loading search hits",
+			"logCtx", logCtx,
+			"type", params.Type,
+			"error", err)
+	}
+	return
+}
+
+// hits the backend still has after the row is gone are left out
+func (s *SearchServiceImpl) withUsers(ctx context.Context, hits []searchmodel.Hit, ids []uint64) (results []searchmodel.Result, err error) {
+	users, err := s.accountRepo.GetUsersByIds(ctx, ids)
+	if err != nil {
+		return
+	}
+	byId := make(map[uint64]accountmodel.User, len(users))
+	for _, user := range users {
+		byId[user.ID] = user
+	}
+	results = make([]searchmodel.Result, 0, len(hits))
+	for _, hit := range hits {
+		if user, ok := byId[hit.ID]; ok {
+			results = append(results, searchmodel.Result{Type: searchmodel.TYPE_USER, Hit: hit, User: &user})
+		}
+	}
+	return
+}
+
+func (s *SearchServiceImpl) withPhotos(ctx context.Context, hits []searchmodel.Hit, ids []uint64) (results []searchmodel.Result, err error) {
+	photos, err := s.accountRepo.GetPhotosByIds(ctx, ids)
+	if err != nil {
+		return
+	}
+	byId := make(map[uint64]accountmodel.Photo, len(photos))
+	for _, photo := range photos {
+		byId[photo.ID] = photo
+	}
+	results = make([]searchmodel.Result, 0, len(hits))
+	for _, hit := range hits {
+		if photo, ok := byId[hit.ID]; ok {
+			results = append(results, searchmodel.Result{Type: searchmodel.TYPE_PHOTO, Hit: hit, Photo: &photo})
+		}
+	}
+	return
+}
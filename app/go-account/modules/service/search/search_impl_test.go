@@ -0,0 +1,72 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	searchmodel "github.com/mygram/go-account/modules/models/search"
+	accountmock "github.com/mygram/go-account/modules/repository/account/mock"
+	searchmock "github.com/mygram/go-account/modules/repository/search/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		params   searchmodel.Params
+		doMock   func(backendMock *searchmock.MockISearchBackend, repoMock *accountmock.MockIAccountRepo)
+		wantIds  []uint64
+		wantMore bool
+	}{
+		{
+			desc:   "photos keep the rank order and deleted ones are left out",
+			params: searchmodel.Params{Q: "sunset", Type: searchmodel.TYPE_PHOTO, Limit: 2},
+			doMock: func(backendMock *searchmock.MockISearchBackend, repoMock *accountmock.MockIAccountRepo) {
+				backendMock.EXPECT().
+					SearchPhotos(gomock.Any(), searchmodel.Params{Q: "sunset", Type: searchmodel.TYPE_PHOTO, Limit: 3}).
+					Return([]searchmodel.Hit{{ID: 9}, {ID: 4}, {ID: 5}}, nil)
+				repoMock.EXPECT().
+					GetPhotosByIds(gomock.Any(), []uint64{9, 4}).
+					Return([]accountmodel.Photo{{ID: 4}, {ID: 9}}, nil)
+			},
+			wantIds:  []uint64{9, 4},
+			wantMore: true,
+		},
+		{
+			desc:   "users",
+			params: searchmodel.Params{Q: "ali", Type: searchmodel.TYPE_USER, Limit: 2},
+			doMock: func(backendMock *searchmock.MockISearchBackend, repoMock *accountmock.MockIAccountRepo) {
+				backendMock.EXPECT().
+					SearchUsers(gomock.Any(), gomock.Any()).
+					Return([]searchmodel.Hit{{ID: 3}, {ID: 1}}, nil)
+				repoMock.EXPECT().
+					GetUsersByIds(gomock.Any(), []uint64{3, 1}).
+					Return([]accountmodel.User{{ID: 1}}, nil)
+			},
+			wantIds: []uint64{1},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			backendMock := searchmock.NewMockISearchBackend(ctrl)
+			repoMock := accountmock.NewMockIAccountRepo(ctrl)
+			tC.doMock(backendMock, repoMock)
+
+			svc := SearchServiceImpl{searchBackend: backendMock, accountRepo: repoMock}
+			results, page, err := svc.Search(context.Background(), tC.params)
+			assert.NoError(t, err)
+			var ids []uint64
+			for _, result := range results {
+				ids = append(ids, result.Hit.ID)
+				assert.Equal(t, tC.params.Type, result.Type)
+			}
+			assert.Equal(t, tC.wantIds, ids)
+			assert.Equal(t, tC.wantMore, page.HasMore)
+		})
+	}
+}
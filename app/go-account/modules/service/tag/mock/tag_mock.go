@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/tag/tag.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	account "github.com/mygram/go-account/modules/models/account"
+	tag "github.com/mygram/go-account/modules/models/tag"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockITagService is a mock of ITagService interface.
+type MockITagService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITagServiceMockRecorder
+}
+
+// MockITagServiceMockRecorder is the mock recorder for MockITagService.
+type MockITagServiceMockRecorder struct {
+	mock *MockITagService
+}
+
+// NewMockITagService creates a new mock instance.
+func NewMockITagService(ctrl *gomock.Controller) *MockITagService {
+	mock := &MockITagService{ctrl: ctrl}
+	mock.recorder = &MockITagServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITagService) EXPECT() *MockITagServiceMockRecorder {
+	return m.recorder
+}
+
+// SyncPhoto mocks base method.
+func (m *MockITagService) SyncPhoto(ctx context.Context, photo account.Photo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncPhoto", ctx, photo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncPhoto indicates an expected call of SyncPhoto.
+func (mr *MockITagServiceMockRecorder) SyncPhoto(ctx, photo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPhoto", reflect.TypeOf((*MockITagService)(nil).SyncPhoto), ctx, photo)
+}
+
+// SyncComment mocks base method.
+func (m *MockITagService) SyncComment(ctx context.Context, comment account.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncComment", ctx, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncComment indicates an expected call of SyncComment.
+func (mr *MockITagServiceMockRecorder) SyncComment(ctx, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncComment", reflect.TypeOf((*MockITagService)(nil).SyncComment), ctx, comment)
+}
+
+// RemovePhoto mocks base method.
+func (m *MockITagService) RemovePhoto(ctx context.Context, photoId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePhoto", ctx, photoId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePhoto indicates an expected call of RemovePhoto.
+func (mr *MockITagServiceMockRecorder) RemovePhoto(ctx, photoId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePhoto", reflect.TypeOf((*MockITagService)(nil).RemovePhoto), ctx, photoId)
+}
+
+// RemoveComment mocks base method.
+func (m *MockITagService) RemoveComment(ctx context.Context, commentId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveComment", ctx, commentId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveComment indicates an expected call of RemoveComment.
+func (mr *MockITagServiceMockRecorder) RemoveComment(ctx, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveComment", reflect.TypeOf((*MockITagService)(nil).RemoveComment), ctx, commentId)
+}
+
+// GetPhotosByTag mocks base method.
+func (m *MockITagService) GetPhotosByTag(ctx context.Context, name string, params pagination.Params) ([]account.Photo, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPhotosByTag", ctx, name, params)
+	ret0, _ := ret[0].([]account.Photo)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPhotosByTag indicates an expected call of GetPhotosByTag.
+func (mr *MockITagServiceMockRecorder) GetPhotosByTag(ctx, name, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhotosByTag", reflect.TypeOf((*MockITagService)(nil).GetPhotosByTag), ctx, name, params)
+}
+
+// GetMentions mocks base method.
+func (m *MockITagService) GetMentions(ctx context.Context, userId uint64, params pagination.Params) ([]tag.Mention, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMentions", ctx, userId, params)
+	ret0, _ := ret[0].([]tag.Mention)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMentions indicates an expected call of GetMentions.
+func (mr *MockITagServiceMockRecorder) GetMentions(ctx, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMentions", reflect.TypeOf((*MockITagService)(nil).GetMentions), ctx, userId, params)
+}
@@ -0,0 +1,23 @@
+package tag
+
+import (
+	"context"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	tagmodel "github.com/mygram/go-account/modules/models/tag"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// ITagService keeps the tags of a photo or comment in step with its
+// text, call the Sync and Remove methods in the transaction of the write.
+type ITagService interface {
+	// SyncPhoto links the hashtags and mentions of the caption to the photo
+	SyncPhoto(ctx context.Context, photo accountmodel.Photo) (err error)
+	// SyncComment links the mentions of the message to the comment
+	SyncComment(ctx context.Context, comment accountmodel.Comment) (err error)
+	RemovePhoto(ctx context.Context, photoId uint64) (err error)
+	RemoveComment(ctx context.Context, commentId uint64) (err error)
+	GetPhotosByTag(ctx context.Context, name string, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error)
+	GetMentions(ctx context.Context, userId uint64, params pagination.Params) (mentions []tagmodel.Mention, page response.Pagination, err error)
+}
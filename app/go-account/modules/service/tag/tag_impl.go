@@ -0,0 +1,133 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	tagmodel "github.com/mygram/go-account/modules/models/tag"
+	tagrepo "github.com/mygram/go-account/modules/repository/tag"
+	notificationsvc "github.com/mygram/go-account/modules/service/notification"
+	"github.com/mygram/go-account/pkg/tagparse"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+var ErrInvalidTag = domainerr.Validation("invalid hashtag")
+
+type TagServiceImpl struct {
+	tagRepo         tagrepo.ITagRepo
+	notificationSvc notificationsvc.INotificationService
+}
+
+func NewTagServiceImpl(tagRepo tagrepo.ITagRepo, notificationSvc notificationsvc.INotificationService) ITagService {
+	return &TagServiceImpl{
+		tagRepo:         tagRepo,
+		notificationSvc: notificationSvc,
+	}
+}
+
+func (t *TagServiceImpl) SyncPhoto(ctx context.Context, photo accountmodel.Photo) (err error) {
+	logCtx := fmt.Sprintf("%T - SyncPhoto", t)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if err = t.tagRepo.SyncPhotoHashtags(ctx, photo.ID, tagparse.Hashtags(photo.Caption)); err != nil {
+		logger.Error(ctx, "error SyncPhotoHashtags",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	source := tagmodel.Mention{
+		AuthorID:   photo.UserID,
+		SourceType: tagmodel.SOURCE_PHOTO,
+		SourceID:   photo.ID,
+		PhotoID:    photo.ID,
+	}
+	err = t.syncMentions(ctx, source, tagparse.Mentions(photo.Caption))
+	return
+}
+
+func (t *TagServiceImpl) SyncComment(ctx context.Context, comment accountmodel.Comment) (err error) {
+	logCtx := fmt.Sprintf("%T - SyncComment", t)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	source := tagmodel.Mention{
+		AuthorID:   comment.UserID,
+		SourceType: tagmodel.SOURCE_COMMENT,
+		SourceID:   comment.ID,
+		PhotoID:    comment.PhotoID,
+	}
+	return t.syncMentions(ctx, source, tagparse.Mentions(comment.Message))
+}
+
+// syncMentions notifies the users mentioned for the first time, an edit
+// that keeps a mention does not notify again.
+func (t *TagServiceImpl) syncMentions(ctx context.Context, source tagmodel.Mention, usernames []string) (err error) {
+	logCtx := fmt.Sprintf("%T - syncMentions", t)
+
+	added, err := t.tagRepo.SyncMentions(ctx, source, usernames)
+	if err != nil {
+		logger.Error(ctx, "error SyncMentions",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	for _, mention := range added {
+		notification := notificationmodel.Notification{
+			UserID:  mention.UserID,
+			ActorID: mention.AuthorID,
+			Type:    notificationmodel.TYPE_MENTION,
+			PhotoID: &source.PhotoID,
+		}
+		if source.SourceType == tagmodel.SOURCE_COMMENT {
+			notification.CommentID = &source.SourceID
+		}
+		// called in the transaction of the source, a failed insert
+		// aborts it anyway
+		if err = t.notificationSvc.Notify(ctx, notification); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (t *TagServiceImpl) RemovePhoto(ctx context.Context, photoId uint64) (err error) {
+	return t.SyncPhoto(ctx, accountmodel.Photo{ID: photoId})
+}
+
+func (t *TagServiceImpl) RemoveComment(ctx context.Context, commentId uint64) (err error) {
+	return t.SyncComment(ctx, accountmodel.Comment{ID: commentId})
+}
+
+func (t *TagServiceImpl) GetPhotosByTag(ctx context.Context, name string, params pagination.Params) (photos []accountmodel.Photo, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetPhotosByTag", t)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	name = tagparse.NormalizeHashtag(name)
+	if name == "" {
+		err = ErrInvalidTag
+		return
+	}
+	if photos, page, err = t.tagRepo.GetPhotosByHashtag(ctx, name, params); err != nil {
+		logger.Error(ctx, "error GetPhotosByHashtag",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (t *TagServiceImpl) GetMentions(ctx context.Context, userId uint64, params pagination.Params) (mentions []tagmodel.Mention, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetMentions", t)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if mentions, page, err = t.tagRepo.GetMentions(ctx, userId, params); err != nil {
+		logger.Error(ctx, "error GetMentions",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
@@ -0,0 +1,134 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	notificationmodel "github.com/mygram/go-account/modules/models/notification"
+	tagmodel "github.com/mygram/go-account/modules/models/tag"
+	repomock "github.com/mygram/go-account/modules/repository/tag/mock"
+	notificationmock "github.com/mygram/go-account/modules/service/notification/mock"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPhoto(t *testing.T) {
+	photo := accountmodel.Photo{ID: 7, UserID: 1, Caption: "#Sunset with @alice and @bob #sunset"}
+	source := tagmodel.Mention{AuthorID: 1, SourceType: tagmodel.SOURCE_PHOTO, SourceID: 7, PhotoID: 7}
+
+	testCases := []struct {
+		desc    string
+		doMock  func(repoMock *repomock.MockITagRepo, notificationMock *notificationmock.MockINotificationService)
+		wantErr error
+	}{
+		{
+			desc: "hashtags and mentions of the caption",
+			doMock: func(repoMock *repomock.MockITagRepo, notificationMock *notificationmock.MockINotificationService) {
+				repoMock.EXPECT().SyncPhotoHashtags(gomock.Any(), uint64(7), []string{"sunset"}).Return(nil)
+				added := source
+				added.UserID = 3
+				repoMock.EXPECT().SyncMentions(gomock.Any(), source, []string{"alice", "bob"}).Return([]tagmodel.Mention{added}, nil)
+				photoId := uint64(7)
+				notificationMock.EXPECT().
+					Notify(gomock.Any(), notificationmodel.Notification{UserID: 3, ActorID: 1, Type: notificationmodel.TYPE_MENTION, PhotoID: &photoId}).
+					Return(nil)
+			},
+		},
+		{
+			desc: "mentions are not synced when hashtags fail",
+			doMock: func(repoMock *repomock.MockITagRepo, notificationMock *notificationmock.MockINotificationService) {
+				repoMock.EXPECT().SyncPhotoHashtags(gomock.Any(), uint64(7), []string{"sunset"}).Return(errors.New("some error"))
+			},
+			wantErr: errors.New("some error"),
+		},
+		{
+			desc: "failed notification fails the sync",
+			doMock: func(repoMock *repomock.MockITagRepo, notificationMock *notificationmock.MockINotificationService) {
+				repoMock.EXPECT().SyncPhotoHashtags(gomock.Any(), uint64(7), []string{"sunset"}).Return(nil)
+				added := source
+				added.UserID = 3
+				repoMock.EXPECT().SyncMentions(gomock.Any(), source, []string{"alice", "bob"}).Return([]tagmodel.Mention{added}, nil)
+				notificationMock.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Return(errors.New("some error"))
+			},
+			wantErr: errors.New("some error"),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockITagRepo(ctrl)
+			notificationMock := notificationmock.NewMockINotificationService(ctrl)
+			tC.doMock(repoMock, notificationMock)
+
+			svc := TagServiceImpl{tagRepo: repoMock, notificationSvc: notificationMock}
+			err := svc.SyncPhoto(context.Background(), photo)
+			if tC.wantErr != nil {
+				assert.EqualError(t, err, tC.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRemoveComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := repomock.NewMockITagRepo(ctrl)
+	source := tagmodel.Mention{SourceType: tagmodel.SOURCE_COMMENT, SourceID: 9}
+	repoMock.EXPECT().SyncMentions(gomock.Any(), source, gomock.Nil()).Return(nil, nil)
+
+	svc := TagServiceImpl{tagRepo: repoMock}
+	assert.NoError(t, svc.RemoveComment(context.Background(), 9))
+}
+
+func TestGetPhotosByTag(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		name    string
+		doMock  func(repoMock *repomock.MockITagRepo)
+		wantErr error
+	}{
+		{
+			desc: "name is normalized",
+			name: "#Sunset",
+			doMock: func(repoMock *repomock.MockITagRepo) {
+				repoMock.EXPECT().
+					GetPhotosByHashtag(gomock.Any(), "sunset", gomock.Any()).
+					Return([]accountmodel.Photo{{ID: 7}}, response.Pagination{}, nil)
+			},
+		},
+		{
+			desc:    "empty name",
+			name:    "#",
+			doMock:  func(repoMock *repomock.MockITagRepo) {},
+			wantErr: ErrInvalidTag,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockITagRepo(ctrl)
+			tC.doMock(repoMock)
+
+			svc := TagServiceImpl{tagRepo: repoMock}
+			_, _, err := svc.GetPhotosByTag(context.Background(), tC.name, pagination.Params{})
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
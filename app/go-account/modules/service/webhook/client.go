@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+var ErrPrivateNetwork = errors.New("webhook url resolves to a private address")
+
+// newClient never follows redirects and, unless private networks are
+// allowed, refuses to connect to loopback, private and link local
+// addresses. The check is done on the dialed address so a public name
+// resolving to an internal address is refused too.
+func newClient(conf Config) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: conf.Timeout,
+	}
+	if !conf.AllowPrivateNetworks {
+		dialer.Control = publicOnly
+	}
+	return &http.Client{
+		Timeout: conf.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: conf.Timeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() {
+		return ErrPrivateNetwork
+	}
+	return nil
+}
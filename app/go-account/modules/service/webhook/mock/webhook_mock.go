@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: modules/service/webhook/webhook.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	webhook "github.com/mygram/go-account/modules/models/webhook"
+	pagination "github.com/mygram/go-common/pkg/pagination"
+	response "github.com/mygram/go-common/pkg/response"
+)
+
+// MockIWebhookService is a mock of IWebhookService interface.
+type MockIWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebhookServiceMockRecorder
+}
+
+// MockIWebhookServiceMockRecorder is the mock recorder for MockIWebhookService.
+type MockIWebhookServiceMockRecorder struct {
+	mock *MockIWebhookService
+}
+
+// NewMockIWebhookService creates a new mock instance.
+func NewMockIWebhookService(ctrl *gomock.Controller) *MockIWebhookService {
+	mock := &MockIWebhookService{ctrl: ctrl}
+	mock.recorder = &MockIWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebhookService) EXPECT() *MockIWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method.
+func (m *MockIWebhookService) Start(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockIWebhookServiceMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockIWebhookService)(nil).Start), ctx)
+}
+
+// Stop mocks base method.
+func (m *MockIWebhookService) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockIWebhookServiceMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockIWebhookService)(nil).Stop))
+}
+
+// CreateWebhook mocks base method.
+func (m *MockIWebhookService) CreateWebhook(ctx context.Context, userId uint64, scope webhook.Scope, req webhook.WebhookRequest) (webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, userId, scope, req)
+	ret0, _ := ret[0].(webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockIWebhookServiceMockRecorder) CreateWebhook(ctx, userId, scope, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockIWebhookService)(nil).CreateWebhook), ctx, userId, scope, req)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockIWebhookService) GetWebhooks(ctx context.Context, userId uint64) ([]webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, userId)
+	ret0, _ := ret[0].([]webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockIWebhookServiceMockRecorder) GetWebhooks(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockIWebhookService)(nil).GetWebhooks), ctx, userId)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockIWebhookService) DeleteWebhook(ctx context.Context, userId, webhookId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, userId, webhookId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockIWebhookServiceMockRecorder) DeleteWebhook(ctx, userId, webhookId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockIWebhookService)(nil).DeleteWebhook), ctx, userId, webhookId)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockIWebhookService) GetDeliveries(ctx context.Context, userId, webhookId uint64, params pagination.Params) ([]webhook.Delivery, response.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, userId, webhookId, params)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockIWebhookServiceMockRecorder) GetDeliveries(ctx, userId, webhookId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockIWebhookService)(nil).GetDeliveries), ctx, userId, webhookId, params)
+}
+
+// ReplayDelivery mocks base method.
+func (m *MockIWebhookService) ReplayDelivery(ctx context.Context, userId, webhookId, deliveryId uint64) (webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDelivery", ctx, userId, webhookId, deliveryId)
+	ret0, _ := ret[0].(webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayDelivery indicates an expected call of ReplayDelivery.
+func (mr *MockIWebhookServiceMockRecorder) ReplayDelivery(ctx, userId, webhookId, deliveryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDelivery", reflect.TypeOf((*MockIWebhookService)(nil).ReplayDelivery), ctx, userId, webhookId, deliveryId)
+}
+
+// Emit mocks base method.
+func (m *MockIWebhookService) Emit(ctx context.Context, ownerId uint64, eventType webhook.EventType, data interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Emit", ctx, ownerId, eventType, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Emit indicates an expected call of Emit.
+func (mr *MockIWebhookServiceMockRecorder) Emit(ctx, ownerId, eventType, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Emit", reflect.TypeOf((*MockIWebhookService)(nil).Emit), ctx, ownerId, eventType, data)
+}
+
+// Dispatch mocks base method.
+func (m *MockIWebhookService) Dispatch(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispatch", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dispatch indicates an expected call of Dispatch.
+func (mr *MockIWebhookServiceMockRecorder) Dispatch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockIWebhookService)(nil).Dispatch), ctx)
+}
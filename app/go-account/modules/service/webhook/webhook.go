@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"context"
+
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// IWebhookService keeps the webhooks of the users and posts them the
+// events they subscribed to from a persistent delivery queue.
+type IWebhookService interface {
+	// Start runs the dispatcher, deliveries left due by the last
+	// instance are sent on its first run
+	Start(ctx context.Context) (err error)
+	Stop()
+
+	CreateWebhook(ctx context.Context, userId uint64, scope webhookmodel.Scope, req webhookmodel.WebhookRequest) (webhook webhookmodel.Webhook, err error)
+	GetWebhooks(ctx context.Context, userId uint64) (webhooks []webhookmodel.Webhook, err error)
+	DeleteWebhook(ctx context.Context, userId uint64, webhookId uint64) (err error)
+	GetDeliveries(ctx context.Context, userId uint64, webhookId uint64, params pagination.Params) (deliveries []webhookmodel.Delivery, page response.Pagination, err error)
+	// ReplayDelivery queues the event again as a new delivery with the
+	// same event id and payload
+	ReplayDelivery(ctx context.Context, userId uint64, webhookId uint64, deliveryId uint64) (delivery webhookmodel.Delivery, err error)
+
+	// Emit queues the event for the webhooks of the owner of the content
+	// and for the app webhooks, it does not wait for the deliveries. Call
+	// it in the transaction of the change, the event is then queued if
+	// and only if the change commits
+	Emit(ctx context.Context, ownerId uint64, eventType webhookmodel.EventType, data interface{}) (err error)
+	// Dispatch sends the deliveries that are due until none is left
+	Dispatch(ctx context.Context) (sent int, err error)
+}
@@ -0,0 +1,448 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
+	webhookrepo "github.com/mygram/go-account/modules/repository/webhook"
+	"github.com/mygram/go-account/pkg/crypto"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/pagination"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+const (
+	DEFAULT_WORKERS       = 4
+	DEFAULT_MAX_ATTEMPTS  = 8
+	DEFAULT_RETRY_BACKOFF = 10 * time.Second
+	DEFAULT_TIMEOUT       = 10 * time.Second
+	DEFAULT_POLL_INTERVAL = 5 * time.Second
+	MAX_RETRY_BACKOFF     = 6 * time.Hour
+	// answers longer than this are cut in the delivery log
+	MAX_ERROR_LENGTH = 512
+	USER_AGENT       = "mygram-webhooks/1.0"
+)
+
+var ErrInvalidURL = domainerr.Validation("webhook url must be an absolute http or https url")
+
+type Config struct {
+	// deliveries sent at once by this instance
+	Workers     int
+	MaxAttempts int
+	// wait before the 2nd attempt, doubled after every attempt
+	RetryBackoff time.Duration
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// lets webhooks point at this machine or its network, local only
+	AllowPrivateNetworks bool
+}
+
+func (conf Config) withDefaults() Config {
+	if conf.Workers <= 0 {
+		conf.Workers = DEFAULT_WORKERS
+	}
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = DEFAULT_MAX_ATTEMPTS
+	}
+	if conf.RetryBackoff <= 0 {
+		conf.RetryBackoff = DEFAULT_RETRY_BACKOFF
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = DEFAULT_TIMEOUT
+	}
+	if conf.PollInterval <= 0 {
+		conf.PollInterval = DEFAULT_POLL_INTERVAL
+	}
+	return conf
+}
+
+// backoff is the wait after the given number of failed attempts.
+func (conf Config) backoff(attempts int) time.Duration {
+	d := conf.RetryBackoff
+	for i := 1; i < attempts && d < MAX_RETRY_BACKOFF; i++ {
+		d *= 2
+	}
+	if d > MAX_RETRY_BACKOFF {
+		d = MAX_RETRY_BACKOFF
+	}
+	return d
+}
+
+type WebhookServiceImpl struct {
+	webhookRepo webhookrepo.IWebhookRepo
+	client      *http.Client
+	conf        Config
+	now         func() time.Time
+
+	// wakes the dispatcher up before its next poll
+	kick   chan struct{}
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWebhookServiceImpl(webhookRepo webhookrepo.IWebhookRepo, conf Config) IWebhookService {
+	conf = conf.withDefaults()
+	return &WebhookServiceImpl{
+		webhookRepo: webhookRepo,
+		client:      newClient(conf),
+		conf:        conf,
+		now:         time.Now,
+		kick:        make(chan struct{}, 1),
+	}
+}
+
+func (w *WebhookServiceImpl) Start(ctx context.Context) (err error) {
+	logCtx := fmt.Sprintf("%T - Start", w)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		return
+	}
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go w.run(ctx)
+	return
+}
+
+// Stop cancels the deliveries being sent, they are not recorded and
+// are sent again once their lease runs out.
+func (w *WebhookServiceImpl) Stop() {
+	w.mu.Lock()
+	cancel := w.cancel
+	w.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	w.wg.Wait()
+}
+
+func (w *WebhookServiceImpl) run(ctx context.Context) {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.conf.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			logger.Error(ctx, "error Dispatch", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.kick:
+		}
+	}
+}
+
+func (w *WebhookServiceImpl) wake() {
+	select {
+	case w.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (w *WebhookServiceImpl) CreateWebhook(ctx context.Context, userId uint64, scope webhookmodel.Scope, req webhookmodel.WebhookRequest) (webhook webhookmodel.Webhook, err error) {
+	logCtx := fmt.Sprintf("%T - CreateWebhook", w)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err = ErrInvalidURL
+		return
+	}
+	seen := make(map[webhookmodel.EventType]bool, len(req.Events))
+	events := make([]webhookmodel.EventType, 0, len(req.Events))
+	for _, event := range req.Events {
+		if !event.Valid() {
+			err = domainerr.Validation(fmt.Sprintf("unknown webhook event %q", event))
+			return
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	req.Events = events
+
+	webhook = req.ToWebhook()
+	webhook.UserID = userId
+	webhook.Scope = scope
+	if webhook.Secret, err = crypto.GenerateWebhookSecret(); err != nil {
+		logger.Error(ctx, "error GenerateWebhookSecret",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if webhook, err = w.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		logger.Error(ctx, "error CreateWebhook",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (w *WebhookServiceImpl) GetWebhooks(ctx context.Context, userId uint64) (webhooks []webhookmodel.Webhook, err error) {
+	logCtx := fmt.Sprintf("%T - GetWebhooks", w)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if webhooks, err = w.webhookRepo.GetWebhooks(ctx, userId); err != nil {
+		logger.Error(ctx, "error GetWebhooks",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (w *WebhookServiceImpl) DeleteWebhook(ctx context.Context, userId uint64, webhookId uint64) (err error) {
+	logCtx := fmt.Sprintf("%T - DeleteWebhook", w)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if err = w.webhookRepo.DeleteWebhook(ctx, userId, webhookId); err != nil {
+		logger.Error(ctx, "error DeleteWebhook",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (w *WebhookServiceImpl) GetDeliveries(ctx context.Context, userId uint64, webhookId uint64, params pagination.Params) (deliveries []webhookmodel.Delivery, page response.Pagination, err error) {
+	logCtx := fmt.Sprintf("%T - GetDeliveries", w)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	// the log is only shown to the owner of the webhook
+	if _, err = w.webhookRepo.GetWebhook(ctx, userId, webhookId); err != nil {
+		logger.Error(ctx, "error GetWebhook",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	if deliveries, page, err = w.webhookRepo.GetDeliveries(ctx, webhookId, params); err != nil {
+		logger.Error(ctx, "error GetDeliveries",
+			"logCtx", logCtx,
+			"error", err)
+	}
+	return
+}
+
+func (w *WebhookServiceImpl) ReplayDelivery(ctx context.Context, userId uint64, webhookId uint64, deliveryId uint64) (delivery webhookmodel.Delivery, err error) {
+	logCtx := fmt.Sprintf("%T - ReplayDelivery", w)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	if _, err = w.webhookRepo.GetWebhook(ctx, userId, webhookId); err != nil {
+		logger.Error(ctx, "error GetWebhook",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	original, err := w.webhookRepo.GetDelivery(ctx, webhookId, deliveryId)
+	if err != nil {
+		logger.Error(ctx, "error GetDelivery",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+
+	deliveries := []webhookmodel.Delivery{{
+		WebhookID:     webhookId,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        webhookmodel.DELIVERY_PENDING,
+		NextAttemptAt: w.now(),
+		ReplayOf:      &original.ID,
+	}}
+	if err = w.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		logger.Error(ctx, "error CreateDeliveries",
+			"logCtx", logCtx,
+			"error", err)
+		return
+	}
+	w.wake()
+	return deliveries[0], err
+}
+
+func (w *WebhookServiceImpl) Emit(ctx context.Context, ownerId uint64, eventType webhookmodel.EventType, data interface{}) (err error) {
+	logCtx := fmt.Sprintf("%T - Emit", w)
+	logger.Info(ctx, "invoked", "logCtx", logCtx)
+
+	webhooks, err := w.webhookRepo.GetSubscribers(ctx, ownerId, eventType)
+	if err != nil {
+		logger.Error(ctx, "error GetSubscribers",
+			"logCtx", logCtx,
+			"type", eventType,
+			"error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	now := w.now()
+	eventId := uuid.New().String()
+	payload, err := json.Marshal(webhookmodel.Envelope{
+		ID:        eventId,
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      raw,
+	})
+	if err != nil {
+		return
+	}
+
+	deliveries := make([]webhookmodel.Delivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, webhookmodel.Delivery{
+			WebhookID:     webhook.ID,
+			EventID:       eventId,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        webhookmodel.DELIVERY_PENDING,
+			NextAttemptAt: now,
+		})
+	}
+	if err = w.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		logger.Error(ctx, "error CreateDeliveries",
+			"logCtx", logCtx,
+			"type", eventType,
+			"error", err)
+		return
+	}
+	// within a transaction the dispatcher may run before the commit,
+	// the deliveries are then sent on its next poll
+	w.wake()
+	return
+}
+
+func (w *WebhookServiceImpl) Dispatch(ctx context.Context) (sent int, err error) {
+	// a claimed batch is sent at once, the lease outlives its slowest delivery
+	lease := 2 * w.conf.Timeout
+	for ctx.Err() == nil {
+		var deliveries []webhookmodel.Delivery
+		deliveries, err = w.webhookRepo.ClaimDueDeliveries(ctx, w.conf.Workers, lease)
+		if err != nil {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery webhookmodel.Delivery) {
+				defer wg.Done()
+				w.deliver(ctx, delivery)
+			}(deliveries[i])
+		}
+		wg.Wait()
+
+		sent += len(deliveries)
+		if len(deliveries) < w.conf.Workers {
+			return
+		}
+	}
+	return
+}
+
+// deliver makes one attempt and records it, the delivery stays pending
+// with a later next attempt until it succeeds or runs out of attempts.
+func (w *WebhookServiceImpl) deliver(ctx context.Context, delivery webhookmodel.Delivery) {
+	logCtx := fmt.Sprintf("%T - deliver", w)
+
+	statusCode, err := w.send(ctx, delivery)
+	if ctx.Err() != nil {
+		// stopping, sent again once the lease runs out
+		return
+	}
+
+	now := w.now()
+	delivery.Attempts++
+	delivery.LastStatusCode = nil
+	delivery.LastError = ""
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+	switch {
+	case err == nil:
+		delivery.Status = webhookmodel.DELIVERY_SUCCEEDED
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= w.conf.MaxAttempts:
+		delivery.Status = webhookmodel.DELIVERY_FAILED
+		delivery.LastError = truncate(err.Error())
+	default:
+		delivery.NextAttemptAt = now.Add(w.conf.backoff(delivery.Attempts))
+		delivery.LastError = truncate(err.Error())
+	}
+
+	if err != nil {
+		logger.Info(ctx, "webhook delivery failed",
+			"logCtx", logCtx,
+			"deliveryId", delivery.ID,
+			"attempt", delivery.Attempts,
+			"status", delivery.Status,
+			"error", err)
+	}
+	if err = w.webhookRepo.SaveAttempt(ctx, delivery); err != nil {
+		logger.Error(ctx, "error SaveAttempt",
+			"logCtx", logCtx,
+			"deliveryId", delivery.ID,
+			"error", err)
+	}
+}
+
+func (w *WebhookServiceImpl) send(ctx context.Context, delivery webhookmodel.Delivery) (statusCode int, err error) {
+	webhook := delivery.Webhook
+	if webhook == nil || !webhook.Active {
+		err = fmt.Errorf("webhook is not active")
+		return
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := w.now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", USER_AGENT)
+	req.Header.Set(webhookmodel.HEADER_EVENT, string(delivery.EventType))
+	req.Header.Set(webhookmodel.HEADER_DELIVERY, delivery.EventID)
+	req.Header.Set(webhookmodel.HEADER_TIMESTAMP, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhookmodel.HEADER_SIGNATURE, crypto.SignPayload(webhook.Secret, timestamp, body))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	// drained so the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	statusCode = res.StatusCode
+	if statusCode < 200 || statusCode > 299 {
+		err = fmt.Errorf("receiver answered %v", statusCode)
+	}
+	return
+}
+
+func truncate(s string) string {
+	if len(s) > MAX_ERROR_LENGTH {
+		return s[:MAX_ERROR_LENGTH]
+	}
+	return s
+}
@@ -0,0 +1,436 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	webhookmodel "github.com/mygram/go-account/modules/models/webhook"
+	repomock "github.com/mygram/go-account/modules/repository/webhook/mock"
+	"github.com/mygram/go-account/pkg/crypto"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+const secret = "whsec_test"
+
+func TestCreateWebhook(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		req     webhookmodel.WebhookRequest
+		doMock  func(repoMock *repomock.MockIWebhookRepo)
+		wantErr error
+	}{
+		{
+			desc: "events are deduped and a secret is generated",
+			req: webhookmodel.WebhookRequest{
+				URL:    "https://example.com/hook",
+				Events: []webhookmodel.EventType{webhookmodel.EVENT_PHOTO_CREATED, webhookmodel.EVENT_PHOTO_CREATED},
+			},
+			doMock: func(repoMock *repomock.MockIWebhookRepo) {
+				repoMock.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, webhook webhookmodel.Webhook) (webhookmodel.Webhook, error) {
+						assert.Equal(t, uint64(1), webhook.UserID)
+						assert.Equal(t, webhookmodel.SCOPE_USER, webhook.Scope)
+						assert.Equal(t, []string{string(webhookmodel.EVENT_PHOTO_CREATED)}, []string(webhook.Events))
+						assert.True(t, strings.HasPrefix(webhook.Secret, crypto.WEBHOOK_SECRET_PREFIX))
+						assert.True(t, webhook.Active)
+						webhook.ID = 2
+						return webhook, nil
+					})
+			},
+		},
+		{
+			desc: "only http and https urls",
+			req: webhookmodel.WebhookRequest{
+				URL:    "ftp://example.com/hook",
+				Events: []webhookmodel.EventType{webhookmodel.EVENT_PHOTO_CREATED},
+			},
+			doMock:  func(repoMock *repomock.MockIWebhookRepo) {},
+			wantErr: ErrInvalidURL,
+		},
+		{
+			desc: "unknown event",
+			req: webhookmodel.WebhookRequest{
+				URL:    "https://example.com/hook",
+				Events: []webhookmodel.EventType{"photo.liked"},
+			},
+			doMock:  func(repoMock *repomock.MockIWebhookRepo) {},
+			wantErr: domainerr.ErrValidation,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIWebhookRepo(ctrl)
+			tC.doMock(repoMock)
+
+			svc := WebhookServiceImpl{webhookRepo: repoMock}
+			_, err := svc.CreateWebhook(context.Background(), 1, webhookmodel.SCOPE_USER, tC.req)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEmit(t *testing.T) {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		desc   string
+		doMock func(repoMock *repomock.MockIWebhookRepo)
+	}{
+		{
+			desc: "one delivery per subscriber with the same event",
+			doMock: func(repoMock *repomock.MockIWebhookRepo) {
+				repoMock.EXPECT().
+					GetSubscribers(gomock.Any(), uint64(1), webhookmodel.EVENT_PHOTO_CREATED).
+					Return([]webhookmodel.Webhook{{ID: 2}, {ID: 3}}, nil)
+				repoMock.EXPECT().
+					CreateDeliveries(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, deliveries []webhookmodel.Delivery) error {
+						if !assert.Len(t, deliveries, 2) {
+							return nil
+						}
+						assert.Equal(t, uint64(2), deliveries[0].WebhookID)
+						assert.Equal(t, uint64(3), deliveries[1].WebhookID)
+						assert.Equal(t, deliveries[0].EventID, deliveries[1].EventID)
+						assert.Equal(t, webhookmodel.DELIVERY_PENDING, deliveries[0].Status)
+						assert.Equal(t, now, deliveries[0].NextAttemptAt)
+
+						var envelope webhookmodel.Envelope
+						assert.NoError(t, json.Unmarshal([]byte(deliveries[0].Payload), &envelope))
+						assert.Equal(t, deliveries[0].EventID, envelope.ID)
+						assert.Equal(t, webhookmodel.EVENT_PHOTO_CREATED, envelope.Type)
+						assert.JSONEq(t, `{"id":9}`, string(envelope.Data))
+						return nil
+					})
+			},
+		},
+		{
+			desc: "nothing is queued without subscribers",
+			doMock: func(repoMock *repomock.MockIWebhookRepo) {
+				repoMock.EXPECT().
+					GetSubscribers(gomock.Any(), uint64(1), webhookmodel.EVENT_PHOTO_CREATED).
+					Return(nil, nil)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := repomock.NewMockIWebhookRepo(ctrl)
+			tC.doMock(repoMock)
+
+			svc := WebhookServiceImpl{
+				webhookRepo: repoMock,
+				now:         func() time.Time { return now },
+				kick:        make(chan struct{}, 1),
+			}
+			err := svc.Emit(context.Background(), 1, webhookmodel.EVENT_PHOTO_CREATED, map[string]int{"id": 9})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestReplayDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	repoMock := repomock.NewMockIWebhookRepo(ctrl)
+	repoMock.EXPECT().
+		GetWebhook(gomock.Any(), uint64(1), uint64(2)).
+		Return(webhookmodel.Webhook{ID: 2, UserID: 1}, nil)
+	repoMock.EXPECT().
+		GetDelivery(gomock.Any(), uint64(2), uint64(5)).
+		Return(webhookmodel.Delivery{ID: 5, WebhookID: 2, EventID: "evt", EventType: webhookmodel.EVENT_PHOTO_CREATED, Payload: `{"id":"evt"}`, Status: webhookmodel.DELIVERY_FAILED, Attempts: 8}, nil)
+	replayOf := uint64(5)
+	repoMock.EXPECT().
+		CreateDeliveries(gomock.Any(), []webhookmodel.Delivery{{
+			WebhookID:     2,
+			EventID:       "evt",
+			EventType:     webhookmodel.EVENT_PHOTO_CREATED,
+			Payload:       `{"id":"evt"}`,
+			Status:        webhookmodel.DELIVERY_PENDING,
+			NextAttemptAt: now,
+			ReplayOf:      &replayOf,
+		}}).
+		Return(nil)
+
+	svc := WebhookServiceImpl{
+		webhookRepo: repoMock,
+		now:         func() time.Time { return now },
+		kick:        make(chan struct{}, 1),
+	}
+	delivery, err := svc.ReplayDelivery(context.Background(), 1, 2, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivery.Attempts)
+	assert.Equal(t, &replayOf, delivery.ReplayOf)
+}
+
+func TestReplayDeliveryOfAnotherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := repomock.NewMockIWebhookRepo(ctrl)
+	repoMock.EXPECT().
+		GetWebhook(gomock.Any(), uint64(1), uint64(2)).
+		Return(webhookmodel.Webhook{}, domainerr.NotFound("webhook"))
+
+	svc := WebhookServiceImpl{webhookRepo: repoMock}
+	_, err := svc.ReplayDelivery(context.Background(), 1, 2, 5)
+	assert.ErrorIs(t, err, domainerr.ErrNotFound)
+}
+
+// receiver is a local webhook endpoint answering with the given codes
+// in turn, it fails the test on a bad signature.
+type receiver struct {
+	t     *testing.T
+	codes []int
+
+	mu       sync.Mutex
+	received []http.Header
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	assert.NoError(r.t, err)
+	timestamp, err := strconv.ParseInt(req.Header.Get(webhookmodel.HEADER_TIMESTAMP), 10, 64)
+	assert.NoError(r.t, err)
+	assert.True(r.t, crypto.VerifyPayload(secret, req.Header.Get(webhookmodel.HEADER_SIGNATURE), timestamp, body), "signature")
+
+	r.mu.Lock()
+	code := r.codes[len(r.received)%len(r.codes)]
+	r.received = append(r.received, req.Header.Clone())
+	r.mu.Unlock()
+	w.WriteHeader(code)
+}
+
+func TestDispatch(t *testing.T) {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	conf := Config{Workers: 2, MaxAttempts: 3, RetryBackoff: 10 * time.Second}.withDefaults()
+
+	testCases := []struct {
+		desc     string
+		code     int
+		attempts int
+		want     func(delivery webhookmodel.Delivery)
+	}{
+		{
+			desc: "2xx succeeds",
+			code: http.StatusNoContent,
+			want: func(delivery webhookmodel.Delivery) {
+				assert.Equal(t, webhookmodel.DELIVERY_SUCCEEDED, delivery.Status)
+				assert.Equal(t, 1, delivery.Attempts)
+				assert.Equal(t, &now, delivery.DeliveredAt)
+				assert.Equal(t, http.StatusNoContent, *delivery.LastStatusCode)
+			},
+		},
+		{
+			desc:     "failed attempt is retried with a doubled backoff",
+			code:     http.StatusInternalServerError,
+			attempts: 1,
+			want: func(delivery webhookmodel.Delivery) {
+				assert.Equal(t, webhookmodel.DELIVERY_PENDING, delivery.Status)
+				assert.Equal(t, 2, delivery.Attempts)
+				assert.Equal(t, now.Add(20*time.Second), delivery.NextAttemptAt)
+				assert.Equal(t, http.StatusInternalServerError, *delivery.LastStatusCode)
+				assert.Equal(t, "receiver answered 500", delivery.LastError)
+				assert.Nil(t, delivery.DeliveredAt)
+			},
+		},
+		{
+			desc:     "last attempt gives up",
+			code:     http.StatusBadGateway,
+			attempts: 2,
+			want: func(delivery webhookmodel.Delivery) {
+				assert.Equal(t, webhookmodel.DELIVERY_FAILED, delivery.Status)
+				assert.Equal(t, 3, delivery.Attempts)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			recv := &receiver{t: t, codes: []int{tC.code}}
+			srv := httptest.NewServer(recv)
+			defer srv.Close()
+
+			repoMock := repomock.NewMockIWebhookRepo(ctrl)
+			gomock.InOrder(
+				repoMock.EXPECT().
+					ClaimDueDeliveries(gomock.Any(), conf.Workers, 2*conf.Timeout).
+					Return([]webhookmodel.Delivery{{
+						ID:        5,
+						WebhookID: 2,
+						EventID:   "evt",
+						EventType: webhookmodel.EVENT_PHOTO_CREATED,
+						Payload:   `{"id":"evt","type":"photo.created"}`,
+						Status:    webhookmodel.DELIVERY_PENDING,
+						Attempts:  tC.attempts,
+						Webhook:   &webhookmodel.Webhook{ID: 2, URL: srv.URL, Secret: secret, Active: true},
+					}}, nil),
+				repoMock.EXPECT().
+					SaveAttempt(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, delivery webhookmodel.Delivery) error {
+						tC.want(delivery)
+						return nil
+					}),
+			)
+
+			svc := WebhookServiceImpl{
+				webhookRepo: repoMock,
+				client:      srv.Client(),
+				conf:        conf,
+				now:         func() time.Time { return now },
+			}
+			sent, err := svc.Dispatch(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 1, sent)
+
+			if assert.Len(t, recv.received, 1) {
+				header := recv.received[0]
+				assert.Equal(t, string(webhookmodel.EVENT_PHOTO_CREATED), header.Get(webhookmodel.HEADER_EVENT))
+				assert.Equal(t, "evt", header.Get(webhookmodel.HEADER_DELIVERY))
+				assert.Equal(t, strconv.FormatInt(now.Unix(), 10), header.Get(webhookmodel.HEADER_TIMESTAMP))
+			}
+		})
+	}
+}
+
+func TestDispatchUntilDrained(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recv := &receiver{t: t, codes: []int{http.StatusOK}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	webhook := &webhookmodel.Webhook{ID: 2, URL: srv.URL, Secret: secret, Active: true}
+	batch := func(ids ...uint64) []webhookmodel.Delivery {
+		deliveries := make([]webhookmodel.Delivery, 0, len(ids))
+		for _, id := range ids {
+			deliveries = append(deliveries, webhookmodel.Delivery{ID: id, Payload: `{}`, Webhook: webhook})
+		}
+		return deliveries
+	}
+
+	repoMock := repomock.NewMockIWebhookRepo(ctrl)
+	gomock.InOrder(
+		repoMock.EXPECT().ClaimDueDeliveries(gomock.Any(), 2, gomock.Any()).Return(batch(1, 2), nil),
+		repoMock.EXPECT().ClaimDueDeliveries(gomock.Any(), 2, gomock.Any()).Return(batch(3), nil),
+	)
+	repoMock.EXPECT().SaveAttempt(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	svc := WebhookServiceImpl{
+		webhookRepo: repoMock,
+		client:      srv.Client(),
+		conf:        Config{Workers: 2}.withDefaults(),
+		now:         time.Now,
+	}
+	sent, err := svc.Dispatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, sent)
+	assert.Len(t, recv.received, 3)
+}
+
+func TestDispatchStopsOnClaimError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := repomock.NewMockIWebhookRepo(ctrl)
+	repoMock.EXPECT().
+		ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("some error"))
+
+	svc := WebhookServiceImpl{webhookRepo: repoMock, conf: Config{}.withDefaults()}
+	_, err := svc.Dispatch(context.Background())
+	assert.EqualError(t, err, "some error")
+}
+
+func TestStartDeliversQueuedEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recv := &receiver{t: t, codes: []int{http.StatusOK}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	delivered := make(chan webhookmodel.Delivery, 1)
+	repoMock := repomock.NewMockIWebhookRepo(ctrl)
+	// the first claim finds what the last instance left due
+	repoMock.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]webhookmodel.Delivery{{ID: 1, Payload: `{}`, Webhook: &webhookmodel.Webhook{URL: srv.URL, Secret: secret, Active: true}}}, nil)
+	repoMock.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	repoMock.EXPECT().
+		SaveAttempt(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, delivery webhookmodel.Delivery) error {
+			delivered <- delivery
+			return nil
+		})
+
+	svc := NewWebhookServiceImpl(repoMock, Config{AllowPrivateNetworks: true, PollInterval: time.Hour})
+	assert.NoError(t, svc.Start(context.Background()))
+	defer svc.Stop()
+
+	select {
+	case delivery := <-delivered:
+		assert.Equal(t, webhookmodel.DELIVERY_SUCCEEDED, delivery.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued delivery was not sent")
+	}
+}
+
+func TestPrivateNetworks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	testCases := []struct {
+		desc    string
+		allow   bool
+		wantErr error
+	}{
+		{desc: "loopback is refused", wantErr: ErrPrivateNetwork},
+		{desc: "loopback is allowed locally", allow: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			client := newClient(Config{AllowPrivateNetworks: tC.allow}.withDefaults())
+			res, err := client.Post(srv.URL, "application/json", nil)
+			if tC.wantErr != nil {
+				assert.ErrorIs(t, err, tC.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				res.Body.Close()
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	conf := Config{RetryBackoff: 10 * time.Second}
+	assert.Equal(t, 10*time.Second, conf.backoff(1))
+	assert.Equal(t, 20*time.Second, conf.backoff(2))
+	assert.Equal(t, 80*time.Second, conf.backoff(4))
+	assert.Equal(t, MAX_RETRY_BACKOFF, conf.backoff(30))
+}
@@ -8,9 +8,34 @@ import (
 
 const sharedKey = ("sercrethatmaycontainch@r$32chars")
 
+// JWTSigner signs a claim into a compact jwt.
+type JWTSigner interface {
+	Sign(claim any) (token []byte, err error)
+}
+
+// JWTVerifier verifies a compact jwt and decodes its claims.
+type JWTVerifier interface {
+	Verify(token []byte, claims any) (err error)
+}
+
+var (
+	// default to the shared HS256 key until keys are configured,
+	// see UseJWTKeys
+	defaultHMAC             = NewHMACKey([]byte(sharedKey))
+	signer      JWTSigner   = defaultHMAC
+	verifier    JWTVerifier = defaultHMAC
+)
+
+// UseJWTKeys replaces the signer and verifier used by SignJWT and ParseJWT.
+// It is not safe for concurrent use, call it once during startup.
+func UseJWTKeys(s JWTSigner, v JWTVerifier) {
+	signer = s
+	verifier = v
+}
+
 func SignJWT(claim any) (token string, err error) {
 	// sign jwt
-	tkn, err := jwt.Sign(jwt.HS256, []byte(sharedKey), claim)
+	tkn, err := signer.Sign(claim)
 	if err != nil {
 		err = errors.New("error sign claim")
 	}
@@ -19,11 +44,31 @@ func SignJWT(claim any) (token string, err error) {
 
 func ParseJWT(token string, claims any) (err error) {
 	// Verify and extract claims from a token:
-	verifiedToken, err := jwt.Verify(jwt.HS256, []byte(sharedKey), []byte(token))
-	if err != nil {
+	if err = verifier.Verify([]byte(token), claims); err != nil {
 		err = errors.New("error parse token")
-		return
 	}
-	err = verifiedToken.Claims(&claims)
 	return err
 }
+
+// HMACKey signs and verifies with a single shared secret (HS256).
+type HMACKey struct {
+	key []byte
+}
+
+func NewHMACKey(key []byte) *HMACKey {
+	return &HMACKey{
+		key: key,
+	}
+}
+
+func (h *HMACKey) Sign(claim any) (token []byte, err error) {
+	return jwt.Sign(jwt.HS256, h.key, claim)
+}
+
+func (h *HMACKey) Verify(token []byte, claims any) (err error) {
+	verifiedToken, err := jwt.Verify(jwt.HS256, h.key, token)
+	if err != nil {
+		return
+	}
+	return verifiedToken.Claims(&claims)
+}
@@ -0,0 +1,205 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/kataras/jwt"
+)
+
+// KeyConfig describes one signing key. Private/Public hold PEM content,
+// PrivateFile/PublicFile point to PEM files and win over the inline value.
+// A key without private part can only verify, which is how a rotated out
+// key stays around until the tokens it signed are expired.
+type KeyConfig struct {
+	ID          string
+	Alg         string
+	Private     string
+	Public      string
+	PrivateFile string
+	PublicFile  string
+}
+
+// KeySet signs with the active kid and verifies with any registered kid.
+type KeySet struct {
+	activeKid string
+	keys      jwt.Keys
+	legacy    *HMACKey
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// OKP (Ed25519)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+func NewKeySet(activeKid string, confs []KeyConfig) (keySet *KeySet, err error) {
+	keys := jwt.Keys{}
+	for _, conf := range confs {
+		if conf.ID == "" {
+			return nil, errors.New("jwt key id is empty")
+		}
+		var private, public []byte
+		if private, err = readKey(conf.Private, conf.PrivateFile); err != nil {
+			return nil, fmt.Errorf("jwt key %v: %w", conf.ID, err)
+		}
+		if public, err = readKey(conf.Public, conf.PublicFile); err != nil {
+			return nil, fmt.Errorf("jwt key %v: %w", conf.ID, err)
+		}
+
+		alg, privKey, pubKey, errParse := parseKeyPair(conf.Alg, private, public)
+		if errParse != nil {
+			return nil, fmt.Errorf("jwt key %v: %w", conf.ID, errParse)
+		}
+		keys.Register(alg, conf.ID, pubKey, privKey)
+	}
+
+	active, ok := keys.Get(activeKid)
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %v is not configured", activeKid)
+	}
+	if active.Private == nil {
+		return nil, fmt.Errorf("active jwt key %v has no private key", activeKid)
+	}
+
+	return &KeySet{
+		activeKid: activeKid,
+		keys:      keys,
+	}, nil
+}
+
+// AcceptLegacyHS256 keeps accepting tokens signed with the old shared key
+// (they have no kid header) while they are still alive.
+func (k *KeySet) AcceptLegacyHS256() *KeySet {
+	k.legacy = defaultHMAC
+	return k
+}
+
+func (k *KeySet) Sign(claim any) (token []byte, err error) {
+	return k.keys.SignToken(k.activeKid, claim)
+}
+
+func (k *KeySet) Verify(token []byte, claims any) (err error) {
+	if k.legacy != nil {
+		unverified, errDecode := jwt.Decode(token)
+		if errDecode != nil {
+			return errDecode
+		}
+		var header jwt.HeaderWithKid
+		if err = jwt.Unmarshal(unverified.Header, &header); err != nil {
+			return
+		}
+		if header.Kid == "" {
+			return k.legacy.Verify(token, claims)
+		}
+	}
+	return k.keys.VerifyToken(token, claims)
+}
+
+// JWKS returns the public part of every registered key.
+func (k *KeySet) JWKS() (set JSONWebKeySet) {
+	set.Keys = []JSONWebKey{}
+	for kid, key := range k.keys {
+		jwk := JSONWebKey{
+			Kid: kid,
+			Use: "sig",
+			Alg: key.Alg.Name(),
+		}
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+	return
+}
+
+// JWKS returns the public keys of the current verifier. It is empty
+// when tokens are still signed with the shared HS256 key.
+func JWKS() JSONWebKeySet {
+	if keySet, ok := verifier.(*KeySet); ok {
+		return keySet.JWKS()
+	}
+	return JSONWebKeySet{Keys: []JSONWebKey{}}
+}
+
+func readKey(inline, filename string) (key []byte, err error) {
+	if filename != "" {
+		return os.ReadFile(filename)
+	}
+	return []byte(inline), nil
+}
+
+func parseKeyPair(algName string, private, public []byte) (alg jwt.Alg, privKey jwt.PrivateKey, pubKey jwt.PublicKey, err error) {
+	switch algName {
+	case jwt.RS256.Name():
+		alg = jwt.RS256
+		var pub *rsa.PublicKey
+		if len(private) > 0 {
+			priv, errParse := jwt.ParsePrivateKeyRSA(private)
+			if errParse != nil {
+				return nil, nil, nil, errParse
+			}
+			privKey, pub = priv, &priv.PublicKey
+		}
+		if len(public) > 0 {
+			if pub, err = jwt.ParsePublicKeyRSA(public); err != nil {
+				return
+			}
+		}
+		if pub == nil {
+			return nil, nil, nil, errors.New("missing public key")
+		}
+		pubKey = pub
+	case jwt.EdDSA.Name():
+		alg = jwt.EdDSA
+		var pub ed25519.PublicKey
+		if len(private) > 0 {
+			priv, errParse := jwt.ParsePrivateKeyEdDSA(private)
+			if errParse != nil {
+				return nil, nil, nil, errParse
+			}
+			privKey, pub = priv, priv.Public().(ed25519.PublicKey)
+		}
+		if len(public) > 0 {
+			if pub, err = jwt.ParsePublicKeyEdDSA(public); err != nil {
+				return
+			}
+		}
+		if pub == nil {
+			return nil, nil, nil, errors.New("missing public key")
+		}
+		pubKey = pub
+	default:
+		err = fmt.Errorf("unsupported jwt alg %v", algName)
+	}
+	return
+}
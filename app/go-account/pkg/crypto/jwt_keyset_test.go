@@ -0,0 +1,132 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/mygram/go-account/modules/models/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateRSAKey(t *testing.T) (private, public string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	private = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	public = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+	return
+}
+
+func generateEdDSAKey(t *testing.T) (private string) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	priv, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: priv}))
+}
+
+func TestKeySetRotation(t *testing.T) {
+	oldPrivate, oldPublic := generateRSAKey(t)
+	newPrivate := generateEdDSAKey(t)
+	claim := token.DefaultClaim{
+		Expired: 1234567899999999,
+		JTI:     "this-is-jti",
+		Type:    token.ACCESS_TOKEN,
+	}
+
+	// token signed before rotation
+	before, err := NewKeySet("2023-01", []KeyConfig{
+		{ID: "2023-01", Alg: "RS256", Private: oldPrivate},
+	})
+	assert.NoError(t, err)
+	oldToken, err := before.Sign(claim)
+	assert.NoError(t, err)
+
+	// old key is demoted to verification only
+	after, err := NewKeySet("2023-02", []KeyConfig{
+		{ID: "2023-01", Alg: "RS256", Public: oldPublic},
+		{ID: "2023-02", Alg: "EdDSA", Private: newPrivate},
+	})
+	assert.NoError(t, err)
+	newToken, err := after.Sign(claim)
+	assert.NoError(t, err)
+
+	for _, tkn := range [][]byte{oldToken, newToken} {
+		var act token.DefaultClaim
+		assert.NoError(t, after.Verify(tkn, &act))
+		assert.Equal(t, claim, act)
+	}
+
+	// legacy HS256 tokens are only accepted when asked to
+	legacyToken, err := defaultHMAC.Sign(claim)
+	assert.NoError(t, err)
+	var act token.DefaultClaim
+	assert.Error(t, after.Verify(legacyToken, &act))
+	assert.NoError(t, after.AcceptLegacyHS256().Verify(legacyToken, &act))
+
+	// a key set that never knew the new kid rejects it
+	assert.Error(t, before.Verify(newToken, &act))
+
+	jwks := after.JWKS()
+	if assert.Len(t, jwks.Keys, 2) {
+		assert.Equal(t, "2023-01", jwks.Keys[0].Kid)
+		assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+		assert.NotEmpty(t, jwks.Keys[0].N)
+		assert.Equal(t, "AQAB", jwks.Keys[0].E)
+		assert.Equal(t, "2023-02", jwks.Keys[1].Kid)
+		assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+		assert.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+		assert.NotEmpty(t, jwks.Keys[1].X)
+	}
+}
+
+func TestNewKeySet(t *testing.T) {
+	_, public := generateRSAKey(t)
+
+	testCases := []struct {
+		desc      string
+		activeKid string
+		confs     []KeyConfig
+		wantErr   bool
+	}{
+		{
+			desc:      "active kid is not configured",
+			activeKid: "missing",
+			confs:     []KeyConfig{{ID: "2023-01", Alg: "EdDSA", Private: generateEdDSAKey(t)}},
+			wantErr:   true,
+		},
+		{
+			desc:      "active kid cannot sign",
+			activeKid: "2023-01",
+			confs:     []KeyConfig{{ID: "2023-01", Alg: "RS256", Public: public}},
+			wantErr:   true,
+		},
+		{
+			desc:      "unsupported alg",
+			activeKid: "2023-01",
+			confs:     []KeyConfig{{ID: "2023-01", Alg: "HS256", Private: "secret"}},
+			wantErr:   true,
+		},
+		{
+			desc:      "happy case",
+			activeKid: "2023-01",
+			confs:     []KeyConfig{{ID: "2023-01", Alg: "EdDSA", Private: generateEdDSAKey(t)}},
+			wantErr:   false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, err := NewKeySet(tC.activeKid, tC.confs)
+			if tC.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	SIGNATURE_PREFIX      = "sha256="
+	WEBHOOK_SECRET_PREFIX = "whsec_"
+)
+
+// SignPayload is the HMAC-SHA256 of "<timestamp>.<body>", the timestamp is
+// signed too so a captured request can not be sent again with a new one.
+func SignPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyPayload is what a receiver does, it is kept next to SignPayload
+// so both sides never drift apart.
+func VerifyPayload(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(SignPayload(secret, timestamp, body)))
+}
+
+func GenerateWebhookSecret() (secret string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	return WEBHOOK_SECRET_PREFIX + hex.EncodeToString(b), err
+}
@@ -0,0 +1,43 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignPayload(t *testing.T) {
+	body := []byte(`{"id":"1","type":"photo.created"}`)
+	signature := SignPayload("this-is-secret", 1700000000, body)
+	// echo -n '1700000000.{"id":"1","type":"photo.created"}' | openssl dgst -sha256 -hmac this-is-secret
+	assert.Equal(t, "sha256=8751d76b7a0346b9eb12b226b3d38f389d4b0c0fdc48cdb8eb2e818d9db810cb", signature)
+
+	testCases := []struct {
+		desc      string
+		secret    string
+		timestamp int64
+		body      []byte
+		want      bool
+	}{
+		{desc: "same payload", secret: "this-is-secret", timestamp: 1700000000, body: body, want: true},
+		{desc: "other secret", secret: "other-secret", timestamp: 1700000000, body: body},
+		{desc: "other timestamp", secret: "this-is-secret", timestamp: 1700000001, body: body},
+		{desc: "other body", secret: "this-is-secret", timestamp: 1700000000, body: []byte(`{}`)},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, VerifyPayload(tC.secret, signature, tC.timestamp, tC.body))
+		})
+	}
+}
+
+func TestGenerateWebhookSecret(t *testing.T) {
+	secret, err := GenerateWebhookSecret()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, WEBHOOK_SECRET_PREFIX))
+
+	other, err := GenerateWebhookSecret()
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
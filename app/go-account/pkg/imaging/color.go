@@ -0,0 +1,44 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+)
+
+// sample size for the color and the blurhash, the details do not matter
+const summarySide = 64
+
+// DominantColor is the most frequent color of img as #rrggbb. Colors are
+// counted in 4 bit per channel buckets, the result is the average of the
+// pixels of the biggest bucket. Mostly transparent pixels do not count.
+func DominantColor(img image.Image) string {
+	small := toRGBA(Fit(img, summarySide))
+
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	var buckets [1 << 12]bucket
+	best := -1
+	for i := 0; i+3 < len(small.Pix); i += 4 {
+		r, g, b, a := int(small.Pix[i]), int(small.Pix[i+1]), int(small.Pix[i+2]), int(small.Pix[i+3])
+		if a < 128 {
+			continue
+		}
+		// back from premultiplied
+		r, g, b = r*255/a, g*255/a, b*255/a
+		k := r>>4<<8 | g>>4<<4 | b>>4
+		buckets[k].count++
+		buckets[k].r += r
+		buckets[k].g += g
+		buckets[k].b += b
+		if best < 0 || buckets[k].count > buckets[best].count {
+			best = k
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	top := buckets[best]
+	return fmt.Sprintf("#%02x%02x%02x", top.r/top.count, top.g/top.count, top.b/top.count)
+}
@@ -0,0 +1,71 @@
+// Package imaging derives what the feed needs from an uploaded photo:
+// metadata free originals, resized variants, a dominant color and a
+// blurhash placeholder. Webp is decoded by golang.org/x/image and
+// encoded lossless by this package, see webp.go.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	FORMAT_JPEG = "jpeg"
+	FORMAT_WEBP = "webp"
+
+	JPEG_QUALITY = 82
+)
+
+// ErrUnsupportedFormat is returned for formats this package can not
+// decode or encode.
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+var contentTypes = map[string]string{
+	FORMAT_JPEG: "image/jpeg",
+	FORMAT_WEBP: "image/webp",
+}
+
+// Decode reads the pixels of data, the exif orientation is applied.
+func Decode(data []byte) (img image.Image, err error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return
+	}
+	if format == "jpeg" {
+		img = Orient(img, Orientation(data))
+	}
+	return
+}
+
+// ContentType of an encoded variant.
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Encode writes img in format, transparent pixels end up on white
+// in jpeg since it has no alpha, webp keeps them.
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case FORMAT_JPEG:
+		b := img.Bounds()
+		flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(flat, flat.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Rect, img, b.Min, draw.Over)
+		return jpeg.Encode(w, flat, &jpeg.Options{Quality: JPEG_QUALITY})
+	case FORMAT_WEBP:
+		return encodeWebP(w, img)
+	}
+	return ErrUnsupportedFormat
+}
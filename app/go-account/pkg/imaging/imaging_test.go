@@ -0,0 +1,373 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fill is a w x h image, left half a, right half b
+func fill(w, h int, a, b color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.SetRGBA(x, y, a)
+			} else {
+				img.SetRGBA(x, y, b)
+			}
+		}
+	}
+	return img
+}
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+	clear = color.RGBA{}
+)
+
+// jpegWithExif is a jpeg with an exif block holding the orientation
+// and a gps latitude, plus a comment
+func jpegWithExif(t *testing.T, img image.Image, orientation uint16) []byte {
+	var encoded bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	var tiff bytes.Buffer
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(0x2a))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(2))
+	binary.Write(&tiff, binary.LittleEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, []uint16{orientation, 0})
+	binary.Write(&tiff, binary.LittleEndian, []uint16{0x8825, 4}) // gps IFD pointer
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+	tiff.WriteString("GPS 52.5200 N 13.4050 E")
+
+	var out bytes.Buffer
+	out.Write(encoded.Bytes()[:2])
+	writeJPEGSegment(&out, jpegMarkerAPP1, append(append([]byte{}, exifHeader...), tiff.Bytes()...))
+	writeJPEGSegment(&out, jpegMarkerCOM, []byte("shot at home"))
+	out.Write(encoded.Bytes()[2:])
+	return out.Bytes()
+}
+
+func pngChunk(typ string, data []byte) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(len(data)))
+	b.WriteString(typ)
+	b.Write(data)
+	binary.Write(&b, binary.BigEndian, crc32.ChecksumIEEE(append([]byte(typ), data...)))
+	return b.Bytes()
+}
+
+func TestStripMetadata(t *testing.T) {
+	t.Run("jpeg keeps only the orientation", func(t *testing.T) {
+		data := jpegWithExif(t, fill(8, 4, red, blue), 6)
+		stripped, err := StripMetadata("image/jpeg", data)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(stripped), "GPS")
+		assert.NotContains(t, string(stripped), "shot at home")
+		assert.Equal(t, 6, Orientation(stripped))
+
+		img, err := Decode(stripped)
+		if assert.NoError(t, err) {
+			assert.Equal(t, image.Rect(0, 0, 4, 8), img.Bounds())
+		}
+	})
+
+	t.Run("jpeg without exif is unchanged", func(t *testing.T) {
+		var data bytes.Buffer
+		jpeg.Encode(&data, fill(8, 4, red, blue), nil)
+		stripped, err := StripMetadata("image/jpeg", data.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, data.Bytes(), stripped)
+		assert.Equal(t, ORIENTATION_NORMAL, Orientation(stripped))
+	})
+
+	t.Run("jpeg ends at the eoi of the primary image", func(t *testing.T) {
+		var encoded bytes.Buffer
+		jpeg.Encode(&encoded, fill(8, 4, red, blue), nil)
+		data := encoded.Bytes()
+		icc := append([]byte("ICC_PROFILE\x00\x01\x01"), "profile"...)
+
+		var withMeta bytes.Buffer
+		withMeta.Write(data[:2])
+		writeJPEGSegment(&withMeta, jpegMarkerAPP2, icc)
+		writeJPEGSegment(&withMeta, jpegMarkerAPP2, append(append([]byte{}, mpfHeader...), "MM\x00\x2a"...))
+		withMeta.Write(data[2:])
+		// secondary image of mpf with its own exif
+		withMeta.Write(jpegWithExif(t, fill(2, 2, red, blue), 1))
+		withMeta.WriteString("trailer GPS 52.5200 N 13.4050 E")
+
+		stripped, err := StripMetadata("image/jpeg", withMeta.Bytes())
+		assert.NoError(t, err)
+		var want bytes.Buffer
+		want.Write(data[:2])
+		writeJPEGSegment(&want, jpegMarkerAPP2, icc)
+		want.Write(data[2:])
+		assert.Equal(t, want.Bytes(), stripped)
+	})
+
+	t.Run("gif drops comments and xmp but keeps the looping", func(t *testing.T) {
+		frame := func(c color.RGBA) *image.Paletted {
+			img := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{red, blue})
+			draw.Draw(img, img.Rect, image.NewUniform(c), image.Point{}, draw.Src)
+			return img
+		}
+		var encoded bytes.Buffer
+		assert.NoError(t, gif.EncodeAll(&encoded, &gif.GIF{
+			Image: []*image.Paletted{frame(red), frame(blue)},
+			Delay: []int{10, 10},
+		}))
+		data := encoded.Bytes()
+		assert.Contains(t, string(data), "NETSCAPE2.0")
+
+		// right after the global color table
+		gctEnd := 13 + gifColorTableSize(data[10])
+		xmp := append([]byte{gifExtension, gifApplication, 11}, "XMP DataXMP"...)
+		xmp = append(append(xmp, 10), "GPS 52.52N"...)
+		xmp = append(xmp, 0)
+		comment := append(append([]byte{gifExtension, gifComment, 12}, "shot at home"...), 0)
+		withMeta := append(append([]byte{}, data[:gctEnd]...), xmp...)
+		withMeta = append(withMeta, comment...)
+		withMeta = append(withMeta, data[gctEnd:]...)
+		withMeta = append(withMeta, "trailer"...)
+
+		stripped, err := StripMetadata("image/gif", withMeta)
+		assert.NoError(t, err)
+		assert.Equal(t, data, stripped)
+	})
+
+	t.Run("png drops text and exif chunks", func(t *testing.T) {
+		var encoded bytes.Buffer
+		png.Encode(&encoded, fill(8, 4, red, blue))
+		data := encoded.Bytes()
+		// right after IHDR
+		ihdrEnd := len(pngHeader) + 12 + 13
+		withMeta := append(append([]byte{}, data[:ihdrEnd]...), pngChunk("tEXt", []byte("Comment\x00shot at home"))...)
+		withMeta = append(withMeta, pngChunk("eXIf", []byte("MM\x00\x2aGPS"))...)
+		withMeta = append(withMeta, data[ihdrEnd:]...)
+
+		stripped, err := StripMetadata("image/png", withMeta)
+		assert.NoError(t, err)
+		assert.Equal(t, data, stripped)
+	})
+
+	t.Run("webp drops exif and clears its flag", func(t *testing.T) {
+		vp8x := []byte{0x08 | 0x04, 0, 0, 0, 7, 0, 0, 3, 0, 0}
+		chunk := func(fourCC string, data []byte) []byte {
+			b := append([]byte(fourCC), 0, 0, 0, 0)
+			binary.LittleEndian.PutUint32(b[4:], uint32(len(data)))
+			b = append(b, data...)
+			if len(data)%2 == 1 {
+				b = append(b, 0)
+			}
+			return b
+		}
+		body := append(chunk("VP8X", vp8x), chunk("VP8L", []byte("pixels"))...)
+		body = append(body, chunk("EXIF", []byte("GPS"))...)
+		body = append(body, chunk("XMP ", []byte("<x:xmpmeta/>"))...)
+		data := append([]byte("RIFF\x00\x00\x00\x00WEBP"), body...)
+		binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+
+		stripped, err := StripMetadata("image/webp", data)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(stripped), "GPS")
+		assert.NotContains(t, string(stripped), "xmpmeta")
+		assert.Contains(t, string(stripped), "pixels")
+		assert.Equal(t, byte(0), stripped[20])
+		assert.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:]))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := StripMetadata("image/jpeg", []byte("\xff\xd8\xff\xe1\xff\xff"))
+		assert.Error(t, err)
+		_, err = StripMetadata("image/png", []byte("not a png"))
+		assert.Error(t, err)
+		_, err = StripMetadata("image/gif", []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00\x21\xfe\x05ab"))
+		assert.Error(t, err)
+	})
+}
+
+func TestOrient(t *testing.T) {
+	// 2x1, red then blue
+	src := fill(2, 1, red, blue)
+	testCases := []struct {
+		desc        string
+		orientation int
+		want        []color.RGBA
+		size        image.Point
+	}{
+		{desc: "normal", orientation: 1, want: []color.RGBA{red, blue}, size: image.Pt(2, 1)},
+		{desc: "mirrored", orientation: 2, want: []color.RGBA{blue, red}, size: image.Pt(2, 1)},
+		{desc: "upside down", orientation: 3, want: []color.RGBA{blue, red}, size: image.Pt(2, 1)},
+		{desc: "rotated clockwise", orientation: 6, want: []color.RGBA{red, blue}, size: image.Pt(1, 2)},
+		{desc: "rotated counterclockwise", orientation: 8, want: []color.RGBA{blue, red}, size: image.Pt(1, 2)},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := toRGBA(Orient(src, tC.orientation))
+			assert.Equal(t, tC.size, got.Rect.Size())
+			if tC.size.X == 2 {
+				assert.Equal(t, tC.want, []color.RGBA{got.RGBAAt(0, 0), got.RGBAAt(1, 0)})
+			} else {
+				assert.Equal(t, tC.want, []color.RGBA{got.RGBAAt(0, 0), got.RGBAAt(0, 1)})
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		size    image.Point
+		maxSide int
+		want    image.Point
+	}{
+		{desc: "landscape", size: image.Pt(400, 200), maxSide: 100, want: image.Pt(100, 50)},
+		{desc: "portrait", size: image.Pt(200, 400), maxSide: 100, want: image.Pt(50, 100)},
+		{desc: "never scaled up", size: image.Pt(40, 20), maxSide: 100, want: image.Pt(40, 20)},
+		{desc: "very thin", size: image.Pt(1000, 1), maxSide: 100, want: image.Pt(100, 1)},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := Fit(fill(tC.size.X, tC.size.Y, red, blue), tC.maxSide)
+			assert.Equal(t, tC.want, got.Bounds().Size())
+		})
+	}
+
+	t.Run("colors are averaged", func(t *testing.T) {
+		got := toRGBA(Fit(fill(4, 4, red, blue), 1))
+		assert.Equal(t, color.RGBA{128, 0, 128, 255}, got.RGBAAt(0, 0))
+	})
+}
+
+func TestCropSquare(t *testing.T) {
+	got := CropSquare(fill(30, 10, red, blue))
+	assert.Equal(t, image.Pt(10, 10), got.Bounds().Size())
+	// the middle third, half red half blue
+	thumb := toRGBA(Fit(got, 2))
+	assert.Equal(t, red, thumb.RGBAAt(0, 0))
+	assert.Equal(t, blue, thumb.RGBAAt(1, 0))
+}
+
+func TestDominantColor(t *testing.T) {
+	// a red stripe on blue
+	striped := fill(10, 10, blue, blue)
+	for y := 0; y < 10; y++ {
+		striped.SetRGBA(0, y, red)
+		striped.SetRGBA(1, y, red)
+	}
+
+	testCases := []struct {
+		desc string
+		img  image.Image
+		want string
+	}{
+		{desc: "solid", img: fill(10, 10, red, red), want: "#ff0000"},
+		{desc: "majority wins", img: striped, want: "#0000ff"},
+		{desc: "transparent pixels are ignored", img: fill(10, 10, clear, red), want: "#ff0000"},
+		{desc: "fully transparent", img: fill(10, 10, clear, clear), want: ""},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, DominantColor(tC.img))
+		})
+	}
+}
+
+func TestBlurHash(t *testing.T) {
+	hash := BlurHash(fill(16, 16, red, red))
+	assert.Len(t, hash, 2+4+2*(BLURHASH_X_COMPONENTS*BLURHASH_Y_COMPONENTS-1))
+	// 4x3 components
+	assert.Equal(t, "L", hash[:1])
+	// the average color, 0xff0000 in base 83
+	assert.Equal(t, "TI:j", hash[2:6])
+
+	assert.NotEqual(t, hash, BlurHash(fill(16, 16, red, blue)))
+}
+
+func TestEncode(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, Encode(&out, fill(8, 8, clear, red), FORMAT_JPEG))
+	img, err := Decode(out.Bytes())
+	if assert.NoError(t, err) {
+		r, g, b, _ := img.At(0, 0).RGBA()
+		// transparent became white
+		assert.True(t, r>>8 > 240 && g>>8 > 240 && b>>8 > 240)
+	}
+
+	assert.ErrorIs(t, Encode(&out, fill(8, 8, red, red), "bmp"), ErrUnsupportedFormat)
+	_, err = Decode([]byte("BM\x46\x00\x00\x00\x00\x00\x00\x00"))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestEncodeWebP(t *testing.T) {
+	// neighbours that differ a bit, like a photo
+	noisy := func(w, h int) *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		seed := uint32(1)
+		for i := range img.Pix {
+			seed = seed*1664525 + 1013904223
+			img.Pix[i] = uint8(i/4%w+i/4/w) + uint8(seed>>28)
+		}
+		return img
+	}
+	withAlpha := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	draw.Draw(withAlpha, withAlpha.Rect, image.NewUniform(color.NRGBA{10, 200, 30, 128}), image.Point{}, draw.Src)
+	withAlpha.SetNRGBA(0, 0, color.NRGBA{})
+
+	testCases := []struct {
+		desc string
+		img  image.Image
+	}{
+		{desc: "single pixel", img: fill(1, 1, red, red)},
+		{desc: "two colors", img: fill(8, 4, red, blue)},
+		{desc: "transparency is kept", img: withAlpha},
+		{desc: "noise", img: noisy(64, 48)},
+		{desc: "more than one predictor block", img: noisy(600, 3)},
+		{desc: "offset bounds", img: noisy(40, 40).SubImage(image.Rect(5, 7, 30, 20))},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, Encode(&out, tC.img, FORMAT_WEBP))
+			assert.Equal(t, "RIFF", out.String()[:4])
+			assert.Equal(t, uint32(out.Len()-8), binary.LittleEndian.Uint32(out.Bytes()[4:]))
+
+			img, err := Decode(out.Bytes())
+			if !assert.NoError(t, err) {
+				return
+			}
+			b := tC.img.Bounds()
+			assert.Equal(t, b.Size(), img.Bounds().Size())
+			for y := 0; y < b.Dy(); y++ {
+				for x := 0; x < b.Dx(); x++ {
+					want := color.NRGBAModel.Convert(tC.img.At(b.Min.X+x, b.Min.Y+y))
+					if !assert.Equal(t, want, color.NRGBAModel.Convert(img.At(x, y)), "pixel %d,%d", x, y) {
+						return
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("too large", func(t *testing.T) {
+		err := Encode(io.Discard, image.NewNRGBA(image.Rect(0, 0, vp8lMaxSide+1, 1)), FORMAT_WEBP)
+		assert.ErrorIs(t, err, errWebPTooLarge)
+	})
+}
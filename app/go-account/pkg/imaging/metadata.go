@@ -0,0 +1,409 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// metadata is removed in place from the original file, the pixels are
+// never re-encoded so the original keeps its quality.
+
+var errMalformed = errors.New("malformed image")
+
+const (
+	ORIENTATION_NORMAL = 1
+
+	jpegMarkerRST0  = 0xd0
+	jpegMarkerRST7  = 0xd7
+	jpegMarkerSOI   = 0xd8
+	jpegMarkerEOI   = 0xd9
+	jpegMarkerAPP0  = 0xe0 // jfif
+	jpegMarkerSOS   = 0xda
+	jpegMarkerAPP1  = 0xe1 // exif, xmp
+	jpegMarkerAPP2  = 0xe2 // icc profile, mpf
+	jpegMarkerAPP13 = 0xed // iptc, photoshop
+	jpegMarkerCOM   = 0xfe
+
+	gifExtension   = 0x21
+	gifImage       = 0x2c
+	gifTrailer     = 0x3b
+	gifApplication = 0xff // xmp, icc profile, looping
+	gifComment     = 0xfe
+
+	exifTagOrientation = 0x0112
+)
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	// multi picture format, the index of the secondary images (thumbnails,
+	// depth maps) a camera appends after the EOI of the primary one
+	mpfHeader  = []byte("MPF\x00")
+	pngHeader  = []byte("\x89PNG\r\n\x1a\n")
+	gifHeaders = [][]byte{[]byte("GIF87a"), []byte("GIF89a")}
+
+	// the looping of an animation is the only application extension
+	// browsers act on
+	gifKeptApplications = map[string]bool{
+		"NETSCAPE2.0": true,
+		"ANIMEXTS1.0": true,
+	}
+
+	// text chunks may hold anything, exif is the gps one
+	pngDroppedChunks = map[string]bool{
+		"eXIf": true,
+		"tEXt": true,
+		"zTXt": true,
+		"iTXt": true,
+		"tIME": true,
+	}
+	webpDroppedChunks = map[string]bool{
+		"EXIF": true,
+		"XMP ": true,
+	}
+)
+
+// StripMetadata removes exif (gps, camera serial...), xmp, iptc and
+// comments. The exif orientation of a jpeg is kept, without it the
+// original would show rotated. Other formats are returned as they are.
+func StripMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/gif":
+		return stripGIF(data)
+	case "image/webp":
+		return stripWebP(data)
+	}
+	return data, nil
+}
+
+// Orientation reads the exif orientation (1-8) of a jpeg,
+// ORIENTATION_NORMAL when there is none.
+func Orientation(data []byte) int {
+	orientation := ORIENTATION_NORMAL
+	walkJPEG(data, func(marker byte, segment []byte) bool {
+		if marker == jpegMarkerAPP1 && bytes.HasPrefix(segment, exifHeader) {
+			if o, ok := exifOrientation(segment[len(exifHeader):]); ok {
+				orientation = o
+			}
+			return false
+		}
+		return true
+	})
+	return orientation
+}
+
+// walkJPEG calls fn with every segment before the scan data until fn
+// returns false, it returns the offset of the SOS marker.
+func walkJPEG(data []byte, fn func(marker byte, segment []byte) bool) (int, error) {
+	if len(data) < 2 || data[0] != 0xff || data[1] != jpegMarkerSOI {
+		return 0, errMalformed
+	}
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xff {
+			return 0, errMalformed
+		}
+		marker := data[i+1]
+		if marker == 0xff { // fill byte
+			i++
+			continue
+		}
+		if marker == jpegMarkerSOS {
+			return i, nil
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 0, errMalformed
+		}
+		if !fn(marker, data[i+4:i+2+length]) {
+			return i, nil
+		}
+		i += 2 + length
+	}
+	return 0, errMalformed
+}
+
+// droppedJPEGSegment tells whether a segment only carries metadata.
+func droppedJPEGSegment(marker byte, segment []byte) bool {
+	switch marker {
+	case jpegMarkerAPP1, jpegMarkerAPP13, jpegMarkerCOM:
+		return true
+	case jpegMarkerAPP2:
+		return bytes.HasPrefix(segment, mpfHeader)
+	}
+	return false
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	orientation := ORIENTATION_NORMAL
+
+	sos, err := walkJPEG(data, func(marker byte, segment []byte) bool {
+		if marker == jpegMarkerAPP1 && bytes.HasPrefix(segment, exifHeader) {
+			if o, ok := exifOrientation(segment[len(exifHeader):]); ok {
+				orientation = o
+			}
+		}
+		if droppedJPEGSegment(marker, segment) {
+			return true
+		}
+		if orientation != ORIENTATION_NORMAL && marker != jpegMarkerAPP0 {
+			// exif goes right after JFIF
+			writeJPEGSegment(out, jpegMarkerAPP1, orientationExif(orientation))
+			orientation = ORIENTATION_NORMAL
+		}
+		writeJPEGSegment(out, marker, segment)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if orientation != ORIENTATION_NORMAL {
+		writeJPEGSegment(out, jpegMarkerAPP1, orientationExif(orientation))
+	}
+	if err = copyJPEGScans(out, data, sos); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// copyJPEGScans copies the scans from sos up to the first EOI. The
+// secondary images of MPF and the trailers some apps append after the
+// EOI carry their own exif, they are not part of the image.
+func copyJPEGScans(out *bytes.Buffer, data []byte, sos int) error {
+	start := sos // of the entropy coded data not written yet
+	for i := sos; i+2 <= len(data); {
+		if data[i] != 0xff {
+			i++
+			continue
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xff: // fill byte
+			i++
+			continue
+		case marker == 0x00, marker >= jpegMarkerRST0 && marker <= jpegMarkerRST7:
+			// stuffed 0xff and restart markers are part of the scan
+			i += 2
+			continue
+		case marker == jpegMarkerEOI:
+			out.Write(data[start : i+2])
+			return nil
+		}
+
+		// tables and the next scans of a progressive jpeg
+		if i+4 > len(data) {
+			return errMalformed
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return errMalformed
+		}
+		out.Write(data[start:i])
+		if segment := data[i+4 : i+2+length]; !droppedJPEGSegment(marker, segment) {
+			writeJPEGSegment(out, marker, segment)
+		}
+		i += 2 + length
+		start = i
+	}
+	return errMalformed
+}
+
+func writeJPEGSegment(out *bytes.Buffer, marker byte, segment []byte) {
+	out.Write([]byte{0xff, marker})
+	binary.Write(out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+}
+
+// exifOrientation looks for the orientation tag in IFD0 of a tiff block.
+func exifOrientation(tiff []byte) (int, bool) {
+	if len(tiff) < 8 {
+		return 0, false
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0, false
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < count; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0, false
+		}
+		if order.Uint16(tiff[entry:]) == exifTagOrientation {
+			o := int(order.Uint16(tiff[entry+8:]))
+			return o, o >= 1 && o <= 8
+		}
+	}
+	return 0, false
+}
+
+// orientationExif is an exif block holding nothing but the orientation.
+func orientationExif(orientation int) []byte {
+	var b bytes.Buffer
+	b.Write(exifHeader)
+	b.WriteString("MM")
+	binary.Write(&b, binary.BigEndian, []uint16{0x2a})
+	binary.Write(&b, binary.BigEndian, uint32(8))                       // IFD0 right after the header
+	binary.Write(&b, binary.BigEndian, uint16(1))                       // one entry
+	binary.Write(&b, binary.BigEndian, []uint16{exifTagOrientation, 3}) // SHORT
+	binary.Write(&b, binary.BigEndian, uint32(1))
+	binary.Write(&b, binary.BigEndian, []uint16{uint16(orientation), 0})
+	binary.Write(&b, binary.BigEndian, uint32(0)) // no next IFD
+	return b.Bytes()
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngHeader) {
+		return nil, errMalformed
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngHeader)
+	for i := len(pngHeader); i < len(data); {
+		if i+12 > len(data) {
+			return nil, errMalformed
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length // length, type, data, crc
+		if length < 0 || end > len(data) {
+			return nil, errMalformed
+		}
+		if !pngDroppedChunks[string(data[i+4:i+8])] {
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+	return out.Bytes(), nil
+}
+
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errMalformed
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, errMalformed
+		}
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size%2 // chunks are padded to even
+		if size < 0 || end > len(data) {
+			return nil, errMalformed
+		}
+		switch {
+		case webpDroppedChunks[fourCC]:
+		case fourCC == "VP8X" && size >= 1:
+			// the extended header flags which chunks follow
+			chunk := append([]byte(nil), data[i:end]...)
+			chunk[8] &^= 0x08 | 0x04 // exif, xmp
+			out.Write(chunk)
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+	riff := out.Bytes()
+	binary.LittleEndian.PutUint32(riff[4:], uint32(len(riff)-8))
+	return riff, nil
+}
+
+// stripGIF drops comments and application extensions (xmp, icc) except
+// the looping of animations, and whatever follows the trailer.
+func stripGIF(data []byte) ([]byte, error) {
+	if len(data) < 13 || !(bytes.HasPrefix(data, gifHeaders[0]) || bytes.HasPrefix(data, gifHeaders[1])) {
+		return nil, errMalformed
+	}
+	// header, logical screen descriptor and global color table
+	i := 13 + gifColorTableSize(data[10])
+	if i > len(data) {
+		return nil, errMalformed
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:i])
+	for i < len(data) {
+		start := i
+		switch data[i] {
+		case gifTrailer:
+			out.WriteByte(gifTrailer)
+			return out.Bytes(), nil
+		case gifImage:
+			// descriptor, local color table and lzw code size
+			if i+10 > len(data) {
+				return nil, errMalformed
+			}
+			i += 10 + gifColorTableSize(data[i+9]) + 1
+		case gifExtension:
+			if i+2 > len(data) {
+				return nil, errMalformed
+			}
+			i += 2
+		default:
+			return nil, errMalformed
+		}
+		end, err := skipGIFSubBlocks(data, i)
+		if err != nil {
+			return nil, err
+		}
+		if !droppedGIFBlock(data[start:end]) {
+			out.Write(data[start:end])
+		}
+		i = end
+	}
+	return nil, errMalformed
+}
+
+// gifColorTableSize is the size of the color table flagged in a
+// logical screen or image descriptor.
+func gifColorTableSize(flags byte) int {
+	if flags&0x80 == 0 {
+		return 0
+	}
+	return 3 << (flags&0x07 + 1)
+}
+
+// skipGIFSubBlocks returns the offset after the block terminator.
+func skipGIFSubBlocks(data []byte, i int) (int, error) {
+	for {
+		if i >= len(data) {
+			return 0, errMalformed
+		}
+		size := int(data[i])
+		i += 1 + size
+		if size == 0 {
+			return i, nil
+		}
+	}
+}
+
+func droppedGIFBlock(block []byte) bool {
+	if block[0] != gifExtension {
+		return false
+	}
+	switch block[1] {
+	case gifComment:
+		return true
+	case gifApplication:
+		// the first sub-block is the 11 byte identifier
+		if len(block) < 14 || block[2] != 11 {
+			return true
+		}
+		return !gifKeptApplications[string(block[3:14])]
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// toRGBA copies img into a premultiplied RGBA starting at 0,0,
+// averaging premultiplied pixels keeps transparent edges clean.
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, b.Min, draw.Src)
+	return rgba
+}
+
+// Orient applies an exif orientation so the pixels are the way the
+// photo is meant to be seen.
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation <= ORIENTATION_NORMAL || orientation > 8 {
+		return img
+	}
+	src := toRGBA(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // transposed
+		dw, dh = h, w
+	}
+
+	// source coordinates of a destination pixel
+	at := map[int]func(x, y int) (int, int){
+		2: func(x, y int) (int, int) { return w - 1 - x, y },
+		3: func(x, y int) (int, int) { return w - 1 - x, h - 1 - y },
+		4: func(x, y int) (int, int) { return x, h - 1 - y },
+		5: func(x, y int) (int, int) { return y, x },
+		6: func(x, y int) (int, int) { return y, h - 1 - x },
+		7: func(x, y int) (int, int) { return w - 1 - y, h - 1 - x },
+		8: func(x, y int) (int, int) { return w - 1 - y, x },
+	}[orientation]
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := at(x, y)
+			copy(dst.Pix[dst.PixOffset(x, y):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+	return dst
+}
+
+// CropSquare keeps the centered square of img.
+func CropSquare(img image.Image) image.Image {
+	rgba := toRGBA(img)
+	w, h := rgba.Rect.Dx(), rgba.Rect.Dy()
+	side := w
+	if h < side {
+		side = h
+	}
+	x, y := (w-side)/2, (h-side)/2
+	return rgba.SubImage(image.Rect(x, y, x+side, y+side))
+}
+
+// Fit scales img down so its longest side is at most maxSide,
+// smaller images are never scaled up.
+func Fit(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+	dw, dh := maxSide, h*maxSide/w
+	if h > w {
+		dw, dh = w*maxSide/h, maxSide
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+	return resize(toRGBA(img), dw, dh)
+}
+
+// resize is a box filter, every source pixel counts once in the
+// destination pixel it falls in. Good enough when only scaling down.
+func resize(src *image.RGBA, dw, dh int) *image.RGBA {
+	b := src.Rect
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	sums := make([]uint64, dw*4)
+	counts := make([]uint64, dw)
+
+	sy := 0
+	for dy := 0; dy < dh; dy++ {
+		for i := range sums {
+			sums[i] = 0
+		}
+		for i := range counts {
+			counts[i] = 0
+		}
+		for ; sy < (dy+1)*h/dh; sy++ {
+			row := src.Pix[src.PixOffset(b.Min.X, b.Min.Y+sy):]
+			for sx := 0; sx < w; sx++ {
+				dx := sx * dw / w
+				for c := 0; c < 4; c++ {
+					sums[dx*4+c] += uint64(row[sx*4+c])
+				}
+				counts[dx]++
+			}
+		}
+		out := dst.Pix[dst.PixOffset(0, dy):]
+		for dx := 0; dx < dw; dx++ {
+			n := counts[dx]
+			if n == 0 {
+				continue
+			}
+			for c := 0; c < 4; c++ {
+				out[dx*4+c] = uint8((sums[dx*4+c] + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
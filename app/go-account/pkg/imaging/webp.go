@@ -0,0 +1,388 @@
+package imaging
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// webp variants are lossless (VP8L), golang.org/x/image only decodes
+// webp. The encoder keeps to what every decoder has to support: the
+// subtract green and predictor transforms and one set of prefix codes,
+// no color cache and no backward references.
+
+var errWebPTooLarge = errors.New("image is too large for webp")
+
+const (
+	vp8lSignature = 0x2f
+	vp8lMaxSide   = 1 << 14
+
+	vp8lTransformPredictor     = 0
+	vp8lTransformSubtractGreen = 2
+
+	// one predictor for every 512x512 block, ClampAddSubtractFull(L, T, TL)
+	// does well on photos
+	vp8lPredictorBits     = 9
+	vp8lPredictorGradient = 12
+
+	vp8lMaxCodeLength           = 15
+	vp8lMaxCodeLengthCodeLength = 7
+)
+
+var (
+	// green, red, blue, alpha and distance
+	vp8lAlphabetSizes = [5]int{256 + 24, 256, 256, 256, 40}
+
+	vp8lCodeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+)
+
+// bitWriter packs values from the least significant bit on.
+type bitWriter struct {
+	buf  []byte
+	bits uint64
+	n    uint
+}
+
+func (w *bitWriter) write(v uint32, n uint) {
+	w.bits |= uint64(v) << w.n
+	w.n += n
+	for w.n >= 8 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits >>= 8
+		w.n -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.n > 0 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits, w.n = 0, 0
+	}
+	return w.buf
+}
+
+func encodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > vp8lMaxSide || height > vp8lMaxSide {
+		return errWebPTooLarge
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(nrgba, nrgba.Rect, img, b.Min, draw.Src)
+
+	alpha := uint32(1)
+	if nrgba.Opaque() {
+		alpha = 0
+	}
+	bw := &bitWriter{}
+	bw.write(vp8lSignature, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	bw.write(alpha, 1) // a hint only
+	bw.write(0, 3)     // version
+
+	// the decoder undoes the transforms in the reverse order
+	bw.write(1, 1)
+	bw.write(vp8lTransformSubtractGreen, 2)
+	pix := subtractGreen(nrgba.Pix)
+	bw.write(1, 1)
+	bw.write(vp8lTransformPredictor, 2)
+	bw.write(vp8lPredictorBits-2, 3)
+	modes := make([]byte, 4*vp8lTiles(width)*vp8lTiles(height))
+	for i := 1; i < len(modes); i += 4 {
+		modes[i] = vp8lPredictorGradient // the mode is the green of a block
+	}
+	writeVP8LImage(bw, modes, false)
+	pix = predictGradient(pix, width, height)
+	bw.write(0, 1)
+
+	writeVP8LImage(bw, pix, true)
+	data := bw.bytes()
+
+	// RIFF container with a single VP8L chunk, chunks are padded to even
+	padded := len(data) + len(data)%2
+	header := make([]byte, 20, 20+padded)
+	copy(header, "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+padded))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+	out := append(header, data...)
+	if len(data)%2 == 1 {
+		out = append(out, 0)
+	}
+	_, err := w.Write(out)
+	return err
+}
+
+func vp8lTiles(size int) int {
+	return (size + 1<<vp8lPredictorBits - 1) >> vp8lPredictorBits
+}
+
+// subtractGreen works on NRGBA, red and blue become their difference
+// with green.
+func subtractGreen(pix []byte) []byte {
+	out := make([]byte, len(pix))
+	for i := 0; i < len(pix); i += 4 {
+		out[i+0] = pix[i+0] - pix[i+1]
+		out[i+1] = pix[i+1]
+		out[i+2] = pix[i+2] - pix[i+1]
+		out[i+3] = pix[i+3]
+	}
+	return out
+}
+
+// predictGradient returns the residuals of every pixel, the first pixel
+// is predicted as opaque black, the first row from the left and the
+// first column from the top.
+func predictGradient(pix []byte, width, height int) []byte {
+	out := make([]byte, len(pix))
+	stride := 4 * width
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := y*stride + 4*x
+			for c := 0; c < 4; c++ {
+				var predicted byte
+				switch {
+				case x == 0 && y == 0:
+					if c == 3 {
+						predicted = 0xff
+					}
+				case y == 0:
+					predicted = pix[p-4+c]
+				case x == 0:
+					predicted = pix[p-stride+c]
+				default:
+					predicted = clampAddSubtract(pix[p-4+c], pix[p-stride+c], pix[p-stride-4+c])
+				}
+				out[p+c] = pix[p+c] - predicted
+			}
+		}
+	}
+	return out
+}
+
+func clampAddSubtract(a, b, c byte) byte {
+	v := int(a) + int(b) - int(c)
+	switch {
+	case v < 0:
+		return 0
+	case v > 0xff:
+		return 0xff
+	}
+	return byte(v)
+}
+
+// writeVP8LImage writes the prefix codes and the literals of pix (NRGBA),
+// only the main image may carry meta prefix codes.
+func writeVP8LImage(bw *bitWriter, pix []byte, main bool) {
+	bw.write(0, 1) // no color cache
+	if main {
+		bw.write(0, 1) // one group of prefix codes
+	}
+
+	var counts [5][]int
+	for i, size := range vp8lAlphabetSizes {
+		counts[i] = make([]int, size)
+	}
+	for p := 0; p < len(pix); p += 4 {
+		counts[0][pix[p+1]]++
+		counts[1][pix[p+0]]++
+		counts[2][pix[p+2]]++
+		counts[3][pix[p+3]]++
+	}
+	var codes [5]prefixCode
+	for i := range codes {
+		codes[i] = writePrefixCode(bw, counts[i])
+	}
+	for p := 0; p < len(pix); p += 4 {
+		codes[0].write(bw, int(pix[p+1]))
+		codes[1].write(bw, int(pix[p+0]))
+		codes[2].write(bw, int(pix[p+2]))
+		codes[3].write(bw, int(pix[p+3]))
+	}
+}
+
+// prefixCode holds the bit reversed canonical code of every symbol, the
+// decoder reads a code from its most significant bit on.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint16
+}
+
+func (c prefixCode) write(bw *bitWriter, symbol int) {
+	bw.write(uint32(c.codes[symbol]), uint(c.lengths[symbol]))
+}
+
+// writePrefixCode writes the code of an alphabet with counts and
+// returns it.
+func writePrefixCode(bw *bitWriter, counts []int) prefixCode {
+	var used []int
+	for symbol, count := range counts {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+	if len(used) == 0 {
+		used = []int{0}
+	}
+
+	code := prefixCode{lengths: make([]uint8, len(counts)), codes: make([]uint16, len(counts))}
+	if len(used) <= 2 && used[len(used)-1] < 256 {
+		// simple code, a single symbol takes no bits
+		bw.write(1, 1)
+		bw.write(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(used[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(used[0]), 8)
+		}
+		if len(used) == 2 {
+			bw.write(uint32(used[1]), 8)
+			code.lengths[used[0]], code.codes[used[0]] = 1, 0
+			code.lengths[used[1]], code.codes[used[1]] = 1, 1
+		}
+		return code
+	}
+
+	lengths := huffmanLengths(counts, vp8lMaxCodeLength)
+	lengthCounts := make([]int, len(vp8lCodeLengthCodeOrder))
+	for _, l := range lengths {
+		lengthCounts[l]++
+	}
+	lengthLengths := huffmanLengths(lengthCounts, vp8lMaxCodeLengthCodeLength)
+	lengthCode := canonicalCode(lengthLengths)
+
+	bw.write(0, 1)
+	n := 4
+	for i, symbol := range vp8lCodeLengthCodeOrder {
+		if lengthLengths[symbol] > 0 && i+1 > n {
+			n = i + 1
+		}
+	}
+	bw.write(uint32(n-4), 4)
+	for _, symbol := range vp8lCodeLengthCodeOrder[:n] {
+		bw.write(uint32(lengthLengths[symbol]), 3)
+	}
+	bw.write(0, 1) // every symbol has a length
+	for _, l := range lengths {
+		lengthCode.write(bw, int(l))
+	}
+	return canonicalCode(lengths)
+}
+
+// canonicalCode assigns the codes in the order of length and symbol,
+// a code of a single symbol takes no bits.
+func canonicalCode(lengths []uint8) prefixCode {
+	code := prefixCode{lengths: make([]uint8, len(lengths)), codes: make([]uint16, len(lengths))}
+	var count [vp8lMaxCodeLength + 1]int
+	used := 0
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+			used++
+		}
+	}
+	if used == 1 {
+		// the decoder builds no tree for it either
+		return code
+	}
+	var next [vp8lMaxCodeLength + 1]int
+	for l, c := 1, 0; l <= vp8lMaxCodeLength; l++ {
+		c = (c + count[l-1]) << 1
+		next[l] = c
+	}
+	for symbol, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		var reversed uint16
+		for i, c := uint8(0), next[l]; i < l; i++ {
+			reversed = reversed<<1 | uint16(c>>i&1)
+		}
+		code.lengths[symbol], code.codes[symbol] = l, reversed
+		next[l]++
+	}
+	return code
+}
+
+// huffmanLengths are the code lengths of counts, no longer than
+// maxLength. Counts are flattened until the tree is shallow enough.
+func huffmanLengths(counts []int, maxLength int) []uint8 {
+	counts = append([]int(nil), counts...)
+	for {
+		lengths := huffmanTree(counts)
+		longest := uint8(0)
+		for _, l := range lengths {
+			if l > longest {
+				longest = l
+			}
+		}
+		if int(longest) <= maxLength {
+			return lengths
+		}
+		for i, c := range counts {
+			if c > 1 {
+				counts[i] = (c + 1) / 2
+			}
+		}
+	}
+}
+
+type huffmanNode struct {
+	count       int
+	symbol      int
+	left, right *huffmanNode
+}
+
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int           { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h huffmanHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x any)        { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+func huffmanTree(counts []int) []uint8 {
+	lengths := make([]uint8, len(counts))
+	h := &huffmanHeap{}
+	for symbol, count := range counts {
+		if count > 0 {
+			*h = append(*h, &huffmanNode{count: count, symbol: symbol})
+		}
+	}
+	switch h.Len() {
+	case 0:
+		return lengths
+	case 1:
+		lengths[(*h)[0].symbol] = 1
+		return lengths
+	}
+	heap.Init(h)
+	for h.Len() > 1 {
+		a := heap.Pop(h).(*huffmanNode)
+		b := heap.Pop(h).(*huffmanNode)
+		heap.Push(h, &huffmanNode{count: a.count + b.count, left: a, right: b})
+	}
+
+	var walk func(n *huffmanNode, depth uint8)
+	walk = func(n *huffmanNode, depth uint8) {
+		if n.left == nil {
+			lengths[n.symbol] = depth
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	walk((*h)[0], 0)
+	return lengths
+}
@@ -1,11 +1,17 @@
 package middleware
 
 import (
-	"net/http"
+	"context"
+	"strconv"
 	"strings"
+	"time"
 
+	accountmodel "github.com/mygram/go-account/modules/models/account"
 	tokenmodel "github.com/mygram/go-account/modules/models/token"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
 	"github.com/mygram/go-account/pkg/crypto"
+	"github.com/mygram/go-common/pkg/domainerr"
+	commonmidware "github.com/mygram/go-common/pkg/middleware"
 	"github.com/mygram/go-common/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -27,86 +33,219 @@ const (
 	Authorization HeaderKey = "Authorization"
 
 	AccessClaim ContextKey = "access_claim"
+	TokenClaim  ContextKey = "token_claim"
 
 	BasicAuth  string = "Basic "
 	BearerAuth string = "Bearer "
+
+	// AccessTokenQuery carries the token of streams opened by browsers,
+	// they can not set headers on websocket and EventSource requests
+	AccessTokenQuery string = "access_token"
 )
 
-func BearerOAuth() gin.HandlerFunc {
+func BearerOAuth(revocationStore revocationrepo.IRevocationStore) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// auth header
 		header := ctx.GetHeader(Authorization.String())
 		if header == "" {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{
-				Message: response.Unauthorized,
-				Error:   "token is not found",
-			})
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_UNAUTHENTICATED, "token is not found"))
+			return
+		}
+		if err := setBearerClaim(ctx, revocationStore, header); err != nil {
+			commonmidware.AbortWithError(ctx, err)
 			return
 		}
+		ctx.Next()
+	}
+}
 
-		// get token
-		token := strings.Split(header, BearerAuth)
-		if len(token) != 2 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{
-				Message: response.Unauthorized,
-				Error:   "token is not found",
-			})
+// OptionalBearerOAuth lets anonymous requests through, a request that
+// carries a token still needs a valid one. Handlers check for the claim.
+func OptionalBearerOAuth(revocationStore revocationrepo.IRevocationStore) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.GetHeader(Authorization.String())
+		if header == "" {
+			ctx.Next()
+			return
+		}
+		if err := setBearerClaim(ctx, revocationStore, header); err != nil {
+			commonmidware.AbortWithError(ctx, err)
 			return
 		}
+		ctx.Next()
+	}
+}
 
-		// header token is found
-		var claim tokenmodel.AccessClaim
-		err := crypto.ParseJWT(token[1], &claim)
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{
-				Message: response.Unauthorized,
-				Error:   "invalid token",
-			})
+// StreamBearerOAuth is BearerOAuth that also takes the token from
+// ?access_token=, only use it on the realtime streams.
+func StreamBearerOAuth(revocationStore revocationrepo.IRevocationStore) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.GetHeader(Authorization.String())
+		if header == "" && ctx.Query(AccessTokenQuery) != "" {
+			header = BearerAuth + ctx.Query(AccessTokenQuery)
+		}
+		if header == "" {
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_UNAUTHENTICATED, "token is not found"))
+			return
+		}
+		if err := setBearerClaim(ctx, revocationStore, header); err != nil {
+			commonmidware.AbortWithError(ctx, err)
 			return
 		}
-		ctx.Set(AccessClaim.String(), claim)
 		ctx.Next()
 	}
 }
 
+func setBearerClaim(ctx *gin.Context, revocationStore revocationrepo.IRevocationStore, header string) error {
+	// get token
+	token := strings.Split(header, BearerAuth)
+	if len(token) != 2 {
+		return domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_UNAUTHENTICATED, "token is not found")
+	}
+
+	// header token is found
+	var claim struct {
+		tokenmodel.DefaultClaim
+		tokenmodel.AccessClaim
+	}
+	err := crypto.ParseJWT(token[1], &claim)
+	if err != nil {
+		return domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "invalid token")
+	}
+	// id and refresh tokens are signed by us too, only an access token
+	// may authorize a request
+	if claim.Type != tokenmodel.ACCESS_TOKEN {
+		return domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "not an access token")
+	}
+	if err = CheckTokenClaim(ctx, revocationStore, claim.DefaultClaim); err != nil {
+		return err
+	}
+	ctx.Set(AccessClaim.String(), claim.AccessClaim)
+	ctx.Set(TokenClaim.String(), claim.DefaultClaim)
+	return nil
+}
+
+// CheckTokenClaim fails when the token has expired or was revoked since
+// it was parsed, streams check their token again on every heartbeat.
+func CheckTokenClaim(ctx context.Context, revocationStore revocationrepo.IRevocationStore, claim tokenmodel.DefaultClaim) error {
+	// exp is in unix seconds, a token without one would never expire
+	if claim.Expired == 0 || time.Now().Unix() > int64(claim.Expired) {
+		return domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "token is expired")
+	}
+
+	// token is signed by us, but may have been revoked by logout
+	revoked, err := revocationStore.IsRevoked(ctx, claim.JTI)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_REVOKED, "token is revoked")
+	}
+	return nil
+}
+
+// RequireRole must be chained after BearerOAuth, it only lets
+// requests through when the access token carries one of roles.
+func RequireRole(roles ...accountmodel.AccountRole) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role, ok := roleFromClaim(ctx)
+		if !ok {
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_UNAUTHENTICATED, "token is not found"))
+			return
+		}
+
+		for _, r := range roles {
+			if role == r {
+				ctx.Next()
+				return
+			}
+		}
+		commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_FORBIDDEN, response.CODE_ROLE_NOT_ALLOWED, "role is not allowed"))
+	}
+}
+
+// RequirePermission must be chained after BearerOAuth, it only lets
+// requests through when the role of the access token has permission.
+func RequirePermission(permission accountmodel.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role, ok := roleFromClaim(ctx)
+		if !ok {
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_UNAUTHENTICATED, "token is not found"))
+			return
+		}
+
+		if !role.Can(permission) {
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_FORBIDDEN, response.CODE_FORBIDDEN, "permission is not granted"))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func roleFromClaim(ctx *gin.Context) (role accountmodel.AccountRole, ok bool) {
+	claimI, ok := ctx.Get(AccessClaim.String())
+	if !ok {
+		return
+	}
+	claim, ok := claimI.(tokenmodel.AccessClaim)
+	if !ok {
+		return
+	}
+	return accountmodel.AccountRole(claim.Role), true
+}
+
+// UserIDFromClaim is the user of the access token set by BearerOAuth or
+// OptionalBearerOAuth, ok is false for anonymous requests.
+func UserIDFromClaim(ctx *gin.Context) (userId uint64, ok bool) {
+	claimI, ok := ctx.Get(AccessClaim.String())
+	if !ok {
+		return
+	}
+	claim, ok := claimI.(tokenmodel.AccessClaim)
+	if !ok {
+		return
+	}
+	userId, err := strconv.ParseUint(claim.UserID, 10, 64)
+	return userId, err == nil
+}
+
+// TokenClaimFromContext is the jti and expiry of the access token set by
+// BearerOAuth, ok is false for anonymous requests.
+func TokenClaimFromContext(ctx *gin.Context) (claim tokenmodel.DefaultClaim, ok bool) {
+	claimI, ok := ctx.Get(TokenClaim.String())
+	if !ok {
+		return
+	}
+	claim, ok = claimI.(tokenmodel.DefaultClaim)
+	return
+}
+
 func BasicAuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// auth header
 		header := ctx.GetHeader(Authorization.String())
 		if header == "" {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{
-				Message: response.Unauthorized,
-				Error:   "token is not found",
-			})
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_UNAUTHENTICATED, "token is not found"))
 			return
 		}
 		// get token
 		token := strings.Split(header, BasicAuth)
 		if len(token) != 2 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{
-				Message: response.Unauthorized,
-				Error:   "token is not found",
-			})
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_UNAUTHENTICATED, "token is not found"))
 			return
 		}
 
 		// header token is found
 		payload, err := crypto.DecodeBase64(ctx, token[1])
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{
-				Message: response.Unauthorized,
-				Error:   "invalid token",
-			})
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "invalid token"))
 			return
 		}
 
 		// check payload
 		splitted := strings.Split(payload, ":")
 		if len(splitted) != 2 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{
-				Message: response.Unauthorized,
-				Error:   "invalid token",
-			})
+			commonmidware.AbortWithError(ctx, domainerr.New(domainerr.KIND_UNAUTHENTICATED, response.CODE_TOKEN_INVALID, "invalid token"))
 			return
 		}
 
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	tokenmodel "github.com/mygram/go-account/modules/models/token"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	"github.com/mygram/go-account/pkg/crypto"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRoleAndPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	withClaim := func(role string) gin.HandlerFunc {
+		return func(ctx *gin.Context) {
+			if role != "" {
+				ctx.Set(AccessClaim.String(), tokenmodel.AccessClaim{Role: role, UserID: "1"})
+			}
+			ctx.Next()
+		}
+	}
+
+	testCases := []struct {
+		desc       string
+		role       string
+		middleware gin.HandlerFunc
+		wantStatus int
+	}{
+		{
+			desc:       "admin passes require role",
+			role:       string(accountmodel.ROLE_ADMIN),
+			middleware: RequireRole(accountmodel.ROLE_ADMIN),
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "normal is forbidden by require role",
+			role:       string(accountmodel.ROLE_NORMAL),
+			middleware: RequireRole(accountmodel.ROLE_ADMIN),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc:       "missing claim is unauthorized",
+			role:       "",
+			middleware: RequireRole(accountmodel.ROLE_ADMIN),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:       "admin has permission",
+			role:       string(accountmodel.ROLE_ADMIN),
+			middleware: RequirePermission(accountmodel.PERMISSION_MANAGE_ANY_CONTENT),
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "normal has no permission",
+			role:       string(accountmodel.ROLE_NORMAL),
+			middleware: RequirePermission(accountmodel.PERMISSION_MANAGE_ANY_CONTENT),
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", withClaim(tC.role), tC.middleware, func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tC.wantStatus, rec.Code)
+			if tC.wantStatus != http.StatusOK {
+				assert.Equal(t, response.ContentTypeProblem, rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestOptionalBearerOAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	valid, err := crypto.SignJWT(map[string]any{
+		"exp":     time.Now().Add(time.Minute).Unix(),
+		"jti":     "this-is-jti",
+		"typ":     string(tokenmodel.ACCESS_TOKEN),
+		"role":    string(accountmodel.ROLE_NORMAL),
+		"user_id": "1",
+	})
+	assert.NoError(t, err)
+
+	revoked, err := crypto.SignJWT(map[string]any{
+		"exp":     time.Now().Add(time.Minute).Unix(),
+		"jti":     "this-is-revoked-jti",
+		"typ":     string(tokenmodel.ACCESS_TOKEN),
+		"user_id": "1",
+	})
+	assert.NoError(t, err)
+	revocationStore := revocationrepo.NewRevocationStoreMemoryImpl()
+	revocationStore.Revoke(context.Background(), "this-is-revoked-jti", time.Minute)
+
+	// an id token carries the role too
+	idToken, err := crypto.SignJWT(map[string]any{
+		"exp":     time.Now().Add(time.Minute).Unix(),
+		"jti":     "this-is-jti",
+		"typ":     string(tokenmodel.ID_TOKEN),
+		"role":    string(accountmodel.ROLE_ADMIN),
+		"user_id": "1",
+	})
+	assert.NoError(t, err)
+
+	// a token without exp would never expire
+	withoutExp, err := crypto.SignJWT(map[string]any{
+		"jti":     "this-is-jti",
+		"typ":     string(tokenmodel.ACCESS_TOKEN),
+		"user_id": "1",
+	})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		desc       string
+		header     string
+		wantStatus int
+		wantUserID string
+	}{
+		{desc: "anonymous", wantStatus: http.StatusOK},
+		{desc: "valid token", header: BearerAuth + valid, wantStatus: http.StatusOK, wantUserID: "1"},
+		{desc: "invalid token", header: BearerAuth + "this-is-not-a-token", wantStatus: http.StatusUnauthorized},
+		{desc: "revoked token", header: BearerAuth + revoked, wantStatus: http.StatusUnauthorized},
+		{desc: "token without exp", header: BearerAuth + withoutExp, wantStatus: http.StatusUnauthorized},
+		{desc: "id token", header: BearerAuth + idToken, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := gin.New()
+			userID := ""
+			r.GET("/", OptionalBearerOAuth(revocationStore), func(ctx *gin.Context) {
+				if claim, ok := ctx.Get(AccessClaim.String()); ok {
+					userID = claim.(tokenmodel.AccessClaim).UserID
+				}
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tC.header != "" {
+				req.Header.Set(Authorization.String(), tC.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tC.wantStatus, rec.Code)
+			assert.Equal(t, tC.wantUserID, userID)
+		})
+	}
+}
+
+func TestStreamBearerOAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	valid, err := crypto.SignJWT(map[string]any{
+		"exp":     time.Now().Add(time.Minute).Unix(),
+		"jti":     "this-is-jti",
+		"typ":     string(tokenmodel.ACCESS_TOKEN),
+		"role":    string(accountmodel.ROLE_NORMAL),
+		"user_id": "1",
+	})
+	assert.NoError(t, err)
+	revocationStore := revocationrepo.NewRevocationStoreMemoryImpl()
+
+	testCases := []struct {
+		desc       string
+		header     string
+		query      string
+		wantStatus int
+		wantUserID string
+	}{
+		{desc: "anonymous", wantStatus: http.StatusUnauthorized},
+		{desc: "token in header", header: BearerAuth + valid, wantStatus: http.StatusOK, wantUserID: "1"},
+		{desc: "token in query", query: "?" + AccessTokenQuery + "=" + valid, wantStatus: http.StatusOK, wantUserID: "1"},
+		{desc: "invalid token in query", query: "?" + AccessTokenQuery + "=this-is-not-a-token", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := gin.New()
+			userID := ""
+			r.GET("/", StreamBearerOAuth(revocationStore), func(ctx *gin.Context) {
+				if claim, ok := ctx.Get(AccessClaim.String()); ok {
+					userID = claim.(tokenmodel.AccessClaim).UserID
+				}
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tC.query, nil)
+			if tC.header != "" {
+				req.Header.Set(Authorization.String(), tC.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tC.wantStatus, rec.Code)
+			assert.Equal(t, tC.wantUserID, userID)
+		})
+	}
+}
+
+func TestCheckTokenClaim(t *testing.T) {
+	revocationStore := revocationrepo.NewRevocationStoreMemoryImpl()
+	revocationStore.Revoke(context.Background(), "this-is-revoked-jti", time.Minute)
+	exp := time.Now().Add(time.Minute).Unix()
+
+	testCases := []struct {
+		desc     string
+		claim    tokenmodel.DefaultClaim
+		wantCode response.ErrorCode
+	}{
+		{desc: "live token", claim: tokenmodel.DefaultClaim{JTI: "this-is-jti", Expired: int(exp)}},
+		{desc: "expired token", claim: tokenmodel.DefaultClaim{JTI: "this-is-jti", Expired: int(time.Now().Add(-time.Minute).Unix())}, wantCode: response.CODE_TOKEN_INVALID},
+		{desc: "revoked token", claim: tokenmodel.DefaultClaim{JTI: "this-is-revoked-jti", Expired: int(exp)}, wantCode: response.CODE_TOKEN_REVOKED},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := CheckTokenClaim(context.Background(), revocationStore, tC.claim)
+			if tC.wantCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var domainErr *domainerr.Error
+			if assert.ErrorAs(t, err, &domainErr) {
+				assert.Equal(t, tC.wantCode, domainErr.ErrorCode())
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks a route as deprecated (draft-ietf-httpapi-deprecation-header)
+// and points clients to the route that replaces it.
+func Deprecated(successor string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", "true")
+		ctx.Header("Link", fmt.Sprintf("<%v>; rel=\"successor-version\"", successor))
+		ctx.Next()
+	}
+}
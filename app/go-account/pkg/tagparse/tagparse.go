@@ -0,0 +1,95 @@
+// Package tagparse finds #hashtags and @mentions in captions and comments.
+package tagparse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MAX_HASHTAG_LENGTH = 64
+	MAX_MENTION_LENGTH = 255
+	MAX_TAGS_PER_TEXT  = 30
+	hashtagSign        = '#'
+	mentionSign        = '@'
+)
+
+// Hashtags is the distinct hashtags of text, lowercased and without the #,
+// in the order they first appear. A tag is letters, digits and _ with at
+// least one letter, and only starts after a space or punctuation so
+// "a#b" and "#1" are not tags.
+func Hashtags(text string) []string {
+	return scan(text, hashtagSign, isHashtagRune, func(tag string) (string, bool) {
+		if utf8.RuneCountInString(tag) > MAX_HASHTAG_LENGTH || strings.IndexFunc(tag, unicode.IsLetter) < 0 {
+			return "", false
+		}
+		return strings.ToLower(tag), true
+	})
+}
+
+// Mentions is the distinct usernames mentioned in text, without the @.
+// Emails are not mentions, the @ must not follow a letter or digit.
+func Mentions(text string) []string {
+	return scan(text, mentionSign, isMentionRune, func(username string) (string, bool) {
+		// a sentence may end right after the mention
+		username = strings.TrimRight(username, ".")
+		if username == "" || len(username) > MAX_MENTION_LENGTH {
+			return "", false
+		}
+		return username, true
+	})
+}
+
+// NormalizeHashtag turns user input such as "#Sunset" into the stored name.
+func NormalizeHashtag(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), string(hashtagSign)))
+}
+
+func scan(text string, sign rune, allowed func(r rune) bool, normalize func(string) (string, bool)) []string {
+	var (
+		found []string
+		seen  = map[string]bool{}
+		prev  rune
+	)
+	for i := 0; i < len(text) && len(found) < MAX_TAGS_PER_TEXT; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r != sign || isWordRune(prev) || prev == sign {
+			prev = r
+			i += size
+			continue
+		}
+
+		start := i + size
+		end := start
+		for end < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[end:])
+			if !allowed(next) {
+				break
+			}
+			end += nextSize
+		}
+		if tag, ok := normalize(text[start:end]); ok && !seen[tag] {
+			seen[tag] = true
+			found = append(found, tag)
+		}
+		prev = r
+		if end > start {
+			prev, _ = utf8.DecodeLastRuneInString(text[start:end])
+		}
+		i = end
+	}
+	return found
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func isHashtagRune(r rune) bool {
+	return isWordRune(r)
+}
+
+func isMentionRune(r rune) bool {
+	return isWordRune(r) || r == '.'
+}
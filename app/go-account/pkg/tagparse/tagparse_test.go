@@ -0,0 +1,56 @@
+package tagparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashtags(t *testing.T) {
+	testCases := []struct {
+		desc string
+		text string
+		want []string
+	}{
+		{desc: "none", text: "just a photo", want: nil},
+		{desc: "lowercased and distinct", text: "#Sunset at the beach #sunset #beach_day", want: []string{"sunset", "beach_day"}},
+		{desc: "punctuation ends a tag", text: "golden hour (#goldenhour), #bali!", want: []string{"goldenhour", "bali"}},
+		{desc: "unicode letters", text: "#café #東京", want: []string{"café", "東京"}},
+		{desc: "not in the middle of a word", text: "issue#12 a#b", want: nil},
+		{desc: "digits only is not a tag", text: "#1 #2023", want: nil},
+		{desc: "digits with letters", text: "#2023vibes", want: []string{"2023vibes"}},
+		{desc: "double sign", text: "##double", want: nil},
+		{desc: "too long", text: "#" + strings.Repeat("a", MAX_HASHTAG_LENGTH+1), want: nil},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, Hashtags(tC.text))
+		})
+	}
+}
+
+func TestMentions(t *testing.T) {
+	testCases := []struct {
+		desc string
+		text string
+		want []string
+	}{
+		{desc: "none", text: "no one here", want: nil},
+		{desc: "distinct", text: "with @alice and @bob, thanks @alice", want: []string{"alice", "bob"}},
+		{desc: "dots inside, not at the end", text: "shot by @john.doe.", want: []string{"john.doe"}},
+		{desc: "email is not a mention", text: "mail me at me@example.com", want: nil},
+		{desc: "lone sign", text: "meet @ noon", want: nil},
+		{desc: "case is kept", text: "@Alice", want: []string{"Alice"}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, Mentions(tC.text))
+		})
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	assert.Equal(t, "sunset", NormalizeHashtag(" #Sunset"))
+	assert.Equal(t, "sunset", NormalizeHashtag("sunset"))
+}
@@ -0,0 +1,83 @@
+package upload
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+const (
+	DEFAULT_MAX_SIZE int64 = 10 << 20
+	// room for the other multipart fields and boundaries
+	MULTIPART_OVERHEAD int64 = 1 << 20
+	// http.DetectContentType never looks further
+	sniffLen = 512
+)
+
+// only these are accepted, whatever the client claims in Content-Type.
+// Every one of them has to be decodable by pkg/imaging, a photo it can
+// not read would never get its variants.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Policy is the set of rules an uploaded photo has to pass.
+type Policy struct {
+	MaxSize int64
+}
+
+func NewPolicy(maxSize int64) Policy {
+	if maxSize <= 0 {
+		maxSize = DEFAULT_MAX_SIZE
+	}
+	return Policy{MaxSize: maxSize}
+}
+
+// MaxBodySize is the request body limit of an upload endpoint.
+func (p Policy) MaxBodySize() int64 {
+	return p.MaxSize + MULTIPART_OVERHEAD
+}
+
+func (p Policy) CheckSize(size int64) error {
+	if size <= 0 {
+		return domainerr.Validation("file is empty")
+	}
+	if size > p.MaxSize {
+		return domainerr.New(domainerr.KIND_VALIDATION, response.CODE_PAYLOAD_TOO_LARGE,
+			fmt.Sprintf("file is larger than %v bytes", p.MaxSize))
+	}
+	return nil
+}
+
+// Sniff detects the image type from the magic bytes of r, body still
+// yields the whole content including the bytes read to sniff.
+func Sniff(r io.Reader) (contentType string, body io.Reader, err error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return
+	}
+	err = nil
+	head = head[:n]
+
+	contentType = http.DetectContentType(head)
+	if _, ok := imageExtensions[contentType]; !ok {
+		err = domainerr.New(domainerr.KIND_VALIDATION, response.CODE_UNSUPPORTED_MEDIA,
+			fmt.Sprintf("%v is not a supported image", contentType))
+		return
+	}
+	return contentType, io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+// NewKey is a unique object key, e.g. photo/1/<uuid>.jpg
+func NewKey(prefix string, ownerID uint64, contentType string) string {
+	return fmt.Sprintf("%v/%v/%v%v", prefix, ownerID, uuid.New(), imageExtensions[contentType])
+}
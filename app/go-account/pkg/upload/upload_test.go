@@ -0,0 +1,76 @@
+package upload
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniff(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   []byte
+		want    string
+		wantErr bool
+	}{
+		{desc: "jpeg", input: []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), want: "image/jpeg"},
+		{desc: "png", input: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), want: "image/png"},
+		{desc: "gif", input: []byte("GIF89a\x01\x00\x01\x00"), want: "image/gif"},
+		{desc: "webp", input: []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), want: "image/webp"},
+		{desc: "html named photo.jpg", input: []byte("<html><script>alert(1)</script>"), wantErr: true},
+		{desc: "plain text", input: []byte("just text"), wantErr: true},
+		{desc: "empty", input: []byte{}, wantErr: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// bigger than the sniff length so the rest has to be kept
+			content := append(tC.input, bytes.Repeat([]byte{0}, 1024)...)
+			contentType, body, err := Sniff(bytes.NewReader(content))
+			if tC.wantErr {
+				assert.ErrorIs(t, err, domainerr.ErrValidation)
+				assert.Equal(t, response.CODE_UNSUPPORTED_MEDIA, err.(*domainerr.Error).ErrorCode())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, contentType)
+			read, err := io.ReadAll(body)
+			assert.NoError(t, err)
+			assert.Equal(t, content, read)
+		})
+	}
+}
+
+func TestPolicyCheckSize(t *testing.T) {
+	policy := NewPolicy(100)
+
+	testCases := []struct {
+		desc  string
+		input int64
+		want  response.ErrorCode
+	}{
+		{desc: "empty", input: 0, want: response.CODE_VALIDATION_FAILED},
+		{desc: "at the limit", input: 100},
+		{desc: "above the limit", input: 101, want: response.CODE_PAYLOAD_TOO_LARGE},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := policy.CheckSize(tC.input)
+			if tC.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tC.want, err.(*domainerr.Error).ErrorCode())
+		})
+	}
+
+	assert.Equal(t, DEFAULT_MAX_SIZE, NewPolicy(0).MaxSize)
+}
+
+func TestNewKey(t *testing.T) {
+	assert.Regexp(t, regexp.MustCompile(`^photo/7/[0-9a-f-]{36}\.png$`), NewKey("photo", 7, "image/png"))
+}
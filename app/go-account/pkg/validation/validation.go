@@ -0,0 +1,231 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// custom tags, used next to the built in ones in `binding` struct tags
+const (
+	TAG_AGE      = "age"
+	TAG_PASSWORD = "password"
+	TAG_EMAIL    = "email_addr"
+	TAG_URL      = "web_url"
+)
+
+const (
+	// users must be older than AGE_LIMIT
+	AGE_LIMIT = 8
+	// bcrypt ignores everything after 72 bytes
+	PASSWORD_MIN_LENGTH = 8
+	PASSWORD_MAX_LENGTH = 72
+)
+
+// Age is the age rule, the user must be older than AGE_LIMIT.
+func Age(age int64) bool {
+	return age > AGE_LIMIT
+}
+
+// Password is the password policy, PASSWORD_MIN_LENGTH to PASSWORD_MAX_LENGTH
+// bytes with at least one letter and one digit.
+func Password(password string) bool {
+	if len(password) < PASSWORD_MIN_LENGTH || len(password) > PASSWORD_MAX_LENGTH {
+		return false
+	}
+	var letter, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return letter && digit
+}
+
+// Email accepts a bare address only, "Name <user@mail.com>" is rejected
+// because it is not what gets stored.
+func Email(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return false
+	}
+	return addr.Address == email
+}
+
+// URL accepts absolute http and https urls with a host.
+func URL(raw string) bool {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+var rules = map[string]validator.Func{
+	TAG_AGE:      ageField,
+	TAG_PASSWORD: stringField(Password),
+	TAG_EMAIL:    stringField(Email),
+	TAG_URL:      stringField(URL),
+}
+
+// age is sent as string by older clients, accept both
+func ageField(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.String:
+		age, err := strconv.ParseInt(field.String(), 10, 64)
+		return err == nil && Age(age)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Age(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint() > AGE_LIMIT
+	}
+	return false
+}
+
+func stringField(rule func(string) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		if fl.Field().Kind() != reflect.String {
+			return false
+		}
+		return rule(fl.Field().String())
+	}
+}
+
+// Register adds the custom rules to v and reports fields by their json name.
+func Register(v *validator.Validate) (err error) {
+	v.RegisterTagNameFunc(jsonName)
+	for tag, rule := range rules {
+		if err = v.RegisterValidation(tag, rule); err != nil {
+			return fmt.Errorf("register %v rule: %w", tag, err)
+		}
+	}
+	return
+}
+
+// Setup registers the custom rules on the validator used by gin binding.
+func Setup() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("gin validator is not go-playground/validator")
+	}
+	return Register(v)
+}
+
+// Struct validates obj with the gin validator, see BindError for the result.
+func Struct(obj any) error {
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return BindError(err)
+	}
+	return nil
+}
+
+// BindError turns a binding error into a domain error, failed rules are
+// reported per field, anything else means the body could not be decoded.
+func BindError(err error) error {
+	return bindError(err, response.CODE_INVALID_BODY, "error binding payload")
+}
+
+// BindQueryError is BindError for the query string.
+func BindQueryError(err error) error {
+	return bindError(err, response.CODE_INVALID_PARAM, "error binding query")
+}
+
+func bindError(err error, code response.ErrorCode, message string) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return domainerr.WrapCode(domainerr.KIND_VALIDATION, response.CODE_PAYLOAD_TOO_LARGE,
+			fmt.Sprintf("request body is larger than %v bytes", tooLarge.Limit), err)
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return domainerr.WrapCode(domainerr.KIND_VALIDATION, code, message, err)
+	}
+	return domainerr.ValidationFields(FieldErrors(errs)...)
+}
+
+// FieldErrors keeps the order of the struct fields, every failed field is reported.
+func FieldErrors(errs validator.ValidationErrors) (fields []response.FieldError) {
+	for _, e := range errs {
+		fields = append(fields, fieldError(e))
+	}
+	return
+}
+
+func fieldError(e validator.FieldError) response.FieldError {
+	field := e.Field()
+	fe := response.FieldError{
+		Field: field,
+		Code:  response.CODE_FIELD_INVALID,
+	}
+	switch e.Tag() {
+	case "required":
+		fe.Code = response.CODE_FIELD_REQUIRED
+		fe.Message = field + " cannot be empty"
+	case TAG_AGE:
+		fe.Message = fmt.Sprintf("%v must be a number greater than %v", field, AGE_LIMIT)
+	case TAG_PASSWORD:
+		fe.Message = fmt.Sprintf("%v must be %v to %v characters with at least one letter and one digit",
+			field, PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH)
+	case TAG_EMAIL:
+		fe.Message = field + " must be a valid email address"
+	case TAG_URL:
+		fe.Message = field + " must be an http or https url"
+	case "gtfield":
+		fe.Message = fmt.Sprintf("%v must be after %v", field, snakeCase(e.Param()))
+	case "min":
+		fe.Message = fmt.Sprintf("%v must be at least %v", field, e.Param())
+	case "max":
+		fe.Message = fmt.Sprintf("%v must be at most %v", field, e.Param())
+	case "oneof":
+		fe.Message = fmt.Sprintf("%v must be one of %v", field, e.Param())
+	default:
+		fe.Message = fmt.Sprintf("%v failed on %v", field, e.Tag())
+	}
+	return fe
+}
+
+// query structs only have a form tag
+func jsonName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "" {
+		name = strings.SplitN(field.Tag.Get("form"), ",", 2)[0]
+	}
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// snakeCase turns the struct field name of a cross field rule param
+// into the name clients know, CreatedFrom becomes created_from
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
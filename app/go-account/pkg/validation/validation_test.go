@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAge(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input int64
+		want  bool
+	}{
+		{desc: "negative", input: -1, want: false},
+		{desc: "at the limit", input: 8, want: false},
+		{desc: "above the limit", input: 9, want: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, Age(tC.input))
+		})
+	}
+}
+
+func TestPassword(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+		want  bool
+	}{
+		{desc: "too short", input: "abc123", want: false},
+		{desc: "too long", input: strings.Repeat("a1", 37), want: false},
+		{desc: "letters only", input: "password", want: false},
+		{desc: "digits only", input: "12345678", want: false},
+		{desc: "letters and digits", input: "secret123", want: true},
+		{desc: "exactly max length", input: strings.Repeat("a1", 36), want: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, Password(tC.input))
+		})
+	}
+}
+
+func TestEmail(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+		want  bool
+	}{
+		{desc: "empty", input: "", want: false},
+		{desc: "missing domain", input: "user@", want: false},
+		{desc: "with display name", input: "User <user@mygram.id>", want: false},
+		{desc: "bare address", input: "user@mygram.id", want: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, Email(tC.input))
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+		want  bool
+	}{
+		{desc: "relative", input: "/photo/1.jpg", want: false},
+		{desc: "not http", input: "ftp://mygram.id/1.jpg", want: false},
+		{desc: "missing host", input: "https:///1.jpg", want: false},
+		{desc: "plain text", input: "my photo", want: false},
+		{desc: "https", input: "https://cdn.mygram.id/1.jpg", want: true},
+		{desc: "http with query", input: "http://mygram.id/1.jpg?size=small", want: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, URL(tC.input))
+		})
+	}
+}
+
+func TestStruct(t *testing.T) {
+	assert.NoError(t, Setup())
+
+	testCases := []struct {
+		desc  string
+		input any
+		want  []response.FieldError
+	}{
+		{
+			desc: "every failed field is reported",
+			input: accountmodel.RegisterUser{
+				Email:    "not an email",
+				Password: "short",
+				Age:      "8",
+			},
+			want: []response.FieldError{
+				{Field: "username", Code: response.CODE_FIELD_REQUIRED, Message: "username cannot be empty"},
+				{Field: "email", Code: response.CODE_FIELD_INVALID, Message: "email must be a valid email address"},
+				{Field: "password", Code: response.CODE_FIELD_INVALID, Message: "password must be 8 to 72 characters with at least one letter and one digit"},
+				{Field: "age", Code: response.CODE_FIELD_INVALID, Message: "age must be a number greater than 8"},
+			},
+		},
+		{
+			desc:  "social media url",
+			input: accountmodel.SocialMediaRequest{Name: "instagram", SocialMediaUrl: "instagram.com/allam"},
+			want: []response.FieldError{
+				{Field: "social_media_url", Code: response.CODE_FIELD_INVALID, Message: "social_media_url must be an http or https url"},
+			},
+		},
+		{
+			desc: "happy case",
+			input: accountmodel.RegisterUser{
+				Username: "allam",
+				Email:    "allam@mygram.id",
+				Password: "secret123",
+				Age:      "20",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := Struct(tC.input)
+			if tC.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var derr *domainerr.Error
+			if assert.True(t, errors.As(err, &derr)) {
+				assert.Equal(t, domainerr.KIND_VALIDATION, derr.Kind)
+				assert.Equal(t, tC.want, derr.Fields)
+			}
+		})
+	}
+}
+
+func TestBindError(t *testing.T) {
+	err := BindError(errors.New("unexpected EOF"))
+	assert.ErrorIs(t, err, domainerr.ErrValidation)
+	assert.Equal(t, response.CODE_INVALID_BODY, err.(*domainerr.Error).ErrorCode())
+}
@@ -0,0 +1,200 @@
+// Package worker runs background jobs on a fixed number of goroutines,
+// failed jobs are retried with an exponential backoff.
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	c "github.com/mygram/go-common/pkg/context"
+	"github.com/mygram/go-common/pkg/logger"
+)
+
+const (
+	DEFAULT_WORKERS      = 2
+	DEFAULT_QUEUE_SIZE   = 100
+	DEFAULT_MAX_ATTEMPTS = 3
+	DEFAULT_BACKOFF      = time.Second
+	MAX_BACKOFF          = time.Minute
+)
+
+var (
+	ErrQueueFull = errors.New("worker queue is full")
+	ErrStopped   = errors.New("worker pool is stopped")
+)
+
+// permanentError is not worth retrying, see Permanent.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err so the job is given up without retrying,
+// e.g. a file that can not be decoded will never be.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+func IsPermanent(err error) bool {
+	return errors.As(err, &permanentError{})
+}
+
+type Config struct {
+	Workers     int
+	QueueSize   int
+	MaxAttempts int
+	// wait before the 2nd attempt, doubled after every attempt
+	Backoff time.Duration
+}
+
+func (conf Config) withDefaults() Config {
+	if conf.Workers <= 0 {
+		conf.Workers = DEFAULT_WORKERS
+	}
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = DEFAULT_QUEUE_SIZE
+	}
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = DEFAULT_MAX_ATTEMPTS
+	}
+	if conf.Backoff <= 0 {
+		conf.Backoff = DEFAULT_BACKOFF
+	}
+	return conf
+}
+
+// Handler processes one job, attempt starts at 1.
+type Handler[T any] func(ctx context.Context, job T, attempt int) error
+
+// GiveUp is called once a job failed its last attempt.
+type GiveUp[T any] func(ctx context.Context, job T, err error)
+
+// Pool is bounded twice: by its workers and by its queue, Enqueue
+// never blocks the caller and fails when the queue is full.
+type Pool[T any] struct {
+	name   string
+	conf   Config
+	handle Handler[T]
+	giveUp GiveUp[T]
+
+	jobs    chan T
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	stopped bool
+	sleep   func(ctx context.Context, d time.Duration) bool
+}
+
+func NewPool[T any](name string, conf Config, handle Handler[T], giveUp GiveUp[T]) *Pool[T] {
+	conf = conf.withDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Pool[T]{
+		name:   name,
+		conf:   conf,
+		handle: handle,
+		giveUp: giveUp,
+		jobs:   make(chan T, conf.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+		sleep:  sleep,
+	}
+}
+
+func (p *Pool[T]) Start() {
+	for i := 0; i < p.conf.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+func (p *Pool[T]) Enqueue(job T) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		return ErrStopped
+	}
+	select {
+	case p.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Stop lets the running jobs finish their current attempt, queued
+// jobs are dropped and have to be recovered on the next start.
+func (p *Pool[T]) Stop() {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	p.stopped = true
+	close(p.jobs)
+	p.mu.Unlock()
+
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *Pool[T]) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		if p.ctx.Err() != nil {
+			continue // drain
+		}
+		p.run(job)
+	}
+}
+
+func (p *Pool[T]) run(job T) {
+	ctx, _ := c.GetCorrelationID(p.ctx)
+	backoff := p.conf.Backoff
+	for attempt := 1; ; attempt++ {
+		err := p.handle(ctx, job, attempt)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			// stopping, the job is recovered on the next start
+			return
+		}
+		if IsPermanent(err) || attempt >= p.conf.MaxAttempts {
+			logger.Error(ctx, "job given up",
+				"pool", p.name,
+				"attempt", attempt,
+				"error", err)
+			if p.giveUp != nil {
+				p.giveUp(ctx, job, err)
+			}
+			return
+		}
+		logger.Info(ctx, "job failed, retrying",
+			"pool", p.name,
+			"attempt", attempt,
+			"backoff", backoff.String(),
+			"error", err)
+		if !p.sleep(ctx, backoff) {
+			return
+		}
+		if backoff *= 2; backoff > MAX_BACKOFF {
+			backoff = MAX_BACKOFF
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func noSleep(ctx context.Context, d time.Duration) bool { return ctx.Err() == nil }
+
+func TestPool(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		failures     int
+		err          error
+		wantAttempts int
+		wantGiveUp   bool
+	}{
+		{desc: "first attempt", failures: 0, wantAttempts: 1},
+		{desc: "retried until it works", failures: 2, err: errors.New("some error"), wantAttempts: 3},
+		{desc: "given up after max attempts", failures: 5, err: errors.New("some error"), wantAttempts: 3, wantGiveUp: true},
+		{desc: "permanent error is not retried", failures: 5, err: Permanent(errors.New("broken")), wantAttempts: 1, wantGiveUp: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				attempts int
+				gaveUp   error
+				done     = make(chan struct{})
+			)
+			pool := NewPool("test", Config{Workers: 1, MaxAttempts: 3},
+				func(ctx context.Context, job string, attempt int) error {
+					mu.Lock()
+					defer mu.Unlock()
+					attempts = attempt
+					if attempt <= tC.failures {
+						return tC.err
+					}
+					close(done)
+					return nil
+				},
+				func(ctx context.Context, job string, err error) {
+					mu.Lock()
+					defer mu.Unlock()
+					gaveUp = err
+					close(done)
+				})
+			pool.sleep = noSleep
+			pool.Start()
+			assert.NoError(t, pool.Enqueue("job"))
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("job never finished")
+			}
+			pool.Stop()
+
+			assert.Equal(t, tC.wantAttempts, attempts)
+			if tC.wantGiveUp {
+				assert.ErrorIs(t, gaveUp, tC.err)
+			} else {
+				assert.NoError(t, gaveUp)
+			}
+		})
+	}
+}
+
+func TestPoolQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	pool := NewPool("test", Config{Workers: 1, QueueSize: 1},
+		func(ctx context.Context, job int, attempt int) error {
+			<-release
+			return nil
+		}, nil)
+	pool.Start()
+
+	// one running, one queued
+	assert.NoError(t, pool.Enqueue(1))
+	assert.Eventually(t, func() bool { return len(pool.jobs) == 0 }, time.Second, time.Millisecond)
+	assert.NoError(t, pool.Enqueue(2))
+	assert.ErrorIs(t, pool.Enqueue(3), ErrQueueFull)
+
+	close(release)
+	pool.Stop()
+	assert.ErrorIs(t, pool.Enqueue(4), ErrStopped)
+}
+
+func TestPoolStopInterruptsBackoff(t *testing.T) {
+	started := make(chan struct{})
+	pool := NewPool("test", Config{Workers: 1, Backoff: time.Hour},
+		func(ctx context.Context, job int, attempt int) error {
+			if attempt == 1 {
+				close(started)
+			}
+			return errors.New("some error")
+		}, func(ctx context.Context, job int, err error) {
+			t.Error("stopping is not giving up")
+		})
+	pool.Start()
+	assert.NoError(t, pool.Enqueue(1))
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		pool.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("stop waited for the backoff")
+	}
+}
@@ -0,0 +1,223 @@
+package servers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mygram/go-account/db"
+	accountmodel "github.com/mygram/go-account/modules/models/account"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-common/config"
+	c "github.com/mygram/go-common/pkg/context"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/migrate"
+)
+
+const (
+	CMD_BOOTSTRAP_ADMIN = "bootstrap-admin"
+	CMD_RECONCILE_LIKES = "reconcile-likes"
+	CMD_MIGRATE         = "migrate"
+)
+
+// ADMIN_PASSWORD_ENV holds the password of bootstrap-admin, a flag would
+// show it in ps and the shell history.
+const ADMIN_PASSWORD_ENV = "ADMIN_PASSWORD"
+
+// RunBootstrapAdmin creates the first admin user, the password is read
+// from ADMIN_PASSWORD or else the first line of stdin, usage:
+//
+//	go-account -config=local bootstrap-admin -username=admin -email=admin@mygram.id -age=20 < password.txt
+func RunBootstrapAdmin(args []string) (err error) {
+	ctx, _ := c.GetCorrelationID(context.Background())
+
+	fs := flag.NewFlagSet(CMD_BOOTSTRAP_ADMIN, flag.ContinueOnError)
+	username := fs.String("username", "", "admin username")
+	email := fs.String("email", "", "admin email")
+	age := fs.String("age", "", "admin age")
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+	password, err := readPassword(os.Stdin)
+	if err != nil {
+		return
+	}
+	if *username == "" || *email == "" || password == "" || *age == "" {
+		return errors.New("username, email, password and age are required")
+	}
+
+	svcs := initServices(ctx)
+	register := accountmodel.RegisterUser{
+		Username: *username,
+		Email:    *email,
+		Password: password,
+		Age:      *age,
+	}
+	if err = validation.Struct(register); err != nil {
+		var derr *domainerr.Error
+		if errors.As(err, &derr) {
+			for _, field := range derr.Fields {
+				fmt.Println(field.Message)
+			}
+		}
+		return
+	}
+	created, err := svcs.accountSvc.BootstrapAdmin(ctx, register)
+	if err != nil {
+		logger.Error(ctx, "error bootstrap admin",
+			"error", err)
+		return
+	}
+	fmt.Printf("admin %v created with id %v\n", created.Username, created.ID)
+	return
+}
+
+// readPassword takes ADMIN_PASSWORD_ENV or the first line of stdin, it
+// only prompts when stdin is a terminal.
+func readPassword(stdin *os.File) (password string, err error) {
+	if password = os.Getenv(ADMIN_PASSWORD_ENV); password != "" {
+		return
+	}
+	if info, errStat := stdin.Stat(); errStat == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stderr, "admin password: ")
+	}
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// RunReconcileLikes recounts the like_count of photos and comments from
+// the likes table, safe to run while serving traffic, usage:
+//
+//	go-account -config=local reconcile-likes
+func RunReconcileLikes(args []string) (err error) {
+	ctx, _ := c.GetCorrelationID(context.Background())
+
+	fs := flag.NewFlagSet(CMD_RECONCILE_LIKES, flag.ContinueOnError)
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	svcs := initServices(ctx)
+	fixed, err := svcs.likeSvc.ReconcileLikeCounts(ctx)
+	if err != nil {
+		logger.Error(ctx, "error reconcile likes",
+			"error", err)
+		return
+	}
+	fmt.Printf("%v like counts fixed\n", fixed)
+	return
+}
+
+// RunMigrate applies or reverts the migrations in db/migrations, usage:
+//
+//	go-account -config=local migrate up|down|status|redo
+//	go-account -config=local migrate baseline <version>
+//
+// down and redo only touch the latest applied migration. baseline records
+// the migrations up to version as applied without running them, a
+// database created before the migrations needs `migrate baseline 1` once.
+func RunMigrate(args []string) (err error) {
+	ctx, _ := c.GetCorrelationID(context.Background())
+
+	fs := flag.NewFlagSet(CMD_MIGRATE, flag.ContinueOnError)
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	return withMigrator(func(m *migrate.Migrator) (err error) {
+		switch fs.Arg(0) {
+		case "up":
+			done, err := m.Up(ctx)
+			for _, migration := range done {
+				fmt.Printf("applied %v\n", migration)
+			}
+			if err == nil && len(done) == 0 {
+				fmt.Println("schema is up to date")
+			}
+			return err
+		case "down", "redo":
+			var migration *migrate.Migration
+			if fs.Arg(0) == "down" {
+				migration, err = m.Down(ctx)
+			} else {
+				migration, err = m.Redo(ctx)
+			}
+			if err != nil {
+				return
+			}
+			if migration == nil {
+				fmt.Println("no migration is applied")
+				return
+			}
+			fmt.Printf("%v %v\n", fs.Arg(0), migration)
+		case "status":
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				return err
+			}
+			for _, status := range statuses {
+				state := "pending"
+				if status.AppliedAt != nil {
+					state = "applied " + status.AppliedAt.Format(time.RFC3339)
+				}
+				if status.Modified {
+					state += ", modified since"
+				}
+				fmt.Printf("%-40v %v\n", status.Migration, state)
+			}
+		case "baseline":
+			version, errVersion := strconv.ParseUint(fs.Arg(1), 10, 64)
+			if errVersion != nil {
+				return errors.New("usage: migrate baseline <version>")
+			}
+			marked, err := m.Baseline(ctx, version)
+			for _, migration := range marked {
+				fmt.Printf("marked %v as applied\n", migration)
+			}
+			return err
+		default:
+			return errors.New("usage: migrate up|down|status|redo|baseline <version>")
+		}
+		return
+	})
+}
+
+// migrateUp applies the pending migrations on start, see dataSource.migrate.
+func migrateUp(ctx context.Context) (err error) {
+	return withMigrator(func(m *migrate.Migrator) (err error) {
+		done, err := m.Up(ctx)
+		for _, migration := range done {
+			logger.Info(ctx, "migration applied",
+				"migration", migration.String())
+		}
+		return
+	})
+}
+
+// withMigrator gives fn its own connection, closed once fn returns.
+func withMigrator(fn func(m *migrate.Migrator) error) (err error) {
+	files, err := fs.Sub(db.Migrations, "migrations")
+	if err != nil {
+		return
+	}
+	conn := config.NewPostgresConn()
+	defer conn.Close()
+
+	m, err := migrate.NewMigrator(conn, files)
+	if err != nil {
+		return
+	}
+	return fn(m)
+}
@@ -1,41 +1,102 @@
 package servers
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mygram/go-account/modules/router/v1/account"
+	"github.com/mygram/go-account/modules/router/v1/feed"
+	"github.com/mygram/go-account/modules/router/v1/follow"
+	"github.com/mygram/go-account/modules/router/v1/like"
+	"github.com/mygram/go-account/modules/router/v1/notification"
+	"github.com/mygram/go-account/modules/router/v1/realtime"
+	"github.com/mygram/go-account/modules/router/v1/search"
+	"github.com/mygram/go-account/modules/router/v1/tag"
+	"github.com/mygram/go-account/modules/router/v1/webhook"
+	"github.com/mygram/go-account/modules/router/wellknown"
+	"github.com/mygram/go-account/pkg/middleware"
 	"github.com/mygram/go-common/config"
+	c "github.com/mygram/go-common/pkg/context"
+	"github.com/mygram/go-common/pkg/logger"
 	commonmidware "github.com/mygram/go-common/pkg/middleware"
 )
 
+// LOCAL_MEDIA_ROUTE serves the local blob store, storage.local.baseURL must point at it
+const LOCAL_MEDIA_ROUTE = "/media"
+
 func NewHttpServer() (srv *http.Server) {
 	hdls := initDI()
 
 	// init server
-	ginServer := gin.Default()
+	// gin.Default would add a second gin.Logger that logs the stream token
+	ginServer := gin.New()
+	// handlers pass *gin.Context on as ctx, the queries they start
+	// are then cancelled with the request
+	ginServer.ContextWithFallback = true
 	if config.Load.Server.Env == config.ENV_PRODUCTION {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	// init middleware
 	ginServer.Use(
-		gin.Logger(),                             // untuk log request yang masuk
+		commonmidware.AccessLog(middleware.AccessTokenQuery), // untuk log request yang masuk
 		gin.Recovery(),                           // untuk auto restart kalau panic
 		commonmidware.CorrelationIDInterceptor(), // tracing purpose
+		commonmidware.ErrorHandler(),             // domain error to http response
 	)
 
 	// register router
-	v1 := ginServer.Group("/api/v1")
-	account.NewAccountRouter(v1, hdls.accountHdl)
+	wellknown.NewWellKnownRouter(ginServer, hdls.accountHdl)
+	requestTimeout := time.Duration(config.Load.Server.Http.RequestTimeout) * time.Second
+	v1 := ginServer.Group("/api/v1", commonmidware.Deadline(requestTimeout))
+	account.NewAccountRouter(v1, hdls.accountHdl, hdls.revocationStore, hdls.uploadPolicy)
+	like.NewLikeRouter(v1, hdls.likeHdl, hdls.revocationStore)
+	follow.NewFollowRouter(v1, hdls.followHdl, hdls.revocationStore)
+	feed.NewFeedRouter(v1, hdls.feedHdl, hdls.revocationStore)
+	tag.NewTagRouter(v1, hdls.tagHdl, hdls.revocationStore)
+	search.NewSearchRouter(v1, hdls.searchHdl, hdls.revocationStore)
+	notification.NewNotificationRouter(v1, hdls.notificationHdl, hdls.revocationStore)
+	webhook.NewWebhookRouter(v1, hdls.webhookHdl, hdls.revocationStore)
+	// streams stay open for as long as the client listens
+	realtime.NewRealtimeRouter(ginServer.Group("/api/v1"), hdls.realtimeHdl, hdls.revocationStore)
+
+	// uploaded files, only when they are kept on this instance
+	if config.Load.Storage.Driver == config.STORAGE_LOCAL || config.Load.Storage.Driver == "" {
+		ginServer.Static(LOCAL_MEDIA_ROUTE, localMediaDir())
+	}
 
 	srv = &http.Server{
 		Addr:    fmt.Sprintf(":%v", config.Load.Server.Http.Port),
 		Handler: ginServer,
 	}
 
+	// photo processing lives as long as the server
+	ctx, _ := c.GetCorrelationID(context.Background())
+	if err := hdls.photoProcessingSvc.Start(ctx); err != nil {
+		logger.Error(ctx, "unprocessed photos were not recovered", "error", err)
+	}
+	srv.RegisterOnShutdown(hdls.photoProcessingSvc.Stop)
+	if err := hdls.feedSvc.Start(ctx); err != nil {
+		logger.Error(ctx, "feed fan-out did not start", "error", err)
+	}
+	srv.RegisterOnShutdown(hdls.feedSvc.Stop)
+	// queued deliveries are sent by whichever instance claims them first
+	if err := hdls.webhookSvc.Start(ctx); err != nil {
+		logger.Error(ctx, "webhook dispatcher did not start", "error", err)
+	}
+	srv.RegisterOnShutdown(hdls.webhookSvc.Stop)
+	// events are relayed by one instance at a time, the others stand by
+	if err := hdls.outboxSvc.Start(ctx); err != nil {
+		logger.Error(ctx, "outbox relay did not start", "error", err)
+	}
+	srv.RegisterOnShutdown(hdls.outboxSvc.Stop)
+	// open streams would hold up the shutdown until it times out
+	srv.RegisterOnShutdown(hdls.realtimeHub.Close)
+
 	go func() {
 		// service connections
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -2,36 +2,292 @@ package servers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/mygram/go-common/config"
 
 	accounthdl "github.com/mygram/go-account/modules/handler/account"
+	feedhdl "github.com/mygram/go-account/modules/handler/feed"
+	followhdl "github.com/mygram/go-account/modules/handler/follow"
+	likehdl "github.com/mygram/go-account/modules/handler/like"
+	notificationhdl "github.com/mygram/go-account/modules/handler/notification"
+	realtimehdl "github.com/mygram/go-account/modules/handler/realtime"
+	searchhdl "github.com/mygram/go-account/modules/handler/search"
+	taghdl "github.com/mygram/go-account/modules/handler/tag"
+	webhookhdl "github.com/mygram/go-account/modules/handler/webhook"
 	accountrepo "github.com/mygram/go-account/modules/repository/account"
 	activityrepo "github.com/mygram/go-account/modules/repository/accountactivity"
+	blobrepo "github.com/mygram/go-account/modules/repository/blob"
+	followrepo "github.com/mygram/go-account/modules/repository/follow"
+	likerepo "github.com/mygram/go-account/modules/repository/like"
+	notificationrepo "github.com/mygram/go-account/modules/repository/notification"
+	outboxrepo "github.com/mygram/go-account/modules/repository/outbox"
+	publisherrepo "github.com/mygram/go-account/modules/repository/publisher"
+	realtimerepo "github.com/mygram/go-account/modules/repository/realtime"
+	revocationrepo "github.com/mygram/go-account/modules/repository/revocation"
+	roleauditrepo "github.com/mygram/go-account/modules/repository/roleaudit"
+	searchrepo "github.com/mygram/go-account/modules/repository/search"
+	tagrepo "github.com/mygram/go-account/modules/repository/tag"
+	webhookrepo "github.com/mygram/go-account/modules/repository/webhook"
 	accountsvc "github.com/mygram/go-account/modules/service/account"
+	feedsvc "github.com/mygram/go-account/modules/service/feed"
+	followsvc "github.com/mygram/go-account/modules/service/follow"
+	likesvc "github.com/mygram/go-account/modules/service/like"
+	notificationsvc "github.com/mygram/go-account/modules/service/notification"
+	outboxsvc "github.com/mygram/go-account/modules/service/outbox"
+	photoprocessingsvc "github.com/mygram/go-account/modules/service/photoprocessing"
+	realtimesvc "github.com/mygram/go-account/modules/service/realtime"
+	searchsvc "github.com/mygram/go-account/modules/service/search"
+	tagsvc "github.com/mygram/go-account/modules/service/tag"
+	webhooksvc "github.com/mygram/go-account/modules/service/webhook"
+	"github.com/mygram/go-account/pkg/crypto"
+	"github.com/mygram/go-account/pkg/upload"
+	"github.com/mygram/go-account/pkg/validation"
+	"github.com/mygram/go-account/pkg/worker"
 	c "github.com/mygram/go-common/pkg/context"
 	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/unitofwork"
 )
 
 type handlers struct {
-	accountHdl accounthdl.IAccountHandler
+	accountHdl         accounthdl.IAccountHandler
+	likeHdl            likehdl.ILikeHandler
+	followHdl          followhdl.IFollowHandler
+	feedHdl            feedhdl.IFeedHandler
+	tagHdl             taghdl.ITagHandler
+	searchHdl          searchhdl.ISearchHandler
+	notificationHdl    notificationhdl.INotificationHandler
+	realtimeHdl        realtimehdl.IRealtimeHandler
+	webhookHdl         webhookhdl.IWebhookHandler
+	realtimeHub        realtimerepo.IHub
+	webhookSvc         webhooksvc.IWebhookService
+	outboxSvc          outboxsvc.IOutboxService
+	revocationStore    revocationrepo.IRevocationStore
+	uploadPolicy       upload.Policy
+	photoProcessingSvc photoprocessingsvc.IPhotoProcessingService
+	feedSvc            feedsvc.IFeedService
+}
+
+type services struct {
+	accountSvc         accountsvc.IAccountService
+	likeSvc            likesvc.ILikeService
+	followSvc          followsvc.IFollowService
+	feedSvc            feedsvc.IFeedService
+	tagSvc             tagsvc.ITagService
+	searchSvc          searchsvc.ISearchService
+	notificationSvc    notificationsvc.INotificationService
+	realtimeSvc        realtimesvc.IRealtimeService
+	realtimeHub        realtimerepo.IHub
+	webhookSvc         webhooksvc.IWebhookService
+	outboxSvc          outboxsvc.IOutboxService
+	revocationStore    revocationrepo.IRevocationStore
+	uploadPolicy       upload.Policy
+	photoProcessingSvc photoprocessingsvc.IPhotoProcessingService
 }
 
 func initDI() handlers {
 	ctx, _ := c.GetCorrelationID(context.Background())
+	svcs := initServices(ctx)
+
+	logger.Info(ctx, "setup handler")
+	accountHdl := accounthdl.NewAccountHandlerImpl(svcs.accountSvc, svcs.likeSvc)
+	likeHdl := likehdl.NewLikeHandlerImpl(svcs.likeSvc)
+	followHdl := followhdl.NewFollowHandlerImpl(svcs.followSvc)
+	feedHdl := feedhdl.NewFeedHandlerImpl(svcs.feedSvc, svcs.likeSvc)
+	tagHdl := taghdl.NewTagHandlerImpl(svcs.tagSvc, svcs.likeSvc)
+	searchHdl := searchhdl.NewSearchHandlerImpl(svcs.searchSvc, svcs.likeSvc)
+	notificationHdl := notificationhdl.NewNotificationHandlerImpl(svcs.notificationSvc)
+	realtimeHdl := realtimehdl.NewRealtimeHandlerImpl(svcs.realtimeSvc, svcs.revocationStore, realtimehdl.Config{
+		Heartbeat:    time.Duration(config.Load.Realtime.Heartbeat) * time.Second,
+		WriteTimeout: time.Duration(config.Load.Realtime.WriteTimeout) * time.Second,
+	})
+	webhookHdl := webhookhdl.NewWebhookHandlerImpl(svcs.webhookSvc)
+
+	return handlers{
+		accountHdl:         accountHdl,
+		likeHdl:            likeHdl,
+		followHdl:          followHdl,
+		feedHdl:            feedHdl,
+		tagHdl:             tagHdl,
+		searchHdl:          searchHdl,
+		notificationHdl:    notificationHdl,
+		realtimeHdl:        realtimeHdl,
+		webhookHdl:         webhookHdl,
+		realtimeHub:        svcs.realtimeHub,
+		webhookSvc:         svcs.webhookSvc,
+		outboxSvc:          svcs.outboxSvc,
+		revocationStore:    svcs.revocationStore,
+		uploadPolicy:       svcs.uploadPolicy,
+		photoProcessingSvc: svcs.photoProcessingSvc,
+		feedSvc:            svcs.feedSvc,
+	}
+}
+
+func initServices(ctx context.Context) services {
+	logger.Info(ctx, "setup jwt keys")
+	setupJWTKeys()
+
+	logger.Info(ctx, "setup validation")
+	if err := validation.Setup(); err != nil {
+		panic(err)
+	}
+
+	// instances starting together take turns, see migrate.LOCK_KEY
+	if config.Load.DataSource.Migrate {
+		logger.Info(ctx, "apply migrations")
+		if err := migrateUp(ctx); err != nil {
+			panic(err)
+		}
+	}
 
 	logger.Info(ctx, "setup repository")
 	pgConn := config.NewPostgresGormConn()
 	accountRepo := accountrepo.NewAccountRepoGormImpl(pgConn)
 	activityRepo := activityrepo.NewActivityRepoGormImpl(pgConn)
+	roleAuditRepo := roleauditrepo.NewRoleAuditRepoGormImpl(pgConn)
+	likeRepo := likerepo.NewLikeRepoGormImpl(pgConn)
+	followRepo := followrepo.NewFollowRepoGormImpl(pgConn)
+	tagRepo := tagrepo.NewTagRepoGormImpl(pgConn)
+	searchBackend := searchrepo.NewSearchBackendPostgresImpl(pgConn)
+	notificationRepo := notificationrepo.NewNotificationRepoGormImpl(pgConn)
+	webhookRepo := webhookrepo.NewWebhookRepoGormImpl(pgConn)
+	outboxRepo := outboxrepo.NewOutboxRepoGormImpl(pgConn)
+	uow := unitofwork.NewUnitOfWork(pgConn)
+
+	// revoked token jti live in redis when it is enabled,
+	// otherwise they only survive as long as this instance.
+	// realtime events only reach the streams of this instance without it
+	revocationStore := revocationrepo.NewRevocationStoreMemoryImpl()
+	realtimeHub := realtimerepo.NewHubMemoryImpl(config.Load.Realtime.BufferSize)
+	if config.Load.DataSource.Redis.Enabled {
+		redisConn := config.NewRedisConn()
+		revocationStore = revocationrepo.NewRevocationStoreRedisImpl(redisConn)
+		realtimeHub = realtimerepo.NewHubRedisImpl(redisConn, config.Load.Realtime.BufferSize)
+	}
+
+	photoStore := newBlobStore()
+	uploadPolicy := upload.NewPolicy(config.Load.Storage.MaxUploadSize)
 
 	logger.Info(ctx, "setup service")
-	accountSvc := accountsvc.NewAccountServiceImpl(accountRepo, activityRepo)
+	processing := config.Load.Storage.Processing
+	photoProcessingSvc := photoprocessingsvc.NewPhotoProcessingServiceImpl(accountRepo, photoStore, worker.Config{
+		Workers:     processing.Workers,
+		QueueSize:   processing.QueueSize,
+		MaxAttempts: processing.MaxAttempts,
+		Backoff:     time.Duration(processing.RetryBackoff) * time.Second,
+	})
+	fanOut := config.Load.Feed.FanOut
+	feedSvc := feedsvc.NewFeedServiceImpl(followRepo, feedsvc.Config{
+		FanOutMaxFollowers: config.Load.Feed.FanOutMaxFollowers,
+		Worker: worker.Config{
+			Workers:     fanOut.Workers,
+			QueueSize:   fanOut.QueueSize,
+			MaxAttempts: fanOut.MaxAttempts,
+			Backoff:     time.Duration(fanOut.RetryBackoff) * time.Second,
+		},
+	})
+	realtimeSvc := realtimesvc.NewRealtimeServiceImpl(realtimeHub)
+	notificationSvc := notificationsvc.NewNotificationServiceImpl(notificationRepo, realtimeSvc)
+	tagSvc := tagsvc.NewTagServiceImpl(tagRepo, notificationSvc)
+	hooks := config.Load.Webhook
+	webhookSvc := webhooksvc.NewWebhookServiceImpl(webhookRepo, webhooksvc.Config{
+		Workers:              hooks.Workers,
+		MaxAttempts:          hooks.MaxAttempts,
+		RetryBackoff:         time.Duration(hooks.RetryBackoff) * time.Second,
+		Timeout:              time.Duration(hooks.Timeout) * time.Second,
+		PollInterval:         time.Duration(hooks.PollInterval) * time.Second,
+		AllowPrivateNetworks: hooks.AllowPrivateNetworks,
+	})
+	outbox := config.Load.Outbox
+	outboxSvc := outboxsvc.NewOutboxServiceImpl(uow, outboxRepo, newPublisher(), outboxsvc.Config{
+		BatchSize:    outbox.BatchSize,
+		PollInterval: time.Duration(outbox.PollInterval) * time.Second,
+	})
+	accountSvc := accountsvc.NewAccountServiceImpl(accountRepo, activityRepo, revocationStore, roleAuditRepo, uow, outboxRepo, photoStore, uploadPolicy, photoProcessingSvc, feedSvc, tagSvc, notificationSvc, realtimeSvc, webhookSvc, config.Load.Comment)
+	likeSvc := likesvc.NewLikeServiceImpl(likeRepo, notificationSvc, realtimeSvc)
+	followSvc := followsvc.NewFollowServiceImpl(followRepo, notificationSvc)
+	searchSvc := searchsvc.NewSearchServiceImpl(searchBackend, accountRepo)
 
-	logger.Info(ctx, "setup handler")
-	accountHdl := accounthdl.NewAccountHandlerImpl(accountSvc)
+	return services{
+		accountSvc:         accountSvc,
+		likeSvc:            likeSvc,
+		followSvc:          followSvc,
+		feedSvc:            feedSvc,
+		tagSvc:             tagSvc,
+		searchSvc:          searchSvc,
+		notificationSvc:    notificationSvc,
+		realtimeSvc:        realtimeSvc,
+		realtimeHub:        realtimeHub,
+		webhookSvc:         webhookSvc,
+		outboxSvc:          outboxSvc,
+		revocationStore:    revocationStore,
+		uploadPolicy:       uploadPolicy,
+		photoProcessingSvc: photoProcessingSvc,
+	}
+}
 
-	return handlers{
-		accountHdl: accountHdl,
+func newBlobStore() blobrepo.IBlobStore {
+	conf := config.Load.Storage
+	switch conf.Driver {
+	case config.STORAGE_S3:
+		return blobrepo.NewBlobStoreS3Impl(&http.Client{Timeout: time.Minute}, blobrepo.S3Config{
+			Endpoint:  conf.S3.Endpoint,
+			Region:    conf.S3.Region,
+			Bucket:    conf.S3.Bucket,
+			AccessKey: conf.S3.AccessKey,
+			SecretKey: conf.S3.SecretKey,
+			PublicURL: conf.S3.PublicURL,
+		})
+	case config.STORAGE_LOCAL, "":
+		return blobrepo.NewBlobStoreLocalImpl(localMediaDir(), conf.Local.BaseURL)
+	}
+	panic(fmt.Sprintf("unknown storage driver %v", conf.Driver))
+}
+
+// newPublisher sends the outbox to kafka, the memory publisher only
+// keeps the last events and is meant for local runs.
+func newPublisher() publisherrepo.IPublisher {
+	conf := config.Load.Outbox
+	switch conf.Publisher {
+	case config.PUBLISHER_KAFKA:
+		return publisherrepo.NewPublisherKafkaImpl(conf.Brokers, conf.TopicPrefix)
+	case config.PUBLISHER_MEMORY, "":
+		return publisherrepo.NewPublisherMemoryImpl(publisherrepo.DEFAULT_RETAIN)
+	}
+	panic(fmt.Sprintf("unknown outbox publisher %v", conf.Publisher))
+}
+
+func localMediaDir() string {
+	if config.Load.Storage.Local.Dir == "" {
+		return "./media"
+	}
+	return config.Load.Storage.Local.Dir
+}
+
+func setupJWTKeys() {
+	if len(config.Load.Jwt.Keys) == 0 {
+		return
+	}
+
+	confs := make([]crypto.KeyConfig, 0, len(config.Load.Jwt.Keys))
+	for _, key := range config.Load.Jwt.Keys {
+		confs = append(confs, crypto.KeyConfig{
+			ID:          key.ID,
+			Alg:         key.Alg,
+			Private:     key.Private,
+			Public:      key.Public,
+			PrivateFile: key.PrivateFile,
+			PublicFile:  key.PublicFile,
+		})
+	}
+	keySet, err := crypto.NewKeySet(config.Load.Jwt.ActiveKid, confs)
+	if err != nil {
+		panic(err)
+	}
+	if config.Load.Jwt.LegacyHS256 {
+		keySet.AcceptLegacyHS256()
 	}
+	crypto.UseJWTKeys(keySet, keySet)
 }
@@ -0,0 +1,7 @@
+package config
+
+// CommentConfig shapes the comment threads, defaults are used for the empty values.
+type CommentConfig struct {
+	// deepest reply, 1 only allows replies to top level comments
+	MaxDepth uint64 `mapstructure:"maxDepth"`
+}
@@ -0,0 +1,10 @@
+package config
+
+// FeedConfig tunes the home feed, defaults are used for the empty values.
+type FeedConfig struct {
+	// authors with more followers are not fanned out on write,
+	// their photos are read at feed time instead
+	FanOutMaxFollowers uint64 `mapstructure:"fanOutMaxFollowers"`
+	// photos are copied to the timelines in the background
+	FanOut ProcessingConfig `mapstructure:"fanOut"`
+}
@@ -22,14 +22,23 @@ var (
 
 type (
 	structure struct {
-		Server     server     `mapstructure:"server"`
-		DataSource dataSource `mapstructure:"dataSource"`
+		Server     server         `mapstructure:"server"`
+		DataSource dataSource     `mapstructure:"dataSource"`
+		Jwt        jwt            `mapstructure:"jwt"`
+		Storage    StorageConfig  `mapstructure:"storage"`
+		Feed       FeedConfig     `mapstructure:"feed"`
+		Comment    CommentConfig  `mapstructure:"comment"`
+		Realtime   RealtimeConfig `mapstructure:"realtime"`
+		Webhook    WebhookConfig  `mapstructure:"webhook"`
+		Outbox     OutboxConfig   `mapstructure:"outbox"`
 	}
 	server struct {
 		Name string `mapstructure:"name"`
 		Env  string `mapstructure:"env"`
 		Http struct {
 			Port uint `mapstructure:"port"`
+			// in seconds, realtime streams are not bound by it
+			RequestTimeout int `mapstructure:"requestTimeout"`
 		} `mapstructure:"http"`
 	}
 	dataSource struct {
@@ -38,12 +47,27 @@ type (
 		Postgres struct {
 			Master PostgresConfig `mapstructure:"master"`
 		}
+		Redis RedisConfig `mapstructure:"redis"`
+	}
+	jwt struct {
+		// empty keys keep signing with the shared HS256 key
+		ActiveKid   string   `mapstructure:"activeKid"`
+		LegacyHS256 bool     `mapstructure:"legacyHS256"`
+		Keys        []jwtKey `mapstructure:"keys"`
+	}
+	jwtKey struct {
+		ID          string `mapstructure:"id"`
+		Alg         string `mapstructure:"alg"`
+		Private     string `mapstructure:"private"`
+		Public      string `mapstructure:"public"`
+		PrivateFile string `mapstructure:"privateFile"`
+		PublicFile  string `mapstructure:"publicFile"`
 	}
 )
 
-// init config to load all
-// config sturcture
-func init() {
+// Init parses the flags and loads the config named by -config
+// into Load, main calls it before anything reads Load.
+func Init() {
 	configName := flag.String("config", "local", "config name for service, default local")
 	source := flag.String("source", "MAP", "data source mode for service, default MAP")
 	flag.Parse()
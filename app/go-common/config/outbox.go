@@ -0,0 +1,20 @@
+package config
+
+const (
+	PUBLISHER_MEMORY = "memory"
+	PUBLISHER_KAFKA  = "kafka"
+)
+
+// OutboxConfig tunes the relay publishing the domain events of the
+// outbox table, defaults are used for the empty values.
+type OutboxConfig struct {
+	// memory or kafka, memory keeps the events in this instance only
+	Publisher string   `mapstructure:"publisher"`
+	Brokers   []string `mapstructure:"brokers"`
+	// the topic of an event is the prefix and its aggregate, e.g. mygram.photo
+	TopicPrefix string `mapstructure:"topicPrefix"`
+	// events published at once
+	BatchSize int `mapstructure:"batchSize"`
+	// in seconds, how often the outbox is checked for new events
+	PollInterval int `mapstructure:"pollInterval"`
+}
@@ -25,6 +25,8 @@ type PostgresConfig struct {
 	MaxIdleConnection int `mapstructure:"maxIdleConnection"`
 	MaxOpenConnection int `mapstructure:"maxOpenConnection"`
 	MaxIdleTime       int `mapstructure:"maxIdleTime"`
+	// in seconds, postgres cancels longer queries. 0 leaves it to the server
+	StatementTimeout int `mapstructure:"statementTimeout"`
 }
 
 func NewPostgresConn() (db *sql.DB) {
@@ -75,7 +77,7 @@ func NewPostgresGormConn() (db *gorm.DB) {
 }
 
 func postgresDSN() string {
-	return fmt.Sprintf(`host=%v port=%v user=%v password=%v dbname=%v sslmode=disable application_name=%v`,
+	dsn := fmt.Sprintf(`host=%v port=%v user=%v password=%v dbname=%v sslmode=disable application_name=%v`,
 		Load.DataSource.Postgres.Master.Host,
 		Load.DataSource.Postgres.Master.Port,
 		Load.DataSource.Postgres.Master.Username,
@@ -83,6 +85,11 @@ func postgresDSN() string {
 		Load.DataSource.Postgres.Master.DBName,
 		Load.Server.Name,
 	)
+	// both drivers send unknown keys as run-time parameters of the session
+	if timeout := Load.DataSource.Postgres.Master.StatementTimeout; timeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", timeout*1000)
+	}
+	return dsn
 }
 
 func postgresPoolConf(dbSQL *sql.DB) {
@@ -0,0 +1,11 @@
+package config
+
+// RealtimeConfig tunes the websocket and sse streams, defaults are used for the empty values.
+type RealtimeConfig struct {
+	// events queued for a client before it is dropped as too slow
+	BufferSize int `mapstructure:"bufferSize"`
+	// seconds between heartbeats, a websocket client that misses two is dropped
+	Heartbeat int `mapstructure:"heartbeat"`
+	// seconds a single write may take before the client is dropped
+	WriteTimeout int `mapstructure:"writeTimeout"`
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Host     string `mapstructure:"host"`
+	Port     uint   `mapstructure:"port"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+}
+
+func NewRedisConn() (client *redis.Client) {
+	client = redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%v:%v", Load.DataSource.Redis.Host, Load.DataSource.Redis.Port),
+		Password: Load.DataSource.Redis.Password,
+		DB:       Load.DataSource.Redis.DB,
+	})
+
+	// test connection
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		panic(err)
+	}
+	log.Println("successfully connect to Redis")
+	return client
+}
@@ -0,0 +1,45 @@
+package config
+
+const (
+	STORAGE_LOCAL = "local"
+	STORAGE_S3    = "s3"
+)
+
+// StorageConfig is where uploaded files are kept, driver is local or s3.
+type StorageConfig struct {
+	Driver string `mapstructure:"driver"`
+	// in bytes, the default is used when empty
+	MaxUploadSize int64              `mapstructure:"maxUploadSize"`
+	Local         LocalStorageConfig `mapstructure:"local"`
+	S3            S3StorageConfig    `mapstructure:"s3"`
+	// background processing of uploaded photos
+	Processing ProcessingConfig `mapstructure:"processing"`
+}
+
+type LocalStorageConfig struct {
+	Dir string `mapstructure:"dir"`
+	// url the dir is served under, e.g. http://localhost:9090/media
+	BaseURL string `mapstructure:"baseURL"`
+}
+
+// S3StorageConfig works with any s3 compatible storage (aws, minio, r2),
+// objects are addressed path style as endpoint/bucket/key.
+type S3StorageConfig struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"accessKey"`
+	SecretKey string `mapstructure:"secretKey"`
+	// public url of the bucket when it is behind a cdn, defaults to endpoint/bucket
+	PublicURL string `mapstructure:"publicURL"`
+}
+
+// ProcessingConfig sizes the photo processing workers, defaults are used
+// for the empty values.
+type ProcessingConfig struct {
+	Workers     int `mapstructure:"workers"`
+	QueueSize   int `mapstructure:"queueSize"`
+	MaxAttempts int `mapstructure:"maxAttempts"`
+	// in seconds, doubled after every failed attempt
+	RetryBackoff int `mapstructure:"retryBackoff"`
+}
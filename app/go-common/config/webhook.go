@@ -0,0 +1,17 @@
+package config
+
+// WebhookConfig tunes the outbound webhook deliveries, defaults are used
+// for the empty values.
+type WebhookConfig struct {
+	// deliveries sent at once by an instance
+	Workers     int `mapstructure:"workers"`
+	MaxAttempts int `mapstructure:"maxAttempts"`
+	// in seconds, doubled after every failed attempt
+	RetryBackoff int `mapstructure:"retryBackoff"`
+	// in seconds, how long a receiver may take to answer
+	Timeout int `mapstructure:"timeout"`
+	// in seconds, how often the queue is checked for due deliveries
+	PollInterval int `mapstructure:"pollInterval"`
+	// lets webhooks point at loopback and private addresses, local only
+	AllowPrivateNetworks bool `mapstructure:"allowPrivateNetworks"`
+}
@@ -0,0 +1,152 @@
+package domainerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mygram/go-common/pkg/response"
+)
+
+type Kind string
+
+const (
+	KIND_NOT_FOUND       Kind = "not_found"
+	KIND_CONFLICT        Kind = "conflict"
+	KIND_FORBIDDEN       Kind = "forbidden"
+	KIND_VALIDATION      Kind = "validation"
+	KIND_UNAUTHENTICATED Kind = "unauthenticated"
+	// the request may succeed when retried later
+	KIND_UNAVAILABLE Kind = "unavailable"
+	KIND_TIMEOUT     Kind = "timeout"
+)
+
+// Error is an error the caller can act on, everything else is
+// treated as an internal error by the http layer.
+// Code is the stable code sent to clients, it defaults to the code of Kind.
+type Error struct {
+	Kind    Kind
+	Code    response.ErrorCode
+	Message string
+	Fields  []response.FieldError
+	Err     error
+}
+
+var kindCode = map[Kind]response.ErrorCode{
+	KIND_NOT_FOUND:       response.CODE_NOT_FOUND,
+	KIND_CONFLICT:        response.CODE_CONFLICT,
+	KIND_FORBIDDEN:       response.CODE_FORBIDDEN,
+	KIND_VALIDATION:      response.CODE_VALIDATION_FAILED,
+	KIND_UNAUTHENTICATED: response.CODE_UNAUTHENTICATED,
+	KIND_UNAVAILABLE:     response.CODE_UNAVAILABLE,
+	KIND_TIMEOUT:         response.CODE_TIMEOUT,
+}
+
+// sentinel per kind, errors.Is(err, ErrNotFound) matches any not found error
+var (
+	ErrNotFound        = &Error{Kind: KIND_NOT_FOUND}
+	ErrConflict        = &Error{Kind: KIND_CONFLICT}
+	ErrForbidden       = &Error{Kind: KIND_FORBIDDEN}
+	ErrValidation      = &Error{Kind: KIND_VALIDATION}
+	ErrUnauthenticated = &Error{Kind: KIND_UNAUTHENTICATED}
+	ErrUnavailable     = &Error{Kind: KIND_UNAVAILABLE}
+	ErrTimeout         = &Error{Kind: KIND_TIMEOUT}
+)
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = string(e.Kind)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%v: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	// a sentinel matches by kind only
+	return t.Message == "" && t.Err == nil && t.Kind == e.Kind
+}
+
+// ErrorCode returns Code or the default code of Kind.
+func (e *Error) ErrorCode() response.ErrorCode {
+	if e.Code != "" {
+		return e.Code
+	}
+	return kindCode[e.Kind]
+}
+
+// New is for errors with a more specific code than the one of kind.
+func New(kind Kind, code response.ErrorCode, message string) error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(entity string) error {
+	return &Error{Kind: KIND_NOT_FOUND, Message: entity + " is not found"}
+}
+
+func Conflict(message string) error {
+	return &Error{Kind: KIND_CONFLICT, Message: message}
+}
+
+func Forbidden(message string) error {
+	return &Error{Kind: KIND_FORBIDDEN, Message: message}
+}
+
+func Validation(message string) error {
+	return &Error{Kind: KIND_VALIDATION, Message: message}
+}
+
+// ValidationFields reports every invalid field at once.
+func ValidationFields(fields ...response.FieldError) error {
+	return &Error{Kind: KIND_VALIDATION, Message: "request has invalid fields", Fields: fields}
+}
+
+func Unauthenticated(message string) error {
+	return &Error{Kind: KIND_UNAUTHENTICATED, Message: message}
+}
+
+// Wrap keeps err as the cause, it is logged but never shown to the client.
+func Wrap(kind Kind, message string, err error) error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// WrapCode is Wrap with a more specific code than the one of kind.
+func WrapCode(kind Kind, code response.ErrorCode, message string, err error) error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: err}
+}
+
+// FromContext turns the error of a ctx that ran out of time into a
+// timeout and the one of a cancelled ctx into unavailable, the client
+// of a cancelled request is gone anyway. Other errors are returned as is.
+func FromContext(err error) error {
+	var e *Error
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(KIND_TIMEOUT, "request took too long", err)
+	case errors.Is(err, context.Canceled):
+		return Wrap(KIND_UNAVAILABLE, "request was cancelled", err)
+	}
+	return err
+}
+
+// KindOf returns the kind of the first domain error in the chain.
+func KindOf(err error) (kind Kind, ok bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind, true
+	}
+	return "", false
+}
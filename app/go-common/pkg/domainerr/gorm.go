@@ -0,0 +1,61 @@
+package domainerr
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mygram/go-common/pkg/response"
+	"gorm.io/gorm"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+	// statement_timeout of the connection, see config.PostgresConfig
+	pgQueryCanceled = "57014"
+)
+
+// FromDB turns gorm and postgres errors into domain errors,
+// unknown errors are returned as is.
+func FromDB(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return NotFound(entity)
+	}
+
+	// the driver wraps the error of the ctx that cancelled the query
+	if ctxErr := FromContext(err); ctxErr != err {
+		return ctxErr
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return WrapCode(KIND_CONFLICT, response.CODE_ALREADY_EXISTS, uniqueColumn(pgErr, entity)+" already exists", err)
+	case pgForeignKeyViolation:
+		return Wrap(KIND_VALIDATION, entity+" references a missing record", err)
+	case pgCheckViolation:
+		return Wrap(KIND_VALIDATION, entity+" violates "+pgErr.ConstraintName, err)
+	case pgQueryCanceled:
+		return Wrap(KIND_UNAVAILABLE, entity+" query took too long", err)
+	}
+	return err
+}
+
+// uniqueColumn guesses the column from the default postgres
+// constraint name, <table>_<column>_key
+func uniqueColumn(pgErr *pgconn.PgError, entity string) string {
+	name := pgErr.ConstraintName
+	if pgErr.TableName == "" || !strings.HasPrefix(name, pgErr.TableName+"_") || !strings.HasSuffix(name, "_key") {
+		return entity
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(name, pgErr.TableName+"_"), "_key")
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const REDACTED = "REDACTED"
+
+// AccessLog is gin.Logger with the values of the query params named in
+// redacted replaced, a token in the url must not end up in the logs.
+func AccessLog(redacted ...string) gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(param gin.LogFormatterParams) string {
+			param.Path = redactQuery(param.Path, redacted)
+			return formatAccessLog(param)
+		},
+	})
+}
+
+// redactQuery keeps the order and the encoding of the other params.
+func redactQuery(path string, redacted []string) string {
+	base, query, ok := strings.Cut(path, "?")
+	if !ok || len(redacted) == 0 {
+		return path
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil {
+			key = name
+		}
+		for _, r := range redacted {
+			if key == r {
+				params[i] = r + "=" + REDACTED
+				break
+			}
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
+
+// formatAccessLog is the default format of gin.Logger.
+func formatAccessLog(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		param.Path,
+		param.ErrorMessage,
+	)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		desc     string
+		target   string
+		wantPath string
+	}{
+		{
+			desc:     "token is redacted",
+			target:   "/stream?photo_id=1&access_token=secret&photo_id=2",
+			wantPath: `"/stream?photo_id=1&access_token=REDACTED&photo_id=2"`,
+		},
+		{
+			desc:     "escaped name is redacted",
+			target:   "/stream?access%5Ftoken=secret",
+			wantPath: `"/stream?access_token=REDACTED"`,
+		},
+		{
+			desc:     "other params stay",
+			target:   "/photo?sort=created_at",
+			wantPath: `"/photo?sort=created_at"`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var out bytes.Buffer
+			defaultWriter := gin.DefaultWriter
+			gin.DefaultWriter = &out
+			defer func() { gin.DefaultWriter = defaultWriter }()
+
+			r := gin.New()
+			r.Use(AccessLog("access_token"))
+			r.GET("/*path", func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tC.target, nil))
+
+			assert.Contains(t, out.String(), tC.wantPath)
+			assert.NotContains(t, out.String(), "secret")
+		})
+	}
+}
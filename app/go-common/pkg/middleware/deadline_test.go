@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		desc       string
+		timeout    time.Duration
+		wantStatus int
+	}{
+		{
+			desc:       "slow handler times out",
+			timeout:    10 * time.Millisecond,
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			desc:       "no timeout",
+			timeout:    0,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := gin.New()
+			r.ContextWithFallback = true
+			r.Use(ErrorHandler(), Deadline(tC.timeout))
+			r.GET("/", func(ctx *gin.Context) {
+				select {
+				case <-ctx.Done():
+					ctx.Error(ctx.Err())
+				case <-time.After(50 * time.Millisecond):
+					ctx.Status(http.StatusOK)
+				}
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tC.wantStatus, w.Code)
+		})
+	}
+}
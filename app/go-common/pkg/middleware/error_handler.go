@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mygram/go-common/pkg/context"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/logger"
+	"github.com/mygram/go-common/pkg/response"
+)
+
+// ErrorHandler writes the response for the last error a handler pushed
+// with ctx.Error, handlers only have to push the error and return.
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		ginErr := ctx.Errors.Last()
+		if ginErr == nil || ctx.Writer.Written() {
+			return
+		}
+		AbortWithError(ctx, ginErr.Err)
+	}
+}
+
+// AbortWithError writes err as application/problem+json, errors
+// that are not domain errors are logged and hidden behind a 500.
+func AbortWithError(ctx *gin.Context, err error) {
+	problem := ProblemOf(err, ctx.GetString(context.CorrID.String()))
+	if problem.Code == response.CODE_INTERNAL {
+		logger.Error(ctx, "unhandled error",
+			"error", err)
+	} else {
+		logger.Error(ctx, "request failed",
+			"code", problem.Code,
+			"error", err)
+	}
+
+	ctx.Header("Content-Type", response.ContentTypeProblem)
+	ctx.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// ProblemOf maps err to a problem, instance is the correlation id.
+// A request that ran out of time is a 504 whichever layer noticed it.
+func ProblemOf(err error, instance string) response.Problem {
+	var domainErr *domainerr.Error
+	if !errors.As(domainerr.FromContext(err), &domainErr) {
+		return response.NewProblem(response.CODE_INTERNAL, response.SomethingWentWrong, instance)
+	}
+
+	detail := domainErr.Message
+	if detail == "" {
+		detail = string(domainErr.Kind)
+	}
+	problem := response.NewProblem(domainErr.ErrorCode(), detail, instance)
+	problem.Errors = domainErr.Fields
+	return problem
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mygram/go-common/pkg/context"
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uniqueViolation := &pgconn.PgError{
+		Code:           "23505",
+		TableName:      "user",
+		ConstraintName: "user_email_key",
+	}
+
+	testCases := []struct {
+		desc       string
+		err        error
+		wantStatus int
+		wantCode   response.ErrorCode
+		wantDetail string
+	}{
+		{
+			desc:       "not found",
+			err:        domainerr.FromDB(gorm.ErrRecordNotFound, "photo"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   response.CODE_NOT_FOUND,
+			wantDetail: "photo is not found",
+		},
+		{
+			desc:       "unique violation",
+			err:        domainerr.FromDB(fmt.Errorf("create user: %w", uniqueViolation), "user"),
+			wantStatus: http.StatusConflict,
+			wantCode:   response.CODE_ALREADY_EXISTS,
+			wantDetail: "email already exists",
+		},
+		{
+			desc:       "forbidden",
+			err:        domainerr.Forbidden("not yours"),
+			wantStatus: http.StatusForbidden,
+			wantCode:   response.CODE_FORBIDDEN,
+			wantDetail: "not yours",
+		},
+		{
+			desc:       "validation",
+			err:        domainerr.Validation("title cannot be empty"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   response.CODE_VALIDATION_FAILED,
+			wantDetail: "title cannot be empty",
+		},
+		{
+			desc:       "unauthenticated",
+			err:        domainerr.Unauthenticated("invalid refresh token"),
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   response.CODE_UNAUTHENTICATED,
+			wantDetail: "invalid refresh token",
+		},
+		{
+			desc:       "query cancelled by the request deadline",
+			err:        domainerr.FromDB(fmt.Errorf("query photo: %w", stdcontext.DeadlineExceeded), "photo"),
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   response.CODE_TIMEOUT,
+			wantDetail: "request took too long",
+		},
+		{
+			desc:       "statement timeout",
+			err:        domainerr.FromDB(&pgconn.PgError{Code: "57014"}, "photo"),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   response.CODE_UNAVAILABLE,
+			wantDetail: "photo query took too long",
+		},
+		{
+			desc:       "deadline outside the db",
+			err:        fmt.Errorf("get revocation: %w", stdcontext.DeadlineExceeded),
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   response.CODE_TIMEOUT,
+			wantDetail: "request took too long",
+		},
+		{
+			desc:       "unknown error is hidden",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   response.CODE_INTERNAL,
+			wantDetail: "something went wrong",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := gin.New()
+			r.Use(CorrelationIDInterceptor(), ErrorHandler())
+			r.GET("/", func(ctx *gin.Context) {
+				ctx.Error(tC.err)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(context.CorrID.String(), "this-is-correlation-id")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tC.wantStatus, w.Code)
+			assert.Equal(t, response.ContentTypeProblem, w.Header().Get("Content-Type"))
+			assert.NotContains(t, w.Body.String(), "23505")
+
+			var problem response.Problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tC.wantStatus, problem.Status)
+			assert.Equal(t, tC.wantCode, problem.Code)
+			assert.Equal(t, tC.wantCode.Type(), problem.Type)
+			assert.Equal(t, tC.wantDetail, problem.Detail)
+			assert.Equal(t, "this-is-correlation-id", problem.Instance)
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// LOCK_KEY is the pg_advisory_lock held while migrating, instances
+// starting together wait for the first one instead of racing it
+const LOCK_KEY int64 = 7250241183
+
+const recordApplied = "INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)"
+
+// Migration is a pair of files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, versions are applied in ascending order.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+	// sha256 of Up, a migration must not change once it was applied
+	Checksum string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%v", m.Version, m.Name)
+}
+
+type Status struct {
+	Migration
+	// nil while pending
+	AppliedAt *time.Time
+	// the up file changed after it was applied
+	Modified bool
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load reads the migrations at the root of fsys, every version needs
+// both an up and a down file.
+func Load(fsys fs.FS) (migrations []Migration, err error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%v is not named <version>_<name>.up.sql or <version>_<name>.down.sql", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("version %v is used by %v and %v", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("%v needs both an up and a down file", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return
+}
+
+// Migrator applies migrations to postgres and records them in
+// schema_migrations, each one runs in its own transaction.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, fsys fs.FS) (m *Migrator, err error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+type applied struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// Up applies every pending migration, it stops at the first one that fails.
+func (m *Migrator) Up(ctx context.Context) (done []Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn) (err error) {
+		appliedMigrations, err := m.verify(ctx, conn)
+		if err != nil {
+			return
+		}
+
+		var latest uint64
+		for version := range appliedMigrations {
+			if version > latest {
+				latest = version
+			}
+		}
+		for _, migration := range m.migrations {
+			if _, ok := appliedMigrations[migration.Version]; ok {
+				continue
+			}
+			// a migration merged after a newer one was applied may
+			// depend on a schema the newer one already changed
+			if migration.Version < latest {
+				return fmt.Errorf("%v is older than the applied version %v, give it a newer version", migration, latest)
+			}
+			if err = m.apply(ctx, conn, migration, true); err != nil {
+				return
+			}
+			done = append(done, migration)
+		}
+		return
+	})
+	return
+}
+
+// Down reverts the latest applied migration, reverted is nil when
+// nothing is applied.
+func (m *Migrator) Down(ctx context.Context) (reverted *Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn) (err error) {
+		reverted, err = m.down(ctx, conn)
+		return
+	})
+	return
+}
+
+// Redo reverts the latest applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (redone *Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn) (err error) {
+		redone, err = m.down(ctx, conn)
+		if err != nil || redone == nil {
+			return
+		}
+		return m.apply(ctx, conn, *redone, true)
+	})
+	return
+}
+
+// Status lists every migration with the time it was applied.
+func (m *Migrator) Status(ctx context.Context) (statuses []Status, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn) (err error) {
+		appliedMigrations, err := m.applied(ctx, conn)
+		if err != nil {
+			return
+		}
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if row, ok := appliedMigrations[migration.Version]; ok {
+				appliedAt := row.appliedAt
+				status.AppliedAt = &appliedAt
+				status.Modified = row.checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		return
+	})
+	return
+}
+
+// Baseline records the migrations up to version as applied without
+// running them, for a database whose schema was created before the
+// migrations were. It refuses once anything was applied.
+func (m *Migrator) Baseline(ctx context.Context, version uint64) (marked []Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn) (err error) {
+		appliedMigrations, err := m.applied(ctx, conn)
+		if err != nil {
+			return
+		}
+		if len(appliedMigrations) > 0 {
+			return errors.New("migrations were applied already, baseline is only for a database that was never migrated")
+		}
+
+		var known bool
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			known = known || migration.Version == version
+			marked = append(marked, migration)
+		}
+		if !known {
+			return fmt.Errorf("version %v has no files", version)
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return
+		}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+			}
+		}()
+		for _, migration := range marked {
+			if _, err = tx.ExecContext(ctx, recordApplied, migration.Version, migration.Name, migration.Checksum); err != nil {
+				return
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		marked = nil
+	}
+	return
+}
+
+func (m *Migrator) down(ctx context.Context, conn *sql.Conn) (reverted *Migration, err error) {
+	appliedMigrations, err := m.verify(ctx, conn)
+	if err != nil {
+		return
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if _, ok := appliedMigrations[m.migrations[i].Version]; !ok {
+			continue
+		}
+		if err = m.apply(ctx, conn, m.migrations[i], false); err != nil {
+			return
+		}
+		return &m.migrations[i], nil
+	}
+	return
+}
+
+// withLock runs fn on a single connection, the advisory lock and the
+// session settings belong to it.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// neither waiting for the lock nor a long migration is a slow query
+	if _, err = conn.ExecContext(ctx, "SET statement_timeout = 0"); err != nil {
+		return
+	}
+	defer conn.ExecContext(context.Background(), "RESET statement_timeout")
+
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", LOCK_KEY); err != nil {
+		return fmt.Errorf("lock schema_migrations: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", LOCK_KEY)
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version BIGINT NOT NULL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  checksum VARCHAR(64) NOT NULL,
+  applied_at timestamptz NOT NULL DEFAULT now()
+)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (appliedMigrations map[uint64]applied, err error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	appliedMigrations = map[uint64]applied{}
+	for rows.Next() {
+		var version uint64
+		var row applied
+		if err = rows.Scan(&version, &row.checksum, &row.appliedAt); err != nil {
+			return
+		}
+		appliedMigrations[version] = row
+	}
+	return appliedMigrations, rows.Err()
+}
+
+// verify refuses to go on when the applied migrations and the files
+// disagree, the schema is then not what the files describe.
+func (m *Migrator) verify(ctx context.Context, conn *sql.Conn) (appliedMigrations map[uint64]applied, err error) {
+	appliedMigrations, err = m.applied(ctx, conn)
+	if err != nil {
+		return
+	}
+
+	known := map[uint64]Migration{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	for version, row := range appliedMigrations {
+		migration, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("version %v is applied but has no files", version)
+		}
+		if row.checksum != migration.Checksum {
+			return nil, fmt.Errorf("%v changed after it was applied, add a new migration instead", migration)
+		}
+	}
+	return
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, up bool) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	script, record, args := migration.Down, "DELETE FROM schema_migrations WHERE version = $1", []interface{}{migration.Version}
+	if up {
+		script, record = migration.Up, recordApplied
+		args = append(args, migration.Name, migration.Checksum)
+	}
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		direction := "down"
+		if up {
+			direction = "up"
+		}
+		return fmt.Errorf("%v %v: %w", migration, direction, err)
+	}
+	if _, err = tx.ExecContext(ctx, record, args...); err != nil {
+		return
+	}
+	return tx.Commit()
+}
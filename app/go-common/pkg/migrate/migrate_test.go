@@ -0,0 +1,313 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var migrationFiles = fstest.MapFS{
+	"0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id INT);")},
+	"0001_init.down.sql":  {Data: []byte("DROP TABLE a;")},
+	"0002_add_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+	"0002_add_b.down.sql": {Data: []byte("DROP TABLE b;")},
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		fsys     fstest.MapFS
+		wantErr  string
+		wantLoad []Migration
+	}{
+		{
+			desc: "sorted by version",
+			fsys: fstest.MapFS{
+				"0002_add_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+				"0002_add_b.down.sql": {Data: []byte("DROP TABLE b;")},
+				"0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id INT);")},
+				"0001_init.down.sql":  {Data: []byte("DROP TABLE a;")},
+			},
+			wantLoad: []Migration{
+				{Version: 1, Name: "init", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;", Checksum: checksum("CREATE TABLE a (id INT);")},
+				{Version: 2, Name: "add_b", Up: "CREATE TABLE b (id INT);", Down: "DROP TABLE b;", Checksum: checksum("CREATE TABLE b (id INT);")},
+			},
+		},
+		{
+			desc: "missing down",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+			},
+			wantErr: "0001_init needs both an up and a down file",
+		},
+		{
+			desc: "unknown file",
+			fsys: fstest.MapFS{
+				"init.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+			},
+			wantErr: "init.sql is not named <version>_<name>.up.sql or <version>_<name>.down.sql",
+		},
+		{
+			desc: "version used twice",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":  {Data: []byte("CREATE TABLE a (id INT);")},
+				"0001_other.up.sql": {Data: []byte("CREATE TABLE b (id INT);")},
+			},
+			wantErr: "version 1 is used by init and other",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			migrations, err := Load(tC.fsys)
+			if tC.wantErr != "" {
+				assert.EqualError(t, err, tC.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.wantLoad, migrations)
+		})
+	}
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SET statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(LOCK_KEY).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(LOCK_KEY).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RESET statement_timeout").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectApplied(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").WillReturnRows(rows)
+}
+
+func appliedRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"version", "checksum", "applied_at"})
+}
+
+func TestUp(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		doMock   func(mock sqlmock.Sqlmock)
+		wantErr  string
+		wantDone []uint64
+	}{
+		{
+			desc: "applies pending migrations in order",
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows().AddRow(1, checksum("CREATE TABLE a (id INT);"), time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b (id INT);")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "add_b", checksum("CREATE TABLE b (id INT);")).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectUnlock(mock)
+			},
+			wantDone: []uint64{2},
+		},
+		{
+			desc: "failed migration is rolled back",
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows())
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a (id INT);")).WillReturnError(errors.New("syntax error"))
+				mock.ExpectRollback()
+				expectUnlock(mock)
+			},
+			wantErr: "0001_init up: syntax error",
+		},
+		{
+			desc: "changed migration",
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows().AddRow(1, checksum("CREATE TABLE a (id BIGINT);"), time.Now()))
+				expectUnlock(mock)
+			},
+			wantErr: "0001_init changed after it was applied, add a new migration instead",
+		},
+		{
+			desc: "applied migration without files",
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows().AddRow(3, "abc", time.Now()))
+				expectUnlock(mock)
+			},
+			wantErr: "version 3 is applied but has no files",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			tC.doMock(mock)
+
+			m, err := NewMigrator(db, migrationFiles)
+			assert.NoError(t, err)
+			done, err := m.Up(context.Background())
+			if tC.wantErr != "" {
+				assert.EqualError(t, err, tC.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			var versions []uint64
+			for _, migration := range done {
+				versions = append(versions, migration.Version)
+			}
+			assert.Equal(t, tC.wantDone, versions)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDown(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		doMock func(mock sqlmock.Sqlmock)
+		// 0 when nothing is reverted
+		wantReverted uint64
+	}{
+		{
+			desc: "reverts the latest applied",
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows().
+					AddRow(1, checksum("CREATE TABLE a (id INT);"), time.Now()).
+					AddRow(2, checksum("CREATE TABLE b (id INT);"), time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DROP TABLE b;")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectUnlock(mock)
+			},
+			wantReverted: 2,
+		},
+		{
+			desc: "nothing applied",
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows())
+				expectUnlock(mock)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			tC.doMock(mock)
+
+			m, err := NewMigrator(db, migrationFiles)
+			assert.NoError(t, err)
+			reverted, err := m.Down(context.Background())
+			assert.NoError(t, err)
+			if tC.wantReverted == 0 {
+				assert.Nil(t, reverted)
+			} else {
+				assert.Equal(t, tC.wantReverted, reverted.Version)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	appliedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expectLock(mock)
+	expectApplied(mock, appliedRows().AddRow(1, "changed", appliedAt))
+	expectUnlock(mock)
+
+	m, err := NewMigrator(db, migrationFiles)
+	assert.NoError(t, err)
+	statuses, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, &appliedAt, statuses[0].AppliedAt)
+	assert.True(t, statuses[0].Modified)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBaseline(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		version    uint64
+		doMock     func(mock sqlmock.Sqlmock)
+		wantErr    string
+		wantMarked []uint64
+	}{
+		{
+			desc:    "marks the existing schema without running it",
+			version: 1,
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows())
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(1, "init", checksum("CREATE TABLE a (id INT);")).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectUnlock(mock)
+			},
+			wantMarked: []uint64{1},
+		},
+		{
+			desc:    "migrated database",
+			version: 1,
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows().AddRow(1, checksum("CREATE TABLE a (id INT);"), time.Now()))
+				expectUnlock(mock)
+			},
+			wantErr: "migrations were applied already, baseline is only for a database that was never migrated",
+		},
+		{
+			desc:    "unknown version",
+			version: 3,
+			doMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				expectApplied(mock, appliedRows())
+				expectUnlock(mock)
+			},
+			wantErr: "version 3 has no files",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			tC.doMock(mock)
+
+			m, err := NewMigrator(db, migrationFiles)
+			assert.NoError(t, err)
+			marked, err := m.Baseline(context.Background(), tC.version)
+			if tC.wantErr != "" {
+				assert.EqualError(t, err, tC.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			var versions []uint64
+			for _, migration := range marked {
+				versions = append(versions, migration.Version)
+			}
+			assert.Equal(t, tC.wantMarked, versions)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/mygram/go-common/pkg/response"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_LIMIT = 20
+	MAX_LIMIT     = 100
+)
+
+// sortable columns, they must be not null and id breaks the ties
+const (
+	SORT_CREATED_AT = "created_at"
+	SORT_ID         = "id"
+)
+
+var DefaultSort = Sort{Column: SORT_CREATED_AT, Desc: true}
+
+// Query is bound from ?limit=&cursor=&sort=, sort is a column name
+// prefixed with - for descending order, e.g. sort=-created_at
+type Query struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort"`
+}
+
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Cursor points at the last row of a page, it is opaque to clients
+// and only valid for the sort it was issued with.
+type Cursor struct {
+	Sort      string    `json:"s"`
+	CreatedAt time.Time `json:"c"`
+	ID        uint64    `json:"i"`
+}
+
+// Params is a validated Query.
+type Params struct {
+	Limit  int
+	Sort   Sort
+	Cursor *Cursor
+}
+
+func invalidParam(message string, err error) error {
+	return domainerr.WrapCode(domainerr.KIND_VALIDATION, response.CODE_INVALID_PARAM, message, err)
+}
+
+func (q Query) Params() (params Params, err error) {
+	params.Limit = q.Limit
+	if params.Limit == 0 {
+		params.Limit = DEFAULT_LIMIT
+	}
+	if params.Limit < 0 || params.Limit > MAX_LIMIT {
+		err = invalidParam(fmt.Sprintf("limit must be between 1 and %v", MAX_LIMIT), nil)
+		return
+	}
+
+	if params.Sort, err = ParseSort(q.Sort); err != nil {
+		return
+	}
+
+	if q.Cursor == "" {
+		return
+	}
+	cursor, err := DecodeCursor(q.Cursor)
+	if err != nil {
+		return
+	}
+	if cursor.Sort != params.Sort.String() {
+		err = invalidParam("cursor was issued for another sort", nil)
+		return
+	}
+	params.Cursor = &cursor
+	return
+}
+
+func ParseSort(raw string) (sort Sort, err error) {
+	if raw == "" {
+		return DefaultSort, nil
+	}
+	sort.Desc = strings.HasPrefix(raw, "-")
+	sort.Column = strings.TrimPrefix(raw, "-")
+	switch sort.Column {
+	case SORT_CREATED_AT, SORT_ID:
+		return
+	}
+	err = invalidParam(fmt.Sprintf("sort must be one of %v, %v", SORT_CREATED_AT, SORT_ID), nil)
+	return
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Column
+	}
+	return s.Column
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(raw string) (cursor Cursor, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		err = invalidParam("cursor is malformed", err)
+		return
+	}
+	if err = json.Unmarshal(b, &cursor); err != nil {
+		err = invalidParam("cursor is malformed", err)
+		return
+	}
+	return
+}
+
+// Scope adds the keyset condition, the order and fetches one extra row
+// so Paginate knows if there is a next page, use it with db.Scopes.
+func (p Params) Scope(db *gorm.DB) *gorm.DB {
+	direction, op := "ASC", ">"
+	if p.Sort.Desc {
+		direction, op = "DESC", "<"
+	}
+
+	if p.Cursor != nil {
+		switch p.Sort.Column {
+		case SORT_ID:
+			db = db.Where(fmt.Sprintf("id %v ?", op), p.Cursor.ID)
+		default:
+			db = db.Where(fmt.Sprintf("(%v, id) %v (?, ?)", p.Sort.Column, op),
+				p.Cursor.CreatedAt, p.Cursor.ID)
+		}
+	}
+
+	if p.Sort.Column != SORT_ID {
+		db = db.Order(fmt.Sprintf("%v %v", p.Sort.Column, direction))
+	}
+	return db.
+		Order(fmt.Sprintf("id %v", direction)).
+		Limit(p.Limit + 1)
+}
+
+// Paginate trims the extra row fetched by Scope and builds the cursor
+// of the next page from the last row, keyOf returns its id and created_at.
+func Paginate[T any](rows []T, params Params, keyOf func(row T) (id uint64, createdAt time.Time)) ([]T, response.Pagination) {
+	if len(rows) <= params.Limit {
+		return rows, response.Pagination{}
+	}
+
+	rows = rows[:params.Limit]
+	id, createdAt := keyOf(rows[len(rows)-1])
+	next := Cursor{
+		Sort:      params.Sort.String(),
+		CreatedAt: createdAt,
+		ID:        id,
+	}
+	return rows, response.Pagination{
+		NextCursor: next.Encode(),
+		HasMore:    true,
+	}
+}
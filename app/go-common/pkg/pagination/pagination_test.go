@@ -0,0 +1,135 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mygram/go-common/pkg/domainerr"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type row struct {
+	ID        uint64
+	CreatedAt time.Time
+}
+
+func keyOf(r row) (uint64, time.Time) {
+	return r.ID, r.CreatedAt
+}
+
+func TestQueryParams(t *testing.T) {
+	cursor := Cursor{Sort: "-created_at", CreatedAt: time.Unix(1680000000, 0).UTC(), ID: 7}
+
+	testCases := []struct {
+		desc    string
+		input   Query
+		want    Params
+		wantErr bool
+	}{
+		{
+			desc:  "defaults",
+			input: Query{},
+			want:  Params{Limit: DEFAULT_LIMIT, Sort: DefaultSort},
+		},
+		{
+			desc:  "ascending id",
+			input: Query{Limit: 5, Sort: "id"},
+			want:  Params{Limit: 5, Sort: Sort{Column: SORT_ID}},
+		},
+		{
+			desc:  "with cursor",
+			input: Query{Cursor: cursor.Encode()},
+			want:  Params{Limit: DEFAULT_LIMIT, Sort: DefaultSort, Cursor: &cursor},
+		},
+		{
+			desc:    "limit above max",
+			input:   Query{Limit: MAX_LIMIT + 1},
+			wantErr: true,
+		},
+		{
+			desc:    "sort is not allowed",
+			input:   Query{Sort: "password"},
+			wantErr: true,
+		},
+		{
+			desc:    "malformed cursor",
+			input:   Query{Cursor: "not-a-cursor"},
+			wantErr: true,
+		},
+		{
+			desc:    "cursor of another sort",
+			input:   Query{Cursor: cursor.Encode(), Sort: "id"},
+			wantErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			params, err := tC.input.Params()
+			if tC.wantErr {
+				assert.ErrorIs(t, err, domainerr.ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, params)
+		})
+	}
+}
+
+func TestScope(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		desc  string
+		input Params
+		want  string
+	}{
+		{
+			desc:  "first page",
+			input: Params{Limit: 20, Sort: DefaultSort},
+			want:  `SELECT * FROM "photo" ORDER BY created_at DESC,id DESC LIMIT 21`,
+		},
+		{
+			desc:  "next page by created_at",
+			input: Params{Limit: 20, Sort: DefaultSort, Cursor: &Cursor{ID: 7}},
+			want:  `SELECT * FROM "photo" WHERE (created_at, id) < ($1, $2) ORDER BY created_at DESC,id DESC LIMIT 21`,
+		},
+		{
+			desc:  "next page by id",
+			input: Params{Limit: 10, Sort: Sort{Column: SORT_ID}, Cursor: &Cursor{ID: 7}},
+			want:  `SELECT * FROM "photo" WHERE id > $1 ORDER BY id ASC LIMIT 11`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var rows []row
+			stmt := db.Table("photo").Scopes(tC.input.Scope).Find(&rows).Statement
+			assert.Equal(t, tC.want, stmt.SQL.String())
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	now := time.Unix(1680000000, 0).UTC()
+	rows := []row{{ID: 3, CreatedAt: now}, {ID: 2, CreatedAt: now}, {ID: 1, CreatedAt: now}}
+	params := Params{Limit: 2, Sort: DefaultSort}
+
+	page, meta := Paginate(rows, params, keyOf)
+	assert.Len(t, page, 2)
+	assert.True(t, meta.HasMore)
+	next, err := DecodeCursor(meta.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: "-created_at", CreatedAt: now, ID: 2}, next)
+
+	page, meta = Paginate(rows[2:], params, keyOf)
+	assert.Len(t, page, 1)
+	assert.False(t, meta.HasMore)
+	assert.Empty(t, meta.NextCursor)
+}
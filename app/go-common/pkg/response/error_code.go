@@ -0,0 +1,81 @@
+package response
+
+import "net/http"
+
+// ErrorCode is part of the api contract, clients branch on it,
+// never rename or reuse a code once it is released.
+type ErrorCode string
+
+const (
+	CODE_INVALID_BODY         ErrorCode = "invalid_body"
+	CODE_INVALID_PARAM        ErrorCode = "invalid_param"
+	CODE_VALIDATION_FAILED    ErrorCode = "validation_failed"
+	CODE_PAYLOAD_TOO_LARGE    ErrorCode = "payload_too_large"
+	CODE_UNSUPPORTED_MEDIA    ErrorCode = "unsupported_media_type"
+	CODE_UNAUTHENTICATED      ErrorCode = "unauthenticated"
+	CODE_INVALID_CREDENTIALS  ErrorCode = "invalid_credentials"
+	CODE_TOKEN_INVALID        ErrorCode = "token_invalid"
+	CODE_TOKEN_REVOKED        ErrorCode = "token_revoked"
+	CODE_REFRESH_TOKEN_REUSED ErrorCode = "refresh_token_reused"
+	CODE_FORBIDDEN            ErrorCode = "forbidden"
+	CODE_NOT_OWNER            ErrorCode = "not_owner"
+	CODE_ROLE_NOT_ALLOWED     ErrorCode = "role_not_allowed"
+	CODE_NOT_FOUND            ErrorCode = "not_found"
+	CODE_CONFLICT             ErrorCode = "conflict"
+	CODE_ALREADY_EXISTS       ErrorCode = "already_exists"
+	CODE_INTERNAL             ErrorCode = "internal_error"
+	CODE_UNAVAILABLE          ErrorCode = "service_unavailable"
+	CODE_TIMEOUT              ErrorCode = "timeout"
+
+	// field level codes, used in Problem.Errors
+	CODE_FIELD_REQUIRED ErrorCode = "required"
+	CODE_FIELD_INVALID  ErrorCode = "invalid"
+)
+
+type errorCodeInfo struct {
+	Title  string
+	Status int
+}
+
+var errorCatalog = map[ErrorCode]errorCodeInfo{
+	CODE_INVALID_BODY:         {"Invalid request body", http.StatusBadRequest},
+	CODE_INVALID_PARAM:        {"Invalid request parameter", http.StatusBadRequest},
+	CODE_VALIDATION_FAILED:    {"Validation failed", http.StatusUnprocessableEntity},
+	CODE_PAYLOAD_TOO_LARGE:    {"Payload too large", http.StatusRequestEntityTooLarge},
+	CODE_UNSUPPORTED_MEDIA:    {"Unsupported media type", http.StatusUnsupportedMediaType},
+	CODE_UNAUTHENTICATED:      {"Authentication required", http.StatusUnauthorized},
+	CODE_INVALID_CREDENTIALS:  {"Invalid credentials", http.StatusUnauthorized},
+	CODE_TOKEN_INVALID:        {"Invalid token", http.StatusUnauthorized},
+	CODE_TOKEN_REVOKED:        {"Token is revoked", http.StatusUnauthorized},
+	CODE_REFRESH_TOKEN_REUSED: {"Refresh token reuse detected", http.StatusUnauthorized},
+	CODE_FORBIDDEN:            {"Forbidden", http.StatusForbidden},
+	CODE_NOT_OWNER:            {"Resource belongs to another user", http.StatusForbidden},
+	CODE_ROLE_NOT_ALLOWED:     {"Role is not allowed", http.StatusForbidden},
+	CODE_NOT_FOUND:            {"Resource not found", http.StatusNotFound},
+	CODE_CONFLICT:             {"Resource conflict", http.StatusConflict},
+	CODE_ALREADY_EXISTS:       {"Resource already exists", http.StatusConflict},
+	CODE_INTERNAL:             {"Internal server error", http.StatusInternalServerError},
+	CODE_UNAVAILABLE:          {"Service unavailable", http.StatusServiceUnavailable},
+	CODE_TIMEOUT:              {"Request timed out", http.StatusGatewayTimeout},
+}
+
+// Title of the code, falls back to the internal error title for unknown codes.
+func (c ErrorCode) Title() string {
+	if info, ok := errorCatalog[c]; ok {
+		return info.Title
+	}
+	return errorCatalog[CODE_INTERNAL].Title
+}
+
+// Status of the code, falls back to 500 for unknown codes.
+func (c ErrorCode) Status() int {
+	if info, ok := errorCatalog[c]; ok {
+		return info.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// Type is the problem type uri of the code.
+func (c ErrorCode) Type() string {
+	return ProblemTypePrefix + string(c)
+}
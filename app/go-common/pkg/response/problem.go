@@ -0,0 +1,36 @@
+package response
+
+// https://www.rfc-editor.org/rfc/rfc7807
+const (
+	ContentTypeProblem = "application/problem+json"
+	ProblemTypePrefix  = "urn:mygram:problem:"
+)
+
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     ErrorCode    `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError points to the invalid field of a validation failure.
+type FieldError struct {
+	Field   string    `json:"field"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// NewProblem fills type, title and status from the catalog.
+func NewProblem(code ErrorCode, detail, instance string) Problem {
+	return Problem{
+		Type:     code.Type(),
+		Title:    code.Title(),
+		Status:   code.Status(),
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+	}
+}
@@ -8,14 +8,20 @@ const (
 	InternalServer     = "internal server error"
 	SomethingWentWrong = "something went wrong"
 	Unauthorized       = "unauthorized request"
+	Forbidden          = "forbidden request"
 )
 
 type SuccessResponse struct {
-	Message string `json:"message"`
-	Data    any    `json:"data"`
+	Message    string      `json:"message"`
+	Data       any         `json:"data"`
+	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
-type ErrorResponse struct {
-	Message string `json:"message"`
-	Error   string `json:"error"`
+// Pagination is set on list responses, pass NextCursor back
+// as ?cursor= to get the next page.
+type Pagination struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
 }
+
+// errors are written as Problem, see problem.go
@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/unitofwork/unit_of_work.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	unitofwork "github.com/mygram/go-common/pkg/unitofwork"
+)
+
+// MockUnitOfWorkInterface is a mock of UnitOfWorkInterface interface.
+type MockUnitOfWorkInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkInterfaceMockRecorder
+}
+
+// MockUnitOfWorkInterfaceMockRecorder is the mock recorder for MockUnitOfWorkInterface.
+type MockUnitOfWorkInterfaceMockRecorder struct {
+	mock *MockUnitOfWorkInterface
+}
+
+// NewMockUnitOfWorkInterface creates a new mock instance.
+func NewMockUnitOfWorkInterface(ctrl *gomock.Controller) *MockUnitOfWorkInterface {
+	mock := &MockUnitOfWorkInterface{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWorkInterface) EXPECT() *MockUnitOfWorkInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method.
+func (m *MockUnitOfWorkInterface) Start() *unitofwork.UnitOfWork {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(*unitofwork.UnitOfWork)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).Start))
+}
+
+// Complete mocks base method.
+func (m *MockUnitOfWorkInterface) Complete() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) Complete() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).Complete))
+}
+
+// Dispose mocks base method.
+func (m *MockUnitOfWorkInterface) Dispose() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispose")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Dispose indicates an expected call of Dispose.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) Dispose() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispose", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).Dispose))
+}
+
+// Finish mocks base method.
+func (m *MockUnitOfWorkInterface) Finish(err error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", err)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) Finish(err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).Finish), err)
+}
+
+// WithinTransaction mocks base method.
+func (m *MockUnitOfWorkInterface) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).WithinTransaction), ctx, fn)
+}
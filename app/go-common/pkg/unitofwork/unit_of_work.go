@@ -1,6 +1,11 @@
 package unitofwork
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
 
 type UnitOfWork struct {
 	DbMaster *gorm.DB
@@ -12,12 +17,84 @@ type UnitOfWorkInterface interface {
 	Complete() error
 	Dispose() error
 	Finish(err error) error
+	// WithinTransaction runs fn in a transaction carried on the ctx given
+	// to fn, repositories join it through DB. Called again within fn it
+	// runs in a savepoint
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+// txState is carried on ctx, depth counts the savepoints opened in tx
+type txState struct {
+	tx    *gorm.DB
+	depth int
+}
+
+func NewUnitOfWork(dbMaster *gorm.DB) UnitOfWorkInterface {
+	return &UnitOfWork{
+		DbMaster: dbMaster,
+	}
+}
+
+// DB is the transaction carried on ctx, or db outside of one. Either
+// way the queries are cancelled with ctx.
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(txState); ok {
+		return state.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// WithinTransaction commits when fn returns nil and rolls back otherwise.
+// Within a transaction already running fn gets a savepoint, a failed fn
+// only undoes its own writes and the caller decides about the rest.
+func (uow *UnitOfWork) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if state, ok := ctx.Value(txKey{}).(txState); ok {
+		return withinSavepoint(ctx, state, fn)
+	}
+
+	// a cancelled ctx rolls the transaction back
+	work := uow.begin(ctx)
+	if err = work.Tx.Error; err != nil {
+		return
+	}
+	defer func() {
+		// a panic must not leave the connection in a transaction
+		if p := recover(); p != nil {
+			work.Dispose()
+			panic(p)
+		}
+	}()
+	return work.Finish(fn(context.WithValue(ctx, txKey{}, txState{tx: work.Tx})))
+}
+
+// withinSavepoint leaves a panic to the outermost WithinTransaction,
+// it rolls the whole transaction back.
+func withinSavepoint(ctx context.Context, state txState, fn func(ctx context.Context) error) (err error) {
+	state.depth++
+	name := fmt.Sprintf("sp%d", state.depth)
+	if err = state.tx.WithContext(ctx).SavePoint(name).Error; err != nil {
+		return
+	}
+	if err = fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		if errRollback := state.tx.WithContext(ctx).RollbackTo(name).Error; errRollback != nil {
+			return errRollback
+		}
+		return
+	}
+	return
 }
 
 func (uow *UnitOfWork) Start() *UnitOfWork {
-	tx := uow.DbMaster.Begin()
+	return uow.begin(context.Background())
+}
+
+func (uow *UnitOfWork) begin(ctx context.Context) *UnitOfWork {
+	tx := uow.DbMaster.WithContext(ctx).Begin()
 	return &UnitOfWork{
-		Tx: tx,
+		DbMaster: uow.DbMaster,
+		Tx:       tx,
 	}
 }
 
@@ -0,0 +1,216 @@
+package unitofwork
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newUnitOfWork(t *testing.T) (UnitOfWorkInterface, *gorm.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	DB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+	return NewUnitOfWork(DB), DB, mock
+}
+
+func TestWithinTransaction(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		fnErr   error
+		doMock  func(mock sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			desc: "commits when fn succeeds",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+		},
+		{
+			desc:  "rolls back when fn fails",
+			fnErr: errors.New("some error"),
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("some error"),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			uow, db, mock := newUnitOfWork(t)
+			tC.doMock(mock)
+
+			err := uow.WithinTransaction(context.Background(), func(ctx context.Context) error {
+				assert.NotEqual(t, db.Statement.ConnPool, DB(ctx, db).Statement.ConnPool, "fn runs in the transaction")
+				return tC.fnErr
+			})
+			if tC.wantErr != nil {
+				assert.EqualError(t, err, tC.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWithinTransactionNested(t *testing.T) {
+	testCases := []struct {
+		desc string
+		// returned by the inner fn
+		innerErr error
+		// the outer fn returns the error of the inner one
+		propagate bool
+		doMock    func(mock sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			desc: "inner runs in a savepoint of the same transaction",
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SAVEPOINT sp1`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			desc:     "failed inner only rolls back to its savepoint",
+			innerErr: errors.New("some error"),
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SAVEPOINT sp1`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`ROLLBACK TO SAVEPOINT sp1`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			desc:      "failed inner returned by outer rolls back everything",
+			innerErr:  errors.New("some error"),
+			propagate: true,
+			doMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SAVEPOINT sp1`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`ROLLBACK TO SAVEPOINT sp1`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("some error"),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			uow, db, mock := newUnitOfWork(t)
+			tC.doMock(mock)
+
+			err := uow.WithinTransaction(context.Background(), func(outer context.Context) error {
+				err := uow.WithinTransaction(outer, func(inner context.Context) error {
+					assert.Equal(t, DB(outer, db).Statement.ConnPool, DB(inner, db).Statement.ConnPool)
+					return tC.innerErr
+				})
+				if tC.propagate {
+					return err
+				}
+				return nil
+			})
+			if tC.wantErr != nil {
+				assert.EqualError(t, err, tC.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWithinTransactionNestedDepth(t *testing.T) {
+	uow, _, mock := newUnitOfWork(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT sp1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT sp2`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT sp1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	noop := func(ctx context.Context) error { return nil }
+	err := uow.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		err := uow.WithinTransaction(ctx, func(ctx context.Context) error {
+			return uow.WithinTransaction(ctx, noop)
+		})
+		if err != nil {
+			return err
+		}
+		// a sibling reuses the name, the first one is done with it
+		return uow.WithinTransaction(ctx, noop)
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStartKeepsDbMaster(t *testing.T) {
+	uow, db, mock := newUnitOfWork(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	work := uow.Start()
+	// a started unit can start the next one
+	assert.Same(t, db, work.DbMaster)
+	assert.NoError(t, work.Complete())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransactionPanic(t *testing.T) {
+	uow, _, mock := newUnitOfWork(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.Panics(t, func() {
+		uow.WithinTransaction(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBOutsideTransaction(t *testing.T) {
+	_, db, _ := newUnitOfWork(t)
+	assert.Equal(t, db.Statement.ConnPool, DB(context.Background(), db).Statement.ConnPool)
+}
+
+func TestDBCarriesContext(t *testing.T) {
+	uow, db, mock := newUnitOfWork(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+	assert.Equal(t, ctx, DB(ctx, db).Statement.Context)
+
+	err := uow.WithinTransaction(ctx, func(ctx context.Context) error {
+		assert.Equal(t, "request", DB(ctx, db).Statement.Context.Value(key{}))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransactionCancelled(t *testing.T) {
+	uow, _, mock := newUnitOfWork(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := uow.WithinTransaction(ctx, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, called, "nothing runs once the request is gone")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,7 @@
+-- runs once when the container starts on an empty volume, the schema is
+-- applied by the services, see `go-account migrate`
+CREATE DATABASE "mygram";
+GRANT ALL PRIVILEGES ON DATABASE "mygram" TO postgres;
+
+CREATE DATABASE "order";
+GRANT ALL PRIVILEGES ON DATABASE "order" TO postgres;